@@ -0,0 +1,91 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"omniscient/internal/model"
+	"omniscient/internal/model/entity"
+)
+
+type ChannelsReq struct {
+	g.Meta `path:"/alert/channel" tags:"Alert" method:"get" summary:"通知渠道列表"`
+}
+type ChannelsRes struct {
+	List []*entity.AlertChannel `json:"list" dc:"通知渠道，地址和密钥中的密钥值已隐藏"`
+}
+
+type SaveChannelReq struct {
+	g.Meta  `path:"/alert/channel" tags:"Alert" method:"post" summary:"新增或修改通知渠道（webhook、钉钉、企业微信、飞书、邮件）"`
+	Id      int                `json:"id"      dc:"渠道ID，为空时新增"`
+	Name    string             `json:"name"    v:"required" dc:"渠道名称"`
+	Type    string             `json:"type"    v:"required|in:webhook,dingtalk,wecom,feishu,email" dc:"渠道类型"`
+	Url     string             `json:"url"     dc:"webhook 或机器人地址，支持 ${secret:NAME}，修改时传回列表中脱敏的地址表示保持不变"`
+	Secret  string             `json:"secret"  dc:"钉钉、飞书机器人的加签密钥，支持 ${secret:NAME}，修改时传 ****** 表示保持不变"`
+	Email   *model.EmailConfig `json:"email"   dc:"邮件配置，type 为 email 时必填"`
+	Enabled bool               `json:"enabled" d:"true" dc:"是否启用"`
+}
+type SaveChannelRes struct {
+	Id int `json:"id" dc:"渠道ID"`
+}
+
+type DeleteChannelReq struct {
+	g.Meta `path:"/alert/channel/:id" tags:"Alert" method:"delete" summary:"删除通知渠道"`
+	Id     int `v:"required|min:1" in:"path" json:"id" dc:"渠道ID"`
+}
+type DeleteChannelRes struct {
+}
+
+type TestChannelReq struct {
+	g.Meta `path:"/alert/channel/:id/test" tags:"Alert" method:"post" summary:"向通知渠道发送测试消息"`
+	Id     int `v:"required|min:1" in:"path" json:"id" dc:"渠道ID"`
+}
+type TestChannelRes struct {
+}
+
+type RulesReq struct {
+	g.Meta `path:"/alert/rule" tags:"Alert" method:"get" summary:"告警规则列表"`
+}
+type RulesRes struct {
+	List []*entity.AlertRule `json:"list" dc:"告警规则"`
+}
+
+type SaveRuleReq struct {
+	g.Meta         `path:"/alert/rule" tags:"Alert" method:"post" summary:"新增或修改告警规则"`
+	Id             int     `json:"id"             dc:"规则ID，为空时新增"`
+	Name           string  `json:"name"           v:"required" dc:"规则名称"`
+	Type           string  `json:"type"           v:"required|in:stopped,restart_loop,unhealthy,rss,port" dc:"规则类型[stopped:意外退出, restart_loop:频繁重启, unhealthy:健康检查失败, rss:内存超限, port:端口不通]"`
+	JpidId         int     `json:"jpidId"         dc:"项目ID，为空表示全部项目"`
+	Threshold      float64 `json:"threshold"      dc:"阈值[restart_loop:启动次数，默认 3, rss:MB]"`
+	Duration       int     `json:"duration"       dc:"持续时间秒[restart_loop:统计窗口，默认 600, unhealthy:连续失败时间]"`
+	Channels       []int   `json:"channels"       dc:"通知渠道ID，为空发送到全部启用的渠道"`
+	RepeatInterval int     `json:"repeatInterval" dc:"未恢复时重复通知的间隔秒，0 只通知一次"`
+	Enabled        bool    `json:"enabled"        d:"true" dc:"是否启用"`
+}
+type SaveRuleRes struct {
+	Id int `json:"id" dc:"规则ID"`
+}
+
+type DeleteRuleReq struct {
+	g.Meta `path:"/alert/rule/:id" tags:"Alert" method:"delete" summary:"删除告警规则及其事件"`
+	Id     int `v:"required|min:1" in:"path" json:"id" dc:"规则ID"`
+}
+type DeleteRuleRes struct {
+}
+
+type SilenceReq struct {
+	g.Meta  `path:"/alert/rule/:id/silence" tags:"Alert" method:"post" summary:"静默告警规则，静默期间只记录事件不发送通知"`
+	Id      int `v:"required|min:1" in:"path" json:"id" dc:"规则ID"`
+	Minutes int `json:"minutes" v:"min:0" dc:"静默分钟数，0 表示取消静默"`
+}
+type SilenceRes struct {
+	SilenceUntil *gtime.Time `json:"silenceUntil" dc:"静默截止时间"`
+}
+
+type EventsReq struct {
+	g.Meta `path:"/alert/event" tags:"Alert" method:"get" summary:"告警事件"`
+	Status string `json:"status" in:"query" v:"in:firing,resolved" dc:"状态[firing, resolved]，为空返回全部"`
+	Limit  int    `json:"limit"  in:"query" dc:"返回条数，默认 100"`
+}
+type EventsRes struct {
+	List []*entity.AlertEvent `json:"list" dc:"告警事件，按时间倒序"`
+}
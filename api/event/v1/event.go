@@ -0,0 +1,16 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+)
+
+type StreamReq struct {
+	g.Meta    `path:"/events" tags:"Event" method:"get" summary:"项目状态变化事件流（SSE），断线后用 Last-Event-ID 或 cursor 续传"`
+	Cursor    int64  `json:"cursor"    in:"query" dc:"从该事件ID之后开始推送，为空时使用 Last-Event-ID 请求头，都为空时只推送新事件"`
+	Types     string `json:"types"     in:"query" dc:"只推送的事件类型，多个逗号隔开，如 project.started,project.crashed"`
+	ProjectId int    `json:"projectId" in:"query" dc:"只推送该项目的事件"`
+}
+
+type StreamRes struct {
+	g.Meta `mime:"text/event-stream"`
+}
@@ -0,0 +1,21 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/internal/model/entity"
+)
+
+type ListReq struct {
+	g.Meta `path:"/jdk" tags:"JDK" method:"get" summary:"JDK 清单"`
+	Worker string `dc:"worker名称，为空时查询当前worker" in:"query"`
+}
+type ListRes struct {
+	List []*entity.Jdk `json:"list" dc:"JDK 清单"`
+}
+
+type ScanReq struct {
+	g.Meta `path:"/jdk/scan" tags:"JDK" method:"post" summary:"扫描本机安装目录和运行中的 java 进程，更新 JDK 清单"`
+}
+type ScanRes struct {
+	List []*entity.Jdk `json:"list" dc:"JDK 清单"`
+}
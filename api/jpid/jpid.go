@@ -21,4 +21,28 @@ type IJpidV1 interface {
 	Delete(ctx context.Context, req *v1.DeleteReq) (res *v1.DeleteRes, err error)
 	StartWithDocker(ctx context.Context, req *v1.StartWithDockerReq) (res *v1.StartWithDockerRes, err error)
 	UpdateAutostart(ctx context.Context, req *v1.UpdateAutostartReq) (res *v1.UpdateAutostartRes, err error)
+	Journal(ctx context.Context, req *v1.JournalReq) (res *v1.JournalRes, err error)
+	AutostartUnits(ctx context.Context, req *v1.AutostartUnitsReq) (res *v1.AutostartUnitsRes, err error)
+	SyncAutostart(ctx context.Context, req *v1.SyncAutostartReq) (res *v1.SyncAutostartRes, err error)
+	AdoptAutostart(ctx context.Context, req *v1.AdoptAutostartReq) (res *v1.AdoptAutostartRes, err error)
+	RegenerateAutostart(ctx context.Context, req *v1.RegenerateAutostartReq) (res *v1.RegenerateAutostartRes, err error)
+	Export(ctx context.Context, req *v1.ExportReq) (res *v1.ExportRes, err error)
+	Import(ctx context.Context, req *v1.ImportReq) (res *v1.ImportRes, err error)
+	UpdateLaunch(ctx context.Context, req *v1.UpdateLaunchReq) (res *v1.UpdateLaunchRes, err error)
+	UpdateJdk(ctx context.Context, req *v1.UpdateJdkReq) (res *v1.UpdateJdkRes, err error)
+	UploadArtifact(ctx context.Context, req *v1.UploadArtifactReq) (res *v1.UploadArtifactRes, err error)
+	Artifacts(ctx context.Context, req *v1.ArtifactsReq) (res *v1.ArtifactsRes, err error)
+	Deploy(ctx context.Context, req *v1.DeployReq) (res *v1.DeployRes, err error)
+	Rollback(ctx context.Context, req *v1.RollbackReq) (res *v1.RollbackRes, err error)
+	Deployments(ctx context.Context, req *v1.DeploymentsReq) (res *v1.DeploymentsRes, err error)
+	Runs(ctx context.Context, req *v1.RunsReq) (res *v1.RunsRes, err error)
+	Jar(ctx context.Context, req *v1.JarReq) (res *v1.JarRes, err error)
+	Builds(ctx context.Context, req *v1.BuildsReq) (res *v1.BuildsRes, err error)
+	Actuator(ctx context.Context, req *v1.ActuatorReq) (res *v1.ActuatorRes, err error)
+	UpdateActuator(ctx context.Context, req *v1.UpdateActuatorReq) (res *v1.UpdateActuatorRes, err error)
+	Loggers(ctx context.Context, req *v1.LoggersReq) (res *v1.LoggersRes, err error)
+	SetLogger(ctx context.Context, req *v1.SetLoggerReq) (res *v1.SetLoggerRes, err error)
+	Detail(ctx context.Context, req *v1.DetailReq) (res *v1.DetailRes, err error)
+	UpdateJolokia(ctx context.Context, req *v1.UpdateJolokiaReq) (res *v1.UpdateJolokiaRes, err error)
+	Metrics(ctx context.Context, req *v1.MetricsReq) (res *v1.MetricsRes, err error)
 }
@@ -0,0 +1,46 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/internal/model"
+)
+
+type ActuatorReq struct {
+	g.Meta `path:"/jpid/:id/actuator" tags:"Actuator" method:"get" summary:"项目 actuator 的健康状态、应用信息和关键指标"`
+	Id     int `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+}
+
+type ActuatorRes struct {
+	*model.ActuatorOverview
+}
+
+type UpdateActuatorReq struct {
+	g.Meta       `path:"/jpid/:id/actuator" tags:"Actuator" method:"post" summary:"设置项目的 actuator 地址和停止方式"`
+	Id           int    `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+	Actuator     string `json:"actuator"     dc:"actuator 基础地址，如 http://127.0.0.1:8081/actuator，为空时按项目端口自动探测"`
+	StopStrategy string `json:"stopStrategy" v:"in:signal,actuator" dc:"停止方式[signal:信号(默认), actuator:/actuator/shutdown]"`
+}
+
+type UpdateActuatorRes struct {
+	Url string `json:"url" dc:"生效的 actuator 地址，暂时无法访问时为空"`
+}
+
+type LoggersReq struct {
+	g.Meta `path:"/jpid/:id/loggers" tags:"Actuator" method:"get" summary:"项目的 logger 级别"`
+	Id     int `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+}
+
+type LoggersRes struct {
+	*model.ActuatorLoggers
+}
+
+type SetLoggerReq struct {
+	g.Meta `path:"/jpid/:id/loggers" tags:"Actuator" method:"post" summary:"修改 logger 级别"`
+	Id     int    `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+	Name   string `v:"required" json:"name" dc:"logger 名称，如 ROOT、com.example"`
+	Level  string `json:"level" dc:"日志级别[OFF, ERROR, WARN, INFO, DEBUG, TRACE]，为空时恢复继承上级"`
+}
+
+type SetLoggerRes struct {
+	*model.ActuatorLogger
+}
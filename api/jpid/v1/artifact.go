@@ -0,0 +1,60 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"omniscient/internal/model/entity"
+)
+
+type UploadArtifactReq struct {
+	g.Meta   `path:"/jpid/:id/artifacts" tags:"Deploy" method:"post" mime:"multipart/form-data" summary:"上传 jar 制品"`
+	Id       int               `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+	File     *ghttp.UploadFile `v:"required" json:"file" type:"file" dc:"jar 文件"`
+	Note     string            `json:"note"     dc:"备注，如版本号、提交号"`
+	Uploader string            `json:"uploader" dc:"上传人，为空时记录客户端IP"`
+	Deploy   bool              `json:"deploy"   dc:"上传后立即部署"`
+}
+
+type UploadArtifactRes struct {
+	Artifact   *entity.Artifact   `json:"artifact"   dc:"制品"`
+	Deployment *entity.Deployment `json:"deployment" dc:"部署记录，deploy 为 true 时返回"`
+}
+
+type ArtifactsReq struct {
+	g.Meta `path:"/jpid/:id/artifacts" tags:"Deploy" method:"get" summary:"项目的制品列表"`
+	Id     int `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+}
+
+type ArtifactsRes struct {
+	List []*entity.Artifact `json:"list" dc:"制品列表，按版本倒序"`
+}
+
+type DeployReq struct {
+	g.Meta     `path:"/jpid/:id/deploy" tags:"Deploy" method:"post" summary:"部署制品：停止项目、替换 jar、启动并等待健康检查，失败时自动回滚"`
+	Id         int    `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+	ArtifactId int    `v:"required|min:1" json:"artifactId" dc:"制品ID"`
+	Operator   string `json:"operator" dc:"操作人，为空时记录客户端IP"`
+}
+
+type DeployRes struct {
+	*entity.Deployment
+}
+
+type RollbackReq struct {
+	g.Meta   `path:"/jpid/:id/rollback" tags:"Deploy" method:"post" summary:"回滚到当前制品部署前的制品"`
+	Id       int    `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+	Operator string `json:"operator" dc:"操作人，为空时记录客户端IP"`
+}
+
+type RollbackRes struct {
+	*entity.Deployment
+}
+
+type DeploymentsReq struct {
+	g.Meta `path:"/jpid/:id/deployments" tags:"Deploy" method:"get" summary:"项目的部署记录"`
+	Id     int `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+}
+
+type DeploymentsRes struct {
+	List []*entity.Deployment `json:"list" dc:"部署记录，按时间倒序"`
+}
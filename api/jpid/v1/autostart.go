@@ -0,0 +1,41 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/internal/model"
+)
+
+type AutostartUnitsReq struct {
+	g.Meta `path:"/jpid/units" tags:"autostart" method:"get" summary:"本机的 autostart-* 自启服务及其关联的项目，包括手动创建的服务"`
+}
+
+type AutostartUnitsRes struct {
+	List []*model.AutostartUnit `json:"list" dc:"自启服务，按服务名排序"`
+}
+
+type SyncAutostartReq struct {
+	g.Meta `path:"/jpid/units/sync" tags:"autostart" method:"post" summary:"立即同步自启服务与项目的关联和状态"`
+}
+
+type SyncAutostartRes struct {
+	*model.AutostartSyncResult
+}
+
+type AdoptAutostartReq struct {
+	g.Meta `path:"/jpid/:id/autostart/adopt" tags:"autostart" method:"post" summary:"认领已有的自启服务，关联到项目"`
+	Id     int    `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+	Name   string `v:"required#请选择自启服务" json:"name" dc:"服务名，可以带 autostart- 前缀"`
+}
+
+type AdoptAutostartRes struct {
+	State *model.AutostartState `json:"state" dc:"服务状态"`
+}
+
+type RegenerateAutostartReq struct {
+	g.Meta `path:"/jpid/:id/autostart/regenerate" tags:"autostart" method:"post" summary:"按项目当前的启动配置重新生成自启服务，没有服务时创建并启用"`
+	Id     int `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+}
+
+type RegenerateAutostartRes struct {
+	State *model.AutostartState `json:"state" dc:"服务状态"`
+}
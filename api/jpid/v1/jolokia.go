@@ -0,0 +1,43 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/internal/model"
+	"omniscient/internal/model/entity"
+)
+
+type DetailReq struct {
+	g.Meta `path:"/jpid/:id/detail" tags:"Java" method:"get" summary:"项目详情，包含运行中的 JDK 和最近一次采集的 JMX 指标"`
+	Id     int `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+}
+
+type DetailRes struct {
+	*entity.Jpid
+	Java    *model.JavaRuntime    `json:"java"    dc:"运行中项目实际使用的 JDK"`
+	Queries []*model.JolokiaQuery `json:"queries" dc:"生效的 MBean 查询"`
+	Jmx     []*entity.JpidMetric  `json:"jmx"     dc:"最近一次采集的 JMX 指标"`
+}
+
+type UpdateJolokiaReq struct {
+	g.Meta  `path:"/jpid/:id/jolokia" tags:"JMX" method:"post" summary:"设置项目的 Jolokia 地址和 MBean 查询，项目运行中时立即采集一次"`
+	Id      int                   `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+	Jolokia string                `json:"jolokia" dc:"Jolokia 地址，如 http://127.0.0.1:8778/jolokia，为空时不采集"`
+	Queries []*model.JolokiaQuery `json:"queries" dc:"MBean 查询，为空时使用 jolokia.queries"`
+}
+
+type UpdateJolokiaRes struct {
+	Metrics  []*entity.JpidMetric `json:"metrics"  dc:"本次采集的指标"`
+	Failures map[string]string    `json:"failures" dc:"读取失败的查询（指标名 -> 原因）"`
+	Error    string               `json:"error"    dc:"采集失败的原因，项目未运行或 Jolokia 不可访问时返回"`
+}
+
+type MetricsReq struct {
+	g.Meta `path:"/jpid/:id/metrics" tags:"JMX" method:"get" summary:"项目 JMX 指标的历史值"`
+	Id     int    `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+	Name   string `json:"name"  in:"query" dc:"指标名，为空时返回全部指标"`
+	Limit  int    `json:"limit" in:"query" dc:"返回条数，默认 100"`
+}
+
+type MetricsRes struct {
+	List []*entity.JpidMetric `json:"list" dc:"指标，按采集时间倒序"`
+}
@@ -1,7 +1,10 @@
 package v1
 
 import (
+	"autostart/pkg/autostart"
 	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/net/ghttp"
+	"omniscient/internal/model"
 	"omniscient/internal/model/entity"
 )
 
@@ -10,7 +13,14 @@ type JpidReq struct {
 	Worker string `dc:"worker名称，为空时查询当前worker的项目" v:"" in:"query"`
 }
 type JpidRes struct {
-	List []*entity.Jpid `json:"list" dc:"java 项目列表"`
+	List []*JpidItem `json:"list" dc:"java 项目列表"`
+}
+
+// JpidItem 项目信息、运行中进程实际使用的 JDK 及自启服务状态
+type JpidItem struct {
+	*entity.Jpid
+	Java           *model.JavaRuntime    `json:"java"           dc:"运行中进程实际使用的 JDK，未运行时为空"`
+	AutostartState *model.AutostartState `json:"autostartState" dc:"关联的自启服务最近一次同步的状态，没有关联时为空"`
 }
 
 type OnlineReq struct {
@@ -102,3 +112,66 @@ type UpdateAutostartReq struct {
 }
 
 type UpdateAutostartRes struct{}
+
+type JournalReq struct {
+	g.Meta   `path:"/jpid/:id/journal" tags:"autostart" method:"get" summary:"项目自启服务的 journal 日志"`
+	Id       int    `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+	Lines    int    `json:"lines" in:"query" dc:"返回最新的多少条，默认 50，最多 1000"`
+	Since    string `json:"since" in:"query" dc:"开始时间，如 2006-01-02 15:04:05、today、1h（一小时前）"`
+	Until    string `json:"until" in:"query" dc:"结束时间，格式同 since"`
+	Priority string `json:"priority" in:"query" dc:"日志级别[emerg, alert, crit, err, warning, notice, info, debug]，返回该级别及更重要的日志"`
+	Grep     string `json:"grep" in:"query" dc:"消息匹配的正则表达式，没有大写字母时不区分大小写；没有开始时间时只查找最近 24 小时"`
+}
+
+type JournalRes struct {
+	Unit string                `json:"unit" dc:"systemd 服务名"`
+	List []*autostart.LogEntry `json:"list" dc:"日志，按时间正序"`
+}
+
+type ExportReq struct {
+	g.Meta `path:"/jpid/export" tags:"Java" method:"get" summary:"导出项目定义"`
+	Worker string `dc:"worker名称，为空时导出当前worker的项目" in:"query"`
+	Format string `dc:"导出格式[yaml, json]，默认 yaml" v:"in:yaml,json" in:"query"`
+}
+
+type ExportRes struct {
+	g.Meta `mime:"application/octet-stream"`
+}
+
+type ImportReq struct {
+	g.Meta   `path:"/jpid/import" tags:"Java" method:"post" summary:"导入项目定义"`
+	Worker   string            `json:"worker"   dc:"目标worker，为空时导入到当前worker"`
+	Strategy string            `json:"strategy" dc:"同名冲突策略[skip, overwrite, rename]，默认 skip" v:"in:skip,overwrite,rename"`
+	Preview  bool              `json:"preview"  dc:"只预览导入结果，不写入"`
+	Content  string            `json:"content"  dc:"导出包内容（yaml 或 json），与 file 二选一"`
+	File     *ghttp.UploadFile `json:"file"     dc:"导出包文件" type:"file"`
+}
+
+type ImportRes struct {
+	*model.ImportResult
+}
+
+type UpdateLaunchReq struct {
+	g.Meta  `path:"/jpid/launch/:id" tags:"Java" method:"post" summary:"更新结构化启动配置（环境变量、JVM参数、程序参数、JDK）"`
+	Id      int               `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+	Env     map[string]string `json:"env"     dc:"环境变量"`
+	JvmOpts *model.JvmOptions `json:"jvmOpts" dc:"JVM参数"`
+	Args    []string          `json:"args"    dc:"程序参数"`
+	JdkHome string            `json:"jdkHome" dc:"JDK目录，为空使用PATH中的java"`
+	JdkId   int               `json:"jdkId"   dc:"指定的JDK（/jdk 清单中的编号），不为 0 时覆盖 jdkHome"`
+	Run     string            `json:"run"     dc:"原生启动命令，不为空时覆盖组合命令；为空时使用组合命令"`
+}
+
+type UpdateLaunchRes struct {
+	Command string `json:"command" dc:"生效的启动命令"`
+}
+
+type UpdateJdkReq struct {
+	g.Meta `path:"/jpid/jdk/:id" tags:"Java" method:"post" summary:"指定项目使用的 JDK，下次启动时生效"`
+	Id     int `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+	JdkId  int `v:"min:0" json:"jdkId" dc:"JDK编号（/jdk 清单中的编号），0 表示使用PATH中的java"`
+}
+
+type UpdateJdkRes struct {
+	Command string `json:"command" dc:"生效的启动命令"`
+}
@@ -0,0 +1,46 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/internal/model"
+	"omniscient/internal/model/entity"
+)
+
+type RunsReq struct {
+	g.Meta `path:"/jpid/:id/runs" tags:"Java" method:"get" summary:"项目的运行记录（每次启动的 pid、jar 构建版本、提交号和意外退出原因）"`
+	Id     int `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+	Limit  int `json:"limit" in:"query" dc:"返回条数，默认 50"`
+}
+
+type RunsRes struct {
+	List []*entity.JpidRun `json:"list" dc:"运行记录，按启动时间倒序"`
+}
+
+type JarReq struct {
+	g.Meta `path:"/jpid/:id/jar" tags:"Java" method:"get" summary:"解析项目 jar 的 MANIFEST、build-info、git 信息"`
+	Id     int `v:"required|min:1" in:"path" json:"id" dc:"项目ID"`
+}
+
+type JarRes struct {
+	*model.JarInfo
+}
+
+type BuildsReq struct {
+	g.Meta `path:"/jpid/builds" tags:"Java" method:"get" summary:"各服务器上项目运行的构建版本"`
+	Name   string `json:"name" in:"query" dc:"项目名，为空时返回全部项目"`
+}
+
+type BuildsRes struct {
+	List []*BuildItem `json:"list" dc:"项目构建版本"`
+}
+
+// BuildItem 项目在某台服务器上运行的构建版本
+type BuildItem struct {
+	Id           int    `json:"id"           dc:"项目ID"`
+	Name         string `json:"name"         dc:"项目名"`
+	Worker       string `json:"worker"       dc:"服务器"`
+	Status       int    `json:"status"       dc:"状态[1:启动，0:停止]"`
+	BuildVersion string `json:"buildVersion" dc:"构建版本"`
+	BuildCommit  string `json:"buildCommit"  dc:"git 提交号"`
+	JarChecksum  string `json:"jarChecksum"  dc:"jar SHA-256"`
+}
@@ -0,0 +1,24 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/internal/model/entity"
+)
+
+type ListReq struct {
+	g.Meta `path:"/notification" tags:"Notification" method:"get" summary:"站内通知列表，如项目意外退出"`
+	Unread bool `json:"unread" in:"query" dc:"只返回未读通知"`
+	Limit  int  `json:"limit"  in:"query" dc:"返回条数，默认 50"`
+}
+type ListRes struct {
+	Unread int                    `json:"unread" dc:"未读通知数"`
+	List   []*entity.Notification `json:"list"   dc:"通知，按时间倒序"`
+}
+
+type ReadReq struct {
+	g.Meta `path:"/notification/read" tags:"Notification" method:"post" summary:"标记通知为已读"`
+	Ids    []int `json:"ids" dc:"通知ID，为空时标记全部"`
+}
+type ReadRes struct {
+	Updated int64 `json:"updated" dc:"标记的通知数"`
+}
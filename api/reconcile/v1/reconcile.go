@@ -0,0 +1,20 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/internal/model"
+)
+
+type PlanReq struct {
+	g.Meta `path:"/reconcile/plan" tags:"Reconcile" method:"get" summary:"对账计划（dry-run），对比项目清单与实际状态"`
+}
+type PlanRes struct {
+	*model.ReconcilePlan
+}
+
+type ApplyReq struct {
+	g.Meta `path:"/reconcile/apply" tags:"Reconcile" method:"post" summary:"按项目清单执行对账"`
+}
+type ApplyRes struct {
+	*model.ReconcilePlan
+}
@@ -0,0 +1,36 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+)
+
+type ListReq struct {
+	g.Meta `path:"/secret" tags:"Secret" method:"get" summary:"密钥列表（不返回明文）"`
+}
+type ListRes []*SecretItem
+
+// SecretItem 密钥信息，不含密文和明文
+type SecretItem struct {
+	Name        string      `json:"name"        dc:"密钥名称"`
+	KeyId       string      `json:"keyId"       dc:"加密使用的主密钥标识"`
+	Description string      `json:"description" dc:"描述"`
+	CreatedAt   *gtime.Time `json:"createdAt"   dc:"创建时间"`
+	UpdatedAt   *gtime.Time `json:"updatedAt"   dc:"更新时间"`
+}
+
+type SetReq struct {
+	g.Meta      `path:"/secret" tags:"Secret" method:"post" summary:"新增或更新密钥，环境变量中使用 ${secret:名称} 引用"`
+	Name        string `json:"name" v:"required" dc:"密钥名称"`
+	Value       string `json:"value" v:"required" dc:"密钥值"`
+	Description string `json:"description" dc:"描述"`
+}
+type SetRes struct {
+}
+
+type DeleteReq struct {
+	g.Meta `path:"/secret/:name" tags:"Secret" method:"delete" summary:"删除密钥，仍被项目引用时拒绝删除"`
+	Name   string `json:"name" v:"required" dc:"密钥名称"`
+}
+type DeleteRes struct {
+}
@@ -0,0 +1,51 @@
+package v1
+
+import (
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/internal/model/entity"
+)
+
+type ListReq struct {
+	g.Meta `path:"/webhook" tags:"Webhook" method:"get" summary:"webhook 订阅列表"`
+}
+type ListRes struct {
+	List []*entity.Webhook `json:"list" dc:"webhook 订阅，地址中的密钥值和签名密钥明文已隐藏"`
+}
+
+type SaveReq struct {
+	g.Meta  `path:"/webhook" tags:"Webhook" method:"post" summary:"新增或修改 webhook 订阅"`
+	Id      int      `json:"id"      dc:"订阅ID，为空时新增"`
+	Name    string   `json:"name"    v:"required" dc:"订阅名称"`
+	Url     string   `json:"url"     v:"required" dc:"推送地址，支持 ${secret:NAME}"`
+	Secret  string   `json:"secret"  dc:"签名密钥，请求头 X-Omniscient-Signature 为 sha256=<hex(HMAC-SHA256(secret, body))>，支持 ${secret:NAME}，修改时传 ****** 保持不变"`
+	Events  []string `json:"events"  dc:"订阅的事件，如 project.started、project.deployed，支持 project.* 前缀，为空表示全部"`
+	Enabled bool     `json:"enabled" d:"true" dc:"是否启用"`
+}
+type SaveRes struct {
+	Id int `json:"id" dc:"订阅ID"`
+}
+
+type DeleteReq struct {
+	g.Meta `path:"/webhook/:id" tags:"Webhook" method:"delete" summary:"删除 webhook 订阅及其投递记录"`
+	Id     int `v:"required|min:1" in:"path" json:"id" dc:"订阅ID"`
+}
+type DeleteRes struct {
+}
+
+type DeliveriesReq struct {
+	g.Meta    `path:"/webhook/delivery" tags:"Webhook" method:"get" summary:"webhook 投递记录"`
+	WebhookId int    `json:"webhookId" in:"query" dc:"订阅ID，为空返回全部"`
+	Status    string `json:"status"    in:"query" v:"in:pending,success,failed" dc:"状态[pending, success, failed]，为空返回全部"`
+	Limit     int    `json:"limit"     in:"query" dc:"返回条数，默认 100"`
+}
+type DeliveriesRes struct {
+	List []*entity.WebhookDelivery `json:"list" dc:"投递记录，按时间倒序"`
+}
+
+type ReplayReq struct {
+	g.Meta `path:"/webhook/delivery/:id/replay" tags:"Webhook" method:"post" summary:"以原请求体重新投递"`
+	Id     int `v:"required|min:1" in:"path" json:"id" dc:"投递记录ID"`
+}
+type ReplayRes struct {
+	Id int `json:"id" dc:"新的投递记录ID"`
+}
@@ -13,13 +13,20 @@ import (
 	"syscall"
 	"time"
 
+	"omniscient/internal/controller/jdk"
 	"omniscient/internal/controller/jpid"
+	"omniscient/internal/controller/notification"
+	"omniscient/internal/controller/reconcile"
+	"omniscient/internal/controller/secret"
+	"omniscient/internal/controller/webhook"
 
 	"github.com/gogf/gf/v2/frame/g"
 	"github.com/gogf/gf/v2/net/ghttp"
 	"github.com/gogf/gf/v2/os/gcmd"
 	"github.com/gogf/gf/v2/os/gfile"
 	"github.com/gogf/gf/v2/text/gstr"
+	"omniscient/internal/controller/alert"
+	"omniscient/internal/controller/event"
 	"omniscient/internal/controller/hello"
 )
 
@@ -58,10 +65,116 @@ var (
 			return handleShellCommand(ctx)
 		},
 	}
+
+	// db 命令 - 数据库备份、恢复、迁移
+	Database = gcmd.Command{
+		Name:  "db",
+		Usage: "db [sub-command]",
+		Brief: "database backup, restore and migration",
+		Func: func(ctx context.Context, parser *gcmd.Parser) (err error) {
+			return handleDatabaseCommand(ctx)
+		},
+	}
+
+	// projects 命令 - 项目定义导入导出
+	Projects = gcmd.Command{
+		Name:  "projects",
+		Usage: "projects [sub-command]",
+		Brief: "export and import project definitions",
+		Func: func(ctx context.Context, parser *gcmd.Parser) (err error) {
+			return handleProjectsCommand(ctx)
+		},
+	}
+
+	// secrets 命令 - 密钥管理
+	Secrets = gcmd.Command{
+		Name:  "secrets",
+		Usage: "secrets [sub-command]",
+		Brief: "manage encrypted secrets and rotate the master key",
+		Func: func(ctx context.Context, parser *gcmd.Parser) (err error) {
+			return handleSecretsCommand(ctx)
+		},
+	}
 )
 
 // 运行服务器
 func runServer(ctx context.Context) error {
+	// 确定使用的配置文件
+	resolveConfigFile(ctx)
+
+	// 初始化数据库 - 添加这部分
+	if err := common.InitDatabase(ctx); err != nil {
+		g.Log().Error(ctx, "数据库初始化失败:", err)
+		return err
+	}
+
+	// 加载主密钥，日志输出开始脱敏
+	if err := common.InitSecrets(ctx); err != nil {
+		g.Log().Error(ctx, "密钥初始化失败:", err)
+		return err
+	}
+
+	// 定时重新加载密钥，CLI 或其他服务器新增的密钥也会被脱敏
+	if err := common.StartSecretReload(ctx); err != nil {
+		g.Log().Warning(ctx, "密钥定时加载启动失败:", err)
+	}
+
+	// 定时自动备份数据库
+	if err := common.StartDatabaseBackup(ctx); err != nil {
+		g.Log().Warning(ctx, "定时备份启动失败:", err)
+	}
+
+	// 出站 webhook，先于对账等会产生事件的任务启动
+	if err := common.StartWebhook(ctx); err != nil {
+		g.Log().Warning(ctx, "webhook 投递启动失败:", err)
+	}
+
+	// 声明式项目清单对账
+	if err := common.StartReconcile(ctx); err != nil {
+		g.Log().Warning(ctx, "项目清单对账启动失败:", err)
+	}
+
+	// JMX 指标采集
+	if err := common.StartJolokia(ctx); err != nil {
+		g.Log().Warning(ctx, "JMX 指标采集启动失败:", err)
+	}
+
+	// 自启服务同步
+	if err := common.StartAutostart(ctx); err != nil {
+		g.Log().Warning(ctx, "自启服务同步启动失败:", err)
+	}
+
+	// 告警
+	if err := common.StartAlert(ctx); err != nil {
+		g.Log().Warning(ctx, "告警检查启动失败:", err)
+	}
+
+	// 打印欢迎信息
+	common.PrintWelcomeInfo(ctx)
+
+	s := g.Server()
+	s.Group("/", func(group *ghttp.RouterGroup) {
+		group.Middleware(ghttp.MiddlewareHandlerResponse)
+		group.Bind(
+			hello.NewV1(),
+			jpid.NewV1(),
+			reconcile.NewV1(),
+			secret.NewV1(),
+			jdk.NewV1(),
+			notification.NewV1(),
+			alert.NewV1(),
+			event.NewV1(),
+			webhook.NewV1(),
+		)
+	})
+	// 绑定静态资源
+	s.SetServerRoot("resource/public")
+	s.Run()
+	return nil
+}
+
+// 确定使用的配置文件
+func resolveConfigFile(ctx context.Context) {
 	// 检查当前目录下是否存在 config.prod.yaml
 	workDir, _ := os.Getwd()
 	configPath := filepath.Join(workDir, DefaultConfigFile)
@@ -93,28 +206,6 @@ func runServer(ctx context.Context) error {
 	} else {
 		g.Log().Info(ctx, "Using built-in config")
 	}
-
-	// 初始化数据库 - 添加这部分
-	if err := common.InitDatabase(ctx); err != nil {
-		g.Log().Error(ctx, "数据库初始化失败:", err)
-		return err
-	}
-
-	// 打印欢迎信息
-	common.PrintWelcomeInfo(ctx)
-
-	s := g.Server()
-	s.Group("/", func(group *ghttp.RouterGroup) {
-		group.Middleware(ghttp.MiddlewareHandlerResponse)
-		group.Bind(
-			hello.NewV1(),
-			jpid.NewV1(),
-		)
-	})
-	// 绑定静态资源
-	s.SetServerRoot("resource/public")
-	s.Run()
-	return nil
 }
 
 // 处理 shell 命令
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gogf/gf/v2/os/gcmd"
+
+	"omniscient/internal/service"
+	"omniscient/internal/util/common"
+)
+
+// 处理 db 命令
+func handleDatabaseCommand(ctx context.Context) error {
+	// 找到 "db" 命令的位置，获取后面的参数
+	var args []string
+	for i, arg := range os.Args {
+		if arg == "db" && i+1 < len(os.Args) {
+			args = os.Args[i+1:]
+			break
+		}
+	}
+
+	if len(args) == 0 {
+		common.PrintDatabaseHelp()
+		return nil
+	}
+
+	parser, err := gcmd.ParseArgs(args, map[string]bool{
+		"out":    true,
+		"format": true,
+		"from":   true,
+		"to":     true,
+		"yes,y":  false,
+	})
+	if err != nil {
+		return err
+	}
+
+	// 与 run 命令使用相同的配置文件
+	resolveConfigFile(ctx)
+
+	switch args[0] {
+	case "backup":
+		return backupDatabase(ctx, parser)
+	case "restore":
+		return restoreDatabase(ctx, parser)
+	case "copy":
+		return copyDatabase(ctx, parser)
+	default:
+		fmt.Printf("Unknown command: %s\n", args[0])
+		common.PrintDatabaseHelp()
+		return nil
+	}
+}
+
+// 备份数据库
+func backupDatabase(ctx context.Context, parser *gcmd.Parser) error {
+	dbManager := service.NewDatabaseManager()
+	if err := dbManager.Initialize(ctx); err != nil {
+		return err
+	}
+
+	backupPath, err := dbManager.Backup(ctx, parser.GetOpt("out").String(), parser.GetOpt("format").String())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Database %s backed up to: %s\n", dbManager.GetDatabaseType(), backupPath)
+	return nil
+}
+
+// 从备份文件恢复数据库
+func restoreDatabase(ctx context.Context, parser *gcmd.Parser) error {
+	backupPath := parser.GetArg(1).String()
+	if backupPath == "" {
+		return fmt.Errorf("missing backup file\nUsage: omniscient db restore <file> [--yes]")
+	}
+
+	dbManager := service.NewDatabaseManager()
+	if err := dbManager.Initialize(ctx); err != nil {
+		return err
+	}
+	if err := dbManager.CreateTables(ctx); err != nil {
+		return err
+	}
+
+	if parser.GetOpt("yes") == nil && !confirm(fmt.Sprintf(
+		"This will replace all data in the %s database with %s. Continue?", dbManager.GetDatabaseType(), backupPath)) {
+		fmt.Println("Restore cancelled.")
+		return nil
+	}
+
+	results, err := dbManager.Restore(ctx, backupPath)
+	if err != nil {
+		return err
+	}
+	printCopyResults(results)
+	fmt.Printf("Database %s restored from: %s\n", dbManager.GetDatabaseType(), backupPath)
+	return nil
+}
+
+// 跨数据库迁移
+func copyDatabase(ctx context.Context, parser *gcmd.Parser) error {
+	from := parser.GetOpt("from").String()
+	to := parser.GetOpt("to").String()
+	if from == "" || to == "" {
+		return fmt.Errorf("missing --from or --to\nUsage: omniscient db copy --from=sqlite --to=mysql [--yes]")
+	}
+	if from == to {
+		return fmt.Errorf("--from and --to must be different database types")
+	}
+
+	source := service.NewDatabaseManager()
+	if err := source.InitializeAs(ctx, from, "copy_from"); err != nil {
+		return fmt.Errorf("failed to connect source database: %v", err)
+	}
+	target := service.NewDatabaseManager()
+	if err := target.InitializeAs(ctx, to, "copy_to"); err != nil {
+		return fmt.Errorf("failed to connect target database: %v", err)
+	}
+	if err := target.CreateTables(ctx); err != nil {
+		return err
+	}
+
+	if parser.GetOpt("yes") == nil && !confirm(fmt.Sprintf(
+		"This will replace all data in the %s database with data from %s. Continue?", to, from)) {
+		fmt.Println("Copy cancelled.")
+		return nil
+	}
+
+	results, err := target.CopyFrom(ctx, source)
+	if err != nil {
+		return err
+	}
+	printCopyResults(results)
+	fmt.Printf("Database copied from %s to %s\n", from, to)
+	return nil
+}
+
+// 打印每张表的迁移结果
+func printCopyResults(results []service.TableCopyResult) {
+	fmt.Printf("%-20s %-10s %-10s\n", "TABLE", "SOURCE", "TARGET")
+	for _, result := range results {
+		fmt.Printf("%-20s %-10d %-10d\n", result.Table, result.Source, result.Target)
+	}
+}
+
+// 确认操作
+func confirm(action string) bool {
+	fmt.Printf("%s (y/N): ", action)
+
+	var response string
+	_, _ = fmt.Scanln(&response)
+
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcmd"
+	"github.com/gogf/gf/v2/os/gfile"
+
+	"omniscient/internal/model"
+	"omniscient/internal/service"
+	"omniscient/internal/util/common"
+)
+
+// 处理 projects 命令
+func handleProjectsCommand(ctx context.Context) error {
+	// 找到 "projects" 命令的位置，获取后面的参数
+	var args []string
+	for i, arg := range os.Args {
+		if arg == "projects" && i+1 < len(os.Args) {
+			args = os.Args[i+1:]
+			break
+		}
+	}
+
+	if len(args) == 0 {
+		common.PrintProjectsHelp()
+		return nil
+	}
+
+	parser, err := gcmd.ParseArgs(args, map[string]bool{
+		"worker":   true,
+		"format":   true,
+		"out":      true,
+		"strategy": true,
+		"preview":  false,
+		"yes,y":    false,
+	})
+	if err != nil {
+		return err
+	}
+
+	// 导出到标准输出时关闭日志输出，保证内容可以直接重定向到文件
+	if args[0] == "export" && parser.GetOpt("out") == nil {
+		g.Log().SetStdoutPrint(false)
+	}
+
+	// 与 run 命令使用相同的配置文件
+	resolveConfigFile(ctx)
+	if err = common.InitDatabase(ctx); err != nil {
+		return err
+	}
+	if err = common.InitSecrets(ctx); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "export":
+		return exportProjects(ctx, parser)
+	case "import":
+		return importProjects(ctx, parser)
+	default:
+		fmt.Printf("Unknown command: %s\n", args[0])
+		common.PrintProjectsHelp()
+		return nil
+	}
+}
+
+// 导出项目定义
+func exportProjects(ctx context.Context, parser *gcmd.Parser) error {
+	bundle, err := service.Jpid().Export(ctx, parser.GetOpt("worker").String())
+	if err != nil {
+		return err
+	}
+	content, err := service.EncodeBundle(bundle, parser.GetOpt("format").String())
+	if err != nil {
+		return err
+	}
+
+	out := parser.GetOpt("out").String()
+	if out == "" {
+		fmt.Print(string(content))
+		return nil
+	}
+	if err = gfile.PutBytes(out, content); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d projects of %s to: %s\n", len(bundle.Projects), bundle.Worker, out)
+	return nil
+}
+
+// 导入项目定义，先预览再确认
+func importProjects(ctx context.Context, parser *gcmd.Parser) error {
+	file := parser.GetArg(1).String()
+	if file == "" {
+		return fmt.Errorf("missing bundle file\nUsage: omniscient projects import <file> [--worker=<name>] [--strategy=skip|overwrite|rename] [--preview] [--yes]")
+	}
+	if !gfile.Exists(file) {
+		return fmt.Errorf("bundle file not found: %s", file)
+	}
+
+	bundle, err := service.DecodeBundle(gfile.GetBytes(file))
+	if err != nil {
+		return err
+	}
+	worker := parser.GetOpt("worker").String()
+	strategy := parser.GetOpt("strategy").String()
+
+	plan, err := service.Jpid().Import(ctx, bundle, worker, strategy, true)
+	if err != nil {
+		return err
+	}
+	printImportResult(plan)
+	if parser.GetOpt("preview") != nil {
+		return nil
+	}
+	if plan.Created+plan.Updated == 0 {
+		fmt.Println("Nothing to import.")
+		return nil
+	}
+
+	if parser.GetOpt("yes") == nil && !confirm(fmt.Sprintf("Import %d projects into %s?", plan.Created+plan.Updated, plan.Worker)) {
+		fmt.Println("Import cancelled.")
+		return nil
+	}
+
+	result, err := service.Jpid().Import(ctx, bundle, worker, strategy, false)
+	if err != nil {
+		return err
+	}
+	for _, item := range result.Items {
+		if item.Message != "" && item.Action != model.ImportSkip {
+			fmt.Printf("%s: %s\n", item.TargetName, item.Message)
+		}
+	}
+	fmt.Printf("Imported into %s: %d created, %d overwritten, %d skipped\n",
+		result.Worker, result.Created, result.Updated, result.Skipped)
+	return nil
+}
+
+// 打印导入计划
+func printImportResult(result *model.ImportResult) {
+	fmt.Printf("Target worker: %s, strategy: %s\n", result.Worker, result.Strategy)
+	fmt.Printf("%-10s %-30s %-30s %s\n", "ACTION", "NAME", "TARGET", "MESSAGE")
+	for _, item := range result.Items {
+		fmt.Printf("%-10s %-30s %-30s %s\n", item.Action, item.Name, item.TargetName, item.Message)
+	}
+}
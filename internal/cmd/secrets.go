@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gogf/gf/v2/os/gcmd"
+
+	"omniscient/internal/service"
+	"omniscient/internal/util/common"
+)
+
+// 处理 secrets 命令
+func handleSecretsCommand(ctx context.Context) error {
+	// 找到 "secrets" 命令的位置，获取后面的参数
+	var args []string
+	for i, arg := range os.Args {
+		if arg == "secrets" && i+1 < len(os.Args) {
+			args = os.Args[i+1:]
+			break
+		}
+	}
+
+	if len(args) == 0 {
+		common.PrintSecretsHelp()
+		return nil
+	}
+
+	parser, err := gcmd.ParseArgs(args, map[string]bool{
+		"value":       true,
+		"description": true,
+		"new-key":     true,
+		"yes,y":       false,
+	})
+	if err != nil {
+		return err
+	}
+
+	// 与 run 命令使用相同的配置文件
+	resolveConfigFile(ctx)
+	if err = common.InitDatabase(ctx); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "list":
+		return listSecrets(ctx)
+	case "set":
+		return setSecret(ctx, parser)
+	case "delete":
+		name := parser.GetArg(1).String()
+		if name == "" {
+			return fmt.Errorf("missing secret name\nUsage: omniscient secrets delete <name>")
+		}
+		if err = service.Secret().Delete(ctx, name); err != nil {
+			return err
+		}
+		fmt.Printf("Secret %s deleted\n", name)
+		return nil
+	case "rotate-key":
+		return rotateMasterKey(ctx, parser)
+	default:
+		fmt.Printf("Unknown command: %s\n", args[0])
+		common.PrintSecretsHelp()
+		return nil
+	}
+}
+
+// 列出密钥
+func listSecrets(ctx context.Context) error {
+	list, err := service.Secret().List(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%-30s %-10s %-20s %s\n", "NAME", "KEY", "UPDATED", "DESCRIPTION")
+	for _, secret := range list {
+		fmt.Printf("%-30s %-10s %-20s %s\n", secret.Name, secret.KeyId, secret.UpdatedAt.String(), secret.Description)
+	}
+	return nil
+}
+
+// 新增或更新密钥，未指定 --value 时从标准输入读取，避免明文留在 shell 历史中
+func setSecret(ctx context.Context, parser *gcmd.Parser) error {
+	name := parser.GetArg(1).String()
+	if name == "" {
+		return fmt.Errorf("missing secret name\nUsage: omniscient secrets set <name> [--value=<value>] [--description=<text>]")
+	}
+
+	value := parser.GetOpt("value").String()
+	if value == "" {
+		fmt.Printf("Value for %s: ", name)
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("failed to read secret value: %v", err)
+		}
+		value = strings.TrimRight(line, "\r\n")
+	}
+
+	if err := service.Secret().Set(ctx, name, value, parser.GetOpt("description").String()); err != nil {
+		return err
+	}
+	fmt.Printf("Secret %s saved, reference it as ${secret:%s}\n", name, name)
+	return nil
+}
+
+// 轮换主密钥
+func rotateMasterKey(ctx context.Context, parser *gcmd.Parser) error {
+	if parser.GetOpt("yes") == nil && !confirm("This will re-encrypt all secrets with a new master key. Continue?") {
+		fmt.Println("Rotation cancelled.")
+		return nil
+	}
+
+	count, err := service.Secret().RotateKey(ctx, parser.GetOpt("new-key").String())
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Master key rotated, %d secrets re-encrypted\n", count)
+	if os.Getenv(service.MasterKeyEnv) != "" {
+		fmt.Printf("Remember to update %s to the new key before restarting the server\n", service.MasterKeyEnv)
+	}
+	return nil
+}
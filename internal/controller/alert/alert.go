@@ -0,0 +1,5 @@
+// =================================================================================
+// 告警规则和通知渠道
+// =================================================================================
+
+package alert
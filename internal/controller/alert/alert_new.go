@@ -0,0 +1,15 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package alert
+
+import (
+	"omniscient/api/alert"
+)
+
+type ControllerV1 struct{}
+
+func NewV1() alert.IAlertV1 {
+	return &ControllerV1{}
+}
@@ -0,0 +1,17 @@
+package alert
+
+import (
+	"context"
+
+	"omniscient/api/alert/v1"
+	"omniscient/internal/service"
+)
+
+// Channels 通知渠道列表
+func (c *ControllerV1) Channels(ctx context.Context, req *v1.ChannelsReq) (res *v1.ChannelsRes, err error) {
+	list, err := service.Alert().Channels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ChannelsRes{List: list}, nil
+}
@@ -0,0 +1,16 @@
+package alert
+
+import (
+	"context"
+
+	"omniscient/api/alert/v1"
+	"omniscient/internal/service"
+)
+
+// DeleteChannel 删除通知渠道
+func (c *ControllerV1) DeleteChannel(ctx context.Context, req *v1.DeleteChannelReq) (res *v1.DeleteChannelRes, err error) {
+	if err = service.Alert().DeleteChannel(ctx, req.Id); err != nil {
+		return nil, err
+	}
+	return &v1.DeleteChannelRes{}, nil
+}
@@ -0,0 +1,16 @@
+package alert
+
+import (
+	"context"
+
+	"omniscient/api/alert/v1"
+	"omniscient/internal/service"
+)
+
+// DeleteRule 删除告警规则
+func (c *ControllerV1) DeleteRule(ctx context.Context, req *v1.DeleteRuleReq) (res *v1.DeleteRuleRes, err error) {
+	if err = service.Alert().DeleteRule(ctx, req.Id); err != nil {
+		return nil, err
+	}
+	return &v1.DeleteRuleRes{}, nil
+}
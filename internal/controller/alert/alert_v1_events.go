@@ -0,0 +1,17 @@
+package alert
+
+import (
+	"context"
+
+	"omniscient/api/alert/v1"
+	"omniscient/internal/service"
+)
+
+// Events 告警事件
+func (c *ControllerV1) Events(ctx context.Context, req *v1.EventsReq) (res *v1.EventsRes, err error) {
+	list, err := service.Alert().Events(ctx, req.Status, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.EventsRes{List: list}, nil
+}
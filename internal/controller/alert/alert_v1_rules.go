@@ -0,0 +1,17 @@
+package alert
+
+import (
+	"context"
+
+	"omniscient/api/alert/v1"
+	"omniscient/internal/service"
+)
+
+// Rules 告警规则列表
+func (c *ControllerV1) Rules(ctx context.Context, req *v1.RulesReq) (res *v1.RulesRes, err error) {
+	list, err := service.Alert().Rules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.RulesRes{List: list}, nil
+}
@@ -0,0 +1,25 @@
+package alert
+
+import (
+	"context"
+
+	"omniscient/api/alert/v1"
+	"omniscient/internal/service"
+)
+
+// SaveChannel 新增或修改通知渠道
+func (c *ControllerV1) SaveChannel(ctx context.Context, req *v1.SaveChannelReq) (res *v1.SaveChannelRes, err error) {
+	id, err := service.Alert().SaveChannel(ctx, &service.AlertChannelConfig{
+		Id:      req.Id,
+		Name:    req.Name,
+		Type:    req.Type,
+		Url:     req.Url,
+		Secret:  req.Secret,
+		Email:   req.Email,
+		Enabled: req.Enabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v1.SaveChannelRes{Id: id}, nil
+}
@@ -0,0 +1,27 @@
+package alert
+
+import (
+	"context"
+
+	"omniscient/api/alert/v1"
+	"omniscient/internal/service"
+)
+
+// SaveRule 新增或修改告警规则
+func (c *ControllerV1) SaveRule(ctx context.Context, req *v1.SaveRuleReq) (res *v1.SaveRuleRes, err error) {
+	id, err := service.Alert().SaveRule(ctx, &service.AlertRuleConfig{
+		Id:             req.Id,
+		Name:           req.Name,
+		Type:           req.Type,
+		JpidId:         req.JpidId,
+		Threshold:      req.Threshold,
+		Duration:       req.Duration,
+		Channels:       req.Channels,
+		RepeatInterval: req.RepeatInterval,
+		Enabled:        req.Enabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v1.SaveRuleRes{Id: id}, nil
+}
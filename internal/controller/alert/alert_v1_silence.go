@@ -0,0 +1,17 @@
+package alert
+
+import (
+	"context"
+
+	"omniscient/api/alert/v1"
+	"omniscient/internal/service"
+)
+
+// Silence 静默或取消静默告警规则
+func (c *ControllerV1) Silence(ctx context.Context, req *v1.SilenceReq) (res *v1.SilenceRes, err error) {
+	until, err := service.Alert().Silence(ctx, req.Id, req.Minutes)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.SilenceRes{SilenceUntil: until}, nil
+}
@@ -0,0 +1,17 @@
+package alert
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"omniscient/api/alert/v1"
+	"omniscient/internal/service"
+)
+
+// TestChannel 发送测试消息
+func (c *ControllerV1) TestChannel(ctx context.Context, req *v1.TestChannelReq) (res *v1.TestChannelRes, err error) {
+	if err = service.Alert().TestChannel(ctx, req.Id); err != nil {
+		return nil, gerror.Wrap(err, "发送测试消息失败")
+	}
+	return &v1.TestChannelRes{}, nil
+}
@@ -0,0 +1,5 @@
+// =================================================================================
+// 项目状态事件流
+// =================================================================================
+
+package event
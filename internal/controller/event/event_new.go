@@ -0,0 +1,15 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package event
+
+import (
+	"omniscient/api/event"
+)
+
+type ControllerV1 struct{}
+
+func NewV1() event.IEventV1 {
+	return &ControllerV1{}
+}
@@ -0,0 +1,89 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/api/event/v1"
+	"omniscient/internal/model"
+	"omniscient/internal/service"
+)
+
+// heartbeatInterval 心跳间隔，避免代理因连接空闲断开
+const heartbeatInterval = 15 * time.Second
+
+// Stream 以 SSE 推送项目事件，先补发游标之后的事件，再持续推送新事件直到客户端断开
+func (c *ControllerV1) Stream(ctx context.Context, req *v1.StreamReq) (res *v1.StreamRes, err error) {
+	r := g.RequestFromCtx(ctx)
+	w := r.Response.Writer
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	// 禁止 nginx 缓冲
+	w.Header().Set("X-Accel-Buffering", "no")
+
+	cursor := req.Cursor
+	if cursor == 0 {
+		cursor, _ = strconv.ParseInt(r.Header.Get("Last-Event-ID"), 10, 64)
+	}
+	types := make(map[string]bool)
+	for _, eventType := range strings.Split(req.Types, ",") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			types[eventType] = true
+		}
+	}
+	match := func(event *model.Event) bool {
+		return (len(types) == 0 || types[event.Type]) && (req.ProjectId == 0 || event.ProjectId == req.ProjectId)
+	}
+
+	backlog, events, cancel, complete := service.Event().Subscribe(cursor)
+	defer cancel()
+
+	fmt.Fprint(w, "retry: 3000\n\n")
+	if !complete {
+		writeEvent(w, &model.Event{Type: model.EventStreamReset, Time: time.Now().Format(time.DateTime)})
+	}
+	for _, event := range backlog {
+		if match(event) {
+			writeEvent(w, event)
+		}
+	}
+	w.Flush()
+
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil, nil
+		case <-ticker.C:
+			fmt.Fprint(w, ": ping\n\n")
+			w.Flush()
+		case event, ok := <-events:
+			if !ok {
+				// 订阅被断开，客户端按 retry 重连并用 Last-Event-ID 续传
+				return nil, nil
+			}
+			if match(event) {
+				writeEvent(w, event)
+				w.Flush()
+			}
+		}
+	}
+}
+
+// writeEvent 写出一条事件，stream.reset 没有 id，不改变客户端的游标
+func writeEvent(w http.ResponseWriter, event *model.Event) {
+	data, _ := json.Marshal(event)
+	if event.Id > 0 {
+		fmt.Fprintf(w, "id: %d\n", event.Id)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+}
@@ -0,0 +1,5 @@
+// =================================================================================
+// JDK 清单
+// =================================================================================
+
+package jdk
@@ -0,0 +1,15 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package jdk
+
+import (
+	"omniscient/api/jdk"
+)
+
+type ControllerV1 struct{}
+
+func NewV1() jdk.IJdkV1 {
+	return &ControllerV1{}
+}
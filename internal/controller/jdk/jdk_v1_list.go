@@ -0,0 +1,17 @@
+package jdk
+
+import (
+	"context"
+
+	"omniscient/api/jdk/v1"
+	"omniscient/internal/service"
+)
+
+// List JDK 清单
+func (c *ControllerV1) List(ctx context.Context, req *v1.ListReq) (res *v1.ListRes, err error) {
+	list, err := service.Jdk().List(ctx, req.Worker)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ListRes{List: list}, nil
+}
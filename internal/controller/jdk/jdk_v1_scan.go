@@ -0,0 +1,17 @@
+package jdk
+
+import (
+	"context"
+
+	"omniscient/api/jdk/v1"
+	"omniscient/internal/service"
+)
+
+// Scan 扫描本机 JDK
+func (c *ControllerV1) Scan(ctx context.Context, req *v1.ScanReq) (res *v1.ScanRes, err error) {
+	list, err := service.Jdk().Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ScanRes{List: list}, nil
+}
@@ -0,0 +1,17 @@
+package jpid
+
+import (
+	"context"
+
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// Actuator 获取项目 actuator 的健康状态、应用信息和关键指标
+func (c *ControllerV1) Actuator(ctx context.Context, req *v1.ActuatorReq) (res *v1.ActuatorRes, err error) {
+	overview, err := service.Actuator().Overview(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ActuatorRes{ActuatorOverview: overview}, nil
+}
@@ -0,0 +1,17 @@
+package jpid
+
+import (
+	"context"
+
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// AdoptAutostart 认领已有的自启服务
+func (c *ControllerV1) AdoptAutostart(ctx context.Context, req *v1.AdoptAutostartReq) (res *v1.AdoptAutostartRes, err error) {
+	state, err := service.Autostart().Adopt(ctx, req.Id, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.AdoptAutostartRes{State: state}, nil
+}
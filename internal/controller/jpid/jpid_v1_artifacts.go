@@ -0,0 +1,17 @@
+package jpid
+
+import (
+	"context"
+
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// Artifacts 项目的制品列表
+func (c *ControllerV1) Artifacts(ctx context.Context, req *v1.ArtifactsReq) (res *v1.ArtifactsRes, err error) {
+	list, err := service.Artifact().List(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ArtifactsRes{List: list}, nil
+}
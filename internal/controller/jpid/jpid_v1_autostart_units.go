@@ -0,0 +1,17 @@
+package jpid
+
+import (
+	"context"
+
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// AutostartUnits 本机的自启服务及其关联的项目，未关联的服务带有可以认领的项目
+func (c *ControllerV1) AutostartUnits(ctx context.Context, req *v1.AutostartUnitsReq) (res *v1.AutostartUnitsRes, err error) {
+	list, err := service.Autostart().Units(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.AutostartUnitsRes{List: list}, nil
+}
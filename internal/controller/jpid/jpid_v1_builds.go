@@ -0,0 +1,29 @@
+package jpid
+
+import (
+	"context"
+
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// Builds 各服务器上项目运行的构建版本
+func (c *ControllerV1) Builds(ctx context.Context, req *v1.BuildsReq) (res *v1.BuildsRes, err error) {
+	list, err := service.JpidRun().Builds(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	res = &v1.BuildsRes{List: make([]*v1.BuildItem, 0, len(list))}
+	for _, project := range list {
+		res.List = append(res.List, &v1.BuildItem{
+			Id:           project.Id,
+			Name:         project.Name,
+			Worker:       project.Worker,
+			Status:       project.Status,
+			BuildVersion: project.BuildVersion,
+			BuildCommit:  project.BuildCommit,
+			JarChecksum:  project.JarChecksum,
+		})
+	}
+	return res, nil
+}
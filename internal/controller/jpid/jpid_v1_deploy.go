@@ -0,0 +1,22 @@
+package jpid
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// Deploy 部署制品，失败时自动回滚到部署前的制品
+func (c *ControllerV1) Deploy(ctx context.Context, req *v1.DeployReq) (res *v1.DeployRes, err error) {
+	operator := req.Operator
+	if operator == "" {
+		operator = g.RequestFromCtx(ctx).GetClientIp()
+	}
+	deployment, err := service.Artifact().Deploy(ctx, req.Id, req.ArtifactId, operator)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.DeployRes{Deployment: deployment}, nil
+}
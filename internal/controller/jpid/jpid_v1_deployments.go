@@ -0,0 +1,17 @@
+package jpid
+
+import (
+	"context"
+
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// Deployments 项目的部署记录
+func (c *ControllerV1) Deployments(ctx context.Context, req *v1.DeploymentsReq) (res *v1.DeploymentsRes, err error) {
+	list, err := service.Artifact().Deployments(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.DeploymentsRes{List: list}, nil
+}
@@ -0,0 +1,35 @@
+package jpid
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/dao"
+	"omniscient/internal/model/entity"
+	"omniscient/internal/service"
+)
+
+// Detail 项目详情，包含运行中的 JDK 和最近一次采集的 JMX 指标
+func (c *ControllerV1) Detail(ctx context.Context, req *v1.DetailReq) (res *v1.DetailRes, err error) {
+	var project *entity.Jpid
+	if err = dao.Jpid.Ctx(ctx).Where("id", req.Id).Scan(&project); err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, gerror.New("项目不存在")
+	}
+
+	jmx, err := service.Jolokia().Latest(ctx, project.Id)
+	if err != nil {
+		return nil, err
+	}
+	res = &v1.DetailRes{
+		Java:    service.Jdk().Runtime(project),
+		Queries: service.Jolokia().Queries(ctx, project),
+		Jmx:     jmx,
+	}
+	service.Secret().MaskProject(project)
+	res.Jpid = project
+	return res, nil
+}
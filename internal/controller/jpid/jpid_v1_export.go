@@ -0,0 +1,35 @@
+package jpid
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// Export 导出项目定义，以附件形式下载
+func (c *ControllerV1) Export(ctx context.Context, req *v1.ExportReq) (res *v1.ExportRes, err error) {
+	bundle, err := service.Jpid().Export(ctx, req.Worker)
+	if err != nil {
+		return nil, err
+	}
+
+	format := req.Format
+	if format == "" {
+		format = service.BundleFormatYAML
+	}
+	content, err := service.EncodeBundle(bundle, format)
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := fmt.Sprintf("projects-%s-%s.%s", bundle.Worker, time.Now().Format("20060102-150405"), format)
+	r := g.RequestFromCtx(ctx)
+	r.Response.Header().Set("Content-Type", "application/octet-stream")
+	r.Response.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, fileName))
+	r.Response.Write(content)
+	return
+}
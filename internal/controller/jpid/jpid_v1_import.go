@@ -0,0 +1,38 @@
+package jpid
+
+import (
+	"context"
+	"io"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// Import 导入项目定义，preview 为 true 时只返回导入计划
+func (c *ControllerV1) Import(ctx context.Context, req *v1.ImportReq) (res *v1.ImportRes, err error) {
+	content := []byte(req.Content)
+	if req.File != nil {
+		file, err := req.File.Open()
+		if err != nil {
+			return nil, gerror.Wrap(err, "读取导出包文件失败")
+		}
+		defer file.Close()
+		if content, err = io.ReadAll(file); err != nil {
+			return nil, gerror.Wrap(err, "读取导出包文件失败")
+		}
+	}
+	if len(content) == 0 {
+		return nil, gerror.New("请上传导出包文件或填写导出包内容")
+	}
+
+	bundle, err := service.DecodeBundle(content)
+	if err != nil {
+		return nil, err
+	}
+	result, err := service.Jpid().Import(ctx, bundle, req.Worker, req.Strategy, req.Preview)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ImportRes{ImportResult: result}, nil
+}
@@ -0,0 +1,17 @@
+package jpid
+
+import (
+	"context"
+
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// Jar 解析项目 jar 的构建信息
+func (c *ControllerV1) Jar(ctx context.Context, req *v1.JarReq) (res *v1.JarRes, err error) {
+	info, err := service.JpidRun().Inspect(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.JarRes{JarInfo: info}, nil
+}
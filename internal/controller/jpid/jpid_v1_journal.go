@@ -0,0 +1,33 @@
+package jpid
+
+import (
+	"context"
+	"time"
+
+	"autostart/pkg/autostart"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// Journal 项目自启服务的 journal 日志
+func (c *ControllerV1) Journal(ctx context.Context, req *v1.JournalReq) (res *v1.JournalRes, err error) {
+	opts := autostart.LogOptions{Lines: req.Lines, Priority: req.Priority, Grep: req.Grep}
+	now := time.Now()
+	if req.Since != "" {
+		if opts.Since, err = autostart.ParseLogTime(req.Since, now); err != nil {
+			return nil, gerror.Wrap(err, "开始时间格式错误")
+		}
+	}
+	if req.Until != "" {
+		if opts.Until, err = autostart.ParseLogTime(req.Until, now); err != nil {
+			return nil, gerror.Wrap(err, "结束时间格式错误")
+		}
+	}
+
+	unit, list, err := service.Jpid().Journal(ctx, req.Id, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.JournalRes{Unit: unit, List: list}, nil
+}
@@ -10,7 +10,18 @@ func (c *ControllerV1) Jpid(ctx context.Context, req *v1.JpidReq) (res *v1.JpidR
 	// 初始化 res , 不初始化会出现 invalid memory address or nil pointer dereference
 	res = &v1.JpidRes{}
 
-	res.List, err = service.Jpid().GetList(ctx, req.Worker)
+	list, err := service.Jpid().GetList(ctx, req.Worker)
+	if err != nil {
+		return nil, err
+	}
+	for _, project := range list {
+		service.Secret().MaskProject(project)
+		res.List = append(res.List, &v1.JpidItem{
+			Jpid:           project,
+			Java:           service.Jdk().Runtime(project),
+			AutostartState: service.Autostart().State(project.Id),
+		})
+	}
 	return
 }
 
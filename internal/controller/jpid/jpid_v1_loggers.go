@@ -0,0 +1,17 @@
+package jpid
+
+import (
+	"context"
+
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// Loggers 获取项目的 logger 级别
+func (c *ControllerV1) Loggers(ctx context.Context, req *v1.LoggersReq) (res *v1.LoggersRes, err error) {
+	loggers, err := service.Actuator().Loggers(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.LoggersRes{ActuatorLoggers: loggers}, nil
+}
@@ -0,0 +1,17 @@
+package jpid
+
+import (
+	"context"
+
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// Metrics 项目 JMX 指标的历史值
+func (c *ControllerV1) Metrics(ctx context.Context, req *v1.MetricsReq) (res *v1.MetricsRes, err error) {
+	list, err := service.Jolokia().History(ctx, req.Id, req.Name, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.MetricsRes{List: list}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"omniscient/api/jpid/v1"
 	"omniscient/internal/model/entity"
+	"omniscient/internal/service"
 	"omniscient/internal/util/javaprocess"
 	"omniscient/internal/util/system"
 )
@@ -25,7 +26,7 @@ func (c *ControllerV1) Online(ctx context.Context, req *v1.OnlineReq) (res *v1.O
 		linuxPid := &entity.LinuxPid{
 			Name:    p.Name,
 			Pid:     p.Pid,
-			Run:     p.Run,
+			Run:     service.Secret().Mask(p.Run),
 			Ports:   p.Ports,
 			Catalog: p.Catalog,
 			Worker:  workerName,
@@ -0,0 +1,17 @@
+package jpid
+
+import (
+	"context"
+
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// RegenerateAutostart 按项目当前的启动配置重新生成自启服务
+func (c *ControllerV1) RegenerateAutostart(ctx context.Context, req *v1.RegenerateAutostartReq) (res *v1.RegenerateAutostartRes, err error) {
+	state, err := service.Autostart().Regenerate(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.RegenerateAutostartRes{State: state}, nil
+}
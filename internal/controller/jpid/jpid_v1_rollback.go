@@ -0,0 +1,22 @@
+package jpid
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// Rollback 一键回滚到上一个制品
+func (c *ControllerV1) Rollback(ctx context.Context, req *v1.RollbackReq) (res *v1.RollbackRes, err error) {
+	operator := req.Operator
+	if operator == "" {
+		operator = g.RequestFromCtx(ctx).GetClientIp()
+	}
+	deployment, err := service.Artifact().Rollback(ctx, req.Id, operator)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.RollbackRes{Deployment: deployment}, nil
+}
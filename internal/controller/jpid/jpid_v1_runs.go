@@ -0,0 +1,17 @@
+package jpid
+
+import (
+	"context"
+
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// Runs 项目的运行记录
+func (c *ControllerV1) Runs(ctx context.Context, req *v1.RunsReq) (res *v1.RunsRes, err error) {
+	list, err := service.JpidRun().List(ctx, req.Id, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.RunsRes{List: list}, nil
+}
@@ -0,0 +1,19 @@
+package jpid
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// SetLogger 修改项目的 logger 级别
+func (c *ControllerV1) SetLogger(ctx context.Context, req *v1.SetLoggerReq) (res *v1.SetLoggerRes, err error) {
+	logger, err := service.Actuator().SetLogger(ctx, req.Id, req.Name, req.Level)
+	if err != nil {
+		return nil, err
+	}
+	g.Log().Infof(ctx, "项目 %d 的 logger %s 级别修改为 %q", req.Id, req.Name, req.Level)
+	return &v1.SetLoggerRes{ActuatorLogger: logger}, nil
+}
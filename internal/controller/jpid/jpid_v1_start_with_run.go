@@ -39,9 +39,19 @@ func (c *ControllerV1) StartWithRun(ctx context.Context, req *v1.StartWithRunReq
 		sendSSEMessage(w, "error", "项目不存在")
 		return nil, gerror.New("项目不存在")
 	}
-	if jpid.Run == "" {
-		sendSSEMessage(w, "error", "run命令为空")
-		return nil, gerror.New("run命令为空")
+	// run 为空时由结构化启动配置组合命令
+	command, err := service.Jpid().Command(jpid)
+	if err != nil {
+		sendSSEMessage(w, "error", err.Error())
+		return nil, err
+	}
+	jpid.Run = command
+
+	// 项目环境变量，密钥引用在此解析为明文，只注入进程不输出
+	launchEnv, err := service.LaunchEnv(ctx, jpid)
+	if err != nil {
+		sendSSEMessage(w, "error", err.Error())
+		return nil, err
 	}
 
 	// 发送启动提示
@@ -141,6 +151,7 @@ echo $PID > "%s"
 			fmt.Sprintf("PROJECT_PID=%d", jpid.Pid),
 			"LANG=en_US.UTF-8", // 添加UTF-8支持
 		)
+		cmd.Env = append(cmd.Env, launchEnv...)
 
 		if err = cmd.Run(); err != nil {
 			sendSSEMessage(w, "error", "启动失败："+err.Error())
@@ -403,6 +414,7 @@ exit $EXIT_CODE
 			fmt.Sprintf("PROJECT_PID=%d", jpid.Pid),
 			"LANG=en_US.UTF-8", // 添加UTF-8支持
 		)
+		cmd.Env = append(cmd.Env, launchEnv...)
 
 		// 创建输出管道
 		stdout, err := cmd.StdoutPipe()
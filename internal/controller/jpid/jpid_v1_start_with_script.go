@@ -63,6 +63,12 @@ func (c *ControllerV1) StartWithScript(ctx context.Context, req *v1.StartWithScr
 		fmt.Sprintf("PROJECT_NAME=%s", jpid.Name),
 		fmt.Sprintf("PROJECT_PID=%d", jpid.Pid),
 	)
+	launchEnv, err := service.LaunchEnv(ctx, jpid)
+	if err != nil {
+		sendSSEMessage(w, "error", err.Error())
+		return nil, err
+	}
+	cmd.Env = append(cmd.Env, launchEnv...)
 
 	// 创建输出管道
 	stdout, err := cmd.StdoutPipe()
@@ -137,7 +143,7 @@ func (c *ControllerV1) StartWithScript(ctx context.Context, req *v1.StartWithScr
 
 // 辅助函数：发送 SSE 消息
 func sendSSEMessage(w http.ResponseWriter, event, data string) {
-	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, service.Secret().Mask(data))
 	if err != nil {
 		return
 	}
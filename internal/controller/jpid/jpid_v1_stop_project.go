@@ -29,6 +29,14 @@ func (c *ControllerV1) StopProject(ctx context.Context, req *v1.StopProjectReq)
 		return nil, gerror.Newf("项目不存在: pid=%d", req.Pid)
 	}
 
+	// 停止方式为 actuator 时优先优雅停止，失败后继续按原方式停止
+	if service.Jpid().StopByActuator(ctx, jpid) {
+		if err = service.Jpid().UpdateStatusById(ctx, jpid.Id, 0); err != nil {
+			return nil, gerror.Wrapf(err, "更新项目状态失败: pid=%d", req.Pid)
+		}
+		return &v1.StopProjectRes{Message: "停止成功"}, nil
+	}
+
 	// Create command based on project type
 	var cmd *exec.Cmd
 	if jpid.Way == 1 {
@@ -0,0 +1,17 @@
+package jpid
+
+import (
+	"context"
+
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// SyncAutostart 立即同步自启服务，不等待定时同步
+func (c *ControllerV1) SyncAutostart(ctx context.Context, req *v1.SyncAutostartReq) (res *v1.SyncAutostartRes, err error) {
+	result, err := service.Autostart().Sync(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.SyncAutostartRes{AutostartSyncResult: result}, nil
+}
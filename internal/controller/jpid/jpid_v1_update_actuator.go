@@ -0,0 +1,17 @@
+package jpid
+
+import (
+	"context"
+
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// UpdateActuator 设置项目的 actuator 地址和停止方式
+func (c *ControllerV1) UpdateActuator(ctx context.Context, req *v1.UpdateActuatorReq) (res *v1.UpdateActuatorRes, err error) {
+	url, err := service.Actuator().Configure(ctx, req.Id, req.Actuator, req.StopStrategy)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.UpdateActuatorRes{Url: url}, nil
+}
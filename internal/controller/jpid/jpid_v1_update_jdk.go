@@ -0,0 +1,17 @@
+package jpid
+
+import (
+	"context"
+
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// UpdateJdk 指定项目使用的 JDK，升级 JDK 只需要修改这一个字段
+func (c *ControllerV1) UpdateJdk(ctx context.Context, req *v1.UpdateJdkReq) (res *v1.UpdateJdkRes, err error) {
+	command, err := service.Jpid().UpdateJdk(ctx, req.Id, req.JdkId)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.UpdateJdkRes{Command: service.Secret().Mask(command)}, nil
+}
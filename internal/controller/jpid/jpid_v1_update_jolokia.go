@@ -0,0 +1,36 @@
+package jpid
+
+import (
+	"context"
+
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/dao"
+	"omniscient/internal/model/entity"
+	"omniscient/internal/service"
+)
+
+// UpdateJolokia 设置项目的 Jolokia 地址和 MBean 查询，项目运行中时立即采集一次用于验证配置
+func (c *ControllerV1) UpdateJolokia(ctx context.Context, req *v1.UpdateJolokiaReq) (res *v1.UpdateJolokiaRes, err error) {
+	if err = service.Jolokia().Configure(ctx, req.Id, req.Jolokia, req.Queries); err != nil {
+		return nil, err
+	}
+
+	res = &v1.UpdateJolokiaRes{}
+	var project *entity.Jpid
+	if err = dao.Jpid.Ctx(ctx).Where("id", req.Id).Scan(&project); err != nil {
+		return nil, err
+	}
+	if project.Jolokia == "" {
+		return res, nil
+	}
+	if !service.Jpid().IsRunning(project) {
+		res.Error = "项目未运行，启动后开始采集"
+		return res, nil
+	}
+	if res.Failures, err = service.Jolokia().Collect(ctx, project); err != nil {
+		res.Error = err.Error()
+		return res, nil
+	}
+	res.Metrics, err = service.Jolokia().Latest(ctx, project.Id)
+	return res, err
+}
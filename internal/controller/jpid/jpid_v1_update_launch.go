@@ -0,0 +1,24 @@
+package jpid
+
+import (
+	"context"
+
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// UpdateLaunch 更新结构化启动配置，下次启动时生效
+func (c *ControllerV1) UpdateLaunch(ctx context.Context, req *v1.UpdateLaunchReq) (res *v1.UpdateLaunchRes, err error) {
+	command, err := service.Jpid().UpdateLaunch(ctx, req.Id, &service.LaunchConfig{
+		Env:     req.Env,
+		JvmOpts: req.JvmOpts,
+		Args:    req.Args,
+		JdkHome: req.JdkHome,
+		JdkId:   req.JdkId,
+		Run:     req.Run,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v1.UpdateLaunchRes{Command: service.Secret().Mask(command)}, nil
+}
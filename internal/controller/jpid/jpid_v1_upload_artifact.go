@@ -0,0 +1,35 @@
+package jpid
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/api/jpid/v1"
+	"omniscient/internal/service"
+)
+
+// UploadArtifact 上传 jar 制品，deploy 为 true 时上传后立即部署
+func (c *ControllerV1) UploadArtifact(ctx context.Context, req *v1.UploadArtifactReq) (res *v1.UploadArtifactRes, err error) {
+	file, err := req.File.Open()
+	if err != nil {
+		return nil, gerror.Wrap(err, "读取上传文件失败")
+	}
+	defer file.Close()
+
+	uploader := req.Uploader
+	if uploader == "" {
+		uploader = g.RequestFromCtx(ctx).GetClientIp()
+	}
+	artifact, err := service.Artifact().Store(ctx, req.Id, req.File.Filename, file, uploader, req.Note)
+	if err != nil {
+		return nil, err
+	}
+	res = &v1.UploadArtifactRes{Artifact: artifact}
+	if req.Deploy {
+		if res.Deployment, err = service.Artifact().Deploy(ctx, req.Id, artifact.Id, uploader); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,5 @@
+// =================================================================================
+// 站内通知
+// =================================================================================
+
+package notification
@@ -0,0 +1,21 @@
+package notification
+
+import (
+	"context"
+
+	"omniscient/api/notification/v1"
+	"omniscient/internal/service"
+)
+
+// List 通知列表和未读数
+func (c *ControllerV1) List(ctx context.Context, req *v1.ListReq) (res *v1.ListRes, err error) {
+	list, err := service.Notification().List(ctx, req.Unread, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := service.Notification().Unread(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ListRes{Unread: unread, List: list}, nil
+}
@@ -0,0 +1,17 @@
+package notification
+
+import (
+	"context"
+
+	"omniscient/api/notification/v1"
+	"omniscient/internal/service"
+)
+
+// Read 标记通知为已读
+func (c *ControllerV1) Read(ctx context.Context, req *v1.ReadReq) (res *v1.ReadRes, err error) {
+	updated, err := service.Notification().Read(ctx, req.Ids)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ReadRes{Updated: updated}, nil
+}
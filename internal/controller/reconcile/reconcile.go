@@ -0,0 +1,5 @@
+// =================================================================================
+// 声明式项目清单对账
+// =================================================================================
+
+package reconcile
@@ -0,0 +1,15 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package reconcile
+
+import (
+	"omniscient/api/reconcile"
+)
+
+type ControllerV1 struct{}
+
+func NewV1() reconcile.IReconcileV1 {
+	return &ControllerV1{}
+}
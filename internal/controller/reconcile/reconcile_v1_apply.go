@@ -0,0 +1,17 @@
+package reconcile
+
+import (
+	"context"
+
+	"omniscient/api/reconcile/v1"
+	"omniscient/internal/service"
+)
+
+// Apply 按项目清单执行对账
+func (c *ControllerV1) Apply(ctx context.Context, req *v1.ApplyReq) (res *v1.ApplyRes, err error) {
+	plan, err := service.Reconcile().Apply(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ApplyRes{ReconcilePlan: plan}, nil
+}
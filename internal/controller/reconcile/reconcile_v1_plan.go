@@ -0,0 +1,17 @@
+package reconcile
+
+import (
+	"context"
+
+	"omniscient/api/reconcile/v1"
+	"omniscient/internal/service"
+)
+
+// Plan 生成对账计划，只报告漂移和待执行动作，不做修改
+func (c *ControllerV1) Plan(ctx context.Context, req *v1.PlanReq) (res *v1.PlanRes, err error) {
+	plan, err := service.Reconcile().Plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.PlanRes{ReconcilePlan: plan}, nil
+}
@@ -0,0 +1,5 @@
+// =================================================================================
+// 加密密钥管理
+// =================================================================================
+
+package secret
@@ -0,0 +1,15 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package secret
+
+import (
+	"omniscient/api/secret"
+)
+
+type ControllerV1 struct{}
+
+func NewV1() secret.ISecretV1 {
+	return &ControllerV1{}
+}
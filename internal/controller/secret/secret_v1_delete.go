@@ -0,0 +1,16 @@
+package secret
+
+import (
+	"context"
+
+	"omniscient/api/secret/v1"
+	"omniscient/internal/service"
+)
+
+// Delete 删除密钥
+func (c *ControllerV1) Delete(ctx context.Context, req *v1.DeleteReq) (res *v1.DeleteRes, err error) {
+	if err = service.Secret().Delete(ctx, req.Name); err != nil {
+		return nil, err
+	}
+	return &v1.DeleteRes{}, nil
+}
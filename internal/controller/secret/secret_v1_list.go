@@ -0,0 +1,27 @@
+package secret
+
+import (
+	"context"
+
+	"omniscient/api/secret/v1"
+	"omniscient/internal/service"
+)
+
+// List 密钥列表，只返回名称和元数据
+func (c *ControllerV1) List(ctx context.Context, req *v1.ListReq) (res *v1.ListRes, err error) {
+	list, err := service.Secret().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items := make(v1.ListRes, 0, len(list))
+	for _, secret := range list {
+		items = append(items, &v1.SecretItem{
+			Name:        secret.Name,
+			KeyId:       secret.KeyId,
+			Description: secret.Description,
+			CreatedAt:   secret.CreatedAt,
+			UpdatedAt:   secret.UpdatedAt,
+		})
+	}
+	return &items, nil
+}
@@ -0,0 +1,16 @@
+package secret
+
+import (
+	"context"
+
+	"omniscient/api/secret/v1"
+	"omniscient/internal/service"
+)
+
+// Set 新增或更新密钥
+func (c *ControllerV1) Set(ctx context.Context, req *v1.SetReq) (res *v1.SetRes, err error) {
+	if err = service.Secret().Set(ctx, req.Name, req.Value, req.Description); err != nil {
+		return nil, err
+	}
+	return &v1.SetRes{}, nil
+}
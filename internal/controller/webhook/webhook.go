@@ -0,0 +1,5 @@
+// =================================================================================
+// 出站 webhook 订阅和投递记录
+// =================================================================================
+
+package webhook
@@ -0,0 +1,15 @@
+// =================================================================================
+// This is auto-generated by GoFrame CLI tool only once. Fill this file as you wish.
+// =================================================================================
+
+package webhook
+
+import (
+	"omniscient/api/webhook"
+)
+
+type ControllerV1 struct{}
+
+func NewV1() webhook.IWebhookV1 {
+	return &ControllerV1{}
+}
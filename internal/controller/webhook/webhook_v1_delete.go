@@ -0,0 +1,16 @@
+package webhook
+
+import (
+	"context"
+
+	"omniscient/api/webhook/v1"
+	"omniscient/internal/service"
+)
+
+// Delete 删除 webhook 订阅
+func (c *ControllerV1) Delete(ctx context.Context, req *v1.DeleteReq) (res *v1.DeleteRes, err error) {
+	if err = service.Webhook().Delete(ctx, req.Id); err != nil {
+		return nil, err
+	}
+	return &v1.DeleteRes{}, nil
+}
@@ -0,0 +1,17 @@
+package webhook
+
+import (
+	"context"
+
+	"omniscient/api/webhook/v1"
+	"omniscient/internal/service"
+)
+
+// Deliveries webhook 投递记录
+func (c *ControllerV1) Deliveries(ctx context.Context, req *v1.DeliveriesReq) (res *v1.DeliveriesRes, err error) {
+	list, err := service.Webhook().Deliveries(ctx, req.WebhookId, req.Status, req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.DeliveriesRes{List: list}, nil
+}
@@ -0,0 +1,17 @@
+package webhook
+
+import (
+	"context"
+
+	"omniscient/api/webhook/v1"
+	"omniscient/internal/service"
+)
+
+// List webhook 订阅列表
+func (c *ControllerV1) List(ctx context.Context, req *v1.ListReq) (res *v1.ListRes, err error) {
+	list, err := service.Webhook().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ListRes{List: list}, nil
+}
@@ -0,0 +1,17 @@
+package webhook
+
+import (
+	"context"
+
+	"omniscient/api/webhook/v1"
+	"omniscient/internal/service"
+)
+
+// Replay 重新投递
+func (c *ControllerV1) Replay(ctx context.Context, req *v1.ReplayReq) (res *v1.ReplayRes, err error) {
+	id, err := service.Webhook().Replay(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ReplayRes{Id: id}, nil
+}
@@ -0,0 +1,24 @@
+package webhook
+
+import (
+	"context"
+
+	"omniscient/api/webhook/v1"
+	"omniscient/internal/service"
+)
+
+// Save 新增或修改 webhook 订阅
+func (c *ControllerV1) Save(ctx context.Context, req *v1.SaveReq) (res *v1.SaveRes, err error) {
+	id, err := service.Webhook().Save(ctx, &service.WebhookConfig{
+		Id:      req.Id,
+		Name:    req.Name,
+		Url:     req.Url,
+		Secret:  req.Secret,
+		Events:  req.Events,
+		Enabled: req.Enabled,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v1.SaveRes{Id: id}, nil
+}
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"omniscient/internal/dao/internal"
+)
+
+// alertChannelDao is the data access object for the table alert_channel.
+// You can define custom methods on it to extend its functionality as needed.
+type alertChannelDao struct {
+	*internal.AlertChannelDao
+}
+
+var (
+	// AlertChannel is a globally accessible object for table alert_channel operations.
+	AlertChannel = alertChannelDao{internal.NewAlertChannelDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"omniscient/internal/dao/internal"
+)
+
+// alertEventDao is the data access object for the table alert_event.
+// You can define custom methods on it to extend its functionality as needed.
+type alertEventDao struct {
+	*internal.AlertEventDao
+}
+
+var (
+	// AlertEvent is a globally accessible object for table alert_event operations.
+	AlertEvent = alertEventDao{internal.NewAlertEventDao()}
+)
+
+// Add your custom methods and functionality below.
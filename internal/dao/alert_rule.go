@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"omniscient/internal/dao/internal"
+)
+
+// alertRuleDao is the data access object for the table alert_rule.
+// You can define custom methods on it to extend its functionality as needed.
+type alertRuleDao struct {
+	*internal.AlertRuleDao
+}
+
+var (
+	// AlertRule is a globally accessible object for table alert_rule operations.
+	AlertRule = alertRuleDao{internal.NewAlertRuleDao()}
+)
+
+// Add your custom methods and functionality below.
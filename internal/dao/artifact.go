@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"omniscient/internal/dao/internal"
+)
+
+// artifactDao is the data access object for the table artifact.
+// You can define custom methods on it to extend its functionality as needed.
+type artifactDao struct {
+	*internal.ArtifactDao
+}
+
+var (
+	// Artifact is a globally accessible object for table artifact operations.
+	Artifact = artifactDao{internal.NewArtifactDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"omniscient/internal/dao/internal"
+)
+
+// deploymentDao is the data access object for the table deployment.
+// You can define custom methods on it to extend its functionality as needed.
+type deploymentDao struct {
+	*internal.DeploymentDao
+}
+
+var (
+	// Deployment is a globally accessible object for table deployment operations.
+	Deployment = deploymentDao{internal.NewDeploymentDao()}
+)
+
+// Add your custom methods and functionality below.
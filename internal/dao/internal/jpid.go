@@ -21,34 +21,66 @@ type JpidDao struct {
 
 // JpidColumns defines and stores column names for the table jpid.
 type JpidColumns struct {
-	Id          string //
-	Name        string // java项目名
-	Ports       string // 运行端口,多个逗号隔开
-	Pid         string // pid
-	Catalog     string // 运行目录
-	Run         string // 原生启动命令
-	Script      string // sh脚本启动命令
-	Worker      string // 服务器
-	Status      string // 状态[1:启动，0:停止]
-	Description string // 项目描述
-	Way         string // 启动方式[1:docker, 2:jdk]
-	Autostart   string // 自启[0:没有自启, 1:自启]
+	Id             string //
+	Name           string // java项目名
+	Ports          string // 运行端口,多个逗号隔开
+	Pid            string // pid
+	Catalog        string // 运行目录
+	Run            string // 原生启动命令
+	Script         string // sh脚本启动命令
+	Worker         string // 服务器
+	Status         string // 状态[1:启动，0:停止]
+	Description    string // 项目描述
+	Way            string // 启动方式[1:docker, 2:jdk]
+	Autostart      string // 自启[0:没有自启, 1:自启]
+	Env            string // 环境变量[JSON]
+	HealthCheck    string // 健康检查地址[http(s)://, tcp://]
+	Manifest       string // 声明清单文件[为空表示非清单管理]
+	JvmOpts        string // JVM参数[JSON]
+	Args           string // 程序参数[JSON数组]
+	JdkHome        string // JDK目录[为空使用PATH中的java]
+	JdkId          string // 指定的JDK[jdk.id, 0:未指定]
+	ArtifactId     string // 当前部署的制品[artifact.id, 0:未通过制品部署]
+	BuildVersion   string // 构建版本
+	BuildCommit    string // git 提交号
+	JarChecksum    string // 运行中 jar 的 SHA-256
+	Actuator       string // Actuator 地址[为空时按端口自动探测]
+	StopStrategy   string // 停止方式[signal:信号, actuator:/actuator/shutdown]
+	Jolokia        string // Jolokia 地址[为空不采集 JMX 指标]
+	JolokiaQueries string // MBean 查询[JSON数组, 为空使用 jolokia.queries]
+	UnitName       string // 自启服务名[不含 autostart- 前缀, 为空表示未关联]
 }
 
 // jpidColumns holds the columns for the table jpid.
 var jpidColumns = JpidColumns{
-	Id:          "id",
-	Name:        "name",
-	Ports:       "ports",
-	Pid:         "pid",
-	Catalog:     "catalog",
-	Run:         "run",
-	Script:      "script",
-	Worker:      "worker",
-	Status:      "status",
-	Description: "description",
-	Way:         "way",
-	Autostart:   "autostart",
+	Id:             "id",
+	Name:           "name",
+	Ports:          "ports",
+	Pid:            "pid",
+	Catalog:        "catalog",
+	Run:            "run",
+	Script:         "script",
+	Worker:         "worker",
+	Status:         "status",
+	Description:    "description",
+	Way:            "way",
+	Autostart:      "autostart",
+	Env:            "env",
+	HealthCheck:    "health_check",
+	Manifest:       "manifest",
+	JvmOpts:        "jvm_opts",
+	Args:           "args",
+	JdkHome:        "jdk_home",
+	JdkId:          "jdk_id",
+	ArtifactId:     "artifact_id",
+	BuildVersion:   "build_version",
+	BuildCommit:    "build_commit",
+	JarChecksum:    "jar_checksum",
+	Actuator:       "actuator",
+	StopStrategy:   "stop_strategy",
+	Jolokia:        "jolokia",
+	JolokiaQueries: "jolokia_queries",
+	UnitName:       "unit_name",
 }
 
 // NewJpidDao creates and returns a new DAO object for table data access.
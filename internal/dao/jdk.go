@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"omniscient/internal/dao/internal"
+)
+
+// jdkDao is the data access object for the table jdk.
+// You can define custom methods on it to extend its functionality as needed.
+type jdkDao struct {
+	*internal.JdkDao
+}
+
+var (
+	// Jdk is a globally accessible object for table jdk operations.
+	Jdk = jdkDao{internal.NewJdkDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"omniscient/internal/dao/internal"
+)
+
+// jpidMetricDao is the data access object for the table jpid_metric.
+// You can define custom methods on it to extend its functionality as needed.
+type jpidMetricDao struct {
+	*internal.JpidMetricDao
+}
+
+var (
+	// JpidMetric is a globally accessible object for table jpid_metric operations.
+	JpidMetric = jpidMetricDao{internal.NewJpidMetricDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"omniscient/internal/dao/internal"
+)
+
+// jpidRunDao is the data access object for the table jpid_run.
+// You can define custom methods on it to extend its functionality as needed.
+type jpidRunDao struct {
+	*internal.JpidRunDao
+}
+
+var (
+	// JpidRun is a globally accessible object for table jpid_run operations.
+	JpidRun = jpidRunDao{internal.NewJpidRunDao()}
+)
+
+// Add your custom methods and functionality below.
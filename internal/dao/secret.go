@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"omniscient/internal/dao/internal"
+)
+
+// secretDao is the data access object for the table secret.
+// You can define custom methods on it to extend its functionality as needed.
+type secretDao struct {
+	*internal.SecretDao
+}
+
+var (
+	// Secret is a globally accessible object for table secret operations.
+	Secret = secretDao{internal.NewSecretDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"omniscient/internal/dao/internal"
+)
+
+// webhookDao is the data access object for the table webhook.
+// You can define custom methods on it to extend its functionality as needed.
+type webhookDao struct {
+	*internal.WebhookDao
+}
+
+var (
+	// Webhook is a globally accessible object for table webhook operations.
+	Webhook = webhookDao{internal.NewWebhookDao()}
+)
+
+// Add your custom methods and functionality below.
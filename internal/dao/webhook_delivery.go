@@ -0,0 +1,22 @@
+// =================================================================================
+// This file is auto-generated by the GoFrame CLI tool. You may modify it as needed.
+// =================================================================================
+
+package dao
+
+import (
+	"omniscient/internal/dao/internal"
+)
+
+// webhookDeliveryDao is the data access object for the table webhook_delivery.
+// You can define custom methods on it to extend its functionality as needed.
+type webhookDeliveryDao struct {
+	*internal.WebhookDeliveryDao
+}
+
+var (
+	// WebhookDelivery is a globally accessible object for table webhook_delivery operations.
+	WebhookDelivery = webhookDeliveryDao{internal.NewWebhookDeliveryDao()}
+)
+
+// Add your custom methods and functionality below.
@@ -0,0 +1,41 @@
+package model
+
+// 项目停止方式
+const (
+	StopSignal   = "signal"   // 发送 SIGTERM，超时后 SIGKILL（默认）
+	StopActuator = "actuator" // 调用 /actuator/shutdown 优雅停止，失败时回退为信号
+)
+
+// LoggerLevels Spring Boot 支持的日志级别
+var LoggerLevels = []string{"OFF", "ERROR", "WARN", "INFO", "DEBUG", "TRACE"}
+
+// ActuatorOverview 项目 actuator 的健康、应用信息和关键指标
+type ActuatorOverview struct {
+	Url     string                 `json:"url"              dc:"actuator 基础地址"`
+	Health  map[string]interface{} `json:"health,omitempty" dc:"/actuator/health"`
+	Info    map[string]interface{} `json:"info,omitempty"   dc:"/actuator/info"`
+	Metrics []*ActuatorMetric      `json:"metrics"          dc:"actuator.metrics 配置的指标"`
+	Errors  map[string]string      `json:"errors,omitempty" dc:"各端点的访问错误，端点未开放时返回"`
+}
+
+// ActuatorMetric 单个指标，Value 优先取 VALUE，其次 COUNT
+type ActuatorMetric struct {
+	Name         string             `json:"name"`
+	Description  string             `json:"description,omitempty"`
+	BaseUnit     string             `json:"baseUnit,omitempty"`
+	Value        float64            `json:"value"`
+	Measurements map[string]float64 `json:"measurements"`
+}
+
+// ActuatorLoggers /actuator/loggers 的结果
+type ActuatorLoggers struct {
+	Levels  []string          `json:"levels"`
+	Loggers []*ActuatorLogger `json:"loggers"`
+}
+
+// ActuatorLogger 单个 logger 的级别，ConfiguredLevel 为空表示继承上级
+type ActuatorLogger struct {
+	Name            string `json:"name"`
+	ConfiguredLevel string `json:"configuredLevel"`
+	EffectiveLevel  string `json:"effectiveLevel"`
+}
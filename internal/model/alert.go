@@ -0,0 +1,53 @@
+package model
+
+// 告警规则类型
+const (
+	AlertStopped     = "stopped"      // 意外退出（崩溃分析记录了退出原因）后未恢复运行
+	AlertRestartLoop = "restart_loop" // duration 秒内启动次数达到 threshold
+	AlertUnhealthy   = "unhealthy"    // 健康检查连续失败 duration 秒
+	AlertRss         = "rss"          // 进程常驻内存超过 threshold MB
+	AlertPort        = "port"         // 运行中但端口无法连接
+)
+
+// AlertRuleTypes 支持的告警规则类型
+var AlertRuleTypes = []string{AlertStopped, AlertRestartLoop, AlertUnhealthy, AlertRss, AlertPort}
+
+// 通知渠道类型
+const (
+	ChannelWebhook  = "webhook"  // 通用 webhook，POST JSON
+	ChannelDingtalk = "dingtalk" // 钉钉机器人
+	ChannelWecom    = "wecom"    // 企业微信机器人
+	ChannelFeishu   = "feishu"   // 飞书机器人
+	ChannelEmail    = "email"    // SMTP 邮件
+)
+
+// AlertChannelTypes 支持的通知渠道类型
+var AlertChannelTypes = []string{ChannelWebhook, ChannelDingtalk, ChannelWecom, ChannelFeishu, ChannelEmail}
+
+// 告警事件状态
+const (
+	AlertFiring   = "firing"
+	AlertResolved = "resolved"
+)
+
+// EmailConfig SMTP 邮件渠道配置，password 可以使用 ${secret:NAME} 引用密钥
+type EmailConfig struct {
+	Host     string   `json:"host"               dc:"SMTP 服务器"`
+	Port     int      `json:"port"               dc:"SMTP 端口，465 使用 TLS 连接，其他端口服务器支持时使用 STARTTLS"`
+	Username string   `json:"username,omitempty" dc:"用户名，为空时不认证"`
+	Password string   `json:"password,omitempty" dc:"密码"`
+	From     string   `json:"from"               dc:"发件人"`
+	To       []string `json:"to"                 dc:"收件人"`
+}
+
+// AlertMessage 发送到通知渠道的消息
+type AlertMessage struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	Level   string `json:"level"             dc:"级别[info, warning, error]"`
+	Status  string `json:"status,omitempty"  dc:"告警状态[firing, resolved]，测试消息为空"`
+	Rule    string `json:"rule,omitempty"    dc:"规则名称"`
+	Project string `json:"project,omitempty" dc:"项目名称"`
+	Worker  string `json:"worker,omitempty"  dc:"服务器"`
+	Time    string `json:"time"`
+}
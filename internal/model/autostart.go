@@ -0,0 +1,43 @@
+package model
+
+import "github.com/gogf/gf/v2/os/gtime"
+
+// 自启服务与项目的关联方式
+const (
+	AutostartMatchLinked = "linked" // 项目记录了服务名
+	AutostartMatchName   = "name"   // 旧的命名规则 <项目名>_<端口>
+	AutostartMatchExec   = "exec"   // 启动命令和运行目录
+)
+
+// AutostartState 自启服务的状态，由自启同步定时刷新
+type AutostartState struct {
+	Unit        string      `json:"unit"        dc:"systemd 服务名"`
+	Exists      bool        `json:"exists"      dc:"服务是否存在，为 false 时项目记录的服务已被删除"`
+	Enabled     bool        `json:"enabled"     dc:"是否开机自启"`
+	Active      bool        `json:"active"      dc:"是否正在运行"`
+	Failed      bool        `json:"failed"      dc:"是否处于 failed 状态"`
+	UnitFile    string      `json:"unitFile"    dc:"UnitFileState，如 enabled、disabled"`
+	ActiveState string      `json:"activeState" dc:"ActiveState，如 active、inactive、failed"`
+	SubState    string      `json:"subState"    dc:"SubState，如 running、dead、auto-restart"`
+	CheckedAt   *gtime.Time `json:"checkedAt"   dc:"同步时间"`
+}
+
+// AutostartUnit 本机的一个 autostart-* 服务及其关联的项目
+type AutostartUnit struct {
+	Name      string          `json:"name"      dc:"服务名，不含 autostart- 前缀"`
+	ExecStart string          `json:"execStart" dc:"单元文件中的启动命令"`
+	WorkDir   string          `json:"workDir"   dc:"单元文件中的运行目录"`
+	Managed   bool            `json:"managed"   dc:"是否有 autostart 保存的配置，手动创建的服务为 false"`
+	ProjectId int             `json:"projectId" dc:"关联的项目，0 表示未关联"`
+	Project   string          `json:"project"   dc:"关联的项目名"`
+	Match     string          `json:"match"     dc:"关联方式[linked:已记录, name:命名规则, exec:启动命令和运行目录]"`
+	Candidate []int           `json:"candidate" dc:"未关联时可以认领的项目（启动命令和运行目录匹配）"`
+	State     *AutostartState `json:"state"     dc:"服务状态"`
+}
+
+// AutostartSyncResult 一次自启同步的结果
+type AutostartSyncResult struct {
+	Units    []*AutostartUnit `json:"units"    dc:"本机全部 autostart-* 服务"`
+	Linked   []string         `json:"linked"   dc:"本次新关联的服务"`
+	Orphaned []string         `json:"orphaned" dc:"本次解除关联的服务（项目记录的服务已不存在）"`
+}
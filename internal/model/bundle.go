@@ -0,0 +1,58 @@
+package model
+
+// ProjectBundle 项目定义导出包，用于在服务器之间迁移项目
+type ProjectBundle struct {
+	Version    int                  `json:"version"    yaml:"version"`    // 导出格式版本
+	Worker     string               `json:"worker"     yaml:"worker"`     // 导出来源服务器
+	ExportedAt string               `json:"exportedAt" yaml:"exportedAt"` // 导出时间
+	Projects   []*ProjectDefinition `json:"projects"   yaml:"projects"`   // 项目定义
+}
+
+// ProjectDefinition 与服务器无关的项目定义，不包含 pid、运行状态等运行时信息
+type ProjectDefinition struct {
+	Name           string            `json:"name"                     yaml:"name"`                     // java项目名
+	Ports          string            `json:"ports"                    yaml:"ports"`                    // 运行端口,多个逗号隔开
+	Catalog        string            `json:"catalog"                  yaml:"catalog"`                  // 运行目录
+	Run            string            `json:"run"                      yaml:"run"`                      // 原生启动命令
+	Script         string            `json:"script"                   yaml:"script"`                   // sh脚本启动命令
+	Description    string            `json:"description"              yaml:"description"`              // 项目描述
+	Way            int               `json:"way"                      yaml:"way"`                      // 启动方式[1:docker, 2:jdk]
+	Autostart      int               `json:"autostart"                yaml:"autostart"`                // 自启[0:没有自启, 1:自启]
+	Env            map[string]string `json:"env,omitempty"            yaml:"env,omitempty"`            // 环境变量
+	HealthCheck    string            `json:"healthCheck,omitempty"    yaml:"healthCheck,omitempty"`    // 健康检查地址
+	JvmOpts        *JvmOptions       `json:"jvmOpts,omitempty"        yaml:"jvmOpts,omitempty"`        // JVM参数
+	Args           []string          `json:"args,omitempty"           yaml:"args,omitempty"`           // 程序参数
+	JdkHome        string            `json:"jdkHome,omitempty"        yaml:"jdkHome,omitempty"`        // JDK目录
+	Actuator       string            `json:"actuator,omitempty"       yaml:"actuator,omitempty"`       // Actuator 地址
+	StopStrategy   string            `json:"stopStrategy,omitempty"   yaml:"stopStrategy,omitempty"`   // 停止方式
+	Jolokia        string            `json:"jolokia,omitempty"        yaml:"jolokia,omitempty"`        // Jolokia 地址
+	JolokiaQueries []*JolokiaQuery   `json:"jolokiaQueries,omitempty" yaml:"jolokiaQueries,omitempty"` // MBean 查询
+}
+
+// 导入冲突策略
+const (
+	ImportCreate    = "create"    // 无冲突，新建项目
+	ImportSkip      = "skip"      // 跳过同名项目
+	ImportOverwrite = "overwrite" // 覆盖同名项目的定义
+	ImportRename    = "rename"    // 重命名后作为新项目导入
+)
+
+// ImportItem 单个项目的导入结果
+type ImportItem struct {
+	Name       string `json:"name"              dc:"导入包中的项目名"`
+	TargetName string `json:"targetName"        dc:"导入后的项目名"`
+	Action     string `json:"action"            dc:"动作[create, overwrite, rename, skip]"`
+	ExistingId int    `json:"existingId"        dc:"冲突的已有项目ID"`
+	Message    string `json:"message,omitempty" dc:"说明"`
+}
+
+// ImportResult 导入结果
+type ImportResult struct {
+	Worker   string        `json:"worker"   dc:"目标服务器"`
+	Strategy string        `json:"strategy" dc:"冲突策略"`
+	Preview  bool          `json:"preview"  dc:"是否仅预览"`
+	Created  int           `json:"created"  dc:"新增数"`
+	Updated  int           `json:"updated"  dc:"覆盖数"`
+	Skipped  int           `json:"skipped"  dc:"跳过数"`
+	Items    []*ImportItem `json:"items"    dc:"明细"`
+}
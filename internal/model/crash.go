@@ -0,0 +1,19 @@
+package model
+
+// 进程退出原因，按可信程度从高到低排列
+const (
+	CrashOomKiller     = "oom_killer"     // 被内核 OOM killer 杀死
+	CrashNativeOom     = "native_oom"     // JVM 无法分配本地内存（hs_err）
+	CrashJvmFatal      = "jvm_fatal"      // JVM 致命错误，如 SIGSEGV（hs_err）
+	CrashOutOfMemory   = "out_of_memory"  // 日志中出现 java.lang.OutOfMemoryError
+	CrashStackOverflow = "stack_overflow" // 日志中出现 java.lang.StackOverflowError
+	CrashUnknown       = "unknown"        // 没有找到崩溃线索
+)
+
+// CrashReport 进程意外退出的分析结果
+type CrashReport struct {
+	Reason   string   `json:"reason"             dc:"退出原因[oom_killer, native_oom, jvm_fatal, out_of_memory, stack_overflow, unknown]"`
+	Detail   string   `json:"detail"             dc:"原因说明，如 hs_err 的信号行、OutOfMemoryError 的类型"`
+	File     string   `json:"file,omitempty"     dc:"hs_err_pid 文件"`
+	Evidence []string `json:"evidence,omitempty" dc:"找到的全部线索"`
+}
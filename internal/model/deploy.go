@@ -0,0 +1,16 @@
+package model
+
+// 部署动作
+const (
+	DeployActionDeploy   = "deploy"   // 部署指定制品
+	DeployActionRollback = "rollback" // 回滚到上一个制品
+)
+
+// 部署状态
+const (
+	DeployRunning        = "running"         // 部署中
+	DeploySuccess        = "success"         // 部署成功
+	DeployFailed         = "failed"          // 部署失败，没有可回滚的制品
+	DeployRolledBack     = "rolled_back"     // 部署失败，已回滚到部署前的制品
+	DeployRollbackFailed = "rollback_failed" // 部署失败，回滚也失败
+)
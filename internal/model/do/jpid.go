@@ -10,17 +10,33 @@ import (
 
 // Jpid is the golang structure of table jpid for DAO operations like Where/Data.
 type Jpid struct {
-	g.Meta      `orm:"table:jpid, do:true"`
-	Id          interface{} //
-	Name        interface{} // java项目名
-	Ports       interface{} // 运行端口,多个逗号隔开
-	Pid         interface{} // pid
-	Catalog     interface{} // 运行目录
-	Run         interface{} // 原生启动命令
-	Script      interface{} // sh脚本启动命令
-	Worker      interface{} // 服务器
-	Status      interface{} // 状态[1:启动，0:停止]
-	Description interface{} // 项目描述
-	Way         interface{} // 启动方式[1:docker, 2:jdk]
-	Autostart   interface{} // 自启[0:没有自启, 1:自启]
+	g.Meta         `orm:"table:jpid, do:true"`
+	Id             interface{} //
+	Name           interface{} // java项目名
+	Ports          interface{} // 运行端口,多个逗号隔开
+	Pid            interface{} // pid
+	Catalog        interface{} // 运行目录
+	Run            interface{} // 原生启动命令
+	Script         interface{} // sh脚本启动命令
+	Worker         interface{} // 服务器
+	Status         interface{} // 状态[1:启动，0:停止]
+	Description    interface{} // 项目描述
+	Way            interface{} // 启动方式[1:docker, 2:jdk]
+	Autostart      interface{} // 自启[0:没有自启, 1:自启]
+	Env            interface{} // 环境变量[JSON]
+	HealthCheck    interface{} // 健康检查地址[http(s)://, tcp://]
+	Manifest       interface{} // 声明清单文件[为空表示非清单管理]
+	JvmOpts        interface{} // JVM参数[JSON]
+	Args           interface{} // 程序参数[JSON数组]
+	JdkHome        interface{} // JDK目录[为空使用PATH中的java]
+	JdkId          interface{} // 指定的JDK[jdk.id, 0:未指定]
+	ArtifactId     interface{} // 当前部署的制品[artifact.id, 0:未通过制品部署]
+	BuildVersion   interface{} // 构建版本
+	BuildCommit    interface{} // git 提交号
+	JarChecksum    interface{} // 运行中 jar 的 SHA-256
+	Actuator       interface{} // Actuator 地址[为空时按端口自动探测]
+	StopStrategy   interface{} // 停止方式[signal:信号, actuator:/actuator/shutdown]
+	Jolokia        interface{} // Jolokia 地址[为空不采集 JMX 指标]
+	JolokiaQueries interface{} // MBean 查询[JSON数组, 为空使用 jolokia.queries]
+	UnitName       interface{} // 自启服务名[不含 autostart- 前缀, 为空表示未关联]
 }
@@ -6,18 +6,34 @@ package entity
 
 // Jpid is the golang structure for table jpid.
 type Jpid struct {
-	Id          int    `json:"id"          orm:"id"          description:""`                      //
-	Name        string `json:"name"        orm:"name"        description:"java项目名"`               // java项目名
-	Ports       string `json:"ports"       orm:"ports"       description:"运行端口,多个逗号隔开"`           // 运行端口,多个逗号隔开
-	Pid         int    `json:"pid"         orm:"pid"         description:"pid"`                   // pid
-	Catalog     string `json:"catalog"     orm:"catalog"     description:"运行目录"`                  // 运行目录
-	Run         string `json:"run"         orm:"run"         description:"原生启动命令"`                // 原生启动命令
-	Script      string `json:"script"      orm:"script"      description:"sh脚本启动命令"`              // sh脚本启动命令
-	Worker      string `json:"worker"      orm:"worker"      description:"服务器"`                   // 服务器
-	Status      int    `json:"status"      orm:"status"      description:"状态[1:启动，0:停止]"`         // 状态[1:启动，0:停止]
-	Description string `json:"description" orm:"description" description:"项目描述"`                  // 项目描述
-	Way         int    `json:"way"         orm:"way"         description:"启动方式[1:docker, 2:jdk]"` // 启动方式[1:docker, 2:jdk]
-	Autostart   int    `json:"autostart"   orm:"autostart"   description:"自启[0:没有自启, 1:自启]"`      // 自启[0:没有自启, 1:自启]
+	Id             int    `json:"id"             orm:"id"              description:""`                                             //
+	Name           string `json:"name"           orm:"name"            description:"java项目名"`                                      // java项目名
+	Ports          string `json:"ports"          orm:"ports"           description:"运行端口,多个逗号隔开"`                                  // 运行端口,多个逗号隔开
+	Pid            int    `json:"pid"            orm:"pid"             description:"pid"`                                          // pid
+	Catalog        string `json:"catalog"        orm:"catalog"         description:"运行目录"`                                         // 运行目录
+	Run            string `json:"run"            orm:"run"             description:"原生启动命令"`                                       // 原生启动命令
+	Script         string `json:"script"         orm:"script"          description:"sh脚本启动命令"`                                     // sh脚本启动命令
+	Worker         string `json:"worker"         orm:"worker"          description:"服务器"`                                          // 服务器
+	Status         int    `json:"status"         orm:"status"          description:"状态[1:启动，0:停止]"`                                // 状态[1:启动，0:停止]
+	Description    string `json:"description"    orm:"description"     description:"项目描述"`                                         // 项目描述
+	Way            int    `json:"way"            orm:"way"             description:"启动方式[1:docker, 2:jdk]"`                        // 启动方式[1:docker, 2:jdk]
+	Autostart      int    `json:"autostart"      orm:"autostart"       description:"自启[0:没有自启, 1:自启]"`                             // 自启[0:没有自启, 1:自启]
+	Env            string `json:"env"            orm:"env"             description:"环境变量[JSON]"`                                   // 环境变量[JSON]
+	HealthCheck    string `json:"healthCheck"    orm:"health_check"    description:"健康检查地址[http(s)://, tcp://]"`                   // 健康检查地址[http(s)://, tcp://]
+	Manifest       string `json:"manifest"       orm:"manifest"        description:"声明清单文件[为空表示非清单管理]"`                            // 声明清单文件[为空表示非清单管理]
+	JvmOpts        string `json:"jvmOpts"        orm:"jvm_opts"        description:"JVM参数[JSON]"`                                  // JVM参数[JSON]
+	Args           string `json:"args"           orm:"args"            description:"程序参数[JSON数组]"`                                 // 程序参数[JSON数组]
+	JdkHome        string `json:"jdkHome"        orm:"jdk_home"        description:"JDK目录[为空使用PATH中的java]"`                        // JDK目录[为空使用PATH中的java]
+	JdkId          int    `json:"jdkId"          orm:"jdk_id"          description:"指定的JDK[jdk.id, 0:未指定]"`                        // 指定的JDK[jdk.id, 0:未指定]
+	ArtifactId     int    `json:"artifactId"     orm:"artifact_id"     description:"当前部署的制品[artifact.id, 0:未通过制品部署]"`              // 当前部署的制品[artifact.id, 0:未通过制品部署]
+	BuildVersion   string `json:"buildVersion"   orm:"build_version"   description:"构建版本"`                                         // 构建版本
+	BuildCommit    string `json:"buildCommit"    orm:"build_commit"    description:"git 提交号"`                                      // git 提交号
+	JarChecksum    string `json:"jarChecksum"    orm:"jar_checksum"    description:"运行中 jar 的 SHA-256"`                            // 运行中 jar 的 SHA-256
+	Actuator       string `json:"actuator"       orm:"actuator"        description:"Actuator 地址[为空时按端口自动探测]"`                      // Actuator 地址[为空时按端口自动探测]
+	StopStrategy   string `json:"stopStrategy"   orm:"stop_strategy"   description:"停止方式[signal:信号, actuator:/actuator/shutdown]"` // 停止方式[signal:信号, actuator:/actuator/shutdown]
+	Jolokia        string `json:"jolokia"        orm:"jolokia"         description:"Jolokia 地址[为空不采集 JMX 指标]"`                     // Jolokia 地址[为空不采集 JMX 指标]
+	JolokiaQueries string `json:"jolokiaQueries" orm:"jolokia_queries" description:"MBean 查询[JSON数组, 为空使用 jolokia.queries]"`       // MBean 查询[JSON数组, 为空使用 jolokia.queries]
+	UnitName       string `json:"unitName"       orm:"unit_name"       description:"自启服务名[不含 autostart- 前缀, 为空表示未关联]"`             // 自启服务名[不含 autostart- 前缀, 为空表示未关联]
 }
 
 // ps -ef | grep java
@@ -0,0 +1,31 @@
+package model
+
+// 事件类型
+const (
+	EventProjectStarted    = "project.started"    // 项目进入运行状态
+	EventProjectStopped    = "project.stopped"    // 项目进入停止状态（意外退出时在 project.crashed 之后发送）
+	EventProjectCrashed    = "project.crashed"    // 项目进程意外退出，data 为崩溃分析结果
+	EventProjectRegistered = "project.registered" // 自动注册发现新项目
+	EventProjectPid        = "project.pid"        // 运行中的项目 PID 变化
+	EventProjectAutostart  = "project.autostart"  // 项目自启状态变化
+	EventProjectDeployed   = "project.deployed"   // 制品部署或回滚结束，data 为部署结果
+	EventStreamReset       = "stream.reset"       // 游标早于缓冲区中最早的事件，客户端需要重新拉取全量状态
+)
+
+// ProjectEventTypes 可以订阅的项目事件
+var ProjectEventTypes = []string{
+	EventProjectStarted, EventProjectStopped, EventProjectCrashed, EventProjectRegistered,
+	EventProjectPid, EventProjectAutostart, EventProjectDeployed,
+}
+
+// Event 项目状态变化事件，Id 单调递增，作为断线重连的游标
+type Event struct {
+	Id        int64                  `json:"id"`
+	Type      string                 `json:"type"`
+	Time      string                 `json:"time"`
+	ProjectId int                    `json:"projectId,omitempty"`
+	Name      string                 `json:"name,omitempty"`
+	Worker    string                 `json:"worker,omitempty"`
+	Pid       int                    `json:"pid,omitempty"`
+	Data      map[string]interface{} `json:"data,omitempty"`
+}
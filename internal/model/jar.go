@@ -0,0 +1,21 @@
+package model
+
+// JarInfo jar 文件的构建信息
+type JarInfo struct {
+	Path              string            `json:"path"              dc:"jar 路径"`
+	Checksum          string            `json:"checksum"          dc:"SHA-256"`
+	Size              int64             `json:"size"              dc:"文件大小[字节]"`
+	ModTime           string            `json:"modTime"           dc:"修改时间"`
+	Title             string            `json:"title"             dc:"Implementation-Title"`
+	Version           string            `json:"version"           dc:"构建版本[build-info、Implementation-Version、git.build.version]"`
+	Commit            string            `json:"commit"            dc:"git 提交号"`
+	Branch            string            `json:"branch"            dc:"git 分支"`
+	BuildTime         string            `json:"buildTime"         dc:"构建时间"`
+	MainClass         string            `json:"mainClass"         dc:"Main-Class"`
+	StartClass        string            `json:"startClass"        dc:"Start-Class（Spring Boot）"`
+	SpringBootVersion string            `json:"springBootVersion" dc:"Spring-Boot-Version"`
+	BuildJdk          string            `json:"buildJdk"          dc:"构建使用的 JDK"`
+	Manifest          map[string]string `json:"manifest"          dc:"META-INF/MANIFEST.MF 主属性"`
+	BuildInfo         map[string]string `json:"buildInfo"         dc:"META-INF/build-info.properties"`
+	Git               map[string]string `json:"git"               dc:"git.properties"`
+}
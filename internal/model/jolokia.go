@@ -0,0 +1,9 @@
+package model
+
+// JolokiaQuery 通过 Jolokia 读取的 MBean 属性
+type JolokiaQuery struct {
+	Name      string `json:"name"           yaml:"name"`           // 指标名，如 tomcat.threads.busy
+	Mbean     string `json:"mbean"          yaml:"mbean"`          // MBean，支持通配符，如 Catalina:type=ThreadPool,name=*
+	Attribute string `json:"attribute"      yaml:"attribute"`      // 属性名
+	Path      string `json:"path,omitempty" yaml:"path,omitempty"` // 复合属性中的路径，如 HeapMemoryUsage 的 used
+}
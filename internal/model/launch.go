@@ -0,0 +1,17 @@
+package model
+
+// JvmOptions 结构化的 JVM 参数
+type JvmOptions struct {
+	Xms        string            `json:"xms,omitempty"        yaml:"xms,omitempty"`        // 初始堆内存，如 512m
+	Xmx        string            `json:"xmx,omitempty"        yaml:"xmx,omitempty"`        // 最大堆内存，如 1g
+	Gc         string            `json:"gc,omitempty"         yaml:"gc,omitempty"`         // 垃圾回收器[G1, ZGC, Parallel, Serial]
+	Properties map[string]string `json:"properties,omitempty" yaml:"properties,omitempty"` // 系统属性，生成 -Dkey=value
+	Options    []string          `json:"options,omitempty"    yaml:"options,omitempty"`    // 其他原样追加的 JVM 参数，如 -XX:MaxGCPauseMillis=200
+}
+
+// JavaRuntime 运行中进程实际使用的 JDK
+type JavaRuntime struct {
+	Home    string `json:"home"    dc:"JDK目录"`
+	Version string `json:"version" dc:"Java版本"`
+	Vendor  string `json:"vendor"  dc:"发行厂商"`
+}
@@ -0,0 +1,8 @@
+package model
+
+// 通知级别
+const (
+	NotifyInfo    = "info"
+	NotifyWarning = "warning"
+	NotifyError   = "error"
+)
@@ -0,0 +1,57 @@
+package model
+
+// ProjectManifest 项目声明清单，一个 yaml 文件描述一个项目
+type ProjectManifest struct {
+	Name        string            `json:"name"`        // java项目名（jar 名称或容器名），同一 worker 下唯一
+	Ports       string            `json:"ports"`       // 运行端口,多个逗号隔开
+	Catalog     string            `json:"catalog"`     // 运行目录
+	Run         string            `json:"run"`         // 原生启动命令
+	Script      string            `json:"script"`      // sh脚本启动命令
+	Runtime     string            `json:"runtime"`     // 启动方式[jdk, docker]，默认 jdk
+	Description string            `json:"description"` // 项目描述
+	Env         map[string]string `json:"env"`         // 环境变量
+	JvmOpts     *JvmOptions       `json:"jvmOpts"`     // JVM参数，run 为空时用于组合启动命令
+	Args        []string          `json:"args"`        // 程序参数
+	JdkHome     string            `json:"jdkHome"`     // JDK目录
+	HealthCheck string            `json:"healthCheck"` // 健康检查地址[http(s)://, tcp://]
+	Autostart   bool              `json:"autostart"`   // 是否开机自启
+	State       string            `json:"state"`       // 期望状态[running, stopped]，默认 running
+	File        string            `json:"-"`           // 清单文件路径
+}
+
+// 对账动作
+const (
+	ReconcileCreate    = "create"    // 新建项目记录
+	ReconcileUpdate    = "update"    // 更新项目配置
+	ReconcileStart     = "start"     // 启动项目
+	ReconcileStop      = "stop"      // 停止项目
+	ReconcileRestart   = "restart"   // 配置变更后重启
+	ReconcileAutostart = "autostart" // 调整开机自启
+)
+
+// ReconcileAction 对账动作
+type ReconcileAction struct {
+	Project  string `json:"project"         dc:"项目名"`
+	Manifest string `json:"manifest"        dc:"清单文件"`
+	Action   string `json:"action"          dc:"动作[create, update, start, stop, restart, autostart]"`
+	Detail   string `json:"detail"          dc:"说明"`
+	Error    string `json:"error,omitempty" dc:"执行失败原因"`
+}
+
+// DriftItem 清单与实际状态的差异
+type DriftItem struct {
+	Project  string `json:"project"  dc:"项目名"`
+	Field    string `json:"field"    dc:"字段"`
+	Declared string `json:"declared" dc:"清单中的值"`
+	Actual   string `json:"actual"   dc:"实际值"`
+}
+
+// ReconcilePlan 对账计划
+type ReconcilePlan struct {
+	Dir         string             `json:"dir"         dc:"清单目录"`
+	GeneratedAt string             `json:"generatedAt" dc:"生成时间"`
+	Applied     bool               `json:"applied"     dc:"是否已执行"`
+	Actions     []*ReconcileAction `json:"actions"     dc:"待执行（或已执行）的动作"`
+	Drift       []*DriftItem       `json:"drift"       dc:"清单与实际状态的差异"`
+	Errors      []string           `json:"errors"      dc:"清单解析错误"`
+}
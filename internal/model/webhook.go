@@ -0,0 +1,8 @@
+package model
+
+// webhook 投递状态
+const (
+	DeliveryPending = "pending" // 等待投递或等待重试
+	DeliverySuccess = "success" // 对方返回 2xx
+	DeliveryFailed  = "failed"  // 超过最大重试次数或订阅已删除
+)
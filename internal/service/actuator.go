@@ -0,0 +1,298 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/text/gstr"
+	"omniscient/internal/dao"
+	"omniscient/internal/model"
+	"omniscient/internal/model/do"
+	"omniscient/internal/model/entity"
+	"omniscient/internal/util/actuator"
+	"omniscient/internal/util/system"
+)
+
+const (
+	defaultActuatorBasePath        = "/actuator"
+	defaultActuatorTimeout         = "3s"
+	defaultActuatorShutdownTimeout = "30s"
+)
+
+// defaultActuatorMetrics 未配置 actuator.metrics 时展示的指标
+var defaultActuatorMetrics = []string{
+	"process.uptime",
+	"process.cpu.usage",
+	"system.cpu.usage",
+	"jvm.memory.used",
+	"jvm.memory.max",
+	"jvm.threads.live",
+	"jvm.gc.pause",
+	"http.server.requests",
+}
+
+// actuatorCache 按项目缓存探测到的 actuator 地址，端口变化或访问失败时重新探测
+var actuatorCache sync.Map
+
+type actuatorCacheEntry struct {
+	ports string
+	url   string
+}
+
+type SActuator struct{}
+
+func Actuator() *SActuator {
+	return &SActuator{}
+}
+
+// Resolve 项目的 actuator 地址：优先使用项目配置的地址，否则在项目端口上探测
+// 探测的是本机端口，其他 worker 的项目直接返回错误
+func (s *SActuator) Resolve(ctx context.Context, project *entity.Jpid) (string, error) {
+	if err := checkLocalWorker(project); err != nil {
+		return "", err
+	}
+	if project.Actuator != "" {
+		return strings.TrimRight(project.Actuator, "/"), nil
+	}
+	if cached, ok := actuatorCache.Load(project.Id); ok {
+		if entry := cached.(*actuatorCacheEntry); entry.ports == project.Ports {
+			return entry.url, nil
+		}
+	}
+
+	basePath := g.Cfg().MustGet(ctx, "actuator.basePath", defaultActuatorBasePath).String()
+	url, err := actuator.Detect(ctx, strings.Split(project.Ports, ","), basePath, s.timeout(ctx))
+	if err != nil {
+		return "", err
+	}
+	actuatorCache.Store(project.Id, &actuatorCacheEntry{ports: project.Ports, url: url})
+	return url, nil
+}
+
+// Overview 获取项目的健康状态、应用信息和关键指标，单个端点失败不影响其他端点
+func (s *SActuator) Overview(ctx context.Context, id int) (*model.ActuatorOverview, error) {
+	project, client, err := s.client(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	overview := &model.ActuatorOverview{Url: client.BaseURL, Errors: make(map[string]string)}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	record := func(endpoint string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		overview.Errors[endpoint] = err.Error()
+	}
+
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		health, err := client.Health(ctx)
+		if err != nil {
+			record("health", err)
+			return
+		}
+		overview.Health = health
+	}()
+	go func() {
+		defer wg.Done()
+		info, err := client.Info(ctx)
+		if err != nil {
+			record("info", err)
+			return
+		}
+		overview.Info = info
+	}()
+
+	names := g.Cfg().MustGet(ctx, "actuator.metrics").Strings()
+	if len(names) == 0 {
+		names = defaultActuatorMetrics
+	}
+	metrics := make([]*model.ActuatorMetric, len(names))
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			metric, err := client.Metric(ctx, name)
+			if err != nil {
+				record("metrics/"+name, err)
+				return
+			}
+			metrics[i] = metric
+		}(i, name)
+	}
+	wg.Wait()
+
+	overview.Metrics = make([]*model.ActuatorMetric, 0, len(metrics))
+	for _, metric := range metrics {
+		if metric != nil {
+			overview.Metrics = append(overview.Metrics, metric)
+		}
+	}
+	if overview.Health == nil && overview.Info == nil && len(overview.Metrics) == 0 {
+		// 所有端点都不可用，地址可能已失效，下次重新探测
+		actuatorCache.Delete(project.Id)
+	}
+	if len(overview.Errors) == 0 {
+		overview.Errors = nil
+	}
+	return overview, nil
+}
+
+// Loggers 获取项目的 logger 级别
+func (s *SActuator) Loggers(ctx context.Context, id int) (*model.ActuatorLoggers, error) {
+	project, client, err := s.client(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	loggers, err := client.Loggers(ctx)
+	if err != nil {
+		actuatorCache.Delete(project.Id)
+		return nil, gerror.Wrap(err, "获取 logger 失败")
+	}
+	return loggers, nil
+}
+
+// SetLogger 修改 logger 级别，level 为空时恢复继承上级，返回修改后的 logger
+func (s *SActuator) SetLogger(ctx context.Context, id int, name, level string) (*model.ActuatorLogger, error) {
+	level = strings.ToUpper(strings.TrimSpace(level))
+	if level != "" && !gstr.InArray(model.LoggerLevels, level) {
+		return nil, gerror.Newf("无效的日志级别: %s", level)
+	}
+	_, client, err := s.client(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err = client.SetLogger(ctx, name, level); err != nil {
+		return nil, gerror.Wrapf(err, "修改 logger %s 级别失败", name)
+	}
+
+	loggers, err := client.Loggers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, logger := range loggers.Loggers {
+		if logger.Name == name {
+			return logger, nil
+		}
+	}
+	return &model.ActuatorLogger{Name: name, ConfiguredLevel: level, EffectiveLevel: level}, nil
+}
+
+// Shutdown 通过 /actuator/shutdown 停止项目，并等待进程退出
+func (s *SActuator) Shutdown(ctx context.Context, project *entity.Jpid) error {
+	url, err := s.Resolve(ctx, project)
+	if err != nil {
+		return err
+	}
+	if err = actuator.New(url, s.timeout(ctx)).Shutdown(ctx); err != nil {
+		return err
+	}
+
+	timeout := g.Cfg().MustGet(ctx, "actuator.shutdownTimeout", defaultActuatorShutdownTimeout).Duration()
+	deadline := time.Now().Add(timeout)
+	for Jpid().IsProcessRunning(project.Pid) {
+		if time.Now().After(deadline) {
+			return gerror.Newf("调用 shutdown 后 %s 内进程 %d 未退出", timeout, project.Pid)
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	return nil
+}
+
+// Configure 设置项目的 actuator 地址和停止方式，返回生效的 actuator 地址（无法访问时为空）
+func (s *SActuator) Configure(ctx context.Context, id int, url, stopStrategy string) (string, error) {
+	url = strings.TrimRight(strings.TrimSpace(url), "/")
+	if url != "" && !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return "", gerror.Newf("actuator 地址必须以 http:// 或 https:// 开头: %s", url)
+	}
+	switch stopStrategy {
+	case "", model.StopSignal, model.StopActuator:
+	default:
+		return "", gerror.Newf("不支持的停止方式: %s", stopStrategy)
+	}
+
+	project, err := s.project(ctx, id)
+	if err != nil {
+		return "", err
+	}
+	if stopStrategy == model.StopActuator && project.Way == 1 {
+		return "", gerror.New("docker 项目不支持通过 actuator 停止")
+	}
+	if _, err = dao.Jpid.Ctx(ctx).Data(do.Jpid{Actuator: url, StopStrategy: stopStrategy}).Where("id", id).Update(); err != nil {
+		return "", err
+	}
+	actuatorCache.Delete(id)
+
+	project.Actuator = url
+	resolved, err := s.Resolve(ctx, project)
+	if err != nil {
+		g.Log().Infof(ctx, "项目 %s 的 actuator 暂不可用: %v", project.Name, err)
+		return "", nil
+	}
+	return resolved, nil
+}
+
+// client 获取项目及其 actuator 客户端
+func (s *SActuator) client(ctx context.Context, id int) (*entity.Jpid, *actuator.Client, error) {
+	project, err := s.project(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !Jpid().IsRunning(project) {
+		return nil, nil, gerror.Newf("项目 %s 未运行", project.Name)
+	}
+	url, err := s.Resolve(ctx, project)
+	if err != nil {
+		return nil, nil, err
+	}
+	return project, actuator.New(url, s.timeout(ctx)), nil
+}
+
+func (s *SActuator) project(ctx context.Context, id int) (*entity.Jpid, error) {
+	var project *entity.Jpid
+	if err := dao.Jpid.Ctx(ctx).Where("id", id).Scan(&project); err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, gerror.New("项目不存在")
+	}
+	if err := checkLocalWorker(project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// checkLocalWorker actuator 通过本机地址访问，只能操作当前 worker 的项目，
+// 否则可能访问到本机同端口的其他应用
+func checkLocalWorker(project *entity.Jpid) error {
+	if worker := system.GetWorkerName(); project.Worker != worker {
+		return gerror.Newf("项目 %s 在服务器 %s 上，请在该服务器的 Omniscient 上操作", project.Name, project.Worker)
+	}
+	return nil
+}
+
+func (s *SActuator) timeout(ctx context.Context) time.Duration {
+	return g.Cfg().MustGet(ctx, "actuator.timeout", defaultActuatorTimeout).Duration()
+}
+
+// StopByActuator 项目停止方式为 actuator 时调用 /actuator/shutdown 停止
+// 未配置或停止失败时返回 false，由调用方回退为信号停止
+func (s *SJpid) StopByActuator(ctx context.Context, project *entity.Jpid) bool {
+	if project.StopStrategy != model.StopActuator || project.Way == 1 || project.Pid <= 0 {
+		return false
+	}
+	if err := Actuator().Shutdown(ctx, project); err != nil {
+		g.Log().Warningf(ctx, "项目 %s 通过 actuator 停止失败，改为信号停止: %v", project.Name, err)
+		return false
+	}
+	g.Log().Infof(ctx, "项目 %s 已通过 actuator 停止", project.Name)
+	return true
+}
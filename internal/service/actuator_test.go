@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"omniscient/internal/model"
+	"omniscient/internal/model/entity"
+	"omniscient/internal/util/system"
+)
+
+// actuatorStub 只实现入口和 shutdown 的 actuator，shutdown 返回 status 并调用 onShutdown
+func actuatorStub(t *testing.T, status int, onShutdown func()) (*httptest.Server, *int32) {
+	t.Helper()
+	var calls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/actuator", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"_links":{"shutdown":{"href":"/actuator/shutdown"}}}`))
+	})
+	mux.HandleFunc("/actuator/shutdown", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if onShutdown != nil {
+			onShutdown()
+		}
+		w.WriteHeader(status)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+// startSleep 启动一个代替 Java 项目的进程，测试结束时结束
+func startSleep(t *testing.T) *exec.Cmd {
+	t.Helper()
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("start sleep: %v", err)
+	}
+	done := make(chan struct{})
+	go func() {
+		cmd.Wait()
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		<-done
+	})
+	return cmd
+}
+
+func actuatorProject(pid int, actuatorURL string) *entity.Jpid {
+	return &entity.Jpid{
+		Id: 9101, Name: "demo.jar", Worker: system.GetWorkerName(), Way: 2, Pid: pid,
+		StopStrategy: model.StopActuator, Actuator: actuatorURL,
+	}
+}
+
+func TestStopByActuator(t *testing.T) {
+	cmd := startSleep(t)
+	server, calls := actuatorStub(t, http.StatusOK, func() { cmd.Process.Kill() })
+
+	project := actuatorProject(cmd.Process.Pid, server.URL+"/actuator/")
+	if !Jpid().StopByActuator(context.Background(), project) {
+		t.Fatal("StopByActuator should succeed once the process exits")
+	}
+	if atomic.LoadInt32(calls) != 1 {
+		t.Errorf("shutdown called %d times", *calls)
+	}
+}
+
+func TestStopByActuatorFallback(t *testing.T) {
+	ctx := context.Background()
+	useTestConfig(t, "actuator:\n  shutdownTimeout: 1s\n")
+
+	tests := []struct {
+		name      string
+		status    int
+		project   func(pid int, url string) *entity.Jpid
+		wantCalls int32
+	}{
+		{"shutdown disabled", http.StatusNotFound, actuatorProject, 1},
+		{"process does not exit", http.StatusOK, actuatorProject, 1},
+		{"signal strategy", http.StatusOK, func(pid int, url string) *entity.Jpid {
+			project := actuatorProject(pid, url)
+			project.StopStrategy = model.StopSignal
+			return project
+		}, 0},
+		{"docker project", http.StatusOK, func(pid int, url string) *entity.Jpid {
+			project := actuatorProject(pid, url)
+			project.Way = 1
+			return project
+		}, 0},
+		{"other worker", http.StatusOK, func(pid int, url string) *entity.Jpid {
+			project := actuatorProject(pid, url)
+			project.Worker = "other-worker-1"
+			return project
+		}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := startSleep(t)
+			server, calls := actuatorStub(t, tt.status, nil)
+			project := tt.project(cmd.Process.Pid, server.URL+"/actuator")
+
+			if Jpid().StopByActuator(ctx, project) {
+				t.Fatal("StopByActuator should fall back to signal stop")
+			}
+			if got := atomic.LoadInt32(calls); got != tt.wantCalls {
+				t.Errorf("shutdown called %d times, want %d", got, tt.wantCalls)
+			}
+			if !Jpid().IsProcessRunning(cmd.Process.Pid) {
+				t.Error("process should still be running for the signal stop")
+			}
+		})
+	}
+}
+
+func TestResolveDetectsPort(t *testing.T) {
+	ctx := context.Background()
+	server, _ := actuatorStub(t, http.StatusOK, nil)
+	u, _ := url.Parse(server.URL)
+	project := actuatorProject(0, "")
+	project.Ports = "1," + u.Port()
+	t.Cleanup(func() { actuatorCache.Delete(project.Id) })
+
+	got, err := Actuator().Resolve(ctx, project)
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if want := "http://127.0.0.1:" + u.Port() + "/actuator"; got != want {
+		t.Errorf("Resolve = %q, want %q", got, want)
+	}
+	if cached, ok := actuatorCache.Load(project.Id); !ok || cached.(*actuatorCacheEntry).url != got {
+		t.Errorf("detected address was not cached: %v", cached)
+	}
+
+	// 其他服务器上的项目不能用本机端口访问
+	project.Worker = "other-worker-1"
+	if _, err = Actuator().Resolve(ctx, project); err == nil || !strings.Contains(err.Error(), "other-worker-1") {
+		t.Errorf("Resolve for other worker = %v", err)
+	}
+}
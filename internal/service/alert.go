@@ -0,0 +1,383 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcron"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/text/gstr"
+	"omniscient/internal/dao"
+	"omniscient/internal/model"
+	"omniscient/internal/model/do"
+	"omniscient/internal/model/entity"
+	"omniscient/internal/util/health"
+	"omniscient/internal/util/javaprocess"
+	"omniscient/internal/util/system"
+)
+
+const (
+	defaultAlertInterval    = "30s"
+	defaultAlertEventLimit  = 100
+	defaultRestartLoopCount = 3
+	defaultRestartLoopTime  = 600
+	alertProbeTimeout       = 3 * time.Second
+)
+
+// unhealthySince 项目健康检查开始连续失败的时间（项目ID -> time.Time）
+var unhealthySince sync.Map
+
+type SAlert struct{}
+
+func Alert() *SAlert {
+	return &SAlert{}
+}
+
+// AlertRuleConfig 新增或修改告警规则的参数
+type AlertRuleConfig struct {
+	Id             int     // 为 0 时新增
+	Name           string  // 规则名称
+	Type           string  // 规则类型[stopped, restart_loop, unhealthy, rss, port]
+	JpidId         int     // 项目ID，0 表示全部项目
+	Threshold      float64 // restart_loop: 启动次数，rss: MB
+	Duration       int     // restart_loop: 统计窗口秒数，unhealthy: 连续失败秒数
+	Channels       []int   // 通知渠道，为空发送到全部启用的渠道
+	RepeatInterval int     // 未恢复时重复通知的间隔秒数，0 只通知一次
+	Enabled        bool
+}
+
+// Start 按 alert.interval 定时检查本机项目：先识别意外退出的进程，再评估告警规则
+func (s *SAlert) Start(ctx context.Context) error {
+	interval := g.Cfg().MustGet(ctx, "alert.interval", defaultAlertInterval).String()
+	_, err := gcron.AddSingleton(ctx, "@every "+interval, func(ctx context.Context) {
+		Jpid().DetectExited(ctx)
+		s.Evaluate(ctx)
+	}, "alert-evaluate")
+	if err != nil {
+		return gerror.Wrap(err, "启动告警检查失败")
+	}
+	return nil
+}
+
+// Evaluate 评估全部启用的规则，同一规则和项目未恢复前只保留一个 firing 事件（去重）
+func (s *SAlert) Evaluate(ctx context.Context) {
+	var rules []*entity.AlertRule
+	if err := dao.AlertRule.Ctx(ctx).Where("enabled", 1).Scan(&rules); err != nil {
+		g.Log().Warningf(ctx, "读取告警规则失败: %v", err)
+		return
+	}
+	if len(rules) == 0 {
+		return
+	}
+	var projects []*entity.Jpid
+	if err := dao.Jpid.Ctx(ctx).Where("worker", system.GetWorkerName()).Scan(&projects); err != nil {
+		g.Log().Warningf(ctx, "读取项目失败: %v", err)
+		return
+	}
+
+	for _, rule := range rules {
+		for _, project := range projects {
+			if rule.JpidId != 0 && rule.JpidId != project.Id {
+				continue
+			}
+			firing, message, err := s.check(ctx, rule, project)
+			if err != nil {
+				g.Log().Debugf(ctx, "评估规则 %s 项目 %s 失败: %v", rule.Name, project.Name, err)
+				continue
+			}
+			if err = s.transition(ctx, rule, project, firing, message); err != nil {
+				g.Log().Warningf(ctx, "记录告警 %s 项目 %s 失败: %v", rule.Name, project.Name, err)
+			}
+		}
+	}
+}
+
+// check 判断项目是否满足规则的告警条件
+func (s *SAlert) check(ctx context.Context, rule *entity.AlertRule, project *entity.Jpid) (bool, string, error) {
+	running := Jpid().IsRunning(project)
+	switch rule.Type {
+	case model.AlertStopped:
+		if running {
+			return false, "", nil
+		}
+		var run *entity.JpidRun
+		if err := dao.JpidRun.Ctx(ctx).Where("jpid_id", project.Id).Order("id DESC").Scan(&run); err != nil {
+			return false, "", err
+		}
+		if run == nil || run.ExitReason == "" {
+			return false, "", nil
+		}
+		message := fmt.Sprintf("项目 %s 意外退出（%s），至今未恢复运行", project.Name, crashReasonTitles[run.ExitReason])
+		if run.ExitDetail != "" {
+			message += "\n" + run.ExitDetail
+		}
+		return true, message, nil
+
+	case model.AlertRestartLoop:
+		count, window := int(rule.Threshold), rule.Duration
+		if count <= 0 {
+			count = defaultRestartLoopCount
+		}
+		if window <= 0 {
+			window = defaultRestartLoopTime
+		}
+		since := gtime.Now().Add(-time.Duration(window) * time.Second)
+		started, err := dao.JpidRun.Ctx(ctx).Where("jpid_id", project.Id).WhereGTE("started_at", since).Count()
+		if err != nil {
+			return false, "", err
+		}
+		return started >= count, fmt.Sprintf("项目 %s 在 %s 内启动了 %d 次", project.Name, time.Duration(window)*time.Second, started), nil
+
+	case model.AlertUnhealthy:
+		if !running || project.HealthCheck == "" {
+			unhealthySince.Delete(project.Id)
+			return false, "", nil
+		}
+		err := health.Check(ctx, project.HealthCheck, alertProbeTimeout)
+		if err == nil {
+			unhealthySince.Delete(project.Id)
+			return false, "", nil
+		}
+		value, _ := unhealthySince.LoadOrStore(project.Id, time.Now())
+		elapsed := time.Since(value.(time.Time))
+		if elapsed < time.Duration(rule.Duration)*time.Second {
+			return false, "", nil
+		}
+		return true, fmt.Sprintf("项目 %s 健康检查已连续失败 %s: %v", project.Name, elapsed.Round(time.Second), err), nil
+
+	case model.AlertRss:
+		if !running || project.Pid <= 0 {
+			return false, "", nil
+		}
+		rss, err := javaprocess.ProcessRSS(project.Pid)
+		if err != nil {
+			return false, "", err
+		}
+		mb := float64(rss) / 1024 / 1024
+		return mb > rule.Threshold, fmt.Sprintf("项目 %s 常驻内存 %.0f MB，超过 %.0f MB", project.Name, mb, rule.Threshold), nil
+
+	case model.AlertPort:
+		if !running || project.Ports == "" {
+			return false, "", nil
+		}
+		var closed []string
+		for _, port := range strings.Split(project.Ports, ",") {
+			if port = strings.TrimSpace(port); port == "" {
+				continue
+			}
+			if err := health.Check(ctx, "tcp://127.0.0.1:"+port, alertProbeTimeout); err != nil {
+				closed = append(closed, port)
+			}
+		}
+		return len(closed) > 0, fmt.Sprintf("项目 %s 运行中但端口 %s 无法连接", project.Name, strings.Join(closed, ",")), nil
+	}
+	return false, "", gerror.Newf("不支持的规则类型: %s", rule.Type)
+}
+
+// transition 根据评估结果创建、重复通知或恢复告警事件
+// 静默期间只记录事件不发送，静默结束后未恢复的告警会补发
+func (s *SAlert) transition(ctx context.Context, rule *entity.AlertRule, project *entity.Jpid, firing bool, message string) error {
+	var event *entity.AlertEvent
+	if err := dao.AlertEvent.Ctx(ctx).
+		Where("rule_id", rule.Id).
+		Where("jpid_id", project.Id).
+		Where("worker", project.Worker).
+		Where("status", model.AlertFiring).
+		Order("id DESC").
+		Scan(&event); err != nil {
+		return err
+	}
+	now := gtime.Now()
+	silenced := rule.SilenceUntil != nil && rule.SilenceUntil.After(now)
+
+	if !firing {
+		if event == nil {
+			return nil
+		}
+		if _, err := dao.AlertEvent.Ctx(ctx).
+			Data(do.AlertEvent{Status: model.AlertResolved, ResolvedAt: now}).
+			Where("id", event.Id).
+			Update(); err != nil {
+			return err
+		}
+		if event.NotifiedAt != nil && !silenced {
+			s.notify(ctx, rule, project, model.AlertResolved, "已恢复: "+event.Message)
+		}
+		return nil
+	}
+
+	if event == nil {
+		data := do.AlertEvent{
+			RuleId:    rule.Id,
+			JpidId:    project.Id,
+			Worker:    project.Worker,
+			Status:    model.AlertFiring,
+			Message:   message,
+			StartedAt: now,
+		}
+		if !silenced {
+			data.NotifiedAt = now
+		}
+		if _, err := dao.AlertEvent.Ctx(ctx).Data(data).Insert(); err != nil {
+			return err
+		}
+		if !silenced {
+			s.notify(ctx, rule, project, model.AlertFiring, message)
+		}
+		return nil
+	}
+
+	data := do.AlertEvent{Message: message}
+	due := event.NotifiedAt == nil ||
+		(rule.RepeatInterval > 0 && now.Sub(event.NotifiedAt) >= time.Duration(rule.RepeatInterval)*time.Second)
+	if due && !silenced {
+		data.NotifiedAt = now
+	}
+	if _, err := dao.AlertEvent.Ctx(ctx).Data(data).Where("id", event.Id).Update(); err != nil {
+		return err
+	}
+	if data.NotifiedAt != nil {
+		s.notify(ctx, rule, project, model.AlertFiring, message)
+	}
+	return nil
+}
+
+// notify 发送告警：写入站内通知，并推送到规则的通知渠道
+func (s *SAlert) notify(ctx context.Context, rule *entity.AlertRule, project *entity.Jpid, status, message string) {
+	level, prefix := model.NotifyError, "告警"
+	if status == model.AlertResolved {
+		level, prefix = model.NotifyInfo, "恢复"
+	}
+	title := fmt.Sprintf("[%s] %s - %s", prefix, rule.Name, project.Name)
+	Notification().Notify(ctx, project.Id, level, title, message)
+	s.dispatch(ctx, rule.Channels, &model.AlertMessage{
+		Title:   title,
+		Content: fmt.Sprintf("%s\n服务器: %s", message, project.Worker),
+		Level:   level,
+		Status:  status,
+		Rule:    rule.Name,
+		Project: project.Name,
+		Worker:  project.Worker,
+		Time:    time.Now().Format(time.DateTime),
+	})
+}
+
+// Rules 告警规则列表
+func (s *SAlert) Rules(ctx context.Context) (list []*entity.AlertRule, err error) {
+	err = dao.AlertRule.Ctx(ctx).Order("id ASC").Scan(&list)
+	return
+}
+
+// SaveRule 新增或修改告警规则，返回规则ID
+func (s *SAlert) SaveRule(ctx context.Context, cfg *AlertRuleConfig) (int, error) {
+	cfg.Name = strings.TrimSpace(cfg.Name)
+	if cfg.Name == "" {
+		return 0, gerror.New("规则名称不能为空")
+	}
+	if !gstr.InArray(model.AlertRuleTypes, cfg.Type) {
+		return 0, gerror.Newf("不支持的规则类型: %s", cfg.Type)
+	}
+	if cfg.Type == model.AlertRss && cfg.Threshold <= 0 {
+		return 0, gerror.New("内存告警需要设置阈值（MB）")
+	}
+	if cfg.Threshold < 0 || cfg.Duration < 0 || cfg.RepeatInterval < 0 {
+		return 0, gerror.New("阈值、持续时间和重复间隔不能为负数")
+	}
+	if cfg.JpidId != 0 {
+		count, err := dao.Jpid.Ctx(ctx).Where("id", cfg.JpidId).Count()
+		if err != nil {
+			return 0, err
+		}
+		if count == 0 {
+			return 0, gerror.New("项目不存在")
+		}
+	}
+	channels := make([]string, 0, len(cfg.Channels))
+	if len(cfg.Channels) > 0 {
+		count, err := dao.AlertChannel.Ctx(ctx).WhereIn("id", cfg.Channels).Count()
+		if err != nil {
+			return 0, err
+		}
+		if count != len(cfg.Channels) {
+			return 0, gerror.New("通知渠道不存在")
+		}
+		for _, id := range cfg.Channels {
+			channels = append(channels, fmt.Sprint(id))
+		}
+	}
+
+	data := do.AlertRule{
+		Name:           cfg.Name,
+		Type:           cfg.Type,
+		JpidId:         cfg.JpidId,
+		Threshold:      cfg.Threshold,
+		Duration:       cfg.Duration,
+		Channels:       strings.Join(channels, ","),
+		RepeatInterval: cfg.RepeatInterval,
+		Enabled:        boolToInt(cfg.Enabled),
+	}
+	if cfg.Id == 0 {
+		data.CreatedAt = gtime.Now()
+		id, err := dao.AlertRule.Ctx(ctx).Data(data).InsertAndGetId()
+		return int(id), err
+	}
+	result, err := dao.AlertRule.Ctx(ctx).Data(data).Where("id", cfg.Id).Update()
+	if err != nil {
+		return 0, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return 0, gerror.New("告警规则不存在")
+	}
+	return cfg.Id, nil
+}
+
+// DeleteRule 删除告警规则及其事件
+func (s *SAlert) DeleteRule(ctx context.Context, id int) error {
+	result, err := dao.AlertRule.Ctx(ctx).Where("id", id).Delete()
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return gerror.New("告警规则不存在")
+	}
+	_, err = dao.AlertEvent.Ctx(ctx).Where("rule_id", id).Delete()
+	return err
+}
+
+// Silence 静默规则 minutes 分钟，0 表示取消静默，返回静默截止时间
+func (s *SAlert) Silence(ctx context.Context, id, minutes int) (*gtime.Time, error) {
+	if minutes < 0 {
+		return nil, gerror.New("静默时间不能为负数")
+	}
+	var until *gtime.Time
+	if minutes > 0 {
+		until = gtime.Now().Add(time.Duration(minutes) * time.Minute)
+	}
+	// 取消静默需要写入 NULL，do 结构体会忽略 nil 字段
+	result, err := dao.AlertRule.Ctx(ctx).Data(g.Map{"silence_until": until}).Where("id", id).Update()
+	if err != nil {
+		return nil, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return nil, gerror.New("告警规则不存在")
+	}
+	return until, nil
+}
+
+// Events 告警事件，按时间倒序，status 为空时返回全部
+func (s *SAlert) Events(ctx context.Context, status string, limit int) (list []*entity.AlertEvent, err error) {
+	if limit <= 0 {
+		limit = defaultAlertEventLimit
+	}
+	query := dao.AlertEvent.Ctx(ctx)
+	if status != "" {
+		query = query.Where("status", status)
+	}
+	err = query.Order("id DESC").Limit(limit).Scan(&list)
+	return
+}
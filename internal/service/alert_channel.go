@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/text/gstr"
+	"omniscient/internal/dao"
+	"omniscient/internal/model"
+	"omniscient/internal/model/do"
+	"omniscient/internal/model/entity"
+	"omniscient/internal/util/notify"
+	"omniscient/internal/util/system"
+)
+
+const defaultAlertTimeout = "10s"
+
+// AlertChannelConfig 新增或修改通知渠道的参数
+type AlertChannelConfig struct {
+	Id      int                // 为 0 时新增
+	Name    string             // 渠道名称
+	Type    string             // 渠道类型[webhook, dingtalk, wecom, feishu, email]
+	Url     string             // webhook 或机器人地址
+	Secret  string             // 钉钉、飞书机器人的加签密钥
+	Email   *model.EmailConfig // 邮件配置
+	Enabled bool
+}
+
+// Channels 通知渠道列表，地址中的 token、明文的加签密钥和邮件密码替换为 ******，密钥引用原样返回
+func (s *SAlert) Channels(ctx context.Context) (list []*entity.AlertChannel, err error) {
+	if err = dao.AlertChannel.Ctx(ctx).Order("id ASC").Scan(&list); err != nil {
+		return nil, err
+	}
+	for _, channel := range list {
+		channel.Url = maskChannelUrl(channel.Url)
+		channel.Secret = maskPlaintext(channel.Secret)
+		if channel.Config != "" {
+			var email model.EmailConfig
+			if json.Unmarshal([]byte(channel.Config), &email) == nil {
+				email.Password = maskPlaintext(email.Password)
+				data, _ := json.Marshal(email)
+				channel.Config = string(data)
+			} else {
+				channel.Config = secretMask
+			}
+		}
+	}
+	return
+}
+
+// maskPlaintext 明文值替换为 ******，${secret:NAME} 引用原样返回
+func maskPlaintext(value string) string {
+	if value == "" || len(Secret().Refs(value)) > 0 {
+		return Secret().Mask(value)
+	}
+	return secretMask
+}
+
+// maskChannelUrl 机器人地址的路径和查询参数中带有 token（钉钉 access_token、企业微信 key、飞书 hook 路径），
+// 只保留协议和主机，其余替换为 ******；使用密钥引用的地址原样返回
+func maskChannelUrl(value string) string {
+	if value == "" || len(Secret().Refs(value)) > 0 {
+		return Secret().Mask(value)
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return secretMask
+	}
+	if strings.Trim(u.Path, "/") == "" && u.RawQuery == "" {
+		return value
+	}
+	return u.Scheme + "://" + u.Host + "/" + secretMask
+}
+
+// SaveChannel 新增或修改通知渠道，返回渠道ID
+// 地址、加签密钥和邮件密码都可以使用 ${secret:NAME} 引用密钥；
+// 修改时列表返回的脱敏值（含 ****** 的地址、****** 的密钥和密码）表示保持不变
+func (s *SAlert) SaveChannel(ctx context.Context, cfg *AlertChannelConfig) (int, error) {
+	cfg.Name, cfg.Url, cfg.Secret = strings.TrimSpace(cfg.Name), strings.TrimSpace(cfg.Url), strings.TrimSpace(cfg.Secret)
+	if cfg.Name == "" {
+		return 0, gerror.New("渠道名称不能为空")
+	}
+	if !gstr.InArray(model.AlertChannelTypes, cfg.Type) {
+		return 0, gerror.Newf("不支持的通知渠道: %s", cfg.Type)
+	}
+	if err := s.keepMasked(ctx, cfg); err != nil {
+		return 0, err
+	}
+	var config string
+	if cfg.Type == model.ChannelEmail {
+		email := cfg.Email
+		if email == nil || email.Host == "" || email.From == "" || len(email.To) == 0 {
+			return 0, gerror.New("邮件渠道需要设置 host、from 和 to")
+		}
+		data, _ := json.Marshal(email)
+		config, cfg.Url, cfg.Secret = string(data), "", ""
+	} else if !strings.HasPrefix(cfg.Url, "http://") && !strings.HasPrefix(cfg.Url, "https://") {
+		return 0, gerror.Newf("通知地址必须以 http:// 或 https:// 开头: %s", cfg.Url)
+	}
+	if err := Secret().CheckRefs(ctx, map[string]string{"url": cfg.Url, "secret": cfg.Secret, "config": config}); err != nil {
+		return 0, err
+	}
+
+	count, err := dao.AlertChannel.Ctx(ctx).Where("name", cfg.Name).WhereNot("id", cfg.Id).Count()
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		return 0, gerror.Newf("通知渠道 %s 已存在", cfg.Name)
+	}
+	data := do.AlertChannel{
+		Name:    cfg.Name,
+		Type:    cfg.Type,
+		Url:     cfg.Url,
+		Secret:  cfg.Secret,
+		Config:  config,
+		Enabled: boolToInt(cfg.Enabled),
+	}
+	if cfg.Id == 0 {
+		data.CreatedAt = gtime.Now()
+		id, err := dao.AlertChannel.Ctx(ctx).Data(data).InsertAndGetId()
+		return int(id), err
+	}
+	result, err := dao.AlertChannel.Ctx(ctx).Data(data).Where("id", cfg.Id).Update()
+	if err != nil {
+		return 0, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return 0, gerror.New("通知渠道不存在")
+	}
+	return cfg.Id, nil
+}
+
+// keepMasked 修改渠道时，传回的脱敏值替换为数据库中保存的原值
+func (s *SAlert) keepMasked(ctx context.Context, cfg *AlertChannelConfig) error {
+	masked := strings.Contains(cfg.Url, secretMask) || cfg.Secret == secretMask ||
+		(cfg.Email != nil && cfg.Email.Password == secretMask)
+	if !masked {
+		return nil
+	}
+	var saved *entity.AlertChannel
+	if cfg.Id > 0 {
+		if err := dao.AlertChannel.Ctx(ctx).Where("id", cfg.Id).Scan(&saved); err != nil {
+			return err
+		}
+	}
+	if saved == nil {
+		return gerror.New("新增通知渠道时地址、密钥不能是 ******")
+	}
+	if strings.Contains(cfg.Url, secretMask) {
+		cfg.Url = saved.Url
+	}
+	if cfg.Secret == secretMask {
+		cfg.Secret = saved.Secret
+	}
+	if cfg.Email != nil && cfg.Email.Password == secretMask {
+		var email model.EmailConfig
+		if saved.Config != "" {
+			if err := json.Unmarshal([]byte(saved.Config), &email); err != nil {
+				return gerror.Wrap(err, "邮件配置格式错误")
+			}
+		}
+		cfg.Email.Password = email.Password
+	}
+	return nil
+}
+
+// DeleteChannel 删除通知渠道，规则中引用的渠道ID发送时会被忽略
+func (s *SAlert) DeleteChannel(ctx context.Context, id int) error {
+	result, err := dao.AlertChannel.Ctx(ctx).Where("id", id).Delete()
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return gerror.New("通知渠道不存在")
+	}
+	return nil
+}
+
+// TestChannel 向通知渠道发送一条测试消息，停用的渠道也可以测试
+func (s *SAlert) TestChannel(ctx context.Context, id int) error {
+	var channel *entity.AlertChannel
+	if err := dao.AlertChannel.Ctx(ctx).Where("id", id).Scan(&channel); err != nil {
+		return err
+	}
+	if channel == nil {
+		return gerror.New("通知渠道不存在")
+	}
+	return s.send(ctx, channel, &model.AlertMessage{
+		Title:   "Omniscient 告警测试",
+		Content: "这是一条来自 " + system.GetWorkerName() + " 的测试消息，收到说明通知渠道 " + channel.Name + " 配置正确",
+		Level:   model.NotifyInfo,
+		Worker:  system.GetWorkerName(),
+		Time:    time.Now().Format(time.DateTime),
+	})
+}
+
+// dispatch 将消息发送到规则的通知渠道，channels 为空时发送到全部启用的渠道，单个渠道失败只记录日志
+func (s *SAlert) dispatch(ctx context.Context, channels string, msg *model.AlertMessage) {
+	query := dao.AlertChannel.Ctx(ctx).Where("enabled", 1)
+	if channels = strings.TrimSpace(channels); channels != "" {
+		query = query.WhereIn("id", strings.Split(channels, ","))
+	}
+	var list []*entity.AlertChannel
+	if err := query.Scan(&list); err != nil {
+		g.Log().Warningf(ctx, "读取通知渠道失败: %v", err)
+		return
+	}
+	for _, channel := range list {
+		if err := s.send(ctx, channel, msg); err != nil {
+			g.Log().Warningf(ctx, "发送告警到 %s 失败: %v", channel.Name, err)
+		}
+	}
+}
+
+// send 解析渠道中的密钥引用后发送
+func (s *SAlert) send(ctx context.Context, channel *entity.AlertChannel, msg *model.AlertMessage) error {
+	ch := &notify.Channel{
+		Type:    channel.Type,
+		Timeout: g.Cfg().MustGet(ctx, "alert.timeout", defaultAlertTimeout).Duration(),
+	}
+	var err error
+	if ch.URL, err = Secret().Resolve(ctx, channel.Url); err != nil {
+		return err
+	}
+	if ch.Secret, err = Secret().Resolve(ctx, channel.Secret); err != nil {
+		return err
+	}
+	if channel.Config != "" {
+		if err = json.Unmarshal([]byte(channel.Config), &ch.Email); err != nil {
+			return gerror.Wrap(err, "邮件配置格式错误")
+		}
+		// 先解析 JSON 再替换密钥，密码中的引号等字符不会破坏配置
+		if ch.Email.Username, err = Secret().Resolve(ctx, ch.Email.Username); err != nil {
+			return err
+		}
+		if ch.Email.Password, err = Secret().Resolve(ctx, ch.Email.Password); err != nil {
+			return err
+		}
+	}
+	return notify.Send(ctx, ch, msg)
+}
@@ -0,0 +1,561 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"omniscient/internal/dao"
+	"omniscient/internal/model"
+	"omniscient/internal/model/do"
+	"omniscient/internal/model/entity"
+	"omniscient/internal/util/health"
+)
+
+const (
+	defaultArtifactDir  = "./data/artifacts"
+	defaultArtifactKeep = 10
+	// deployStartupGrace 没有配置健康检查时，启动后观察进程存活的时间
+	deployStartupGrace = 10 * time.Second
+	// artifactModeSymlink 以软链接方式替换 jar，默认复制
+	artifactModeSymlink = "symlink"
+	// artifactUploaderSystem 系统自动保存的制品
+	artifactUploaderSystem = "system"
+	// artifactVersionTimeout 并发上传版本号冲突时重新取号的最长时间
+	artifactVersionTimeout = 30 * time.Second
+	// artifactVersionBackoff 版本号冲突后重新取号前的最长随机等待，错开同时冲突的上传
+	artifactVersionBackoff = 50 * time.Millisecond
+)
+
+// jarMagic jar（zip）文件头
+var jarMagic = []byte("PK\x03\x04")
+
+// deployLocks 每个项目同一时间只允许一个部署
+var deployLocks sync.Map
+
+type SArtifact struct{}
+
+func Artifact() *SArtifact {
+	return &SArtifact{}
+}
+
+// List 获取项目的制品，按版本倒序
+func (s *SArtifact) List(ctx context.Context, projectId int) (list []*entity.Artifact, err error) {
+	err = dao.Artifact.Ctx(ctx).Where("jpid_id", projectId).Order("version DESC").Scan(&list)
+	return
+}
+
+// Get 获取项目的制品
+func (s *SArtifact) Get(ctx context.Context, projectId, id int) (*entity.Artifact, error) {
+	var artifact *entity.Artifact
+	if err := dao.Artifact.Ctx(ctx).Where("id", id).Where("jpid_id", projectId).Scan(&artifact); err != nil {
+		return nil, err
+	}
+	if artifact == nil {
+		return nil, gerror.Newf("制品 %d 不存在", id)
+	}
+	return artifact, nil
+}
+
+// Deployments 获取项目的部署记录，按时间倒序
+func (s *SArtifact) Deployments(ctx context.Context, projectId int) (list []*entity.Deployment, err error) {
+	err = dao.Deployment.Ctx(ctx).Where("jpid_id", projectId).Order("id DESC").Scan(&list)
+	return
+}
+
+// Store 保存上传的 jar 为项目的新版本制品，记录校验和、大小和上传人
+func (s *SArtifact) Store(ctx context.Context, projectId int, fileName string, reader io.Reader, uploader, note string) (*entity.Artifact, error) {
+	project, err := s.project(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	fileName = filepath.Base(fileName)
+	if !strings.HasSuffix(fileName, ".jar") {
+		return nil, gerror.Newf("只支持上传 jar 文件: %s", fileName)
+	}
+
+	// 第一次上传前，先把目录中现有的 jar 保存为 v1，部署失败时可以回滚
+	if uploader != artifactUploaderSystem {
+		count, err := dao.Artifact.Ctx(ctx).Where("jpid_id", project.Id).Count()
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			if _, err = s.capture(ctx, project); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// 先写入临时文件，确定版本号后再移入对应目录
+	root, err := filepath.Abs(filepath.Join(s.dir(ctx), fmt.Sprint(project.Id)))
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(root, 0755); err != nil {
+		return nil, gerror.Wrap(err, "创建制品目录失败")
+	}
+	temp, err := os.CreateTemp(root, ".upload-*")
+	if err != nil {
+		return nil, gerror.Wrap(err, "创建制品临时文件失败")
+	}
+	temp.Close()
+	defer os.Remove(temp.Name())
+	checksum, size, err := writeArtifact(temp.Name(), reader)
+	if err != nil {
+		return nil, err
+	}
+
+	artifact := &entity.Artifact{
+		JpidId:    project.Id,
+		FileName:  fileName,
+		Checksum:  checksum,
+		Size:      size,
+		Uploader:  uploader,
+		Note:      note,
+		CreatedAt: gtime.Now(),
+	}
+	if err = s.insert(ctx, root, artifact); err != nil {
+		return nil, err
+	}
+	dir := filepath.Dir(artifact.Path)
+	if err = os.MkdirAll(dir, 0755); err == nil {
+		err = os.Rename(temp.Name(), artifact.Path)
+	}
+	if err != nil {
+		_, _ = dao.Artifact.Ctx(ctx).Where("id", artifact.Id).Delete()
+		_ = os.RemoveAll(dir)
+		return nil, gerror.Wrap(err, "保存制品失败")
+	}
+	g.Log().Infof(ctx, "项目 %s 上传制品 v%d (%s, %d 字节, sha256:%s)", project.Name, artifact.Version, fileName, size, checksum)
+	return artifact, nil
+}
+
+// Deploy 部署指定制品：停止项目、替换 jar、启动并等待健康检查，失败时自动回滚到部署前的制品
+func (s *SArtifact) Deploy(ctx context.Context, projectId, artifactId int, operator string) (*entity.Deployment, error) {
+	artifact, err := s.Get(ctx, projectId, artifactId)
+	if err != nil {
+		return nil, err
+	}
+	return s.deploy(ctx, projectId, artifact, model.DeployActionDeploy, operator)
+}
+
+// Rollback 回滚到当前制品部署前的制品
+func (s *SArtifact) Rollback(ctx context.Context, projectId int, operator string) (*entity.Deployment, error) {
+	project, err := s.project(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	if project.ArtifactId == 0 {
+		return nil, gerror.New("项目没有通过制品部署，无法回滚")
+	}
+
+	var current *entity.Deployment
+	if err = dao.Deployment.Ctx(ctx).
+		Where("jpid_id", projectId).
+		Where("artifact_id", project.ArtifactId).
+		Where("status", model.DeploySuccess).
+		Order("id DESC").
+		Scan(&current); err != nil {
+		return nil, err
+	}
+	if current == nil || current.PreviousId == 0 {
+		return nil, gerror.New("没有可回滚的制品")
+	}
+
+	artifact, err := s.Get(ctx, projectId, current.PreviousId)
+	if err != nil {
+		return nil, gerror.Wrap(err, "上一个制品已被清理，无法回滚")
+	}
+	return s.deploy(ctx, projectId, artifact, model.DeployActionRollback, operator)
+}
+
+// deploy 执行部署并记录部署历史
+func (s *SArtifact) deploy(ctx context.Context, projectId int, artifact *entity.Artifact, action, operator string) (*entity.Deployment, error) {
+	value, _ := deployLocks.LoadOrStore(projectId, &sync.Mutex{})
+	lock := value.(*sync.Mutex)
+	if !lock.TryLock() {
+		return nil, gerror.New("项目正在部署中，请稍后再试")
+	}
+	defer lock.Unlock()
+
+	// 部署过程不随请求断开而中断，避免停在半部署状态
+	ctx = context.WithoutCancel(ctx)
+
+	project, err := s.project(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	if project.Way == 1 {
+		return nil, gerror.New("docker 项目不支持制品部署")
+	}
+	if project.Catalog == "" || !strings.HasSuffix(project.Name, ".jar") {
+		return nil, gerror.New("项目需要设置运行目录，且项目名为 jar 文件名")
+	}
+
+	// 没有通过制品部署过的项目，以目录中现有 jar 对应的制品作为回滚目标
+	previousId := project.ArtifactId
+	if previousId == 0 {
+		if previous, err := s.current(ctx, project); err != nil {
+			return nil, err
+		} else if previous != nil {
+			previousId = previous.Id
+		}
+	}
+
+	deployment := &entity.Deployment{
+		JpidId:     project.Id,
+		ArtifactId: artifact.Id,
+		Version:    artifact.Version,
+		PreviousId: previousId,
+		Action:     action,
+		Status:     model.DeployRunning,
+		Operator:   operator,
+		StartedAt:  gtime.Now(),
+	}
+	id, err := dao.Deployment.Ctx(ctx).Data(do.Deployment{
+		JpidId:     deployment.JpidId,
+		ArtifactId: deployment.ArtifactId,
+		Version:    deployment.Version,
+		PreviousId: deployment.PreviousId,
+		Action:     deployment.Action,
+		Status:     deployment.Status,
+		Operator:   deployment.Operator,
+		StartedAt:  deployment.StartedAt,
+	}).InsertAndGetId()
+	if err != nil {
+		return nil, err
+	}
+	deployment.Id = int(id)
+	g.Log().Infof(ctx, "项目 %s 开始部署制品 v%d（部署记录 #%d）", project.Name, artifact.Version, deployment.Id)
+
+	current := artifact.Id
+	deployErr := s.switchTo(ctx, project, artifact)
+	switch {
+	case deployErr == nil:
+		deployment.Status = model.DeploySuccess
+		deployment.Message = fmt.Sprintf("已部署 v%d", artifact.Version)
+	case previousId == 0:
+		deployment.Status = model.DeployFailed
+		deployment.Message = deployErr.Error()
+		current = 0
+	default:
+		g.Log().Warningf(ctx, "项目 %s 部署 v%d 失败，开始回滚: %v", project.Name, artifact.Version, deployErr)
+		previous, err := s.Get(ctx, projectId, previousId)
+		if err == nil {
+			err = s.switchTo(ctx, project, previous)
+		}
+		if err != nil {
+			deployment.Status = model.DeployRollbackFailed
+			deployment.Message = fmt.Sprintf("部署失败: %v；回滚失败: %v", deployErr, err)
+			current = 0
+		} else {
+			deployment.Status = model.DeployRolledBack
+			deployment.Message = fmt.Sprintf("部署失败: %v；已回滚到 v%d", deployErr, previous.Version)
+			current = previous.Id
+		}
+	}
+
+	deployment.FinishedAt = gtime.Now()
+	if _, err = dao.Deployment.Ctx(ctx).Data(do.Deployment{
+		Status:     deployment.Status,
+		Message:    deployment.Message,
+		FinishedAt: deployment.FinishedAt,
+	}).Where("id", deployment.Id).Update(); err != nil {
+		return nil, err
+	}
+	if _, err = dao.Jpid.Ctx(ctx).Data(do.Jpid{ArtifactId: current}).Where("id", project.Id).Update(); err != nil {
+		return nil, err
+	}
+	if latest, err := s.project(ctx, project.Id); err == nil {
+		project = latest
+	}
+	Event().Publish(ctx, model.EventProjectDeployed, project, map[string]interface{}{
+		"deploymentId": deployment.Id,
+		"action":       action,
+		"status":       deployment.Status,
+		"message":      deployment.Message,
+		"artifactId":   artifact.Id,
+		"version":      artifact.Version,
+	})
+
+	if deployment.Status != model.DeploySuccess {
+		g.Log().Errorf(ctx, "项目 %s 部署记录 #%d: %s", project.Name, deployment.Id, deployment.Message)
+		return deployment, gerror.Newf("部署失败（部署记录 #%d，状态 %s）: %s", deployment.Id, deployment.Status, deployment.Message)
+	}
+	g.Log().Infof(ctx, "项目 %s 部署 v%d 成功（部署记录 #%d）", project.Name, artifact.Version, deployment.Id)
+	s.prune(ctx, project.Id, artifact.Id, previousId)
+	return deployment, nil
+}
+
+// switchTo 停止项目，替换为指定制品后启动，并等待健康检查通过
+func (s *SArtifact) switchTo(ctx context.Context, project *entity.Jpid, artifact *entity.Artifact) error {
+	// 重新读取，获取最新的 pid 和状态
+	project, err := s.project(ctx, project.Id)
+	if err != nil {
+		return err
+	}
+	if Jpid().IsRunning(project) {
+		if err = Jpid().Shutdown(ctx, project); err != nil {
+			return gerror.Wrap(err, "停止项目失败")
+		}
+	}
+	if err = s.place(ctx, artifact, filepath.Join(project.Catalog, project.Name)); err != nil {
+		return err
+	}
+
+	pid, err := Jpid().Launch(ctx, project)
+	if err != nil {
+		return err
+	}
+
+	if project.HealthCheck != "" {
+		timeout := g.Cfg().MustGet(ctx, "artifact.healthTimeout", defaultHealthTimeout).Duration()
+		return health.Wait(ctx, project.HealthCheck, timeout)
+	}
+	time.Sleep(deployStartupGrace)
+	if !Jpid().IsProcessRunning(pid) {
+		return gerror.Newf("项目启动后 %s 内退出，请查看 %s", deployStartupGrace, filepath.Join(project.Catalog, "nohup.log"))
+	}
+	return nil
+}
+
+// place 将制品放到项目目录，先写入临时文件再重命名，保证替换是原子的
+func (s *SArtifact) place(ctx context.Context, artifact *entity.Artifact, target string) error {
+	source, err := filepath.Abs(artifact.Path)
+	if err != nil {
+		return err
+	}
+	if _, err = os.Stat(source); err != nil {
+		return gerror.Wrapf(err, "制品文件不存在: %s", source)
+	}
+
+	tmp := target + ".deploying"
+	_ = os.Remove(tmp)
+	if g.Cfg().MustGet(ctx, "artifact.mode").String() == artifactModeSymlink {
+		err = os.Symlink(source, tmp)
+	} else {
+		err = copyFile(source, tmp)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return gerror.Wrap(err, "替换 jar 失败")
+	}
+	if err = os.Rename(tmp, target); err != nil {
+		_ = os.Remove(tmp)
+		return gerror.Wrap(err, "替换 jar 失败")
+	}
+	return nil
+}
+
+// capture 将项目目录中现有的 jar 保存为制品，不存在时返回 nil
+func (s *SArtifact) capture(ctx context.Context, project *entity.Jpid) (*entity.Artifact, error) {
+	file, err := os.Open(filepath.Join(project.Catalog, project.Name))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return s.Store(ctx, project.Id, project.Name, file, artifactUploaderSystem, "首次上传前目录中的 jar")
+}
+
+// current 按校验和查找项目目录中现有 jar 对应的制品，没有对应制品时保存为新制品
+func (s *SArtifact) current(ctx context.Context, project *entity.Jpid) (*entity.Artifact, error) {
+	checksum, err := fileChecksum(filepath.Join(project.Catalog, project.Name))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var artifact *entity.Artifact
+	if err = dao.Artifact.Ctx(ctx).
+		Where("jpid_id", project.Id).
+		Where("checksum", checksum).
+		Order("version DESC").
+		Scan(&artifact); err != nil || artifact != nil {
+		return artifact, err
+	}
+	return s.capture(ctx, project)
+}
+
+// prune 按 artifact.keep 清理旧制品，当前和上一个制品始终保留
+func (s *SArtifact) prune(ctx context.Context, projectId int, keepIds ...int) {
+	keep := g.Cfg().MustGet(ctx, "artifact.keep", defaultArtifactKeep).Int()
+	if keep <= 0 {
+		return
+	}
+	list, err := s.List(ctx, projectId)
+	if err != nil || len(list) <= keep {
+		return
+	}
+
+	protected := make(map[int]bool)
+	for _, id := range keepIds {
+		protected[id] = true
+	}
+	for _, artifact := range list[keep:] {
+		if protected[artifact.Id] {
+			continue
+		}
+		if _, err = dao.Artifact.Ctx(ctx).Where("id", artifact.Id).Delete(); err != nil {
+			g.Log().Warningf(ctx, "清理制品 v%d 失败: %v", artifact.Version, err)
+			continue
+		}
+		_ = os.RemoveAll(filepath.Dir(artifact.Path))
+		g.Log().Infof(ctx, "已清理项目 %d 的旧制品 v%d", projectId, artifact.Version)
+	}
+}
+
+// project 获取项目
+func (s *SArtifact) project(ctx context.Context, id int) (*entity.Jpid, error) {
+	var project *entity.Jpid
+	if err := dao.Jpid.Ctx(ctx).Where("id", id).Scan(&project); err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, gerror.New("项目不存在")
+	}
+	return project, nil
+}
+
+// nextVersion 项目的下一个制品版本号
+func (s *SArtifact) nextVersion(ctx context.Context, projectId int) (int, error) {
+	value, err := dao.Artifact.Ctx(ctx).Where("jpid_id", projectId).Max("version")
+	if err != nil {
+		return 0, err
+	}
+	return int(value) + 1, nil
+}
+
+// insert 以项目的下一个版本号写入制品记录
+// 并发上传时版本号由 (jpid_id, version) 唯一索引保证不重复，冲突时随机等待后重新取号，直到 artifactVersionTimeout
+func (s *SArtifact) insert(ctx context.Context, root string, artifact *entity.Artifact) error {
+	deadline := time.Now().Add(artifactVersionTimeout)
+	for {
+		version, err := s.nextVersion(ctx, artifact.JpidId)
+		if err != nil {
+			return err
+		}
+		artifact.Version = version
+		artifact.Path = filepath.Join(root, fmt.Sprintf("v%d", version), artifact.FileName)
+		id, err := dao.Artifact.Ctx(ctx).Data(do.Artifact{
+			JpidId:    artifact.JpidId,
+			Version:   artifact.Version,
+			FileName:  artifact.FileName,
+			Path:      artifact.Path,
+			Checksum:  artifact.Checksum,
+			Size:      artifact.Size,
+			Uploader:  artifact.Uploader,
+			Note:      artifact.Note,
+			CreatedAt: artifact.CreatedAt,
+		}).InsertAndGetId()
+		if err == nil {
+			artifact.Id = int(id)
+			return nil
+		}
+		if !isUniqueViolation(err) {
+			return err
+		}
+		if time.Now().After(deadline) {
+			return gerror.Wrap(err, "并发上传过多，分配制品版本号超时")
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(rand.Int63n(int64(artifactVersionBackoff)))):
+		}
+	}
+}
+
+// dir 制品存储目录
+func (s *SArtifact) dir(ctx context.Context) string {
+	return g.Cfg().MustGet(ctx, "artifact.dir", defaultArtifactDir).String()
+}
+
+// writeArtifact 写入制品文件，返回 SHA-256 和大小，并校验是 jar（zip）文件
+func writeArtifact(path string, reader io.Reader) (string, int64, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return "", 0, gerror.Wrap(err, "保存制品失败")
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	header := &headerWriter{limit: len(jarMagic)}
+	size, err := io.Copy(io.MultiWriter(file, hash, header), reader)
+	if err != nil {
+		return "", 0, gerror.Wrap(err, "保存制品失败")
+	}
+	if !bytes.Equal(header.data, jarMagic) {
+		return "", 0, gerror.New("上传的文件不是有效的 jar 文件")
+	}
+	if err = file.Sync(); err != nil {
+		return "", 0, gerror.Wrap(err, "保存制品失败")
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
+
+// fileChecksum 计算文件的 SHA-256
+func fileChecksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// headerWriter 记录写入内容的前 limit 个字节
+type headerWriter struct {
+	limit int
+	data  []byte
+}
+
+func (w *headerWriter) Write(p []byte) (int, error) {
+	if remain := w.limit - len(w.data); remain > 0 {
+		if len(p) < remain {
+			remain = len(p)
+		}
+		w.data = append(w.data, p[:remain]...)
+	}
+	return len(p), nil
+}
+
+// copyFile 复制文件
+func copyFile(source, target string) error {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
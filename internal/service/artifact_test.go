@@ -0,0 +1,77 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+
+	"omniscient/internal/dao"
+	"omniscient/internal/model/do"
+)
+
+func TestStoreConcurrentVersions(t *testing.T) {
+	ctx := context.Background()
+	useTestDB(t)
+	useTestConfig(t, "artifact:\n  dir: "+t.TempDir()+"\n")
+	id, err := dao.Jpid.Ctx(ctx).Data(do.Jpid{Name: "demo.jar", Ports: "", Pid: 0, Catalog: t.TempDir(), Worker: "test-worker", Way: 2}).InsertAndGetId()
+	if err != nil {
+		t.Fatalf("insert project: %v", err)
+	}
+	t.Cleanup(func() {
+		dao.Jpid.Ctx(ctx).Where("id", id).Delete()
+		dao.Artifact.Ctx(ctx).Where("jpid_id", id).Delete()
+	})
+
+	// 上传数明显多于同时冲突时一轮能成功的数量，每个上传都要多次重新取号
+	const uploads = 16
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		versions []int
+	)
+	for i := 0; i < uploads; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			jar := append([]byte("PK\x03\x04"), bytes.Repeat([]byte{byte(i)}, 16)...)
+			artifact, err := Artifact().Store(ctx, int(id), "demo.jar", bytes.NewReader(jar), "tester", "")
+			if err != nil {
+				t.Errorf("Store %d: %v", i, err)
+				return
+			}
+			mu.Lock()
+			versions = append(versions, artifact.Version)
+			mu.Unlock()
+		}(i)
+	}
+	wg.Wait()
+
+	want := make([]int, uploads)
+	for i := range want {
+		want[i] = i + 1
+	}
+	sort.Ints(versions)
+	if fmt.Sprint(versions) != fmt.Sprint(want) {
+		t.Fatalf("returned versions = %v, want %v", versions, want)
+	}
+
+	list, err := Artifact().List(ctx, int(id))
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var saved []int
+	for _, artifact := range list {
+		saved = append(saved, artifact.Version)
+		checksum, err := fileChecksum(artifact.Path)
+		if err != nil || checksum != artifact.Checksum {
+			t.Errorf("v%d file %s checksum = %s, %v, want %s", artifact.Version, artifact.Path, checksum, err, artifact.Checksum)
+		}
+	}
+	sort.Ints(saved)
+	if fmt.Sprint(saved) != fmt.Sprint(want) {
+		t.Errorf("saved versions = %v, want %v", saved, want)
+	}
+}
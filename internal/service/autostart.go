@@ -0,0 +1,452 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"autostart/pkg/autostart"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcron"
+	"github.com/gogf/gf/v2/os/gtime"
+	"omniscient/internal/dao"
+	"omniscient/internal/model"
+	"omniscient/internal/model/entity"
+	"omniscient/internal/util/system"
+)
+
+const defaultAutostartSyncInterval = "60s"
+
+type SAutostart struct{}
+
+func Autostart() *SAutostart {
+	return &SAutostart{}
+}
+
+var (
+	// autostartStates 项目自启服务最近一次同步的状态，按项目 ID
+	autostartStates   = map[int]*model.AutostartState{}
+	autostartStatesMu sync.RWMutex
+)
+
+// Start 按 autostart.syncInterval 定时同步本机 autostart-* 服务与项目的关联和状态，系统不支持 systemd 时不启用
+func (s *SAutostart) Start(ctx context.Context) error {
+	manager, err := autostartManager()
+	if err == nil {
+		err = manager.CheckSystem()
+	}
+	if err != nil {
+		g.Log().Debugf(ctx, "当前系统不支持自启服务，不同步自启状态: %v", err)
+		return nil
+	}
+
+	interval := g.Cfg().MustGet(ctx, "autostart.syncInterval", defaultAutostartSyncInterval).String()
+	_, err = gcron.AddSingleton(ctx, "@every "+interval, func(ctx context.Context) {
+		s.run(ctx)
+	}, "autostart-sync")
+	if err != nil {
+		return gerror.Wrap(err, "启动自启同步失败")
+	}
+	go s.run(ctx)
+	return nil
+}
+
+func (s *SAutostart) run(ctx context.Context) {
+	result, err := s.Sync(ctx)
+	if err != nil {
+		g.Log().Warningf(ctx, "自启同步失败: %v", err)
+		return
+	}
+	for _, name := range result.Linked {
+		g.Log().Infof(ctx, "自启服务 %s 已关联项目", autostart.UnitName(name))
+	}
+	for _, name := range result.Orphaned {
+		g.Log().Warningf(ctx, "项目关联的自启服务 %s 已不存在，解除关联", autostart.UnitName(name))
+	}
+}
+
+// State 项目自启服务最近一次同步的状态，没有关联服务或还未同步时返回 nil
+func (s *SAutostart) State(projectId int) *model.AutostartState {
+	autostartStatesMu.RLock()
+	defer autostartStatesMu.RUnlock()
+	return autostartStates[projectId]
+}
+
+// Units 本机全部 autostart-* 服务及其关联的项目，只读取不修改项目
+func (s *SAutostart) Units(ctx context.Context) ([]*model.AutostartUnit, error) {
+	manager, projects, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	units, err := s.inspect(ctx, manager)
+	if err != nil {
+		return nil, err
+	}
+	s.match(units, projects)
+	return units, nil
+}
+
+// Sync 读取本机全部 autostart-* 服务，按记录的服务名、旧的命名规则、启动命令和运行目录关联项目，
+// 更新项目的服务名和自启状态，并刷新项目列表展示的服务状态
+// 启动命令和运行目录只有一一对应时才自动关联，其余的需要认领（Adopt）
+// 只清除在当前管理范围内确认不存在服务的项目，服务在其他范围的项目保持不变
+func (s *SAutostart) Sync(ctx context.Context) (*model.AutostartSyncResult, error) {
+	manager, projects, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	units, err := s.inspect(ctx, manager)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.AutostartSyncResult{Units: units}
+	byName := make(map[string]*model.AutostartUnit, len(units))
+	for _, unit := range units {
+		byName[unit.Name] = unit
+	}
+	// 服务在另一个管理范围（非 root 运行时看不到的系统服务）中的项目，不改动关联和自启状态
+	elsewhere := make(map[int]bool)
+	for _, project := range projects {
+		if project.UnitName != "" && byName[project.UnitName] != nil {
+			continue
+		}
+		if otherScopeExists(manager, project.UnitName, legacyAutostartName(project)) {
+			elsewhere[project.Id] = true
+			continue
+		}
+		if project.UnitName != "" {
+			result.Orphaned = append(result.Orphaned, project.UnitName)
+			project.UnitName = ""
+		}
+	}
+
+	s.match(units, projects)
+	states := make(map[int]*model.AutostartState, len(units))
+	for _, unit := range units {
+		if unit.ProjectId != 0 {
+			states[unit.ProjectId] = unit.State
+		}
+	}
+	for _, project := range projects {
+		state := states[project.Id]
+		if state == nil && elsewhere[project.Id] {
+			continue
+		}
+		if state == nil {
+			// 没有服务的项目不是自启
+			s.save(ctx, project, nil)
+			continue
+		}
+		if name := strings.TrimPrefix(state.Unit, autostart.UnitPrefix); project.UnitName != name {
+			result.Linked = append(result.Linked, name)
+			project.UnitName = name
+		}
+		s.save(ctx, project, state)
+	}
+
+	autostartStatesMu.Lock()
+	autostartStates = states
+	autostartStatesMu.Unlock()
+	return result, nil
+}
+
+// Adopt 认领手动创建或没有自动关联的服务，项目之前关联的服务保留不动
+func (s *SAutostart) Adopt(ctx context.Context, projectId int, name string) (*model.AutostartState, error) {
+	project, err := s.project(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	name = strings.TrimSuffix(strings.TrimPrefix(name, autostart.UnitPrefix), ".service")
+	manager, err := autostartManager()
+	if err != nil {
+		return nil, gerror.Wrap(err, "当前系统不支持自启服务")
+	}
+	if !manager.Exists(name) {
+		return nil, gerror.Newf("自启服务不存在: %s", autostart.UnitName(name))
+	}
+
+	var owner *entity.Jpid
+	if err = dao.Jpid.Ctx(ctx).
+		Where("worker", project.Worker).
+		Where("unit_name", name).
+		WhereNot("id", project.Id).
+		Scan(&owner); err != nil {
+		return nil, err
+	}
+	if owner != nil {
+		return nil, gerror.Newf("自启服务 %s 已关联项目 %s", autostart.UnitName(name), owner.Name)
+	}
+
+	project.UnitName = name
+	state := s.refresh(ctx, manager, project.Id, name)
+	s.save(ctx, project, state)
+	return state, nil
+}
+
+// Regenerate 按项目当前的启动命令、运行目录和环境变量重新生成自启服务
+// 已有服务只重写单元文件，自启状态不变，正在运行的服务重启后生效；没有服务时创建并启用
+func (s *SAutostart) Regenerate(ctx context.Context, projectId int) (*model.AutostartState, error) {
+	project, err := s.project(ctx, projectId)
+	if err != nil {
+		return nil, err
+	}
+	manager, err := autostartManager()
+	if err == nil {
+		err = manager.CheckSystem()
+	}
+	if err != nil {
+		return nil, gerror.Wrap(err, "当前系统不支持自启服务")
+	}
+
+	name := autostartName(project)
+	cfg, err := Jpid().autostartConfig(ctx, project, name)
+	if err != nil {
+		return nil, err
+	}
+	if manager.Exists(name) {
+		// 保留原配置中项目没有的设置（重启策略、资源限制等），只更新启动相关的字段
+		if old, err := manager.Config(name); err == nil {
+			old.ExecStart, old.WorkDir, old.Description, old.Env = cfg.ExecStart, cfg.WorkDir, cfg.Description, cfg.Env
+			cfg = old
+		}
+		if err = manager.Update(ctx, cfg); err != nil {
+			return nil, gerror.Wrap(err, "重新生成自启服务失败")
+		}
+	} else {
+		if err = manager.Add(ctx, cfg); err != nil {
+			return nil, gerror.Wrap(err, "注册自启服务失败")
+		}
+		if err = manager.Enable(ctx, name); err != nil {
+			return nil, gerror.Wrap(err, "启用自启服务失败")
+		}
+	}
+
+	project.UnitName = name
+	state := s.refresh(ctx, manager, project.Id, name)
+	s.save(ctx, project, state)
+	return state, nil
+}
+
+// load 服务管理器和本机的非 docker 项目
+func (s *SAutostart) load(ctx context.Context) (*autostart.ServiceManager, []*entity.Jpid, error) {
+	manager, err := autostartManager()
+	if err == nil {
+		err = manager.CheckSystem()
+	}
+	if err != nil {
+		return nil, nil, gerror.Wrap(err, "当前系统不支持自启服务")
+	}
+	var projects []*entity.Jpid
+	if err = dao.Jpid.Ctx(ctx).
+		Where("worker", system.GetWorkerName()).
+		WhereNot("way", 1).
+		Order("id ASC").
+		Scan(&projects); err != nil {
+		return nil, nil, err
+	}
+	return manager, projects, nil
+}
+
+// project 读取本机的项目，docker 项目没有自启服务
+func (s *SAutostart) project(ctx context.Context, id int) (*entity.Jpid, error) {
+	var project *entity.Jpid
+	if err := dao.Jpid.Ctx(ctx).Where("id", id).Scan(&project); err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, gerror.New("项目不存在")
+	}
+	if project.Way == 1 {
+		return nil, gerror.New("docker 方式运行的项目不支持设置自启动")
+	}
+	if err := checkLocalWorker(project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// inspect 读取全部 autostart-* 服务的启动命令、运行目录和状态
+func (s *SAutostart) inspect(ctx context.Context, manager *autostart.ServiceManager) ([]*model.AutostartUnit, error) {
+	names, err := manager.Names()
+	if err != nil {
+		return nil, gerror.Wrap(err, "读取自启服务失败")
+	}
+	units := make([]*model.AutostartUnit, 0, len(names))
+	for _, name := range names {
+		unit := &model.AutostartUnit{Name: name, Candidate: []int{}}
+		if unit.ExecStart, unit.WorkDir, err = manager.UnitExec(name); err != nil {
+			// 读取期间被删除
+			continue
+		}
+		_, err = manager.Config(name)
+		unit.Managed = err == nil
+		unit.State = autostartState(ctx, manager, name)
+		units = append(units, unit)
+	}
+	return units, nil
+}
+
+// match 关联服务和项目：项目记录的服务名，然后是旧的命名规则，最后是一一对应的启动命令和运行目录
+func (s *SAutostart) match(units []*model.AutostartUnit, projects []*entity.Jpid) {
+	byName := make(map[string]*model.AutostartUnit, len(units))
+	for _, unit := range units {
+		byName[unit.Name] = unit
+	}
+	linked := make(map[int]bool, len(projects))
+	link := func(unit *model.AutostartUnit, project *entity.Jpid, match string) {
+		unit.ProjectId, unit.Project, unit.Match = project.Id, project.Name, match
+		linked[project.Id] = true
+	}
+
+	for _, project := range projects {
+		if unit := byName[project.UnitName]; project.UnitName != "" && unit != nil && unit.ProjectId == 0 {
+			link(unit, project, model.AutostartMatchLinked)
+		}
+	}
+	for _, project := range projects {
+		if unit := byName[legacyAutostartName(project)]; project.UnitName == "" && unit != nil && unit.ProjectId == 0 {
+			link(unit, project, model.AutostartMatchName)
+		}
+	}
+
+	// 启动命令和运行目录，一个服务只匹配一个项目、该项目也只匹配这个服务时自动关联
+	matches := make(map[int]int, len(projects))
+	for _, unit := range units {
+		if unit.ProjectId != 0 {
+			continue
+		}
+		for _, project := range projects {
+			if !linked[project.Id] && project.UnitName == "" && matchExec(unit, project) {
+				unit.Candidate = append(unit.Candidate, project.Id)
+				matches[project.Id]++
+			}
+		}
+	}
+	for _, unit := range units {
+		if unit.ProjectId != 0 || len(unit.Candidate) != 1 || matches[unit.Candidate[0]] != 1 {
+			continue
+		}
+		for _, project := range projects {
+			if project.Id == unit.Candidate[0] {
+				link(unit, project, model.AutostartMatchExec)
+				unit.Candidate = []int{}
+				break
+			}
+		}
+	}
+}
+
+// save 更新项目的服务名，以及按服务是否开机自启更新自启状态，state 为 nil 表示没有服务
+// project.UnitName 为新的服务名，与数据库中相同且自启状态不变时不更新
+func (s *SAutostart) save(ctx context.Context, project *entity.Jpid, state *model.AutostartState) {
+	enabled := 0
+	if state != nil && state.Enabled {
+		enabled = 1
+	}
+	var saved *entity.Jpid
+	if err := dao.Jpid.Ctx(ctx).Fields("unit_name").Where("id", project.Id).Scan(&saved); err != nil || saved == nil {
+		return
+	}
+	if saved.UnitName == project.UnitName && project.Autostart == enabled {
+		return
+	}
+	_, err := dao.Jpid.Ctx(ctx).Data(g.Map{
+		"unit_name": project.UnitName,
+		"autostart": enabled,
+	}).Where("id", project.Id).Update()
+	if err != nil {
+		g.Log().Warningf(ctx, "更新项目 %s 的自启服务失败: %v", project.Name, err)
+		return
+	}
+	if project.Autostart != enabled {
+		Event().Publish(ctx, model.EventProjectAutostart, project, map[string]interface{}{"autostart": enabled})
+		project.Autostart = enabled
+	}
+}
+
+// refresh 立即刷新项目的服务状态，name 为空或服务不存在时清除
+func (s *SAutostart) refresh(ctx context.Context, manager *autostart.ServiceManager, projectId int, name string) *model.AutostartState {
+	var state *model.AutostartState
+	if name != "" && manager.Exists(name) {
+		state = autostartState(ctx, manager, name)
+	}
+	autostartStatesMu.Lock()
+	defer autostartStatesMu.Unlock()
+	if state == nil {
+		delete(autostartStates, projectId)
+	} else {
+		autostartStates[projectId] = state
+	}
+	return state
+}
+
+// forget 项目删除后清除服务状态
+func (s *SAutostart) forget(projectId int) {
+	autostartStatesMu.Lock()
+	defer autostartStatesMu.Unlock()
+	delete(autostartStates, projectId)
+}
+
+// autostartState 查询服务的自启和运行状态
+func autostartState(ctx context.Context, manager *autostart.ServiceManager, name string) *model.AutostartState {
+	state := &model.AutostartState{Unit: autostart.UnitName(name), Exists: true, CheckedAt: gtime.Now()}
+	info, err := manager.Status(ctx, name)
+	if errors.Is(err, autostart.ErrNotFound) {
+		state.Exists = false
+		return state
+	} else if err != nil {
+		state.UnitFile, state.ActiveState = "unknown", "unknown"
+		return state
+	}
+	state.Enabled, state.Active = info.Enabled, info.Active
+	state.Failed = info.ActiveStatus == "failed"
+	state.UnitFile, state.ActiveState, state.SubState = info.AutostartStatus, info.ActiveStatus, info.SubState
+	return state
+}
+
+// otherScopeExists 服务是否存在于当前管理范围之外：以普通用户运行时检查系统服务目录
+// 以 root 运行时无法确定是哪个用户的服务，总是返回 false
+func otherScopeExists(manager *autostart.ServiceManager, names ...string) bool {
+	if !manager.User {
+		return false
+	}
+	systemManager := autostart.NewServiceManager()
+	for _, name := range names {
+		if name != "" && systemManager.Exists(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchExec 服务是否运行项目的 jar 或启动脚本：启动命令中的 jar、脚本（相对路径按服务的运行目录）
+// 与项目运行目录下的 jar、脚本是同一个文件
+func matchExec(unit *model.AutostartUnit, project *entity.Jpid) bool {
+	if project.Catalog == "" || unit.ExecStart == "" {
+		return false
+	}
+	targets := map[string]bool{filepath.Join(project.Catalog, project.Name): true}
+	for _, field := range strings.Fields(project.Script) {
+		if strings.HasSuffix(field, ".sh") {
+			targets[resolvePath(project.Catalog, field)] = true
+		}
+	}
+	for _, field := range strings.Fields(unit.ExecStart) {
+		if targets[resolvePath(unit.WorkDir, strings.Trim(field, `'"`))] {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePath 相对路径按 dir 转为绝对路径
+func resolvePath(dir, path string) string {
+	if filepath.IsAbs(path) || dir == "" {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"autostart/pkg/autostart"
+	"omniscient/internal/dao"
+	"omniscient/internal/model/do"
+)
+
+// insertTestProject 写入一个测试项目，测试结束时删除
+func insertTestProject(t *testing.T, worker string) int {
+	t.Helper()
+	ctx := context.Background()
+	useTestDB(t)
+	id, err := dao.Jpid.Ctx(ctx).Data(do.Jpid{Name: "demo.jar", Ports: "8080", Pid: 0, Catalog: t.TempDir(), Worker: worker, Way: 2}).InsertAndGetId()
+	if err != nil {
+		t.Fatalf("insert project: %v", err)
+	}
+	t.Cleanup(func() { dao.Jpid.Ctx(ctx).Where("id", id).Delete() })
+	return int(id)
+}
+
+func TestAutostartRejectsOtherWorker(t *testing.T) {
+	ctx := context.Background()
+	id := insertTestProject(t, "other-worker-1")
+
+	if _, err := Autostart().Regenerate(ctx, id); err == nil || !strings.Contains(err.Error(), "other-worker-1") {
+		t.Errorf("Regenerate = %v, want other worker error", err)
+	}
+	if _, err := Autostart().Adopt(ctx, id, "autostart-demo"); err == nil || !strings.Contains(err.Error(), "other-worker-1") {
+		t.Errorf("Adopt = %v, want other worker error", err)
+	}
+	if _, _, err := Jpid().Journal(ctx, id, autostart.LogOptions{}); err == nil || !strings.Contains(err.Error(), "other-worker-1") {
+		t.Errorf("Journal = %v, want other worker error", err)
+	}
+}
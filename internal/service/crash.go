@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/internal/dao"
+	"omniscient/internal/model"
+	"omniscient/internal/model/entity"
+	"omniscient/internal/util/crash"
+	"omniscient/internal/util/system"
+)
+
+// defaultCrashLogFiles 未配置 crash.logFiles 时扫描的日志，相对路径基于项目运行目录
+var defaultCrashLogFiles = []string{"nohup.log", "logs/*.log"}
+
+// crashReasonTitles 退出原因的说明
+var crashReasonTitles = map[string]string{
+	model.CrashOomKiller:     "被系统 OOM killer 杀死",
+	model.CrashNativeOom:     "JVM 本地内存不足",
+	model.CrashJvmFatal:      "JVM 致命错误",
+	model.CrashOutOfMemory:   "内存溢出",
+	model.CrashStackOverflow: "栈溢出",
+	model.CrashUnknown:       "原因未知",
+}
+
+// AnalyzeCrash 分析进程意外退出的原因，jdk 项目才会写出 hs_err 和日志
+func (s *SJpid) AnalyzeCrash(ctx context.Context, project *entity.Jpid) *model.CrashReport {
+	var run *entity.JpidRun
+	if err := dao.JpidRun.Ctx(ctx).
+		Where("jpid_id", project.Id).
+		WhereNull("stopped_at").
+		Order("id DESC").
+		Scan(&run); err != nil {
+		g.Log().Warningf(ctx, "读取项目 %s 运行记录失败: %v", project.Name, err)
+	}
+
+	opts := crash.Options{
+		Pid:       project.Pid,
+		Dirs:      []string{project.Catalog},
+		ErrorFile: errorFileOption(project.Run),
+		TailBytes: g.Cfg().MustGet(ctx, "crash.tailBytes", 0).Int64(),
+	}
+	if run != nil {
+		if run.StartedAt != nil {
+			opts.Since = run.StartedAt.Time
+		}
+		if run.JarPath != "" {
+			opts.Dirs = append(opts.Dirs, filepath.Dir(run.JarPath))
+		}
+	}
+	if opts.ErrorFile != "" && !filepath.IsAbs(opts.ErrorFile) && project.Catalog != "" {
+		opts.ErrorFile = filepath.Join(project.Catalog, opts.ErrorFile)
+	}
+
+	logFiles := g.Cfg().MustGet(ctx, "crash.logFiles").Strings()
+	if len(logFiles) == 0 {
+		logFiles = defaultCrashLogFiles
+	}
+	for _, file := range logFiles {
+		if !filepath.IsAbs(file) {
+			if project.Catalog == "" {
+				continue
+			}
+			file = filepath.Join(project.Catalog, file)
+		}
+		opts.LogFiles = append(opts.LogFiles, file)
+	}
+	return crash.Analyze(opts)
+}
+
+// exitSuspects 上一次检查时进程已不存在的项目（项目ID -> pid）
+// 停止、重启过程中进程短暂不存在而状态尚未更新，连续两次检查都不存在才视为意外退出
+var exitSuspects sync.Map
+
+// DetectExited 检查本机状态为运行中的 jdk 项目进程是否还在，不在时分析退出原因并更新状态
+// 与 AutoRegister 不同，不需要扫描全部 java 进程，供定时任务使用
+func (s *SJpid) DetectExited(ctx context.Context) {
+	var projects []*entity.Jpid
+	if err := dao.Jpid.Ctx(ctx).
+		Where("worker", system.GetWorkerName()).
+		Where("status", 1).
+		WhereNot("way", 1).
+		Scan(&projects); err != nil {
+		g.Log().Warningf(ctx, "读取运行中的项目失败: %v", err)
+		return
+	}
+	running := make(map[int]bool, len(projects))
+	for _, project := range projects {
+		running[project.Id] = true
+		if !s.confirmExited(project) {
+			continue
+		}
+		s.recordCrash(ctx, project)
+		if err := s.UpdateStatusById(ctx, project.Id, 0); err != nil {
+			g.Log().Warningf(ctx, "更新已停止项目 %s 状态失败: %v", project.Name, err)
+		}
+	}
+	exitSuspects.Range(func(key, _ interface{}) bool {
+		if !running[key.(int)] {
+			exitSuspects.Delete(key)
+		}
+		return true
+	})
+}
+
+// confirmExited 项目进程是否确实已退出：进程仍在时清除嫌疑，
+// 第一次发现不存在只记录嫌疑，同一个 pid 连续两次检查都不存在才返回 true
+func (s *SJpid) confirmExited(project *entity.Jpid) bool {
+	if project.Pid > 0 && s.IsProcessRunning(project.Pid) {
+		exitSuspects.Delete(project.Id)
+		return false
+	}
+	if pid, ok := exitSuspects.Load(project.Id); !ok || pid.(int) != project.Pid {
+		exitSuspects.Store(project.Id, project.Pid)
+		return false
+	}
+	exitSuspects.Delete(project.Id)
+	return true
+}
+
+// recordCrash 进程意外退出时分析原因，记录到运行记录并发送通知，失败只记录日志
+func (s *SJpid) recordCrash(ctx context.Context, project *entity.Jpid) {
+	if project.Way == 1 || !g.Cfg().MustGet(ctx, "crash.enabled", true).Bool() {
+		return
+	}
+	report := s.AnalyzeCrash(ctx, project)
+	if err := JpidRun().Crashed(ctx, project.Id, report); err != nil {
+		g.Log().Warningf(ctx, "记录项目 %s 退出原因失败: %v", project.Name, err)
+	}
+	Event().Publish(ctx, model.EventProjectCrashed, project, map[string]interface{}{
+		"reason": report.Reason,
+		"detail": report.Detail,
+		"file":   report.File,
+	})
+
+	title := fmt.Sprintf("项目 %s 意外退出：%s", project.Name, crashReasonTitles[report.Reason])
+	content := fmt.Sprintf("服务器 %s，PID %d，时间 %s", project.Worker, project.Pid, time.Now().Format(time.DateTime))
+	if report.Detail != "" {
+		content += "\n" + report.Detail
+	}
+	if report.File != "" {
+		content += "\n崩溃文件: " + report.File
+	}
+	if len(report.Evidence) > 1 {
+		content += "\n全部线索:\n" + strings.Join(report.Evidence, "\n")
+	}
+	Notification().Notify(ctx, project.Id, model.NotifyError, title, content)
+}
+
+// errorFileOption 启动命令中 -XX:ErrorFile 的值
+func errorFileOption(command string) string {
+	for _, field := range strings.Fields(command) {
+		if value, ok := strings.CutPrefix(field, "-XX:ErrorFile="); ok {
+			return strings.Trim(value, `"'`)
+		}
+	}
+	return ""
+}
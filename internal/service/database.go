@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/gogf/gf/v2/database/gdb"
 	"github.com/gogf/gf/v2/frame/g"
@@ -14,24 +15,62 @@ import (
 // DatabaseManager 数据库管理器
 type DatabaseManager struct {
 	dbType string
+	group  string
 	config gdb.ConfigNode
 }
 
 // NewDatabaseManager 创建数据库管理器
 func NewDatabaseManager() *DatabaseManager {
-	return &DatabaseManager{}
+	return &DatabaseManager{group: gdb.DefaultGroupName}
 }
 
 // Initialize 初始化数据库连接
 func (dm *DatabaseManager) Initialize(ctx context.Context) error {
-	cfg := g.Cfg()
-
 	// 获取数据库类型
-	dbType := cfg.MustGet(ctx, "database.type", "mysql").String()
-	dm.dbType = dbType
+	dbType := g.Cfg().MustGet(ctx, "database.type", "mysql").String()
+	return dm.InitializeAs(ctx, dbType, gdb.DefaultGroupName)
+}
 
+// InitializeAs 按指定的数据库类型初始化连接，并注册到指定的配置分组
+// 默认分组供业务使用，其它分组用于备份恢复、跨库迁移等需要同时连接多个库的场景
+func (dm *DatabaseManager) InitializeAs(ctx context.Context, dbType, group string) error {
 	g.Log().Infof(ctx, "正在初始化数据库类型: %s", dbType)
 
+	config, err := dm.loadConfig(ctx, dbType)
+	if err != nil {
+		return err
+	}
+	dm.dbType = dbType
+	dm.group = group
+	dm.config = config
+
+	// 打印数据库配置信息，方便debug
+	g.Log().Infof(ctx, "========== 数据库配置信息 ==========")
+	g.Log().Infof(ctx, "数据库类型: %s", config.Type)
+	g.Log().Infof(ctx, "连接字符串: %s", config.Link)
+	g.Log().Infof(ctx, "最大空闲连接数: %d", config.MaxIdleConnCount)
+	g.Log().Infof(ctx, "最大打开连接数: %d", config.MaxOpenConnCount)
+	g.Log().Infof(ctx, "调试模式: %v", config.Debug)
+	g.Log().Infof(ctx, "=====================================")
+
+	return dm.connect(ctx)
+}
+
+// Attach 关联已经初始化的默认数据库连接，只读取配置不重新注册连接
+func (dm *DatabaseManager) Attach(ctx context.Context) error {
+	dbType := g.Cfg().MustGet(ctx, "database.type", "mysql").String()
+	config, err := dm.loadConfig(ctx, dbType)
+	if err != nil {
+		return err
+	}
+	dm.dbType = dbType
+	dm.group = gdb.DefaultGroupName
+	dm.config = config
+	return nil
+}
+
+// loadConfig 读取指定数据库类型的连接配置
+func (dm *DatabaseManager) loadConfig(ctx context.Context, dbType string) (gdb.ConfigNode, error) {
 	// 根据数据库类型获取配置
 	var configKey string
 	switch dbType {
@@ -39,40 +78,44 @@ func (dm *DatabaseManager) Initialize(ctx context.Context) error {
 		configKey = "database.mysql"
 	case "sqlite":
 		configKey = "database.sqlite"
+	case "pgsql":
+		configKey = "database.pgsql"
 	default:
-		return fmt.Errorf("不支持的数据库类型: %s", dbType)
+		return gdb.ConfigNode{}, fmt.Errorf("不支持的数据库类型: %s", dbType)
 	}
 
 	// 读取对应的数据库配置
 	var config gdb.ConfigNode
-	if err := cfg.MustGet(ctx, configKey).Struct(&config); err != nil {
-		return fmt.Errorf("读取数据库配置失败: %v", err)
+	if err := g.Cfg().MustGet(ctx, configKey).Struct(&config); err != nil {
+		return config, fmt.Errorf("读取数据库配置失败: %v", err)
+	}
+	if config.Link == "" {
+		return config, fmt.Errorf("数据库配置 %s 不存在或连接字符串为空", configKey)
 	}
 
 	// 确保配置中的 Type 字段被正确设置
 	config.Type = dbType
-	dm.config = config
-
-	// 打印数据库配置信息，方便debug
-	g.Log().Infof(ctx, "========== 数据库配置信息 ==========")
-	g.Log().Infof(ctx, "数据库类型: %s", config.Type)
-	g.Log().Infof(ctx, "连接字符串: %s", config.Link)
-	g.Log().Infof(ctx, "最大空闲连接数: %d", config.MaxIdleConnCount)
-	g.Log().Infof(ctx, "最大打开连接数: %d", config.MaxOpenConnCount)
-	g.Log().Infof(ctx, "调试模式: %v", config.Debug)
-	g.Log().Infof(ctx, "=====================================")
+	return config, nil
+}
 
+// connect 注册数据库配置并测试连接
+func (dm *DatabaseManager) connect(ctx context.Context) error {
 	// 如果是 SQLite，确保数据目录存在
-	if dbType == "sqlite" {
+	if dm.dbType == "sqlite" {
 		if err := dm.ensureSQLiteDir(); err != nil {
 			return fmt.Errorf("创建 SQLite 数据目录失败: %v", err)
 		}
 	}
 
 	// 设置数据库配置
-	err := gdb.SetConfig(gdb.Config{
-		"default": gdb.ConfigGroup{config},
-	})
+	var err error
+	if dm.group == gdb.DefaultGroupName {
+		err = gdb.SetConfig(gdb.Config{
+			dm.group: gdb.ConfigGroup{dm.config},
+		})
+	} else {
+		err = gdb.SetConfigGroup(dm.group, gdb.ConfigGroup{dm.config})
+	}
 	if err != nil {
 		return err
 	}
@@ -82,10 +125,15 @@ func (dm *DatabaseManager) Initialize(ctx context.Context) error {
 		return fmt.Errorf("数据库连接测试失败: %v", err)
 	}
 
-	g.Log().Infof(ctx, "数据库初始化成功: %s", dbType)
+	g.Log().Infof(ctx, "数据库初始化成功: %s", dm.dbType)
 	return nil
 }
 
+// DB 获取当前管理器对应的数据库对象
+func (dm *DatabaseManager) DB() gdb.DB {
+	return g.DB(dm.group)
+}
+
 // ensureSQLiteDir 确保 SQLite 数据目录存在
 func (dm *DatabaseManager) ensureSQLiteDir() error {
 	// 从连接字符串中提取数据库文件路径
@@ -96,27 +144,7 @@ func (dm *DatabaseManager) ensureSQLiteDir() error {
 
 	g.Log().Infof(context.Background(), "正在解析 SQLite 连接字符串: %s", link)
 
-	var dbPath string
-
-	// 解析不同格式的 SQLite 连接字符串
-	if len(link) > 7 && link[:7] == "sqlite:" {
-		remaining := link[7:] // 去除 "sqlite:" 前缀
-
-		// 处理不同的连接字符串格式
-		if remaining[:6] == "@file(" && remaining[len(remaining)-1] == ')' {
-			// 格式: sqlite:@file(./data/omniscient.db)
-			dbPath = remaining[6 : len(remaining)-1]
-		} else if remaining[0] == ':' && len(remaining) > 6 && remaining[1:7] == "@file(" && remaining[len(remaining)-1] == ')' {
-			// 格式: sqlite::@file(./data/omniscient.db)
-			dbPath = remaining[7 : len(remaining)-1]
-		} else {
-			// 简单格式: sqlite:./data/omniscient.db
-			dbPath = remaining
-		}
-	} else {
-		// 如果不是以 sqlite: 开头，直接使用原字符串
-		dbPath = link
-	}
+	dbPath := sqliteFilePath(link)
 
 	g.Log().Infof(context.Background(), "提取的数据库文件路径: %s", dbPath)
 
@@ -142,9 +170,47 @@ func (dm *DatabaseManager) ensureSQLiteDir() error {
 	return nil
 }
 
+// sqliteFilePath 从 SQLite 连接字符串中解析数据库文件路径
+func sqliteFilePath(link string) string {
+	var dbPath string
+
+	// 解析不同格式的 SQLite 连接字符串
+	if len(link) > 7 && link[:7] == "sqlite:" {
+		remaining := link[7:] // 去除 "sqlite:" 前缀
+
+		// 处理不同的连接字符串格式
+		if len(remaining) > 6 && remaining[:6] == "@file(" && remaining[len(remaining)-1] == ')' {
+			// 格式: sqlite:@file(./data/omniscient.db)
+			dbPath = remaining[6 : len(remaining)-1]
+		} else if len(remaining) > 7 && remaining[0] == ':' && remaining[1:7] == "@file(" && remaining[len(remaining)-1] == ')' {
+			// 格式: sqlite::@file(./data/omniscient.db)
+			dbPath = remaining[7 : len(remaining)-1]
+		} else {
+			// 简单格式: sqlite:./data/omniscient.db
+			dbPath = remaining
+		}
+	} else {
+		// 如果不是以 sqlite: 开头，直接使用原字符串
+		dbPath = link
+	}
+
+	return dbPath
+}
+
+// isUniqueViolation 是否违反唯一约束（MySQL、SQLite、PostgreSQL）
+func isUniqueViolation(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := err.Error()
+	return strings.Contains(msg, "Duplicate entry") ||
+		strings.Contains(msg, "UNIQUE constraint failed") ||
+		strings.Contains(msg, "duplicate key value")
+}
+
 // testConnection 测试数据库连接
 func (dm *DatabaseManager) testConnection(ctx context.Context) error {
-	db := g.DB()
+	db := dm.DB()
 
 	var testSQL string
 	switch dm.dbType {
@@ -152,6 +218,8 @@ func (dm *DatabaseManager) testConnection(ctx context.Context) error {
 		testSQL = "SELECT 1"
 	case "sqlite":
 		testSQL = "SELECT 1"
+	case "pgsql":
+		testSQL = "SELECT 1"
 	default:
 		testSQL = "SELECT 1"
 	}
@@ -172,7 +240,7 @@ func (dm *DatabaseManager) GetDatabaseType() string {
 
 // tableExists 检查表是否存在
 func (dm *DatabaseManager) tableExists(ctx context.Context, tableName string) (bool, error) {
-	db := g.DB()
+	db := dm.DB()
 
 	var checkSQL string
 	switch dm.dbType {
@@ -180,6 +248,8 @@ func (dm *DatabaseManager) tableExists(ctx context.Context, tableName string) (b
 		checkSQL = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name = ?"
 	case "sqlite":
 		checkSQL = "SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name = ?"
+	case "pgsql":
+		checkSQL = "SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = ?"
 	default:
 		return false, fmt.Errorf("不支持的数据库类型: %s", dm.dbType)
 	}
@@ -194,76 +264,85 @@ func (dm *DatabaseManager) tableExists(ctx context.Context, tableName string) (b
 
 // CreateTables 创建数据表（避免重复创建）
 func (dm *DatabaseManager) CreateTables(ctx context.Context) error {
-	db := g.DB()
+	db := dm.DB()
 
-	// 检查 jpid 表是否已存在
-	exists, err := dm.tableExists(ctx, "jpid")
-	if err != nil {
-		g.Log().Warningf(ctx, "检查表是否存在时出错: %v", err)
-	}
+	for _, table := range managedTables {
+		// 检查表是否已存在
+		exists, err := dm.tableExists(ctx, table.Name)
+		if err != nil {
+			g.Log().Warningf(ctx, "检查表是否存在时出错: %v", err)
+		}
+
+		if exists {
+			g.Log().Infof(ctx, "数据表 %s 已存在，跳过创建", table.Name)
+			continue
+		}
+
+		g.Log().Infof(ctx, "正在创建数据表 %s...", table.Name)
 
-	if exists {
-		g.Log().Info(ctx, "数据表 jpid 已存在，跳过创建")
-		return nil
+		// 根据数据库类型选择合适的 SQL
+		createTableSQL, ok := table.DDL[dm.dbType]
+		if !ok {
+			return fmt.Errorf("不支持的数据库类型: %s", dm.dbType)
+		}
+
+		// 执行建表语句
+		if _, err = db.Exec(ctx, createTableSQL); err != nil {
+			return fmt.Errorf("创建 %s 表失败: %v", table.Name, err)
+		}
+
+		g.Log().Infof(ctx, "数据表 %s 创建成功", table.Name)
 	}
 
-	g.Log().Info(ctx, "正在创建数据表 jpid...")
+	// 补齐新增字段
+	for _, column := range managedColumns {
+		exists, err := dm.columnExists(ctx, column.Table, column.Name)
+		if err != nil {
+			return fmt.Errorf("检查字段 %s.%s 是否存在失败: %v", column.Table, column.Name, err)
+		}
+		if exists {
+			continue
+		}
+
+		columnDDL, ok := column.DDL[dm.dbType]
+		if !ok {
+			return fmt.Errorf("不支持的数据库类型: %s", dm.dbType)
+		}
+
+		alterSQL := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", column.Table, column.Name, columnDDL)
+		if _, err = db.Exec(ctx, alterSQL); err != nil {
+			return fmt.Errorf("新增字段 %s.%s 失败: %v", column.Table, column.Name, err)
+		}
+		g.Log().Infof(ctx, "数据表 %s 新增字段 %s", column.Table, column.Name)
+	}
+	return nil
+}
 
-	// 根据数据库类型选择合适的 SQL
-	var createJpidTableSQL string
+// columnExists 检查字段是否存在
+func (dm *DatabaseManager) columnExists(ctx context.Context, tableName, columnName string) (bool, error) {
+	var checkSQL string
 	switch dm.dbType {
 	case "mysql":
-		createJpidTableSQL = `
-			CREATE TABLE IF NOT EXISTS jpid (
-				id INT NOT NULL AUTO_INCREMENT,
-				name VARCHAR(120) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT 'java项目名',
-				ports VARCHAR(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '运行端口,多个逗号隔开',
-				pid INT NOT NULL COMMENT 'pid',
-				catalog VARCHAR(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '运行目录',
-				run LONGTEXT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci COMMENT '原生启动命令',
-				script LONGTEXT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci COMMENT 'sh脚本启动命令',
-				worker VARCHAR(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '服务器',
-				status INT DEFAULT '0' COMMENT '状态[1:启动，0:停止]',
-				description VARCHAR(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '项目描述',
-				way INT DEFAULT '2' COMMENT '启动方式[1:docker, 2:jdk]',
-				autostart INT DEFAULT '0' COMMENT '自启[0:没有自启, 1:自启]',
-				PRIMARY KEY (id)
-			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='java项目详情';
-			`
+		checkSQL = "SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = DATABASE() AND table_name = ? AND column_name = ?"
 	case "sqlite":
-		createJpidTableSQL = `
-			CREATE TABLE IF NOT EXISTS jpid (
-				id INTEGER PRIMARY KEY AUTOINCREMENT,
-				name TEXT NOT NULL, -- java项目名
-				ports TEXT NOT NULL, -- 运行端口,多个逗号隔开
-				pid INTEGER NOT NULL, -- pid
-				catalog TEXT DEFAULT NULL, -- 运行目录
-				run TEXT, -- 原生启动命令
-				script TEXT, -- sh脚本启动命令
-				worker TEXT NOT NULL, -- 服务器
-				status INTEGER DEFAULT 0, -- 状态[1:启动，0:停止]
-				description TEXT DEFAULT NULL, -- 项目描述
-				way INTEGER DEFAULT 2, -- 启动方式[1:docker, 2:jdk]
-				autostart INTEGER DEFAULT 0 -- 自启[0:没有自启, 1:自启]
-			);
-	   `
+		checkSQL = "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?"
+	case "pgsql":
+		checkSQL = "SELECT COUNT(*) FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?"
 	default:
-		return fmt.Errorf("不支持的数据库类型: %s", dm.dbType)
+		return false, fmt.Errorf("不支持的数据库类型: %s", dm.dbType)
 	}
 
-	// 执行建表语句
-	_, err = db.Exec(ctx, createJpidTableSQL)
+	count, err := dm.DB().GetValue(ctx, checkSQL, tableName, columnName)
 	if err != nil {
-		return fmt.Errorf("创建 jpid 表失败: %v", err)
+		return false, err
 	}
 
-	g.Log().Info(ctx, "数据表 jpid 创建成功")
-	return nil
+	return count.Int() > 0, nil
 }
 
 // GetDatabaseInfo 获取数据库信息
 func (dm *DatabaseManager) GetDatabaseInfo(ctx context.Context) (map[string]interface{}, error) {
-	db := g.DB()
+	db := dm.DB()
 	info := make(map[string]interface{})
 
 	info["type"] = dm.dbType
@@ -303,21 +382,7 @@ func (dm *DatabaseManager) GetDatabaseInfo(ctx context.Context) (map[string]inte
 		}
 
 		// 解析数据库文件路径
-		link := dm.config.Link
-		var dbPath string
-		if len(link) > 7 && link[:7] == "sqlite:" {
-			remaining := link[7:]
-			if remaining[:6] == "@file(" && remaining[len(remaining)-1] == ')' {
-				dbPath = remaining[6 : len(remaining)-1]
-			} else if remaining[0] == ':' && len(remaining) > 6 && remaining[1:7] == "@file(" && remaining[len(remaining)-1] == ')' {
-				dbPath = remaining[7 : len(remaining)-1]
-			} else {
-				dbPath = remaining
-			}
-		} else {
-			dbPath = link
-		}
-
+		dbPath := sqliteFilePath(dm.config.Link)
 		if dbPath != "" {
 			info["database_file"] = dbPath
 		}
@@ -331,6 +396,35 @@ func (dm *DatabaseManager) GetDatabaseInfo(ctx context.Context) (map[string]inte
 			}
 			info["tables"] = tableNames
 		}
+
+	case "pgsql":
+		// 获取 PostgreSQL 服务端版本
+		version, err := db.GetValue(ctx, "SHOW server_version")
+		if err == nil {
+			info["version"] = version
+		}
+
+		// 获取数据库名
+		dbName, err := db.GetValue(ctx, "SELECT current_database()")
+		if err == nil {
+			info["database"] = dbName
+		}
+
+		// 获取当前 schema（受 namespace 配置的 search_path 影响）
+		schema, err := db.GetValue(ctx, "SELECT current_schema()")
+		if err == nil {
+			info["schema"] = schema
+		}
+
+		// 获取表信息
+		tables, err := db.GetAll(ctx, "SELECT table_name FROM information_schema.tables WHERE table_schema = current_schema() AND table_type = 'BASE TABLE'")
+		if err == nil {
+			tableNames := make([]string, 0)
+			for _, table := range tables {
+				tableNames = append(tableNames, table["table_name"].String())
+			}
+			info["tables"] = tableNames
+		}
 	}
 
 	return info, nil
@@ -0,0 +1,388 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcron"
+	"github.com/gogf/gf/v2/os/gfile"
+)
+
+const (
+	// BackupFormatSQLite SQLite 在线备份（VACUUM INTO），仅 SQLite 可用
+	BackupFormatSQLite = "sqlite"
+	// BackupFormatJSON 逻辑备份，与数据库类型无关，可跨库恢复
+	BackupFormatJSON = "json"
+
+	backupFilePrefix = "omniscient-"
+	// autoBackupFilePrefix 定时备份的文件名前缀，清理时只处理这些文件，不会删除手动备份
+	autoBackupFilePrefix = "omniscient-auto-"
+	// backupTimeLayout 备份文件名中的时间戳格式
+	backupTimeLayout   = "20060102-150405"
+	defaultBackupDir   = "./data/backup"
+	logicalDumpVersion = 1
+	copyBatchSize      = 200
+)
+
+// sqliteFileHeader SQLite 数据库文件头
+var sqliteFileHeader = []byte("SQLite format 3\x00")
+
+// LogicalDump 逻辑备份文件结构
+type LogicalDump struct {
+	Version   int                                 `json:"version"`   // 备份格式版本
+	Source    string                              `json:"source"`    // 备份来源的数据库类型
+	CreatedAt string                              `json:"createdAt"` // 备份时间
+	Tables    map[string][]map[string]interface{} `json:"tables"`    // 表名 -> 数据行
+}
+
+// TableCopyResult 单表迁移结果
+type TableCopyResult struct {
+	Table  string // 表名
+	Source int    // 源表行数
+	Target int    // 迁移后目标表行数
+}
+
+// Backup 备份当前数据库，output 为文件或目录（为空时使用 database.backup.dir），返回备份文件路径
+func (dm *DatabaseManager) Backup(ctx context.Context, output, format string) (string, error) {
+	return dm.backup(ctx, output, format, backupFilePrefix)
+}
+
+// backup 备份当前数据库，output 为目录时按 prefix 和时间生成文件名
+func (dm *DatabaseManager) backup(ctx context.Context, output, format, prefix string) (string, error) {
+	if format == "" {
+		format = BackupFormatJSON
+		if dm.dbType == "sqlite" {
+			format = BackupFormatSQLite
+		}
+	}
+	if format == BackupFormatSQLite && dm.dbType != "sqlite" {
+		return "", fmt.Errorf("%s 数据库不支持 sqlite 格式备份，请使用 json 格式", dm.dbType)
+	}
+	if format != BackupFormatSQLite && format != BackupFormatJSON {
+		return "", fmt.Errorf("不支持的备份格式: %s", format)
+	}
+
+	backupPath, err := dm.resolveBackupPath(ctx, output, format, prefix)
+	if err != nil {
+		return "", err
+	}
+
+	if format == BackupFormatSQLite {
+		// VACUUM INTO 在一个读事务中生成完整的数据库副本，不影响正在运行的服务
+		if _, err = dm.DB().Exec(ctx, "VACUUM INTO ?", backupPath); err != nil {
+			return "", fmt.Errorf("SQLite 在线备份失败: %v", err)
+		}
+		return backupPath, nil
+	}
+
+	tables, err := dm.readTables(ctx)
+	if err != nil {
+		return "", err
+	}
+	dump := LogicalDump{
+		Version:   logicalDumpVersion,
+		Source:    dm.dbType,
+		CreatedAt: time.Now().Format(time.RFC3339),
+		Tables:    tables,
+	}
+	data, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("序列化备份数据失败: %v", err)
+	}
+	if err = gfile.PutBytes(backupPath, data); err != nil {
+		return "", fmt.Errorf("写入备份文件失败: %v", err)
+	}
+	return backupPath, nil
+}
+
+// resolveBackupPath 生成备份文件路径
+func (dm *DatabaseManager) resolveBackupPath(ctx context.Context, output, format, prefix string) (string, error) {
+	if output == "" {
+		output = g.Cfg().MustGet(ctx, "database.backup.dir", defaultBackupDir).String() + "/"
+	}
+
+	// 以分隔符结尾或已存在的目录，在目录下按时间生成文件名
+	if strings.HasSuffix(output, "/") || gfile.IsDir(output) {
+		ext := ".json"
+		if format == BackupFormatSQLite {
+			ext = ".sqlite3"
+		}
+		output = filepath.Join(output, fmt.Sprintf("%s%s-%s%s",
+			prefix, dm.dbType, time.Now().Format(backupTimeLayout), ext))
+	}
+
+	absPath, err := filepath.Abs(output)
+	if err != nil {
+		return "", fmt.Errorf("解析备份路径失败: %v", err)
+	}
+	if gfile.Exists(absPath) {
+		return "", fmt.Errorf("备份文件已存在: %s", absPath)
+	}
+	if err = gfile.Mkdir(filepath.Dir(absPath)); err != nil {
+		return "", fmt.Errorf("创建备份目录失败: %v", err)
+	}
+	return absPath, nil
+}
+
+// readTables 在一致性快照中读取全部数据表
+func (dm *DatabaseManager) readTables(ctx context.Context) (map[string][]map[string]interface{}, error) {
+	tables := make(map[string][]map[string]interface{})
+	err := dm.DB().TransactionWithOptions(ctx, dm.snapshotTxOptions(), func(ctx context.Context, tx gdb.TX) error {
+		for _, name := range ManagedTableNames() {
+			exists, err := dm.tableExists(ctx, name)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				continue
+			}
+			result, err := tx.GetAll(fmt.Sprintf("SELECT * FROM %s ORDER BY id", name))
+			if err != nil {
+				return fmt.Errorf("读取 %s 表失败: %v", name, err)
+			}
+			tables[name] = result.List()
+		}
+		return nil
+	})
+	return tables, err
+}
+
+// snapshotTxOptions 读取快照使用的事务选项，SQLite 的读事务本身就是一致性快照
+func (dm *DatabaseManager) snapshotTxOptions() gdb.TxOptions {
+	if dm.dbType == "sqlite" {
+		return gdb.DefaultTxOptions()
+	}
+	return gdb.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+}
+
+// Restore 从备份文件恢复数据，会清空当前库中对应表的数据
+func (dm *DatabaseManager) Restore(ctx context.Context, backupPath string) ([]TableCopyResult, error) {
+	if !gfile.Exists(backupPath) {
+		return nil, fmt.Errorf("备份文件不存在: %s", backupPath)
+	}
+
+	header := make([]byte, len(sqliteFileHeader))
+	file, err := os.Open(backupPath)
+	if err != nil {
+		return nil, fmt.Errorf("打开备份文件失败: %v", err)
+	}
+	_, _ = file.Read(header)
+	_ = file.Close()
+
+	// SQLite 备份文件：以独立分组打开后按表导入
+	if bytes.Equal(header, sqliteFileHeader) {
+		absPath, _ := filepath.Abs(backupPath)
+		source := &DatabaseManager{
+			dbType: "sqlite",
+			group:  "restore_source",
+			config: gdb.ConfigNode{Type: "sqlite", Link: fmt.Sprintf("sqlite::@file(%s)", absPath)},
+		}
+		if err = gdb.SetConfigGroup(source.group, gdb.ConfigGroup{source.config}); err != nil {
+			return nil, err
+		}
+		return dm.CopyFrom(ctx, source)
+	}
+
+	// 逻辑备份文件
+	var dump LogicalDump
+	decoder := json.NewDecoder(bytes.NewReader(gfile.GetBytes(backupPath)))
+	decoder.UseNumber()
+	if err = decoder.Decode(&dump); err != nil {
+		return nil, fmt.Errorf("解析备份文件失败: %v", err)
+	}
+	if dump.Version > logicalDumpVersion {
+		return nil, fmt.Errorf("备份文件版本 %d 高于当前支持的版本 %d", dump.Version, logicalDumpVersion)
+	}
+	names := make([]string, 0, len(dump.Tables))
+	for _, name := range ManagedTableNames() {
+		if _, ok := dump.Tables[name]; ok {
+			names = append(names, name)
+		}
+	}
+	return dm.loadTables(ctx, names, func(name string, write func(rows gdb.List) error) (int, error) {
+		rows := dump.Tables[name]
+		for start := 0; start < len(rows); start += copyBatchSize {
+			if err := write(rows[start:min(start+copyBatchSize, len(rows))]); err != nil {
+				return 0, err
+			}
+		}
+		return len(rows), nil
+	})
+}
+
+// CopyFrom 将源数据库的全部数据表迁移到当前数据库，迁移后校验行数
+// 在源库的一致性快照中按主键分页读取，每页读取后立即写入，不会把整张表载入内存
+func (dm *DatabaseManager) CopyFrom(ctx context.Context, source *DatabaseManager) ([]TableCopyResult, error) {
+	var results []TableCopyResult
+	err := source.DB().TransactionWithOptions(ctx, source.snapshotTxOptions(), func(ctx context.Context, src gdb.TX) error {
+		var names []string
+		for _, name := range ManagedTableNames() {
+			exists, err := source.tableExists(ctx, name)
+			if err != nil {
+				return err
+			}
+			if exists {
+				names = append(names, name)
+			}
+		}
+
+		var err error
+		results, err = dm.loadTables(ctx, names, func(name string, write func(rows gdb.List) error) (int, error) {
+			var (
+				total int
+				last  interface{} = 0
+			)
+			for {
+				result, err := src.GetAll(fmt.Sprintf("SELECT * FROM %s WHERE id > ? ORDER BY id LIMIT %d", name, copyBatchSize), last)
+				if err != nil {
+					return 0, fmt.Errorf("读取 %s 表失败: %v", name, err)
+				}
+				if len(result) == 0 {
+					return total, nil
+				}
+				if err = write(result.List()); err != nil {
+					return 0, err
+				}
+				total += len(result)
+				if len(result) < copyBatchSize {
+					return total, nil
+				}
+				last = result[len(result)-1]["id"].Val()
+			}
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// tableReader 分批读取一张表的数据，每批调用一次 write，返回读取的总行数
+type tableReader func(name string, write func(rows gdb.List) error) (int, error)
+
+// loadTables 在事务中清空并导入数据表，行数不一致时整体回滚
+func (dm *DatabaseManager) loadTables(ctx context.Context, names []string, read tableReader) ([]TableCopyResult, error) {
+	results := make([]TableCopyResult, 0, len(names))
+	err := dm.DB().Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		for _, name := range names {
+			if _, err := tx.Model(name).Where("1=1").Delete(); err != nil {
+				return fmt.Errorf("清空 %s 表失败: %v", name, err)
+			}
+			total, err := read(name, func(rows gdb.List) error {
+				if _, err := tx.Model(name).Data(rows).Insert(); err != nil {
+					return fmt.Errorf("导入 %s 表失败: %v", name, err)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			count, err := tx.Model(name).Count()
+			if err != nil {
+				return fmt.Errorf("统计 %s 表行数失败: %v", name, err)
+			}
+			if count != total {
+				return fmt.Errorf("%s 表行数校验失败: 源 %d 行, 目标 %d 行", name, total, count)
+			}
+
+			// PostgreSQL 显式写入自增主键后需要同步序列
+			if dm.dbType == "pgsql" {
+				syncSQL := fmt.Sprintf("SELECT setval(pg_get_serial_sequence('%s', 'id'), COALESCE(MAX(id), 0) + 1, false) FROM %s", name, name)
+				if _, err = tx.Exec(syncSQL); err != nil {
+					return fmt.Errorf("同步 %s 表序列失败: %v", name, err)
+				}
+			}
+
+			results = append(results, TableCopyResult{Table: name, Source: total, Target: count})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// StartBackupSchedule 按 database.backup 配置启动定时自动备份，cron 为空时不启用
+func (dm *DatabaseManager) StartBackupSchedule(ctx context.Context) error {
+	pattern := g.Cfg().MustGet(ctx, "database.backup.cron").String()
+	if pattern == "" {
+		return nil
+	}
+	keep := g.Cfg().MustGet(ctx, "database.backup.keep", 7).Int()
+	dir := g.Cfg().MustGet(ctx, "database.backup.dir", defaultBackupDir).String()
+
+	_, err := gcron.AddSingleton(ctx, pattern, func(ctx context.Context) {
+		backupPath, err := dm.backup(ctx, dir+"/", "", autoBackupFilePrefix)
+		if err != nil {
+			g.Log().Errorf(ctx, "定时备份数据库失败: %v", err)
+			return
+		}
+		g.Log().Infof(ctx, "定时备份数据库完成: %s", backupPath)
+
+		if err = pruneBackups(dir, keep); err != nil {
+			g.Log().Warningf(ctx, "清理过期备份失败: %v", err)
+		}
+	}, "database-backup")
+	if err != nil {
+		return fmt.Errorf("启动定时备份失败: %v", err)
+	}
+
+	g.Log().Infof(ctx, "已启用定时备份: cron=%s, dir=%s, keep=%d", pattern, dir, keep)
+	return nil
+}
+
+// pruneBackups 只保留最近 keep 份定时备份，按文件名中的时间戳排序，手动备份和无法识别的文件不做处理
+func pruneBackups(dir string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	files, err := gfile.ScanDirFile(dir, autoBackupFilePrefix+"*")
+	if err != nil {
+		return err
+	}
+	type backupFile struct {
+		path string
+		time time.Time
+	}
+	var backups []backupFile
+	for _, file := range files {
+		if t, ok := backupTime(file); ok {
+			backups = append(backups, backupFile{path: file, time: t})
+		}
+	}
+	if len(backups) <= keep {
+		return nil
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].time.Before(backups[j].time)
+	})
+	for _, backup := range backups[:len(backups)-keep] {
+		if err = gfile.Remove(backup.path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backupTime 解析备份文件名末尾的时间戳，如 omniscient-auto-mysql-20240102-030405.json
+func backupTime(path string) (time.Time, bool) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if len(name) < len(backupTimeLayout) {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(backupTimeLayout, name[len(name)-len(backupTimeLayout):], time.Local)
+	return t, err == nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gogf/gf/v2/database/gdb"
+)
+
+// newTestSQLite 在临时目录中创建一个已建表的 SQLite 库，注册到指定分组
+func newTestSQLite(t *testing.T, group string) *DatabaseManager {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), group+".sqlite3")
+	useTestConfig(t, "database:\n  sqlite:\n    link: \"sqlite::@file("+file+")\"\n")
+	dm := NewDatabaseManager()
+	if err := dm.InitializeAs(context.Background(), "sqlite", group); err != nil {
+		t.Fatalf("InitializeAs %s: %v", group, err)
+	}
+	if err := dm.CreateTables(context.Background()); err != nil {
+		t.Fatalf("CreateTables %s: %v", group, err)
+	}
+	return dm
+}
+
+// copyResult 迁移结果中指定表的行数
+func copyResult(t *testing.T, results []TableCopyResult, table string) TableCopyResult {
+	t.Helper()
+	for _, result := range results {
+		if result.Table == table {
+			return result
+		}
+	}
+	t.Fatalf("no result for table %s in %v", table, results)
+	return TableCopyResult{}
+}
+
+func TestCopyFromPaged(t *testing.T) {
+	ctx := context.Background()
+	source := newTestSQLite(t, "test_copy_source")
+	target := newTestSQLite(t, "test_copy_target")
+
+	// 跨越多页，最后一页不满，并留出主键空洞
+	rows := 2*copyBatchSize + 7
+	data := make(gdb.List, 0, rows)
+	for i := 1; i <= rows; i++ {
+		data = append(data, gdb.Map{"id": i * 2, "name": fmt.Sprintf("S%03d", i), "ciphertext": "c", "key_id": "k"})
+	}
+	if _, err := source.DB().Model("secret").Data(data).Insert(); err != nil {
+		t.Fatalf("insert source rows: %v", err)
+	}
+	// 目标库中的旧数据会被清空
+	if _, err := target.DB().Model("secret").Data(gdb.Map{"name": "OLD", "ciphertext": "c", "key_id": "k"}).Insert(); err != nil {
+		t.Fatalf("insert target row: %v", err)
+	}
+
+	results, err := target.CopyFrom(ctx, source)
+	if err != nil {
+		t.Fatalf("CopyFrom: %v", err)
+	}
+	if result := copyResult(t, results, "secret"); result.Source != rows || result.Target != rows {
+		t.Errorf("secret result = %+v, want %d rows", result, rows)
+	}
+	if result := copyResult(t, results, "jpid"); result.Source != 0 || result.Target != 0 {
+		t.Errorf("jpid result = %+v", result)
+	}
+	last, err := target.DB().Model("secret").Order("id DESC").One()
+	if err != nil || last["id"].Int() != rows*2 || last["name"].String() != fmt.Sprintf("S%03d", rows) {
+		t.Errorf("last copied row = %v, %v", last, err)
+	}
+	if count, _ := target.DB().Model("secret").Where("name", "OLD").Count(); count != 0 {
+		t.Error("existing target rows were not cleared")
+	}
+
+	// JSON 逻辑备份按同样的批次恢复
+	backup, err := source.Backup(ctx, filepath.Join(t.TempDir(), "backup.json"), BackupFormatJSON)
+	if err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+	if _, err = target.DB().Model("secret").Where("1=1").Delete(); err != nil {
+		t.Fatal(err)
+	}
+	results, err = target.Restore(ctx, backup)
+	if err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if result := copyResult(t, results, "secret"); result.Source != rows || result.Target != rows {
+		t.Errorf("restored secret result = %+v, want %d rows", result, rows)
+	}
+
+	// SQLite 备份文件按主键分页恢复
+	backup, err = source.Backup(ctx, filepath.Join(t.TempDir(), "backup.sqlite3"), BackupFormatSQLite)
+	if err != nil {
+		t.Fatalf("Backup sqlite: %v", err)
+	}
+	results, err = target.Restore(ctx, backup)
+	if err != nil {
+		t.Fatalf("Restore sqlite: %v", err)
+	}
+	if result := copyResult(t, results, "secret"); result.Source != rows || result.Target != rows {
+		t.Errorf("restored sqlite secret result = %+v, want %d rows", result, rows)
+	}
+}
+
+func TestPruneBackups(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{
+		// 切换数据库类型前后的定时备份，mysql 的文件名排在 sqlite 前面但时间更新
+		"omniscient-auto-sqlite-20240101-020000.sqlite3",
+		"omniscient-auto-sqlite-20240102-020000.sqlite3",
+		"omniscient-auto-mysql-20240103-020000.json",
+		"omniscient-auto-mysql-20240104-020000.json",
+		// 手动备份和无法识别的文件不清理
+		"omniscient-sqlite-20230101-000000.sqlite3",
+		"omniscient-auto-notes.txt",
+	}
+	for _, name := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := pruneBackups(dir, 2); err != nil {
+		t.Fatalf("pruneBackups: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var left []string
+	for _, entry := range entries {
+		left = append(left, entry.Name())
+	}
+	sort.Strings(left)
+	want := []string{
+		"omniscient-auto-mysql-20240103-020000.json",
+		"omniscient-auto-mysql-20240104-020000.json",
+		"omniscient-auto-notes.txt",
+		"omniscient-sqlite-20230101-000000.sqlite3",
+	}
+	if strings.Join(left, ",") != strings.Join(want, ",") {
+		t.Errorf("left = %v, want %v", left, want)
+	}
+}
@@ -0,0 +1,819 @@
+package service
+
+// tableSchema 数据表定义
+type tableSchema struct {
+	Name string            // 表名
+	DDL  map[string]string // 按数据库类型区分的建表语句
+}
+
+// managedTables 系统管理的全部数据表
+// 建表、备份、恢复、跨库迁移都以此为准，顺序即创建和导入的顺序
+var managedTables = []tableSchema{
+	{
+		Name: "jpid",
+		DDL: map[string]string{
+			"mysql": `
+			CREATE TABLE IF NOT EXISTS jpid (
+				id INT NOT NULL AUTO_INCREMENT,
+				name VARCHAR(120) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT 'java项目名',
+				ports VARCHAR(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '运行端口,多个逗号隔开',
+				pid INT NOT NULL COMMENT 'pid',
+				catalog VARCHAR(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '运行目录',
+				run LONGTEXT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci COMMENT '原生启动命令',
+				script LONGTEXT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci COMMENT 'sh脚本启动命令',
+				worker VARCHAR(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '服务器',
+				status INT DEFAULT '0' COMMENT '状态[1:启动，0:停止]',
+				description VARCHAR(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '项目描述',
+				way INT DEFAULT '2' COMMENT '启动方式[1:docker, 2:jdk]',
+				autostart INT DEFAULT '0' COMMENT '自启[0:没有自启, 1:自启]',
+				PRIMARY KEY (id)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='java项目详情';
+			`,
+			"sqlite": `
+			CREATE TABLE IF NOT EXISTS jpid (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT NOT NULL, -- java项目名
+				ports TEXT NOT NULL, -- 运行端口,多个逗号隔开
+				pid INTEGER NOT NULL, -- pid
+				catalog TEXT DEFAULT NULL, -- 运行目录
+				run TEXT, -- 原生启动命令
+				script TEXT, -- sh脚本启动命令
+				worker TEXT NOT NULL, -- 服务器
+				status INTEGER DEFAULT 0, -- 状态[1:启动，0:停止]
+				description TEXT DEFAULT NULL, -- 项目描述
+				way INTEGER DEFAULT 2, -- 启动方式[1:docker, 2:jdk]
+				autostart INTEGER DEFAULT 0 -- 自启[0:没有自启, 1:自启]
+			);
+			`,
+			"pgsql": `
+			CREATE TABLE IF NOT EXISTS jpid (
+				id SERIAL PRIMARY KEY,
+				name VARCHAR(120) NOT NULL, -- java项目名
+				ports VARCHAR(50) NOT NULL, -- 运行端口,多个逗号隔开
+				pid INTEGER NOT NULL, -- pid
+				catalog VARCHAR(100) DEFAULT NULL, -- 运行目录
+				run TEXT, -- 原生启动命令
+				script TEXT, -- sh脚本启动命令
+				worker VARCHAR(50) NOT NULL, -- 服务器
+				status INTEGER DEFAULT 0, -- 状态[1:启动，0:停止]
+				description VARCHAR(100) DEFAULT NULL, -- 项目描述
+				way INTEGER DEFAULT 2, -- 启动方式[1:docker, 2:jdk]
+				autostart INTEGER DEFAULT 0 -- 自启[0:没有自启, 1:自启]
+			);
+			`,
+		},
+	},
+	{
+		Name: "secret",
+		DDL: map[string]string{
+			"mysql": `
+			CREATE TABLE IF NOT EXISTS secret (
+				id INT NOT NULL AUTO_INCREMENT,
+				name VARCHAR(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '密钥名称',
+				ciphertext TEXT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '加密后的值',
+				key_id VARCHAR(16) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '加密使用的主密钥标识',
+				description VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '说明',
+				created_at DATETIME DEFAULT NULL COMMENT '创建时间',
+				updated_at DATETIME DEFAULT NULL COMMENT '更新时间',
+				PRIMARY KEY (id),
+				UNIQUE KEY uk_secret_name (name)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='加密密钥';
+			`,
+			"sqlite": `
+			CREATE TABLE IF NOT EXISTS secret (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT NOT NULL UNIQUE, -- 密钥名称
+				ciphertext TEXT NOT NULL, -- 加密后的值
+				key_id TEXT NOT NULL, -- 加密使用的主密钥标识
+				description TEXT DEFAULT NULL, -- 说明
+				created_at DATETIME DEFAULT NULL, -- 创建时间
+				updated_at DATETIME DEFAULT NULL -- 更新时间
+			);
+			`,
+			"pgsql": `
+			CREATE TABLE IF NOT EXISTS secret (
+				id SERIAL PRIMARY KEY,
+				name VARCHAR(100) NOT NULL UNIQUE, -- 密钥名称
+				ciphertext TEXT NOT NULL, -- 加密后的值
+				key_id VARCHAR(16) NOT NULL, -- 加密使用的主密钥标识
+				description VARCHAR(255) DEFAULT NULL, -- 说明
+				created_at TIMESTAMP DEFAULT NULL, -- 创建时间
+				updated_at TIMESTAMP DEFAULT NULL -- 更新时间
+			);
+			`,
+		},
+	},
+	{
+		Name: "jdk",
+		DDL: map[string]string{
+			"mysql": `
+			CREATE TABLE IF NOT EXISTS jdk (
+				id INT NOT NULL AUTO_INCREMENT,
+				worker VARCHAR(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '服务器',
+				home VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT 'JDK目录',
+				version VARCHAR(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT 'Java版本',
+				vendor VARCHAR(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '发行厂商',
+				source VARCHAR(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '来源[scan:目录扫描, process:运行进程]',
+				created_at DATETIME DEFAULT NULL COMMENT '创建时间',
+				updated_at DATETIME DEFAULT NULL COMMENT '更新时间',
+				PRIMARY KEY (id),
+				UNIQUE KEY uk_jdk_worker_home (worker, home)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='JDK清单';
+			`,
+			"sqlite": `
+			CREATE TABLE IF NOT EXISTS jdk (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				worker TEXT NOT NULL, -- 服务器
+				home TEXT NOT NULL, -- JDK目录
+				version TEXT DEFAULT NULL, -- Java版本
+				vendor TEXT DEFAULT NULL, -- 发行厂商
+				source TEXT DEFAULT NULL, -- 来源[scan:目录扫描, process:运行进程]
+				created_at DATETIME DEFAULT NULL, -- 创建时间
+				updated_at DATETIME DEFAULT NULL, -- 更新时间
+				UNIQUE (worker, home)
+			);
+			`,
+			"pgsql": `
+			CREATE TABLE IF NOT EXISTS jdk (
+				id SERIAL PRIMARY KEY,
+				worker VARCHAR(50) NOT NULL, -- 服务器
+				home VARCHAR(255) NOT NULL, -- JDK目录
+				version VARCHAR(50) DEFAULT NULL, -- Java版本
+				vendor VARCHAR(100) DEFAULT NULL, -- 发行厂商
+				source VARCHAR(20) DEFAULT NULL, -- 来源[scan:目录扫描, process:运行进程]
+				created_at TIMESTAMP DEFAULT NULL, -- 创建时间
+				updated_at TIMESTAMP DEFAULT NULL, -- 更新时间
+				UNIQUE (worker, home)
+			);
+			`,
+		},
+	},
+	{
+		Name: "artifact",
+		DDL: map[string]string{
+			"mysql": `
+			CREATE TABLE IF NOT EXISTS artifact (
+				id INT NOT NULL AUTO_INCREMENT,
+				jpid_id INT NOT NULL COMMENT '项目ID',
+				version INT NOT NULL COMMENT '制品版本号[项目内递增]',
+				file_name VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '上传的文件名',
+				path VARCHAR(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '制品存储路径',
+				checksum VARCHAR(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT 'SHA-256',
+				size BIGINT DEFAULT '0' COMMENT '文件大小[字节]',
+				uploader VARCHAR(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '上传人',
+				note VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '备注',
+				created_at DATETIME DEFAULT NULL COMMENT '上传时间',
+				PRIMARY KEY (id),
+				UNIQUE KEY uk_artifact_version (jpid_id, version)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='jar制品';
+			`,
+			"sqlite": `
+			CREATE TABLE IF NOT EXISTS artifact (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				jpid_id INTEGER NOT NULL, -- 项目ID
+				version INTEGER NOT NULL, -- 制品版本号[项目内递增]
+				file_name TEXT NOT NULL, -- 上传的文件名
+				path TEXT NOT NULL, -- 制品存储路径
+				checksum TEXT NOT NULL, -- SHA-256
+				size INTEGER DEFAULT 0, -- 文件大小[字节]
+				uploader TEXT DEFAULT NULL, -- 上传人
+				note TEXT DEFAULT NULL, -- 备注
+				created_at DATETIME DEFAULT NULL, -- 上传时间
+				UNIQUE (jpid_id, version)
+			);
+			`,
+			"pgsql": `
+			CREATE TABLE IF NOT EXISTS artifact (
+				id SERIAL PRIMARY KEY,
+				jpid_id INTEGER NOT NULL, -- 项目ID
+				version INTEGER NOT NULL, -- 制品版本号[项目内递增]
+				file_name VARCHAR(255) NOT NULL, -- 上传的文件名
+				path VARCHAR(500) NOT NULL, -- 制品存储路径
+				checksum VARCHAR(64) NOT NULL, -- SHA-256
+				size BIGINT DEFAULT 0, -- 文件大小[字节]
+				uploader VARCHAR(100) DEFAULT NULL, -- 上传人
+				note VARCHAR(255) DEFAULT NULL, -- 备注
+				created_at TIMESTAMP DEFAULT NULL, -- 上传时间
+				UNIQUE (jpid_id, version)
+			);
+			`,
+		},
+	},
+	{
+		Name: "deployment",
+		DDL: map[string]string{
+			"mysql": `
+			CREATE TABLE IF NOT EXISTS deployment (
+				id INT NOT NULL AUTO_INCREMENT,
+				jpid_id INT NOT NULL COMMENT '项目ID',
+				artifact_id INT NOT NULL COMMENT '部署的制品ID',
+				version INT NOT NULL COMMENT '部署的制品版本号',
+				previous_id INT DEFAULT '0' COMMENT '部署前的制品ID[0:无]',
+				action VARCHAR(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '动作[deploy:部署, rollback:回滚]',
+				status VARCHAR(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '状态[running, success, failed, rolled_back, rollback_failed]',
+				message TEXT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci COMMENT '说明',
+				operator VARCHAR(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '操作人',
+				started_at DATETIME DEFAULT NULL COMMENT '开始时间',
+				finished_at DATETIME DEFAULT NULL COMMENT '结束时间',
+				PRIMARY KEY (id),
+				KEY idx_deployment_jpid (jpid_id)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='部署记录';
+			`,
+			"sqlite": `
+			CREATE TABLE IF NOT EXISTS deployment (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				jpid_id INTEGER NOT NULL, -- 项目ID
+				artifact_id INTEGER NOT NULL, -- 部署的制品ID
+				version INTEGER NOT NULL, -- 部署的制品版本号
+				previous_id INTEGER DEFAULT 0, -- 部署前的制品ID[0:无]
+				action TEXT NOT NULL, -- 动作[deploy:部署, rollback:回滚]
+				status TEXT NOT NULL, -- 状态[running, success, failed, rolled_back, rollback_failed]
+				message TEXT, -- 说明
+				operator TEXT DEFAULT NULL, -- 操作人
+				started_at DATETIME DEFAULT NULL, -- 开始时间
+				finished_at DATETIME DEFAULT NULL -- 结束时间
+			);
+			`,
+			"pgsql": `
+			CREATE TABLE IF NOT EXISTS deployment (
+				id SERIAL PRIMARY KEY,
+				jpid_id INTEGER NOT NULL, -- 项目ID
+				artifact_id INTEGER NOT NULL, -- 部署的制品ID
+				version INTEGER NOT NULL, -- 部署的制品版本号
+				previous_id INTEGER DEFAULT 0, -- 部署前的制品ID[0:无]
+				action VARCHAR(20) NOT NULL, -- 动作[deploy:部署, rollback:回滚]
+				status VARCHAR(20) NOT NULL, -- 状态[running, success, failed, rolled_back, rollback_failed]
+				message TEXT, -- 说明
+				operator VARCHAR(100) DEFAULT NULL, -- 操作人
+				started_at TIMESTAMP DEFAULT NULL, -- 开始时间
+				finished_at TIMESTAMP DEFAULT NULL -- 结束时间
+			);
+			`,
+		},
+	},
+	{
+		Name: "jpid_run",
+		DDL: map[string]string{
+			"mysql": `
+			CREATE TABLE IF NOT EXISTS jpid_run (
+				id INT NOT NULL AUTO_INCREMENT,
+				jpid_id INT NOT NULL COMMENT '项目ID',
+				worker VARCHAR(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '服务器',
+				pid INT NOT NULL COMMENT 'pid',
+				jar_path VARCHAR(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT 'jar 路径',
+				checksum VARCHAR(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT 'jar SHA-256',
+				build_version VARCHAR(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '构建版本',
+				build_commit VARCHAR(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT 'git 提交号',
+				jar_info LONGTEXT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci COMMENT 'jar 构建信息[JSON]',
+				started_at DATETIME DEFAULT NULL COMMENT '启动时间',
+				stopped_at DATETIME DEFAULT NULL COMMENT '停止时间[为空表示运行中]',
+				PRIMARY KEY (id),
+				KEY idx_jpid_run_jpid (jpid_id)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='项目运行记录';
+			`,
+			"sqlite": `
+			CREATE TABLE IF NOT EXISTS jpid_run (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				jpid_id INTEGER NOT NULL, -- 项目ID
+				worker TEXT NOT NULL, -- 服务器
+				pid INTEGER NOT NULL, -- pid
+				jar_path TEXT DEFAULT NULL, -- jar 路径
+				checksum TEXT DEFAULT NULL, -- jar SHA-256
+				build_version TEXT DEFAULT NULL, -- 构建版本
+				build_commit TEXT DEFAULT NULL, -- git 提交号
+				jar_info TEXT, -- jar 构建信息[JSON]
+				started_at DATETIME DEFAULT NULL, -- 启动时间
+				stopped_at DATETIME DEFAULT NULL -- 停止时间[为空表示运行中]
+			);
+			`,
+			"pgsql": `
+			CREATE TABLE IF NOT EXISTS jpid_run (
+				id SERIAL PRIMARY KEY,
+				jpid_id INTEGER NOT NULL, -- 项目ID
+				worker VARCHAR(50) NOT NULL, -- 服务器
+				pid INTEGER NOT NULL, -- pid
+				jar_path VARCHAR(500) DEFAULT NULL, -- jar 路径
+				checksum VARCHAR(64) DEFAULT NULL, -- jar SHA-256
+				build_version VARCHAR(100) DEFAULT NULL, -- 构建版本
+				build_commit VARCHAR(64) DEFAULT NULL, -- git 提交号
+				jar_info TEXT, -- jar 构建信息[JSON]
+				started_at TIMESTAMP DEFAULT NULL, -- 启动时间
+				stopped_at TIMESTAMP DEFAULT NULL -- 停止时间[为空表示运行中]
+			);
+			`,
+		},
+	},
+	{
+		Name: "jpid_metric",
+		DDL: map[string]string{
+			"mysql": `
+			CREATE TABLE IF NOT EXISTS jpid_metric (
+				id BIGINT NOT NULL AUTO_INCREMENT,
+				jpid_id INT NOT NULL COMMENT '项目ID',
+				name VARCHAR(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '指标名',
+				mbean VARCHAR(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT 'MBean',
+				value DOUBLE DEFAULT NULL COMMENT '数值',
+				raw TEXT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci COMMENT '非数值的原始值[JSON]',
+				collected_at DATETIME NOT NULL COMMENT '采集时间',
+				PRIMARY KEY (id),
+				KEY idx_jpid_metric_jpid (jpid_id, collected_at)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='项目 JMX 指标';
+			`,
+			"sqlite": `
+			CREATE TABLE IF NOT EXISTS jpid_metric (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				jpid_id INTEGER NOT NULL, -- 项目ID
+				name TEXT NOT NULL, -- 指标名
+				mbean TEXT NOT NULL, -- MBean
+				value REAL DEFAULT NULL, -- 数值
+				raw TEXT, -- 非数值的原始值[JSON]
+				collected_at DATETIME NOT NULL -- 采集时间
+			);
+			`,
+			"pgsql": `
+			CREATE TABLE IF NOT EXISTS jpid_metric (
+				id BIGSERIAL PRIMARY KEY,
+				jpid_id INTEGER NOT NULL, -- 项目ID
+				name VARCHAR(100) NOT NULL, -- 指标名
+				mbean VARCHAR(500) NOT NULL, -- MBean
+				value DOUBLE PRECISION DEFAULT NULL, -- 数值
+				raw TEXT, -- 非数值的原始值[JSON]
+				collected_at TIMESTAMP NOT NULL -- 采集时间
+			);
+			`,
+		},
+	},
+	{
+		Name: "notification",
+		DDL: map[string]string{
+			"mysql": `
+			CREATE TABLE IF NOT EXISTS notification (
+				id INT NOT NULL AUTO_INCREMENT,
+				jpid_id INT DEFAULT '0' COMMENT '项目ID[0:与项目无关]',
+				worker VARCHAR(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '服务器',
+				level VARCHAR(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '级别[info, warning, error]',
+				title VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '标题',
+				content TEXT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci COMMENT '内容',
+				created_at DATETIME NOT NULL COMMENT '创建时间',
+				read_at DATETIME DEFAULT NULL COMMENT '已读时间[为空表示未读]',
+				PRIMARY KEY (id),
+				KEY idx_notification_read (read_at)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='站内通知';
+			`,
+			"sqlite": `
+			CREATE TABLE IF NOT EXISTS notification (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				jpid_id INTEGER DEFAULT 0, -- 项目ID[0:与项目无关]
+				worker TEXT DEFAULT NULL, -- 服务器
+				level TEXT NOT NULL, -- 级别[info, warning, error]
+				title TEXT NOT NULL, -- 标题
+				content TEXT, -- 内容
+				created_at DATETIME NOT NULL, -- 创建时间
+				read_at DATETIME DEFAULT NULL -- 已读时间[为空表示未读]
+			);
+			`,
+			"pgsql": `
+			CREATE TABLE IF NOT EXISTS notification (
+				id SERIAL PRIMARY KEY,
+				jpid_id INTEGER DEFAULT 0, -- 项目ID[0:与项目无关]
+				worker VARCHAR(50) DEFAULT NULL, -- 服务器
+				level VARCHAR(20) NOT NULL, -- 级别[info, warning, error]
+				title VARCHAR(255) NOT NULL, -- 标题
+				content TEXT, -- 内容
+				created_at TIMESTAMP NOT NULL, -- 创建时间
+				read_at TIMESTAMP DEFAULT NULL -- 已读时间[为空表示未读]
+			);
+			`,
+		},
+	},
+	{
+		Name: "alert_channel",
+		DDL: map[string]string{
+			"mysql": `
+			CREATE TABLE IF NOT EXISTS alert_channel (
+				id INT NOT NULL AUTO_INCREMENT,
+				name VARCHAR(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '渠道名称',
+				type VARCHAR(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '渠道类型[webhook, dingtalk, wecom, feishu, email]',
+				url VARCHAR(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT 'webhook 或机器人地址',
+				secret VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '机器人加签密钥',
+				config TEXT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci COMMENT '邮件配置[JSON]',
+				enabled INT DEFAULT '1' COMMENT '启用[0:停用, 1:启用]',
+				created_at DATETIME DEFAULT NULL COMMENT '创建时间',
+				PRIMARY KEY (id),
+				UNIQUE KEY uk_alert_channel_name (name)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='告警通知渠道';
+			`,
+			"sqlite": `
+			CREATE TABLE IF NOT EXISTS alert_channel (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT NOT NULL UNIQUE, -- 渠道名称
+				type TEXT NOT NULL, -- 渠道类型[webhook, dingtalk, wecom, feishu, email]
+				url TEXT DEFAULT NULL, -- webhook 或机器人地址
+				secret TEXT DEFAULT NULL, -- 机器人加签密钥
+				config TEXT, -- 邮件配置[JSON]
+				enabled INTEGER DEFAULT 1, -- 启用[0:停用, 1:启用]
+				created_at DATETIME DEFAULT NULL -- 创建时间
+			);
+			`,
+			"pgsql": `
+			CREATE TABLE IF NOT EXISTS alert_channel (
+				id SERIAL PRIMARY KEY,
+				name VARCHAR(100) NOT NULL UNIQUE, -- 渠道名称
+				type VARCHAR(20) NOT NULL, -- 渠道类型[webhook, dingtalk, wecom, feishu, email]
+				url VARCHAR(500) DEFAULT NULL, -- webhook 或机器人地址
+				secret VARCHAR(255) DEFAULT NULL, -- 机器人加签密钥
+				config TEXT, -- 邮件配置[JSON]
+				enabled INTEGER DEFAULT 1, -- 启用[0:停用, 1:启用]
+				created_at TIMESTAMP DEFAULT NULL -- 创建时间
+			);
+			`,
+		},
+	},
+	{
+		Name: "alert_rule",
+		DDL: map[string]string{
+			"mysql": `
+			CREATE TABLE IF NOT EXISTS alert_rule (
+				id INT NOT NULL AUTO_INCREMENT,
+				name VARCHAR(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '规则名称',
+				type VARCHAR(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '规则类型[stopped, restart_loop, unhealthy, rss, port]',
+				jpid_id INT DEFAULT '0' COMMENT '项目ID[0:全部项目]',
+				threshold DOUBLE DEFAULT '0' COMMENT '阈值[restart_loop:启动次数, rss:MB]',
+				duration INT DEFAULT '0' COMMENT '持续时间秒[restart_loop:统计窗口, unhealthy:连续失败时间]',
+				channels VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '通知渠道ID[逗号隔开, 为空使用全部启用的渠道]',
+				repeat_interval INT DEFAULT '0' COMMENT '未恢复时重复通知的间隔秒[0:只通知一次]',
+				silence_until DATETIME DEFAULT NULL COMMENT '静默截止时间',
+				enabled INT DEFAULT '1' COMMENT '启用[0:停用, 1:启用]',
+				created_at DATETIME DEFAULT NULL COMMENT '创建时间',
+				PRIMARY KEY (id)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='告警规则';
+			`,
+			"sqlite": `
+			CREATE TABLE IF NOT EXISTS alert_rule (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT NOT NULL, -- 规则名称
+				type TEXT NOT NULL, -- 规则类型[stopped, restart_loop, unhealthy, rss, port]
+				jpid_id INTEGER DEFAULT 0, -- 项目ID[0:全部项目]
+				threshold REAL DEFAULT 0, -- 阈值[restart_loop:启动次数, rss:MB]
+				duration INTEGER DEFAULT 0, -- 持续时间秒[restart_loop:统计窗口, unhealthy:连续失败时间]
+				channels TEXT DEFAULT NULL, -- 通知渠道ID[逗号隔开, 为空使用全部启用的渠道]
+				repeat_interval INTEGER DEFAULT 0, -- 未恢复时重复通知的间隔秒[0:只通知一次]
+				silence_until DATETIME DEFAULT NULL, -- 静默截止时间
+				enabled INTEGER DEFAULT 1, -- 启用[0:停用, 1:启用]
+				created_at DATETIME DEFAULT NULL -- 创建时间
+			);
+			`,
+			"pgsql": `
+			CREATE TABLE IF NOT EXISTS alert_rule (
+				id SERIAL PRIMARY KEY,
+				name VARCHAR(100) NOT NULL, -- 规则名称
+				type VARCHAR(20) NOT NULL, -- 规则类型[stopped, restart_loop, unhealthy, rss, port]
+				jpid_id INTEGER DEFAULT 0, -- 项目ID[0:全部项目]
+				threshold DOUBLE PRECISION DEFAULT 0, -- 阈值[restart_loop:启动次数, rss:MB]
+				duration INTEGER DEFAULT 0, -- 持续时间秒[restart_loop:统计窗口, unhealthy:连续失败时间]
+				channels VARCHAR(255) DEFAULT NULL, -- 通知渠道ID[逗号隔开, 为空使用全部启用的渠道]
+				repeat_interval INTEGER DEFAULT 0, -- 未恢复时重复通知的间隔秒[0:只通知一次]
+				silence_until TIMESTAMP DEFAULT NULL, -- 静默截止时间
+				enabled INTEGER DEFAULT 1, -- 启用[0:停用, 1:启用]
+				created_at TIMESTAMP DEFAULT NULL -- 创建时间
+			);
+			`,
+		},
+	},
+	{
+		Name: "alert_event",
+		DDL: map[string]string{
+			"mysql": `
+			CREATE TABLE IF NOT EXISTS alert_event (
+				id INT NOT NULL AUTO_INCREMENT,
+				rule_id INT NOT NULL COMMENT '规则ID',
+				jpid_id INT NOT NULL COMMENT '项目ID',
+				worker VARCHAR(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '服务器',
+				status VARCHAR(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '状态[firing, resolved]',
+				message TEXT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci COMMENT '告警内容',
+				started_at DATETIME NOT NULL COMMENT '触发时间',
+				notified_at DATETIME DEFAULT NULL COMMENT '最近通知时间[为空表示静默中未通知]',
+				resolved_at DATETIME DEFAULT NULL COMMENT '恢复时间',
+				PRIMARY KEY (id),
+				KEY idx_alert_event_rule (rule_id, jpid_id, status)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='告警事件';
+			`,
+			"sqlite": `
+			CREATE TABLE IF NOT EXISTS alert_event (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				rule_id INTEGER NOT NULL, -- 规则ID
+				jpid_id INTEGER NOT NULL, -- 项目ID
+				worker TEXT NOT NULL, -- 服务器
+				status TEXT NOT NULL, -- 状态[firing, resolved]
+				message TEXT, -- 告警内容
+				started_at DATETIME NOT NULL, -- 触发时间
+				notified_at DATETIME DEFAULT NULL, -- 最近通知时间[为空表示静默中未通知]
+				resolved_at DATETIME DEFAULT NULL -- 恢复时间
+			);
+			`,
+			"pgsql": `
+			CREATE TABLE IF NOT EXISTS alert_event (
+				id SERIAL PRIMARY KEY,
+				rule_id INTEGER NOT NULL, -- 规则ID
+				jpid_id INTEGER NOT NULL, -- 项目ID
+				worker VARCHAR(50) NOT NULL, -- 服务器
+				status VARCHAR(20) NOT NULL, -- 状态[firing, resolved]
+				message TEXT, -- 告警内容
+				started_at TIMESTAMP NOT NULL, -- 触发时间
+				notified_at TIMESTAMP DEFAULT NULL, -- 最近通知时间[为空表示静默中未通知]
+				resolved_at TIMESTAMP DEFAULT NULL -- 恢复时间
+			);
+			`,
+		},
+	},
+	{
+		Name: "webhook",
+		DDL: map[string]string{
+			"mysql": `
+			CREATE TABLE IF NOT EXISTS webhook (
+				id INT NOT NULL AUTO_INCREMENT,
+				name VARCHAR(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '订阅名称',
+				url VARCHAR(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '推送地址',
+				secret VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '签名密钥[HMAC-SHA256]',
+				events VARCHAR(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '订阅事件[逗号分隔，支持 project.* 前缀，为空表示全部]',
+				enabled INT DEFAULT '1' COMMENT '启用[0:停用, 1:启用]',
+				created_at DATETIME DEFAULT NULL COMMENT '创建时间',
+				PRIMARY KEY (id),
+				UNIQUE KEY uk_webhook_name (name)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='出站 webhook 订阅';
+			`,
+			"sqlite": `
+			CREATE TABLE IF NOT EXISTS webhook (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				name TEXT NOT NULL UNIQUE, -- 订阅名称
+				url TEXT NOT NULL, -- 推送地址
+				secret TEXT DEFAULT NULL, -- 签名密钥[HMAC-SHA256]
+				events TEXT DEFAULT NULL, -- 订阅事件[逗号分隔，支持 project.* 前缀，为空表示全部]
+				enabled INTEGER DEFAULT 1, -- 启用[0:停用, 1:启用]
+				created_at DATETIME DEFAULT NULL -- 创建时间
+			);
+			`,
+			"pgsql": `
+			CREATE TABLE IF NOT EXISTS webhook (
+				id SERIAL PRIMARY KEY,
+				name VARCHAR(100) NOT NULL UNIQUE, -- 订阅名称
+				url VARCHAR(500) NOT NULL, -- 推送地址
+				secret VARCHAR(255) DEFAULT NULL, -- 签名密钥[HMAC-SHA256]
+				events VARCHAR(500) DEFAULT NULL, -- 订阅事件[逗号分隔，支持 project.* 前缀，为空表示全部]
+				enabled INTEGER DEFAULT 1, -- 启用[0:停用, 1:启用]
+				created_at TIMESTAMP DEFAULT NULL -- 创建时间
+			);
+			`,
+		},
+	},
+	{
+		Name: "webhook_delivery",
+		DDL: map[string]string{
+			"mysql": `
+			CREATE TABLE IF NOT EXISTS webhook_delivery (
+				id INT NOT NULL AUTO_INCREMENT,
+				webhook_id INT NOT NULL COMMENT '订阅ID',
+				worker VARCHAR(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '投递服务器',
+				event_id BIGINT NOT NULL COMMENT '事件ID',
+				event_type VARCHAR(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '事件类型',
+				payload LONGTEXT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '请求体[JSON]',
+				status VARCHAR(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci NOT NULL COMMENT '状态[pending, success, failed]',
+				attempts INT DEFAULT '0' COMMENT '已尝试次数',
+				next_attempt_at DATETIME DEFAULT NULL COMMENT '下次尝试时间',
+				response_status INT DEFAULT '0' COMMENT '最近一次响应状态码',
+				last_error TEXT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci COMMENT '最近一次错误',
+				created_at DATETIME NOT NULL COMMENT '创建时间',
+				delivered_at DATETIME DEFAULT NULL COMMENT '投递成功时间',
+				PRIMARY KEY (id),
+				KEY idx_webhook_delivery_status (status, worker, next_attempt_at),
+				KEY idx_webhook_delivery_webhook (webhook_id)
+			) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_general_ci COMMENT='webhook 投递记录';
+			`,
+			"sqlite": `
+			CREATE TABLE IF NOT EXISTS webhook_delivery (
+				id INTEGER PRIMARY KEY AUTOINCREMENT,
+				webhook_id INTEGER NOT NULL, -- 订阅ID
+				worker TEXT NOT NULL, -- 投递服务器
+				event_id INTEGER NOT NULL, -- 事件ID
+				event_type TEXT NOT NULL, -- 事件类型
+				payload TEXT NOT NULL, -- 请求体[JSON]
+				status TEXT NOT NULL, -- 状态[pending, success, failed]
+				attempts INTEGER DEFAULT 0, -- 已尝试次数
+				next_attempt_at DATETIME DEFAULT NULL, -- 下次尝试时间
+				response_status INTEGER DEFAULT 0, -- 最近一次响应状态码
+				last_error TEXT, -- 最近一次错误
+				created_at DATETIME NOT NULL, -- 创建时间
+				delivered_at DATETIME DEFAULT NULL -- 投递成功时间
+			);
+			`,
+			"pgsql": `
+			CREATE TABLE IF NOT EXISTS webhook_delivery (
+				id SERIAL PRIMARY KEY,
+				webhook_id INTEGER NOT NULL, -- 订阅ID
+				worker VARCHAR(50) NOT NULL, -- 投递服务器
+				event_id BIGINT NOT NULL, -- 事件ID
+				event_type VARCHAR(50) NOT NULL, -- 事件类型
+				payload TEXT NOT NULL, -- 请求体[JSON]
+				status VARCHAR(20) NOT NULL, -- 状态[pending, success, failed]
+				attempts INTEGER DEFAULT 0, -- 已尝试次数
+				next_attempt_at TIMESTAMP DEFAULT NULL, -- 下次尝试时间
+				response_status INTEGER DEFAULT 0, -- 最近一次响应状态码
+				last_error TEXT, -- 最近一次错误
+				created_at TIMESTAMP NOT NULL, -- 创建时间
+				delivered_at TIMESTAMP DEFAULT NULL -- 投递成功时间
+			);
+			`,
+		},
+	},
+}
+
+// columnSchema 增量字段定义
+type columnSchema struct {
+	Table string            // 表名
+	Name  string            // 字段名
+	DDL   map[string]string // 按数据库类型区分的字段定义
+}
+
+// managedColumns 在初始建表之后新增的字段，启动时自动补齐，老版本创建的数据表也能平滑升级
+var managedColumns = []columnSchema{
+	{
+		Table: "jpid",
+		Name:  "env",
+		DDL: map[string]string{
+			"mysql":  "LONGTEXT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci COMMENT '环境变量[JSON]'",
+			"sqlite": "TEXT DEFAULT NULL",
+			"pgsql":  "TEXT DEFAULT NULL",
+		},
+	},
+	{
+		Table: "jpid",
+		Name:  "health_check",
+		DDL: map[string]string{
+			"mysql":  "VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '健康检查地址[http(s)://, tcp://]'",
+			"sqlite": "TEXT DEFAULT NULL",
+			"pgsql":  "VARCHAR(255) DEFAULT NULL",
+		},
+	},
+	{
+		Table: "jpid",
+		Name:  "manifest",
+		DDL: map[string]string{
+			"mysql":  "VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '声明清单文件[为空表示非清单管理]'",
+			"sqlite": "TEXT DEFAULT NULL",
+			"pgsql":  "VARCHAR(255) DEFAULT NULL",
+		},
+	},
+	{
+		Table: "jpid",
+		Name:  "jvm_opts",
+		DDL: map[string]string{
+			"mysql":  "LONGTEXT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci COMMENT 'JVM参数[JSON]'",
+			"sqlite": "TEXT DEFAULT NULL",
+			"pgsql":  "TEXT DEFAULT NULL",
+		},
+	},
+	{
+		Table: "jpid",
+		Name:  "args",
+		DDL: map[string]string{
+			"mysql":  "LONGTEXT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci COMMENT '程序参数[JSON数组]'",
+			"sqlite": "TEXT DEFAULT NULL",
+			"pgsql":  "TEXT DEFAULT NULL",
+		},
+	},
+	{
+		Table: "jpid",
+		Name:  "jdk_home",
+		DDL: map[string]string{
+			"mysql":  "VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT 'JDK目录[为空使用PATH中的java]'",
+			"sqlite": "TEXT DEFAULT NULL",
+			"pgsql":  "VARCHAR(255) DEFAULT NULL",
+		},
+	},
+	{
+		Table: "jpid",
+		Name:  "jdk_id",
+		DDL: map[string]string{
+			"mysql":  "INT DEFAULT '0' COMMENT '指定的JDK[jdk.id, 0:未指定]'",
+			"sqlite": "INTEGER DEFAULT 0",
+			"pgsql":  "INTEGER DEFAULT 0",
+		},
+	},
+	{
+		Table: "jpid",
+		Name:  "artifact_id",
+		DDL: map[string]string{
+			"mysql":  "INT DEFAULT '0' COMMENT '当前部署的制品[artifact.id, 0:未通过制品部署]'",
+			"sqlite": "INTEGER DEFAULT 0",
+			"pgsql":  "INTEGER DEFAULT 0",
+		},
+	},
+	{
+		Table: "jpid",
+		Name:  "build_version",
+		DDL: map[string]string{
+			"mysql":  "VARCHAR(100) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '构建版本'",
+			"sqlite": "TEXT DEFAULT NULL",
+			"pgsql":  "VARCHAR(100) DEFAULT NULL",
+		},
+	},
+	{
+		Table: "jpid",
+		Name:  "build_commit",
+		DDL: map[string]string{
+			"mysql":  "VARCHAR(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT 'git 提交号'",
+			"sqlite": "TEXT DEFAULT NULL",
+			"pgsql":  "VARCHAR(64) DEFAULT NULL",
+		},
+	},
+	{
+		Table: "jpid",
+		Name:  "jar_checksum",
+		DDL: map[string]string{
+			"mysql":  "VARCHAR(64) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '运行中 jar 的 SHA-256'",
+			"sqlite": "TEXT DEFAULT NULL",
+			"pgsql":  "VARCHAR(64) DEFAULT NULL",
+		},
+	},
+	{
+		Table: "jpid",
+		Name:  "actuator",
+		DDL: map[string]string{
+			"mysql":  "VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT 'Actuator 地址[为空时按端口自动探测]'",
+			"sqlite": "TEXT DEFAULT NULL",
+			"pgsql":  "VARCHAR(255) DEFAULT NULL",
+		},
+	},
+	{
+		Table: "jpid",
+		Name:  "stop_strategy",
+		DDL: map[string]string{
+			"mysql":  "VARCHAR(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '停止方式[signal:信号, actuator:/actuator/shutdown]'",
+			"sqlite": "TEXT DEFAULT NULL",
+			"pgsql":  "VARCHAR(20) DEFAULT NULL",
+		},
+	},
+	{
+		Table: "jpid",
+		Name:  "jolokia",
+		DDL: map[string]string{
+			"mysql":  "VARCHAR(255) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT 'Jolokia 地址[为空不采集 JMX 指标]'",
+			"sqlite": "TEXT DEFAULT NULL",
+			"pgsql":  "VARCHAR(255) DEFAULT NULL",
+		},
+	},
+	{
+		Table: "jpid",
+		Name:  "jolokia_queries",
+		DDL: map[string]string{
+			"mysql":  "TEXT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci COMMENT 'MBean 查询[JSON数组, 为空使用 jolokia.queries]'",
+			"sqlite": "TEXT DEFAULT NULL",
+			"pgsql":  "TEXT DEFAULT NULL",
+		},
+	},
+	{
+		Table: "jpid",
+		Name:  "unit_name",
+		DDL: map[string]string{
+			"mysql":  "VARCHAR(120) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '自启服务名[不含 autostart- 前缀, 为空表示未关联]'",
+			"sqlite": "TEXT DEFAULT NULL",
+			"pgsql":  "VARCHAR(120) DEFAULT NULL",
+		},
+	},
+	{
+		Table: "jpid_run",
+		Name:  "exit_reason",
+		DDL: map[string]string{
+			"mysql":  "VARCHAR(20) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT '意外退出原因[oom_killer, native_oom, jvm_fatal, out_of_memory, stack_overflow, unknown]'",
+			"sqlite": "TEXT DEFAULT NULL",
+			"pgsql":  "VARCHAR(20) DEFAULT NULL",
+		},
+	},
+	{
+		Table: "jpid_run",
+		Name:  "exit_detail",
+		DDL: map[string]string{
+			"mysql":  "TEXT CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci COMMENT '退出原因说明'",
+			"sqlite": "TEXT DEFAULT NULL",
+			"pgsql":  "TEXT DEFAULT NULL",
+		},
+	},
+	{
+		Table: "jpid_run",
+		Name:  "crash_file",
+		DDL: map[string]string{
+			"mysql":  "VARCHAR(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_general_ci DEFAULT NULL COMMENT 'hs_err_pid 文件'",
+			"sqlite": "TEXT DEFAULT NULL",
+			"pgsql":  "VARCHAR(500) DEFAULT NULL",
+		},
+	},
+}
+
+// ManagedTableNames 获取系统管理的数据表名
+func ManagedTableNames() []string {
+	names := make([]string, 0, len(managedTables))
+	for _, table := range managedTables {
+		names = append(names, table.Name)
+	}
+	return names
+}
@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	_ "github.com/gogf/gf/contrib/drivers/pgsql/v2"
+	_ "github.com/gogf/gf/contrib/drivers/sqlite/v2"
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcfg"
+	"omniscient/internal/util/secretbox"
+)
+
+// testPgsqlDSNEnv PostgreSQL 集成测试的连接字符串，如 pgsql:user:pass@tcp(127.0.0.1:5432)/omniscient_test
+// 测试会在该库中建表，应使用专门的测试库
+const testPgsqlDSNEnv = "OMNISCIENT_TEST_PGSQL_DSN"
+
+var (
+	dialects = []string{"mysql", "sqlite", "pgsql"}
+	// ddlColumnPattern 建表语句中的字段行
+	ddlColumnPattern = regexp.MustCompile("^`?([a-z_]+)`?\\s+[A-Z]")
+	// mysqlOnly 只有 MySQL 支持的写法
+	mysqlOnly = []string{"AUTO_INCREMENT", "ENGINE=", "CHARACTER SET", "COLLATE", "COMMENT '", "LONGTEXT", "UNIQUE KEY", "KEY idx_", "`"}
+)
+
+// ddlColumns 建表语句中的字段名，按名称排序
+func ddlColumns(ddl string) []string {
+	var columns []string
+	for _, line := range strings.Split(ddl, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "CREATE ") || strings.HasPrefix(line, "PRIMARY ") || strings.HasPrefix(line, "UNIQUE ") {
+			continue
+		}
+		if match := ddlColumnPattern.FindStringSubmatch(line); match != nil {
+			columns = append(columns, match[1])
+		}
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+// TestMain 测试使用临时生成的主密钥，不在包目录下生成 ./data/secret.key
+func TestMain(m *testing.M) {
+	key, err := secretbox.GenerateKey()
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv(MasterKeyEnv, secretbox.EncodeKey(key))
+	os.Exit(m.Run())
+}
+
+var (
+	testDBOnce sync.Once
+	testDBErr  error
+)
+
+// useTestDB 将默认分组连接到临时的 sqlite 库并建表，整个测试进程共用，测试自己清理写入的数据
+func useTestDB(t *testing.T) {
+	t.Helper()
+	testDBOnce.Do(func() {
+		dir, err := os.MkdirTemp("", "omniscient-test")
+		if err != nil {
+			testDBErr = err
+			return
+		}
+		adapter, err := gcfg.NewAdapterContent("database:\n  sqlite:\n    link: \"sqlite::@file(" + filepath.Join(dir, "omniscient.sqlite3") + ")\"\n")
+		if err != nil {
+			testDBErr = err
+			return
+		}
+		previous := g.Cfg().GetAdapter()
+		g.Cfg().SetAdapter(adapter)
+		defer g.Cfg().SetAdapter(previous)
+
+		ctx := context.Background()
+		dm := NewDatabaseManager()
+		if testDBErr = dm.InitializeAs(ctx, "sqlite", gdb.DefaultGroupName); testDBErr == nil {
+			testDBErr = dm.CreateTables(ctx)
+		}
+	})
+	if testDBErr != nil {
+		t.Fatalf("test database: %v", testDBErr)
+	}
+}
+
+// useTestConfig 替换全局配置，测试结束后恢复
+func useTestConfig(t *testing.T, content string) {
+	t.Helper()
+	adapter, err := gcfg.NewAdapterContent(content)
+	if err != nil {
+		t.Fatalf("NewAdapterContent: %v", err)
+	}
+	previous := g.Cfg().GetAdapter()
+	g.Cfg().SetAdapter(adapter)
+	t.Cleanup(func() { g.Cfg().SetAdapter(previous) })
+}
+
+func TestManagedTablesDialects(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, table := range managedTables {
+		if seen[table.Name] {
+			t.Errorf("table %s is declared twice", table.Name)
+		}
+		seen[table.Name] = true
+
+		columns := ddlColumns(table.DDL["mysql"])
+		if len(columns) == 0 {
+			t.Errorf("no columns parsed from mysql DDL of %s", table.Name)
+		}
+		for _, dialect := range dialects {
+			ddl, ok := table.DDL[dialect]
+			if !ok {
+				t.Errorf("table %s has no %s DDL", table.Name, dialect)
+				continue
+			}
+			if !strings.Contains(ddl, "CREATE TABLE IF NOT EXISTS "+table.Name+" (") {
+				t.Errorf("%s DDL of %s does not create the table", dialect, table.Name)
+			}
+			if got := ddlColumns(ddl); strings.Join(got, ",") != strings.Join(columns, ",") {
+				t.Errorf("%s columns of %s = %v, mysql has %v", dialect, table.Name, got, columns)
+			}
+			if dialect == "mysql" {
+				continue
+			}
+			for _, syntax := range mysqlOnly {
+				if strings.Contains(ddl, syntax) {
+					t.Errorf("%s DDL of %s contains MySQL syntax %q", dialect, table.Name, syntax)
+				}
+			}
+		}
+
+		pgsql := table.DDL["pgsql"]
+		if strings.Contains(pgsql, "DATETIME") || strings.Contains(pgsql, "AUTOINCREMENT") {
+			t.Errorf("pgsql DDL of %s should use TIMESTAMP and SERIAL", table.Name)
+		}
+		if !strings.Contains(pgsql, "SERIAL PRIMARY KEY") {
+			t.Errorf("pgsql DDL of %s has no SERIAL primary key", table.Name)
+		}
+	}
+}
+
+func TestManagedColumnsDialects(t *testing.T) {
+	tables := make(map[string][]string)
+	for _, table := range managedTables {
+		tables[table.Name] = ddlColumns(table.DDL["mysql"])
+	}
+	seen := make(map[string]bool)
+	for _, column := range managedColumns {
+		key := column.Table + "." + column.Name
+		if seen[key] {
+			t.Errorf("column %s is declared twice", key)
+		}
+		seen[key] = true
+
+		created, ok := tables[column.Table]
+		if !ok {
+			t.Errorf("column %s belongs to an unmanaged table", key)
+			continue
+		}
+		// 新增字段不能写在建表语句里，否则新建的库会重复添加
+		if i := sort.SearchStrings(created, column.Name); i < len(created) && created[i] == column.Name {
+			t.Errorf("column %s is also in the CREATE TABLE statement", key)
+		}
+		for _, dialect := range dialects {
+			ddl, ok := column.DDL[dialect]
+			if !ok || ddl == "" {
+				t.Errorf("column %s has no %s DDL", key, dialect)
+				continue
+			}
+			if dialect == "mysql" {
+				continue
+			}
+			for _, syntax := range mysqlOnly {
+				if strings.Contains(ddl, syntax) {
+					t.Errorf("%s DDL of column %s contains MySQL syntax %q", dialect, key, syntax)
+				}
+			}
+		}
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	useTestConfig(t, `
+database:
+  mysql:
+    link: "mysql:root:root@tcp(127.0.0.1:3306)/omniscient"
+  pgsql:
+    link: "pgsql:postgres:postgres@tcp(127.0.0.1:5432)/omniscient"
+    namespace: "ops"
+`)
+	dm := NewDatabaseManager()
+	config, err := dm.loadConfig(context.Background(), "pgsql")
+	if err != nil {
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if config.Type != "pgsql" || config.Namespace != "ops" || !strings.HasPrefix(config.Link, "pgsql:") {
+		t.Errorf("config = %+v", config)
+	}
+	if _, err := dm.loadConfig(context.Background(), "sqlite"); err == nil {
+		t.Error("missing sqlite config should fail")
+	}
+	if _, err := dm.loadConfig(context.Background(), "oracle"); err == nil {
+		t.Error("unsupported type should fail")
+	}
+}
+
+func TestInitializeAsSQLite(t *testing.T) {
+	ctx := context.Background()
+	// 数据目录不存在时自动创建
+	file := filepath.Join(t.TempDir(), "data", "omniscient.sqlite3")
+	useTestConfig(t, `
+database:
+  sqlite:
+    link: "sqlite::@file(`+file+`)"
+`)
+	dm := NewDatabaseManager()
+	if err := dm.InitializeAs(ctx, "sqlite", "test_sqlite"); err != nil {
+		t.Fatalf("InitializeAs: %v", err)
+	}
+	if dm.GetDatabaseType() != "sqlite" || dm.DB().GetGroup() != "test_sqlite" {
+		t.Fatalf("type = %s, group = %s", dm.GetDatabaseType(), dm.DB().GetGroup())
+	}
+	if _, err := os.Stat(filepath.Dir(file)); err != nil {
+		t.Fatalf("data directory was not created: %v", err)
+	}
+
+	// 第二次建表时表和字段都已存在，不应报错
+	for i := 0; i < 2; i++ {
+		if err := dm.CreateTables(ctx); err != nil {
+			t.Fatalf("CreateTables #%d: %v", i+1, err)
+		}
+	}
+	assertManagedSchema(t, dm)
+
+	info, err := dm.GetDatabaseInfo(ctx)
+	if err != nil {
+		t.Fatalf("GetDatabaseInfo: %v", err)
+	}
+	if info["type"] != "sqlite" || info["database_file"] != file || info["version"] == nil {
+		t.Errorf("info = %v", info)
+	}
+	assertTables(t, info)
+}
+
+// TestCreateTablesAddsColumns 旧版本建的表缺少新增字段时补齐
+func TestCreateTablesAddsColumns(t *testing.T) {
+	ctx := context.Background()
+	file := filepath.Join(t.TempDir(), "old.sqlite3")
+	useTestConfig(t, `
+database:
+  sqlite:
+    link: "sqlite::@file(`+file+`)"
+`)
+	dm := NewDatabaseManager()
+	if err := dm.InitializeAs(ctx, "sqlite", "test_sqlite_old"); err != nil {
+		t.Fatalf("InitializeAs: %v", err)
+	}
+	if _, err := dm.DB().Exec(ctx, "CREATE TABLE jpid (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL, ports TEXT NOT NULL, pid INTEGER NOT NULL, worker TEXT NOT NULL)"); err != nil {
+		t.Fatalf("create old table: %v", err)
+	}
+	if _, err := dm.DB().Exec(ctx, "INSERT INTO jpid (name, ports, pid, worker) VALUES ('demo.jar', '8080', 0, 'vm-1')"); err != nil {
+		t.Fatalf("insert: %v", err)
+	}
+
+	if err := dm.CreateTables(ctx); err != nil {
+		t.Fatalf("CreateTables: %v", err)
+	}
+	assertManagedSchema(t, dm)
+	name, err := dm.DB().GetValue(ctx, "SELECT name FROM jpid WHERE unit_name IS NULL")
+	if err != nil || name.String() != "demo.jar" {
+		t.Errorf("existing row = %v, %v", name, err)
+	}
+}
+
+// TestPgsqlIntegration 设置 OMNISCIENT_TEST_PGSQL_DSN 时在真实的 PostgreSQL 上建表
+func TestPgsqlIntegration(t *testing.T) {
+	dsn := os.Getenv(testPgsqlDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testPgsqlDSNEnv)
+	}
+	ctx := context.Background()
+	useTestConfig(t, `
+database:
+  pgsql:
+    link: "`+dsn+`"
+`)
+	dm := NewDatabaseManager()
+	if err := dm.InitializeAs(ctx, "pgsql", "test_pgsql"); err != nil {
+		t.Fatalf("InitializeAs: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := dm.CreateTables(ctx); err != nil {
+			t.Fatalf("CreateTables #%d: %v", i+1, err)
+		}
+	}
+	assertManagedSchema(t, dm)
+
+	info, err := dm.GetDatabaseInfo(ctx)
+	if err != nil {
+		t.Fatalf("GetDatabaseInfo: %v", err)
+	}
+	for _, key := range []string{"version", "database", "schema"} {
+		if info[key] == nil || info[key] == "" {
+			t.Errorf("info[%s] is empty: %v", key, info)
+		}
+	}
+	assertTables(t, info)
+}
+
+// assertManagedSchema 全部管理的表和字段都存在
+func assertManagedSchema(t *testing.T, dm *DatabaseManager) {
+	t.Helper()
+	ctx := context.Background()
+	for _, table := range managedTables {
+		if exists, err := dm.tableExists(ctx, table.Name); err != nil || !exists {
+			t.Errorf("table %s exists = %v, %v", table.Name, exists, err)
+		}
+	}
+	for _, column := range managedColumns {
+		if exists, err := dm.columnExists(ctx, column.Table, column.Name); err != nil || !exists {
+			t.Errorf("column %s.%s exists = %v, %v", column.Table, column.Name, exists, err)
+		}
+	}
+}
+
+// assertTables GetDatabaseInfo 列出了全部管理的表
+func assertTables(t *testing.T, info map[string]interface{}) {
+	t.Helper()
+	tables, _ := info["tables"].([]string)
+	found := make(map[string]bool)
+	for _, name := range tables {
+		found[name] = true
+	}
+	for _, name := range ManagedTableNames() {
+		if !found[name] {
+			t.Errorf("tables %v do not include %s", tables, name)
+		}
+	}
+}
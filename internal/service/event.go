@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/internal/model"
+	"omniscient/internal/model/entity"
+)
+
+const (
+	// eventBufferSize 保留最近的事件数，断线重连时从中补发
+	eventBufferSize = 1000
+	// eventSubscriberBuffer 单个订阅者的待发送事件数，消费过慢时断开订阅，由客户端按游标重连
+	eventSubscriberBuffer = 256
+)
+
+// eventBus 进程内的事件总线，每个服务器只发布本机的事件
+var eventBus = &bus{
+	// 游标从启动时间（毫秒）开始，重启后的事件游标仍大于之前的游标
+	seq:         time.Now().UnixMilli(),
+	subscribers: make(map[chan *model.Event]struct{}),
+}
+
+type bus struct {
+	mu          sync.Mutex
+	seq         int64
+	buffer      []*model.Event
+	subscribers map[chan *model.Event]struct{}
+}
+
+type SEvent struct{}
+
+func Event() *SEvent {
+	return &SEvent{}
+}
+
+// Publish 发布项目事件，不会阻塞调用方
+func (s *SEvent) Publish(ctx context.Context, eventType string, project *entity.Jpid, data map[string]interface{}) {
+	event := &model.Event{
+		Type: eventType,
+		Time: time.Now().Format(time.DateTime),
+		Data: data,
+	}
+	if project != nil {
+		event.ProjectId, event.Name, event.Worker, event.Pid = project.Id, project.Name, project.Worker, project.Pid
+	}
+
+	eventBus.mu.Lock()
+	defer eventBus.mu.Unlock()
+	eventBus.seq++
+	event.Id = eventBus.seq
+	eventBus.buffer = append(eventBus.buffer, event)
+	if len(eventBus.buffer) > eventBufferSize {
+		eventBus.buffer = eventBus.buffer[len(eventBus.buffer)-eventBufferSize:]
+	}
+	for ch := range eventBus.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(eventBus.subscribers, ch)
+			close(ch)
+			g.Log().Infof(ctx, "事件订阅者消费过慢，已断开")
+		}
+	}
+}
+
+// Subscribe 订阅事件，返回游标之后缓冲区中的事件和后续事件的通道
+// cursor 为 0 时只接收新事件；cursor 早于缓冲区时 complete 为 false，中间的事件已丢失
+// 通道被关闭表示订阅被断开，调用方用完需要调用 cancel
+func (s *SEvent) Subscribe(cursor int64) (backlog []*model.Event, events <-chan *model.Event, cancel func(), complete bool) {
+	ch := make(chan *model.Event, eventSubscriberBuffer)
+	eventBus.mu.Lock()
+	defer eventBus.mu.Unlock()
+
+	complete = true
+	if cursor > 0 {
+		if len(eventBus.buffer) > 0 && eventBus.buffer[0].Id > cursor+1 || len(eventBus.buffer) == 0 && eventBus.seq > cursor {
+			complete = false
+		}
+		for _, event := range eventBus.buffer {
+			if event.Id > cursor {
+				backlog = append(backlog, event)
+			}
+		}
+	}
+	eventBus.subscribers[ch] = struct{}{}
+	cancel = func() {
+		eventBus.mu.Lock()
+		defer eventBus.mu.Unlock()
+		if _, ok := eventBus.subscribers[ch]; ok {
+			delete(eventBus.subscribers, ch)
+			close(ch)
+		}
+	}
+	return backlog, ch, cancel, complete
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/internal/dao"
+	"omniscient/internal/model"
+	"omniscient/internal/model/do"
+	"omniscient/internal/model/entity"
+	"omniscient/internal/util/javaprocess"
+	"omniscient/internal/util/jdk"
+	"omniscient/internal/util/system"
+)
+
+// JDK 来源
+const (
+	JdkSourceScan    = "scan"    // 扫描安装目录
+	JdkSourceProcess = "process" // 运行中的 java 进程
+)
+
+type SJdk struct{}
+
+func Jdk() *SJdk {
+	return &SJdk{}
+}
+
+// List 获取 JDK 清单
+func (s *SJdk) List(ctx context.Context, worker string) (list []*entity.Jdk, err error) {
+	if worker == "" {
+		worker = system.GetWorkerName()
+	}
+	err = dao.Jdk.Ctx(ctx).Where("worker", worker).Order("home ASC").Scan(&list)
+	return
+}
+
+// Get 根据编号获取 JDK
+func (s *SJdk) Get(ctx context.Context, id int) (*entity.Jdk, error) {
+	var record *entity.Jdk
+	if err := dao.Jdk.Ctx(ctx).Where("id", id).Scan(&record); err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, gerror.Newf("JDK %d 不存在", id)
+	}
+	return record, nil
+}
+
+// Scan 扫描本机的 JDK（常见安装目录、jdk.searchPaths、运行中 java 进程的 /proc/<pid>/exe）并更新清单
+// 目录已不存在且没有项目指定的记录会被删除
+func (s *SJdk) Scan(ctx context.Context) ([]*entity.Jdk, error) {
+	found := make(map[string]bool)
+	for _, home := range jdk.Scan(g.Cfg().MustGet(ctx, "jdk.searchPaths").Strings()...) {
+		info, err := jdk.Inspect(home)
+		if err != nil {
+			g.Log().Warning(ctx, err)
+			continue
+		}
+		if _, err = s.Register(ctx, info, JdkSourceScan); err != nil {
+			return nil, err
+		}
+		found[home] = true
+	}
+
+	if processes, err := javaprocess.GetJavaProcesses(); err == nil {
+		for _, process := range processes {
+			if process.Way == 1 {
+				continue
+			}
+			record, err := s.Record(ctx, process.Pid)
+			if err != nil {
+				g.Log().Warningf(ctx, "识别进程 %d 的 JDK 失败: %v", process.Pid, err)
+				continue
+			}
+			found[record.Home] = true
+		}
+	}
+
+	list, err := s.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*entity.Jdk, 0, len(list))
+	for _, record := range list {
+		if found[record.Home] || jdk.IsHome(record.Home) {
+			result = append(result, record)
+			continue
+		}
+		count, err := dao.Jpid.Ctx(ctx).Where("jdk_id", record.Id).Count()
+		if err != nil {
+			return nil, err
+		}
+		if count > 0 {
+			g.Log().Warningf(ctx, "JDK %s 已不存在，但仍有 %d 个项目指定使用", record.Home, count)
+			result = append(result, record)
+			continue
+		}
+		if _, err = dao.Jdk.Ctx(ctx).Where("id", record.Id).Delete(); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Record 记录运行进程实际使用的 JDK
+func (s *SJdk) Record(ctx context.Context, pid int) (*entity.Jdk, error) {
+	info, err := jdk.ProcessInfo(pid)
+	if err != nil {
+		return nil, err
+	}
+	return s.Register(ctx, info, JdkSourceProcess)
+}
+
+// Register 按 worker+目录 新增或更新 JDK 记录
+func (s *SJdk) Register(ctx context.Context, info *jdk.Info, source string) (*entity.Jdk, error) {
+	worker := system.GetWorkerName()
+	var record *entity.Jdk
+	if err := dao.Jdk.Ctx(ctx).Where("worker", worker).Where("home", info.Home).Scan(&record); err != nil {
+		return nil, err
+	}
+
+	if record == nil {
+		id, err := dao.Jdk.Ctx(ctx).Data(do.Jdk{
+			Worker:  worker,
+			Home:    info.Home,
+			Version: info.Version,
+			Vendor:  info.Vendor,
+			Source:  source,
+		}).InsertAndGetId()
+		if err != nil {
+			return nil, err
+		}
+		return &entity.Jdk{Id: int(id), Worker: worker, Home: info.Home, Version: info.Version, Vendor: info.Vendor, Source: source}, nil
+	}
+
+	if record.Version != info.Version || record.Vendor != info.Vendor {
+		if _, err := dao.Jdk.Ctx(ctx).Data(do.Jdk{
+			Version: info.Version,
+			Vendor:  info.Vendor,
+		}).Where("id", record.Id).Update(); err != nil {
+			return nil, err
+		}
+		record.Version, record.Vendor = info.Version, info.Vendor
+	}
+	return record, nil
+}
+
+// Runtime 运行中项目实际使用的 JDK，未运行、不是本机项目或无法识别时返回 nil
+// 其他 worker 的 pid 在本机没有意义，不读取
+func (s *SJdk) Runtime(project *entity.Jpid) *model.JavaRuntime {
+	if project.Status != 1 || project.Pid <= 0 || project.Worker != system.GetWorkerName() {
+		return nil
+	}
+	info, err := jdk.ProcessInfo(project.Pid)
+	if err != nil {
+		return nil
+	}
+	return &model.JavaRuntime{Home: info.Home, Version: info.Version, Vendor: info.Vendor}
+}
+
+// UpdateJdk 指定项目使用的 JDK，jdkId 为 0 时取消指定（使用 PATH 中的 java），返回生效的启动命令
+func (s *SJpid) UpdateJdk(ctx context.Context, id int, jdkId int) (string, error) {
+	var project *entity.Jpid
+	if err := dao.Jpid.Ctx(ctx).Where("id", id).Scan(&project); err != nil {
+		return "", err
+	}
+	if project == nil {
+		return "", gerror.New("项目不存在")
+	}
+	if project.Way == 1 {
+		return "", gerror.New("docker 项目不能指定 JDK")
+	}
+
+	if err := checkManifestJdk(project, jdkId); err != nil {
+		return "", err
+	}
+
+	home := ""
+	if jdkId > 0 {
+		record, err := Jdk().Get(ctx, jdkId)
+		if err != nil {
+			return "", err
+		}
+		if record.Worker != project.Worker {
+			return "", gerror.Newf("JDK %d 不在项目所在的服务器 %s 上", jdkId, project.Worker)
+		}
+		if err = ValidateLaunch(nil, record.Home); err != nil {
+			return "", err
+		}
+		home = record.Home
+	}
+
+	project.JdkId, project.JdkHome = jdkId, home
+	command, err := s.Command(project)
+	if err != nil {
+		return "", err
+	}
+	_, err = dao.Jpid.Ctx(ctx).Data(do.Jpid{JdkId: jdkId, JdkHome: home}).Where("id", id).Update()
+	return command, err
+}
+
+// checkManifestJdk 清单管理的项目每次对账都会按清单的 jdkHome 覆盖 JDK 设置，不能在页面上指定 JDK
+func checkManifestJdk(project *entity.Jpid, jdkId int) error {
+	if jdkId > 0 && project.Manifest != "" {
+		return gerror.Newf("项目 %s 由清单 %s 管理，请在清单中通过 jdkHome 指定 JDK", project.Name, project.Manifest)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"omniscient/internal/dao"
+	"omniscient/internal/model/do"
+	"omniscient/internal/util/system"
+)
+
+func TestJdkSelectionRejectedForManifestProjects(t *testing.T) {
+	ctx := context.Background()
+	id := insertTestProject(t, system.GetWorkerName())
+	if _, err := dao.Jpid.Ctx(ctx).Data(do.Jpid{Manifest: "/etc/omniscient/manifests/demo.yaml"}).Where("id", id).Update(); err != nil {
+		t.Fatal(err)
+	}
+	jdkId, err := dao.Jdk.Ctx(ctx).Data(do.Jdk{Worker: system.GetWorkerName(), Home: t.TempDir(), Version: "17"}).InsertAndGetId()
+	if err != nil {
+		t.Fatalf("insert jdk: %v", err)
+	}
+	t.Cleanup(func() { dao.Jdk.Ctx(ctx).Where("id", jdkId).Delete() })
+
+	if _, err = Jpid().UpdateJdk(ctx, id, int(jdkId)); err == nil || !strings.Contains(err.Error(), "由清单") {
+		t.Errorf("UpdateJdk = %v, want manifest error", err)
+	}
+	if _, err = Jpid().UpdateLaunch(ctx, id, &LaunchConfig{JdkId: int(jdkId)}); err == nil || !strings.Contains(err.Error(), "由清单") {
+		t.Errorf("UpdateLaunch = %v, want manifest error", err)
+	}
+	// 取消指定不受影响，对账会按清单恢复 jdkHome
+	if _, err = Jpid().UpdateJdk(ctx, id, 0); err != nil {
+		t.Errorf("UpdateJdk(0) = %v", err)
+	}
+}
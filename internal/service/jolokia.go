@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcron"
+	"github.com/gogf/gf/v2/os/gtime"
+	"omniscient/internal/dao"
+	"omniscient/internal/model"
+	"omniscient/internal/model/do"
+	"omniscient/internal/model/entity"
+	"omniscient/internal/util/jolokia"
+	"omniscient/internal/util/system"
+)
+
+const (
+	defaultJolokiaInterval = "60s"
+	defaultJolokiaTimeout  = "5s"
+	defaultJolokiaKeepDays = 7
+	defaultMetricLimit     = 100
+)
+
+// defaultJolokiaQueries 项目和 jolokia.queries 都没有配置时采集的 MBean
+// Spring Boot 内嵌 Tomcat 的 MBean 需要开启 server.tomcat.mbeanregistry.enabled
+var defaultJolokiaQueries = []*model.JolokiaQuery{
+	{Name: "jvm.heap.used", Mbean: "java.lang:type=Memory", Attribute: "HeapMemoryUsage", Path: "used"},
+	{Name: "jvm.threads", Mbean: "java.lang:type=Threading", Attribute: "ThreadCount"},
+	{Name: "tomcat.threads.busy", Mbean: "Tomcat:type=ThreadPool,name=*", Attribute: "currentThreadsBusy"},
+	{Name: "hikari.connections.active", Mbean: "com.zaxxer.hikari:type=Pool (*)", Attribute: "ActiveConnections"},
+	{Name: "hikari.connections.pending", Mbean: "com.zaxxer.hikari:type=Pool (*)", Attribute: "ThreadsAwaitingConnection"},
+}
+
+type SJolokia struct{}
+
+func Jolokia() *SJolokia {
+	return &SJolokia{}
+}
+
+// Start 按 jolokia.interval 定时采集本机运行中且配置了 Jolokia 地址的项目，并清理过期指标
+func (s *SJolokia) Start(ctx context.Context) error {
+	interval := g.Cfg().MustGet(ctx, "jolokia.interval", defaultJolokiaInterval).String()
+	_, err := gcron.AddSingleton(ctx, "@every "+interval, func(ctx context.Context) {
+		s.run(ctx)
+	}, "jolokia-collect")
+	if err != nil {
+		return gerror.Wrap(err, "启动 JMX 指标采集失败")
+	}
+	return nil
+}
+
+func (s *SJolokia) run(ctx context.Context) {
+	var projects []*entity.Jpid
+	if err := dao.Jpid.Ctx(ctx).
+		Where("worker", system.GetWorkerName()).
+		Where("status", 1).
+		WhereNot("jolokia", "").
+		Scan(&projects); err != nil {
+		g.Log().Warningf(ctx, "读取采集 JMX 指标的项目失败: %v", err)
+		return
+	}
+	for _, project := range projects {
+		if failures, err := s.Collect(ctx, project); err != nil {
+			g.Log().Warningf(ctx, "采集项目 %s 的 JMX 指标失败: %v", project.Name, err)
+		} else if len(failures) > 0 {
+			g.Log().Debugf(ctx, "项目 %s 部分 MBean 读取失败: %v", project.Name, failures)
+		}
+	}
+
+	keepDays := g.Cfg().MustGet(ctx, "jolokia.keepDays", defaultJolokiaKeepDays).Int()
+	if keepDays > 0 {
+		before := gtime.Now().Add(-time.Duration(keepDays) * 24 * time.Hour)
+		if _, err := dao.JpidMetric.Ctx(ctx).WhereLT("collected_at", before).Delete(); err != nil {
+			g.Log().Warningf(ctx, "清理过期 JMX 指标失败: %v", err)
+		}
+	}
+}
+
+// Collect 采集一次项目的 JMX 指标并保存，返回读取失败的查询（指标名 -> 原因）
+func (s *SJolokia) Collect(ctx context.Context, project *entity.Jpid) (map[string]string, error) {
+	if project.Jolokia == "" {
+		return nil, gerror.Newf("项目 %s 没有设置 Jolokia 地址", project.Name)
+	}
+	// 地址中可以用 ${secret:NAME} 引用密钥，如 http://jolokia:${secret:JOLOKIA_PASSWORD}@127.0.0.1:8778/jolokia
+	url, err := Secret().Resolve(ctx, project.Jolokia)
+	if err != nil {
+		return nil, err
+	}
+	timeout := g.Cfg().MustGet(ctx, "jolokia.timeout", defaultJolokiaTimeout).Duration()
+	samples, err := jolokia.New(url, timeout).Read(ctx, s.Queries(ctx, project))
+	if err != nil {
+		return nil, err
+	}
+
+	now := gtime.Now()
+	var (
+		data     []do.JpidMetric
+		failures = make(map[string]string)
+	)
+	for _, sample := range samples {
+		if sample.Err != nil {
+			failures[sample.Name] = sample.Err.Error()
+			continue
+		}
+		row := do.JpidMetric{JpidId: project.Id, Name: sample.Name, Mbean: sample.Mbean, CollectedAt: now}
+		if sample.Value != nil {
+			row.Value = *sample.Value
+		} else {
+			row.Raw = sample.Raw
+		}
+		data = append(data, row)
+	}
+	if len(data) > 0 {
+		if _, err = dao.JpidMetric.Ctx(ctx).Data(data).Insert(); err != nil {
+			return nil, err
+		}
+	}
+	return failures, nil
+}
+
+// Queries 项目采集的 MBean：优先使用项目配置，其次 jolokia.queries，最后使用默认查询
+func (s *SJolokia) Queries(ctx context.Context, project *entity.Jpid) []*model.JolokiaQuery {
+	if queries := ParseJolokiaQueries(project.JolokiaQueries); len(queries) > 0 {
+		return queries
+	}
+	var queries []*model.JolokiaQuery
+	if err := g.Cfg().MustGet(ctx, "jolokia.queries").Structs(&queries); err == nil && len(queries) > 0 {
+		return queries
+	}
+	return defaultJolokiaQueries
+}
+
+// Latest 项目最近一次采集的指标
+// 各数据库读取的采集时间精度不同，按秒比较；采集间隔远大于 1 秒，不会取到上一次的结果
+func (s *SJolokia) Latest(ctx context.Context, projectId int) (list []*entity.JpidMetric, err error) {
+	collectedAt, err := dao.JpidMetric.Ctx(ctx).Where("jpid_id", projectId).Order("id DESC").Value("collected_at")
+	if err != nil || collectedAt.IsEmpty() {
+		return nil, err
+	}
+	err = dao.JpidMetric.Ctx(ctx).
+		Where("jpid_id", projectId).
+		WhereGTE("collected_at", collectedAt.GTime().Format("Y-m-d H:i:s")).
+		Order("name ASC, mbean ASC").
+		Scan(&list)
+	return
+}
+
+// History 项目指标的历史值，按采集时间倒序，name 为空时返回全部指标
+func (s *SJolokia) History(ctx context.Context, projectId int, name string, limit int) (list []*entity.JpidMetric, err error) {
+	if limit <= 0 {
+		limit = defaultMetricLimit
+	}
+	query := dao.JpidMetric.Ctx(ctx).Where("jpid_id", projectId)
+	if name != "" {
+		query = query.Where("name", name)
+	}
+	err = query.Order("id DESC").Limit(limit).Scan(&list)
+	return
+}
+
+// Configure 设置项目的 Jolokia 地址和 MBean 查询，queries 为空时使用 jolokia.queries
+func (s *SJolokia) Configure(ctx context.Context, id int, url string, queries []*model.JolokiaQuery) error {
+	url = strings.TrimRight(strings.TrimSpace(url), "/")
+	if url != "" && !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+		return gerror.Newf("Jolokia 地址必须以 http:// 或 https:// 开头: %s", url)
+	}
+	if err := Secret().CheckRefs(ctx, map[string]string{"jolokia": url}); err != nil {
+		return err
+	}
+	for i, query := range queries {
+		query.Name, query.Mbean = strings.TrimSpace(query.Name), strings.TrimSpace(query.Mbean)
+		if query.Name == "" || query.Mbean == "" {
+			return gerror.Newf("第 %d 个查询缺少 name 或 mbean", i+1)
+		}
+	}
+
+	count, err := dao.Jpid.Ctx(ctx).Where("id", id).Count()
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return gerror.New("项目不存在")
+	}
+	_, err = dao.Jpid.Ctx(ctx).Data(do.Jpid{
+		Jolokia:        url,
+		JolokiaQueries: FormatJolokiaQueries(queries),
+	}).Where("id", id).Update()
+	return err
+}
+
+// ParseJolokiaQueries 解析项目的 MBean 查询（JSON 数组）
+func ParseJolokiaQueries(data string) []*model.JolokiaQuery {
+	var queries []*model.JolokiaQuery
+	if strings.TrimSpace(data) != "" {
+		_ = json.Unmarshal([]byte(data), &queries)
+	}
+	return queries
+}
+
+// FormatJolokiaQueries 序列化 MBean 查询
+func FormatJolokiaQueries(queries []*model.JolokiaQuery) string {
+	if len(queries) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(queries)
+	return string(data)
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"omniscient/internal/dao"
+	"omniscient/internal/model"
+	"omniscient/internal/model/entity"
+)
+
+// jolokiaStub 按 MBean 返回固定结果的 Jolokia，没有配置的 MBean 返回 404
+func jolokiaStub(t *testing.T, values map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var requests []struct {
+			Mbean string `json:"mbean"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&requests); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		parts := make([]string, 0, len(requests))
+		for _, req := range requests {
+			if value, ok := values[req.Mbean]; ok {
+				parts = append(parts, `{"status":200,"value":`+value+`}`)
+			} else {
+				parts = append(parts, `{"status":404,"error":"javax.management.InstanceNotFoundException : `+req.Mbean+`"}`)
+			}
+		}
+		_, _ = w.Write([]byte("[" + strings.Join(parts, ",") + "]"))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCollect(t *testing.T) {
+	useTestDB(t)
+	ctx := context.Background()
+	server := jolokiaStub(t, map[string]string{
+		"java.lang:type=Threading": `42`,
+		"java.lang:type=Runtime":   `"OpenJDK 64-Bit Server VM"`,
+		"Tomcat:type=ThreadPool,name=*": `{
+			"Tomcat:name=\"http-nio-8080\",type=ThreadPool":{"currentThreadsBusy":3},
+			"Tomcat:name=\"http-nio-8443\",type=ThreadPool":{"currentThreadsBusy":1}}`,
+	})
+	project := &entity.Jpid{Id: 9001, Name: "demo.jar", Jolokia: server.URL + "/jolokia", JolokiaQueries: FormatJolokiaQueries([]*model.JolokiaQuery{
+		{Name: "jvm.threads", Mbean: "java.lang:type=Threading", Attribute: "ThreadCount"},
+		{Name: "jvm.name", Mbean: "java.lang:type=Runtime", Attribute: "VmName"},
+		{Name: "tomcat.threads.busy", Mbean: "Tomcat:type=ThreadPool,name=*", Attribute: "currentThreadsBusy"},
+		{Name: "hikari.active", Mbean: "com.zaxxer.hikari:type=Pool (*)", Attribute: "ActiveConnections"},
+		{Name: "jvm.heap.used", Mbean: "java.lang:type=Threading", Attribute: "ThreadCount", Path: "used"},
+	})}
+	t.Cleanup(func() { dao.JpidMetric.Ctx(ctx).Where("jpid_id", project.Id).Delete() })
+
+	failures, err := Jolokia().Collect(ctx, project)
+	if err != nil {
+		t.Fatalf("Collect: %v", err)
+	}
+	// 单个查询失败不影响其他查询，按指标名返回原因
+	if len(failures) != 2 ||
+		!strings.HasPrefix(failures["hikari.active"], "404 javax.management.InstanceNotFoundException") ||
+		!strings.Contains(failures["jvm.heap.used"], "没有路径 used") {
+		t.Errorf("failures = %v", failures)
+	}
+
+	var rows []*entity.JpidMetric
+	if err := dao.JpidMetric.Ctx(ctx).Where("jpid_id", project.Id).Order("name ASC, mbean ASC").Scan(&rows); err != nil {
+		t.Fatalf("scan metrics: %v", err)
+	}
+	var got []string
+	for _, row := range rows {
+		got = append(got, row.Name+"|"+row.Mbean+"|"+strconv.FormatFloat(row.Value, 'f', -1, 64)+"|"+row.Raw)
+	}
+	want := []string{
+		`jvm.name|java.lang:type=Runtime|0|"OpenJDK 64-Bit Server VM"`,
+		`jvm.threads|java.lang:type=Threading|42|`,
+		`tomcat.threads.busy|Tomcat:name="http-nio-8080",type=ThreadPool|3|`,
+		`tomcat.threads.busy|Tomcat:name="http-nio-8443",type=ThreadPool|1|`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("metrics = %q, want %q", got, want)
+	}
+
+	latest, err := Jolokia().Latest(ctx, project.Id)
+	if err != nil || len(latest) != len(want) {
+		t.Errorf("Latest = %d rows, %v", len(latest), err)
+	}
+}
+
+func TestCollectErrors(t *testing.T) {
+	useTestDB(t)
+	ctx := context.Background()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer failing.Close()
+
+	tests := []struct {
+		name    string
+		jolokia string
+		wantErr string
+	}{
+		{"no address", "", "没有设置 Jolokia 地址"},
+		{"unauthorized", failing.URL, "状态码 401"},
+		{"missing secret", "http://jolokia:${secret:NO_SUCH_SECRET}@127.0.0.1:8778/jolokia", "NO_SUCH_SECRET"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project := &entity.Jpid{Id: 9002, Name: "demo.jar", Jolokia: tt.jolokia}
+			failures, err := Jolokia().Collect(ctx, project)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Collect = %v, %v, want error containing %q", failures, err, tt.wantErr)
+			}
+			if count, _ := dao.JpidMetric.Ctx(ctx).Where("jpid_id", project.Id).Count(); count != 0 {
+				t.Errorf("%d metrics saved after a failed collect", count)
+			}
+		})
+	}
+}
+
+func TestJolokiaQueriesRoundTrip(t *testing.T) {
+	queries := []*model.JolokiaQuery{
+		{Name: "jvm.heap.used", Mbean: "java.lang:type=Memory", Attribute: "HeapMemoryUsage", Path: "used"},
+		{Name: "tomcat.threads.busy", Mbean: "Tomcat:type=ThreadPool,name=*", Attribute: "currentThreadsBusy"},
+	}
+	data := FormatJolokiaQueries(queries)
+	if strings.Count(data, `"path"`) != 1 {
+		t.Errorf("empty path should be omitted: %s", data)
+	}
+	if got := ParseJolokiaQueries(data); !reflect.DeepEqual(got, queries) {
+		t.Errorf("ParseJolokiaQueries(FormatJolokiaQueries(q)) = %+v", got)
+	}
+
+	if data := FormatJolokiaQueries(nil); data != "" {
+		t.Errorf("FormatJolokiaQueries(nil) = %q", data)
+	}
+	for _, data := range []string{"", "  ", "not json", `{"name":"x"}`} {
+		if got := ParseJolokiaQueries(data); len(got) != 0 {
+			t.Errorf("ParseJolokiaQueries(%q) = %+v", data, got)
+		}
+	}
+}
+
+func TestJolokiaQueriesFallback(t *testing.T) {
+	ctx := context.Background()
+	custom := []*model.JolokiaQuery{{Name: "app.up", Mbean: "app:type=Up", Attribute: "Up"}}
+	project := &entity.Jpid{JolokiaQueries: FormatJolokiaQueries(custom)}
+	if got := Jolokia().Queries(ctx, project); !reflect.DeepEqual(got, custom) {
+		t.Errorf("project queries = %+v", got)
+	}
+
+	useTestConfig(t, `
+jolokia:
+  queries:
+    - name: jvm.threads
+      mbean: java.lang:type=Threading
+      attribute: ThreadCount
+`)
+	if got := Jolokia().Queries(ctx, &entity.Jpid{}); len(got) != 1 || got[0].Name != "jvm.threads" {
+		t.Errorf("configured queries = %+v", got)
+	}
+
+	useTestConfig(t, "jolokia:\n  interval: 60s\n")
+	if got := Jolokia().Queries(ctx, &entity.Jpid{}); !reflect.DeepEqual(got, defaultJolokiaQueries) {
+		t.Errorf("default queries = %+v", got)
+	}
+}
@@ -1,16 +1,19 @@
 package service
 
 import (
+	"autostart/pkg/autostart"
 	"context"
+	"errors"
 	"fmt"
 	"github.com/gogf/gf/v2/errors/gerror"
 	"github.com/gogf/gf/v2/frame/g"
 	"omniscient/internal/dao"
+	"omniscient/internal/model"
 	"omniscient/internal/model/do"
 	"omniscient/internal/model/entity"
-	"omniscient/internal/util/autostart"
 	"omniscient/internal/util/javaprocess"
 	"omniscient/internal/util/system"
+	"os"
 	"os/exec"
 	"strings"
 	"time"
@@ -58,22 +61,85 @@ func (s *SJpid) GetList(ctx context.Context, worker string) (list []*entity.Jpid
 
 // UpdateStatus 更新项目状态
 func (s *SJpid) UpdateStatus(ctx context.Context, pid int, status int) error {
+	var projects []*entity.Jpid
+	if err := dao.Jpid.Ctx(ctx).Where("pid", pid).Scan(&projects); err != nil {
+		return err
+	}
 	_, err := dao.Jpid.Ctx(ctx).
 		Data(g.Map{"status": status}).
 		Where("pid", pid).
 		Update()
-	return err
+	if err != nil {
+		return err
+	}
+	for _, project := range projects {
+		if status == 0 {
+			s.recordStopped(ctx, project.Id)
+		}
+		s.publishStatus(ctx, project, status)
+	}
+	return nil
 }
 
 // UpdateStatusById 更新项目状态
 func (s *SJpid) UpdateStatusById(ctx context.Context, id int, status int) error {
+	var project *entity.Jpid
+	if err := dao.Jpid.Ctx(ctx).Where("id", id).Scan(&project); err != nil {
+		return err
+	}
 	_, err := dao.Jpid.Ctx(ctx).
 		Data(g.Map{"status": status}).
 		Where("id", id).
 		Update()
+	if err == nil && status == 0 {
+		s.recordStopped(ctx, id)
+	}
+	if err == nil && project != nil {
+		s.publishStatus(ctx, project, status)
+	}
 	return err
 }
 
+// publishStatus 项目状态发生变化时发布 project.started 或 project.stopped
+func (s *SJpid) publishStatus(ctx context.Context, project *entity.Jpid, status int) {
+	if project.Status == status {
+		return
+	}
+	project.Status = status
+	eventType := model.EventProjectStopped
+	if status == 1 {
+		eventType = model.EventProjectStarted
+	}
+	Event().Publish(ctx, eventType, project, nil)
+}
+
+// publishPid 运行中的项目 PID 变化时发布 project.pid，未运行的项目发布 project.started
+func (s *SJpid) publishPid(ctx context.Context, project *entity.Jpid, pid int) {
+	oldPid := project.Pid
+	project.Pid = pid
+	if project.Status != 1 {
+		s.publishStatus(ctx, project, 1)
+		return
+	}
+	if oldPid != pid {
+		Event().Publish(ctx, model.EventProjectPid, project, map[string]interface{}{"oldPid": oldPid})
+	}
+}
+
+// recordStarted 记录项目运行，失败只记录日志，不影响启动流程
+func (s *SJpid) recordStarted(ctx context.Context, project *entity.Jpid, pid int) {
+	if err := JpidRun().Started(ctx, project, pid); err != nil {
+		g.Log().Warningf(ctx, "记录项目 %s 运行失败: %v", project.Name, err)
+	}
+}
+
+// recordStopped 结束项目运行记录，失败只记录日志
+func (s *SJpid) recordStopped(ctx context.Context, id int) {
+	if err := JpidRun().Stopped(ctx, id); err != nil {
+		g.Log().Warningf(ctx, "结束项目 %d 运行记录失败: %v", id, err)
+	}
+}
+
 // AutoRegister 自动注册和更新Java进程
 func (s *SJpid) AutoRegister(ctx context.Context, processes []*entity.LinuxPid) (total, updated, created int, err error) {
 	// 获取当前服务器标识
@@ -96,8 +162,15 @@ func (s *SJpid) AutoRegister(ctx context.Context, processes []*entity.LinuxPid)
 	}
 
 	// 更新已停止的进程状态
+	// 扫描结果只包含监听了端口的 java 进程，不在其中的进程可能仍在运行（启动中或关闭了端口），
+	// 与 DetectExited 一样确认进程连续两次不存在后才视为退出
 	for _, project := range existingProjects {
 		if project.Status == 1 && !runningPids[project.Pid] {
+			if !s.confirmExited(project) {
+				continue
+			}
+			// 先分析退出原因，再由 UpdateStatus 结束运行记录
+			s.recordCrash(ctx, project)
 			if err := s.UpdateStatus(ctx, project.Pid, 0); err != nil {
 				g.Log().Warningf(ctx, "更新已停止项目状态失败 [Worker:%s, PID:%d]: %v",
 					currentWorker, project.Pid, err)
@@ -163,7 +236,7 @@ func (s *SJpid) AutoRegister(ctx context.Context, processes []*entity.LinuxPid)
 
 // updateExistingProject 更新已存在的项目
 func (s *SJpid) updateExistingProject(ctx context.Context, existing *entity.Jpid, process *entity.LinuxPid) error {
-	_, err := dao.Jpid.Ctx(ctx).Data(g.Map{
+	data := g.Map{
 		"pid":     process.Pid,
 		"name":    process.Name,
 		"catalog": process.Catalog,
@@ -171,14 +244,29 @@ func (s *SJpid) updateExistingProject(ctx context.Context, existing *entity.Jpid
 		"status":  1,
 		"worker":  system.GetWorkerName(), // 确保worker字段也更新
 		"way":     process.Way,
-	}).Where("id", existing.Id).Update()
-	return err
+	}
+	// 清单管理或使用结构化启动配置的项目，不用进程命令行覆盖启动配置
+	if existing.Manifest != "" || (existing.Run == "" && HasLaunchConfig(existing)) {
+		data = g.Map{"pid": process.Pid, "status": 1}
+	} else if existing.JdkId == 0 && existing.JdkHome == "" {
+		// 记录进程实际使用的 JDK
+		if record := s.processJdk(ctx, process); record != nil {
+			data["jdk_id"] = record.Id
+			data["jdk_home"] = record.Home
+		}
+	}
+	if _, err := dao.Jpid.Ctx(ctx).Data(data).Where("id", existing.Id).Update(); err != nil {
+		return err
+	}
+	s.recordStarted(ctx, existing, process.Pid)
+	s.publishPid(ctx, existing, process.Pid)
+	return nil
 }
 
 // createNewProject 创建新项目
 func (s *SJpid) createNewProject(ctx context.Context, process *entity.LinuxPid) error {
 	workerName := system.GetWorkerName()
-	_, err := dao.Jpid.Ctx(ctx).Data(do.Jpid{
+	data := do.Jpid{
 		Name:    process.Name,
 		Ports:   process.Ports,
 		Pid:     process.Pid,
@@ -187,8 +275,32 @@ func (s *SJpid) createNewProject(ctx context.Context, process *entity.LinuxPid)
 		Status:  1,
 		Worker:  workerName,
 		Way:     process.Way,
-	}).Insert()
-	return err
+	}
+	if record := s.processJdk(ctx, process); record != nil {
+		data.JdkId = record.Id
+		data.JdkHome = record.Home
+	}
+	id, err := dao.Jpid.Ctx(ctx).Data(data).InsertAndGetId()
+	if err != nil {
+		return err
+	}
+	project := &entity.Jpid{Id: int(id), Name: process.Name, Catalog: process.Catalog, Worker: workerName, Pid: process.Pid, Status: 1}
+	s.recordStarted(ctx, project, process.Pid)
+	Event().Publish(ctx, model.EventProjectRegistered, project, map[string]interface{}{"ports": process.Ports, "way": process.Way})
+	return nil
+}
+
+// processJdk 登记进程使用的 JDK，docker 进程和识别失败时返回 nil
+func (s *SJpid) processJdk(ctx context.Context, process *entity.LinuxPid) *entity.Jdk {
+	if process.Way == 1 {
+		return nil
+	}
+	record, err := Jdk().Record(ctx, process.Pid)
+	if err != nil {
+		g.Log().Warningf(ctx, "识别进程 %d 的 JDK 失败: %v", process.Pid, err)
+		return nil
+	}
+	return record
 }
 
 // UpdateProject 更新项目基础信息
@@ -206,6 +318,10 @@ func (s *SJpid) UpdateInfo(ctx context.Context, pid int, script, catalog, descri
 
 // UpdatePid 更新项目的 PID
 func (s *SJpid) UpdatePid(ctx context.Context, oldPid int, newPid int) error {
+	var previous []*entity.Jpid
+	if err := dao.Jpid.Ctx(ctx).Where("pid", oldPid).Scan(&previous); err != nil {
+		return err
+	}
 	_, err := dao.Jpid.Ctx(ctx).
 		Data(g.Map{
 			"pid":    newPid,
@@ -213,7 +329,14 @@ func (s *SJpid) UpdatePid(ctx context.Context, oldPid int, newPid int) error {
 		}).
 		Where("pid", oldPid).
 		Update()
-	return err
+	if err != nil {
+		return err
+	}
+	for _, project := range previous {
+		s.recordStarted(ctx, project, newPid)
+		s.publishPid(ctx, project, newPid)
+	}
+	return nil
 }
 
 // FindNewPid 查找新的 PID
@@ -310,23 +433,85 @@ func (s *SJpid) Delete(ctx context.Context, id int) error {
 		return gerror.New("项目正在运行中，请先停止项目后再删除")
 	}
 
-	// 如果项目设置了自启，先移除自启动服务
-	if jpid.Autostart == 1 {
-		autoName := jpid.Name + "_" + jpid.Ports
-		if autostart.CheckAutostartServiceExists(ctx, autoName) {
-			err := autostart.RemoveAutostartServiceNonInteractive(ctx, autoName)
-			if err != nil {
-				g.Log().Warning(ctx, "移除自启动服务失败", "error", err)
-				// 继续执行删除操作，不中断流程
-			}
+	// 如果项目设置了自启或关联了自启服务，先移除自启动服务
+	if jpid.Autostart == 1 || jpid.UnitName != "" {
+		autoName := autostartName(jpid)
+		manager, err := autostartManager()
+		if err == nil {
+			err = manager.Remove(ctx, autoName)
+		}
+		if err != nil && !errors.Is(err, autostart.ErrNotFound) {
+			g.Log().Warning(ctx, "移除自启动服务失败", "error", err)
+			// 继续执行删除操作，不中断流程
 		}
 	}
 
 	// 执行删除操作
 	_, err = dao.Jpid.Ctx(ctx).Where("id", id).Delete()
+	if err == nil {
+		Autostart().forget(id)
+	}
 	return err
 }
 
+// autostartName 项目的自启服务名，systemd 服务为 autostart-<服务名>
+// 优先使用项目关联的服务，没有关联时为 <项目名>_<端口>
+func autostartName(jpid *entity.Jpid) string {
+	if jpid.UnitName != "" {
+		return jpid.UnitName
+	}
+	return legacyAutostartName(jpid)
+}
+
+// legacyAutostartName 按项目名和端口生成的服务名，关联服务名之前注册的自启服务都是这样命名的
+func legacyAutostartName(jpid *entity.Jpid) string {
+	return jpid.Name + "_" + jpid.Ports
+}
+
+// autostartConfig 按项目的启动命令、运行目录和环境变量生成自启服务配置
+func (s *SJpid) autostartConfig(ctx context.Context, jpid *entity.Jpid, name string) (*autostart.ServiceConfig, error) {
+	var execStr string
+	if jpid.Script == "" {
+		var err error
+		if execStr, err = s.Command(jpid); err != nil {
+			return nil, err
+		}
+	} else {
+		execStr = jpid.Script + " -b false"
+	}
+
+	// 自启服务的 unit 文件是明文，不写入密钥
+	if len(Secret().Refs(jpid.Env)) > 0 {
+		return nil, gerror.New("项目环境变量引用了密钥，自启服务不支持写入密钥")
+	}
+	launchEnv, err := LaunchEnv(ctx, jpid)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := autostart.NewServiceConfig(name, execStr)
+	cfg.WorkDir = jpid.Catalog
+	// 获取description,如果为空设置默认值
+	cfg.Description = jpid.Description
+	if cfg.Description == "" {
+		cfg.Description = "Service for " + jpid.Name
+	}
+	for _, env := range launchEnv {
+		if key, value, ok := strings.Cut(env, "="); ok {
+			cfg.Env[key] = value
+		}
+	}
+	return cfg, nil
+}
+
+// autostartManager 以 root 运行时管理系统服务，否则管理当前用户的服务（systemctl --user）
+func autostartManager() (*autostart.ServiceManager, error) {
+	if os.Geteuid() == 0 {
+		return autostart.NewServiceManager(), nil
+	}
+	return autostart.NewUserServiceManager()
+}
+
 // UpdateAutostart 更新自启状态并处理自启服务
 func (s *SJpid) UpdateAutostart(ctx context.Context, id int, autostartType int) error {
 	// 获取项目信息
@@ -344,73 +529,71 @@ func (s *SJpid) UpdateAutostart(ctx context.Context, id int, autostartType int)
 		return gerror.New("docker 方式运行的项目不支持设置自启动")
 	}
 
-	// 检查autostart命令是否存在
-	if !autostart.IsAutostartInstalled() {
-		return gerror.New("请先安装autostart并设置环境变量")
+	// 直接管理 systemd 服务，不再依赖 autostart 命令
+	manager, err := autostartManager()
+	if err != nil {
+		return gerror.Wrap(err, "当前系统不支持设置自启动")
+	}
+	if err = manager.CheckSystem(); err != nil {
+		return gerror.Wrap(err, "当前系统不支持设置自启动")
 	}
 
-	var autoName = jpid.Name + "_" + jpid.Ports
+	var autoName = autostartName(jpid)
 	g.Log().Info(ctx, "更新自启状态", "pid", jpid.Pid, "autoName", autoName, "autostart", autostartType)
 
-	// 检查服务是否已存在
-	serviceExists := autostart.CheckAutostartServiceExists(ctx, autoName)
-
 	if autostartType == 1 {
 		// 启用自启
-		if !serviceExists {
+		if !manager.Exists(autoName) {
 			// 服务不存在，添加新服务
-			var execStr string
-			// 使用单引号包裹整个命令
-			if jpid.Script == "" {
-				execStr = jpid.Run
-			} else {
-				execStr = jpid.Script + " -b false"
-			}
-
-			g.Log().Info(ctx, "添加自启服务", "execStr", execStr)
-
-			// 获取description,如果为空设置默认值
-			description := jpid.Description
-			if description == "" {
-				description = "Service for " + jpid.Name
-			}
-
-			// 注册自启（使用改进的命令执行函数）
-			err := system.ExecCommand(ctx, "autostart", "add", autoName, execStr,
-				"--workdir="+jpid.Catalog, "--description="+description)
+			cfg, err := s.autostartConfig(ctx, jpid, autoName)
 			if err != nil {
+				return err
+			}
+			g.Log().Info(ctx, "添加自启服务", "execStr", cfg.ExecStart)
+			if err = manager.Add(ctx, cfg); err != nil {
 				return gerror.Wrap(err, "注册自启服务失败")
 			}
 		}
 
 		// 启用自启（无论服务是否已存在都需要确保启用）
-		err := system.ExecCommand(ctx, "autostart", "enable", autoName)
-		if err != nil {
+		if err = manager.Enable(ctx, autoName); err != nil {
 			return gerror.Wrap(err, "启用自启服务失败")
 		}
+		if lingering, err := manager.Lingering(); err == nil && !lingering {
+			g.Log().Warning(ctx, "当前用户未开启 lingering，用户级自启服务开机不会启动，需要执行 sudo loginctl enable-linger <用户名>")
+		}
 
 		// 验证自启动服务
-		err = autostart.VerifyAutostartService(ctx, autoName)
-		if err != nil {
+		if status, err := manager.Status(ctx, autoName); err != nil {
 			g.Log().Warning(ctx, "验证自启动服务失败", "error", err)
+		} else if !status.Enabled {
+			g.Log().Warning(ctx, "自启动服务未启用", "autoName", autoName, "autostart", status.AutostartStatus)
 		}
 
 	} else {
 		// 移除自启
-		if serviceExists {
-			// 使用非交互模式移除服务
-			err := autostart.RemoveAutostartServiceNonInteractive(ctx, autoName)
-			if err != nil {
-				return gerror.Wrap(err, "移除自启服务失败")
-			}
-		} else {
+		err = manager.Remove(ctx, autoName)
+		if errors.Is(err, autostart.ErrNotFound) {
 			g.Log().Info(ctx, "自启服务不存在，跳过移除", "autoName", autoName)
+		} else if err != nil {
+			return gerror.Wrap(err, "移除自启服务失败")
 		}
 	}
 
-	// 更新数据库
+	// 更新数据库，记录关联的服务名，项目改名、改端口后仍能找到
+	unitName := autoName
+	if autostartType != 1 {
+		unitName = ""
+	}
 	_, err = dao.Jpid.Ctx(ctx).Data(g.Map{
 		"autostart": autostartType,
+		"unit_name": unitName,
 	}).Where("id", id).Update()
+	if err == nil {
+		Autostart().refresh(ctx, manager, id, unitName)
+	}
+	if err == nil && jpid.Autostart != autostartType {
+		Event().Publish(ctx, model.EventProjectAutostart, jpid, map[string]interface{}{"autostart": autostartType})
+	}
 	return err
 }
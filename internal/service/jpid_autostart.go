@@ -0,0 +1,50 @@
+package service
+
+import (
+	"autostart/pkg/autostart"
+	"context"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"omniscient/internal/dao"
+	"omniscient/internal/model/entity"
+)
+
+// journalMaxLines 一次最多返回的日志条数
+const journalMaxLines = 1000
+
+// Journal 读取项目自启服务的 journal 日志，返回 systemd 服务名和日志
+// 只有本机注册了自启的项目才有，项目以自启服务运行时 stdout、stderr 都在这里，消息中的密钥值会被遮盖
+func (s *SJpid) Journal(ctx context.Context, id int, opts autostart.LogOptions) (unit string, list []*autostart.LogEntry, err error) {
+	var jpid *entity.Jpid
+	if err = dao.Jpid.Ctx(ctx).Where("id", id).Scan(&jpid); err != nil {
+		return "", nil, err
+	}
+	if jpid == nil {
+		return "", nil, gerror.New("项目不存在")
+	}
+	// 其他服务器上的项目不能读取本机同名服务的日志
+	if err = checkLocalWorker(jpid); err != nil {
+		return "", nil, err
+	}
+
+	manager, err := autostartManager()
+	if err != nil {
+		return "", nil, gerror.Wrap(err, "当前系统不支持自启服务")
+	}
+	name := autostartName(jpid)
+	if !manager.Exists(name) {
+		return "", nil, gerror.Newf("项目没有注册自启服务: %s", autostart.UnitName(name))
+	}
+
+	if opts.Lines > journalMaxLines {
+		opts.Lines = journalMaxLines
+	}
+	list, err = manager.QueryLogs(ctx, name, opts)
+	if err != nil {
+		return "", nil, gerror.Wrap(err, "读取自启服务日志失败")
+	}
+	// 项目启动时可能把 ${secret:NAME} 展开后的值打印到日志
+	for _, entry := range list {
+		entry.Message = Secret().Mask(entry.Message)
+	}
+	return autostart.UnitName(name), list, nil
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/encoding/gyaml"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/internal/dao"
+	"omniscient/internal/model"
+	"omniscient/internal/model/do"
+	"omniscient/internal/model/entity"
+	"omniscient/internal/util/system"
+)
+
+const (
+	// BundleFormatYAML yaml 格式导出包
+	BundleFormatYAML = "yaml"
+	// BundleFormatJSON json 格式导出包
+	BundleFormatJSON = "json"
+
+	bundleVersion = 1
+)
+
+// Export 导出服务器上的全部项目定义，worker 为空时导出当前服务器
+func (s *SJpid) Export(ctx context.Context, worker string) (*model.ProjectBundle, error) {
+	if worker == "" {
+		worker = system.GetWorkerName()
+	}
+
+	var projects []*entity.Jpid
+	if err := dao.Jpid.Ctx(ctx).Where("worker", worker).Order("id ASC").Scan(&projects); err != nil {
+		return nil, err
+	}
+
+	bundle := &model.ProjectBundle{
+		Version:    bundleVersion,
+		Worker:     worker,
+		ExportedAt: time.Now().Format(time.RFC3339),
+		Projects:   make([]*model.ProjectDefinition, 0, len(projects)),
+	}
+	refer, err := Secret().Referrer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, project := range projects {
+		def, err := exportDefinition(project, refer)
+		if err != nil {
+			return nil, err
+		}
+		bundle.Projects = append(bundle.Projects, def)
+	}
+	return bundle, nil
+}
+
+// exportDefinition 导出包会离开本机，环境变量和 Jolokia 地址中的密钥明文换成 ${secret:NAME} 引用，导入后按目标服务器的密钥解析
+// 其他字段启动时不解析引用，含有密钥明文时拒绝导出
+func exportDefinition(project *entity.Jpid, refer *strings.Replacer) (*model.ProjectDefinition, error) {
+	for _, field := range [][2]string{{"run", project.Run}, {"script", project.Script}, {"jvmOpts", project.JvmOpts}, {"args", project.Args}} {
+		if refer.Replace(field[1]) != field[1] {
+			return nil, gerror.Newf("项目 %s 的 %s 中包含密钥明文，请改为在环境变量中引用密钥后再导出", project.Name, field[0])
+		}
+	}
+	def := projectDefinition(project)
+	for key, value := range def.Env {
+		def.Env[key] = refer.Replace(value)
+	}
+	def.Jolokia = refer.Replace(def.Jolokia)
+	return def, nil
+}
+
+// EncodeBundle 序列化导出包
+func EncodeBundle(bundle *model.ProjectBundle, format string) ([]byte, error) {
+	switch format {
+	case "", BundleFormatYAML:
+		return gyaml.Encode(bundle)
+	case BundleFormatJSON:
+		return json.MarshalIndent(bundle, "", "  ")
+	default:
+		return nil, gerror.Newf("不支持的导出格式: %s", format)
+	}
+}
+
+// DecodeBundle 解析导出包，json 是 yaml 的子集，两种格式都可以直接解析
+func DecodeBundle(data []byte) (*model.ProjectBundle, error) {
+	var bundle *model.ProjectBundle
+	if err := gyaml.DecodeTo(data, &bundle); err != nil {
+		return nil, gerror.Wrap(err, "解析导出包失败")
+	}
+	if bundle == nil {
+		return nil, gerror.New("导出包内容为空")
+	}
+	if bundle.Version > bundleVersion {
+		return nil, gerror.Newf("导出包版本 %d 高于当前支持的版本 %d", bundle.Version, bundleVersion)
+	}
+	for i, def := range bundle.Projects {
+		if def == nil || def.Name == "" {
+			return nil, gerror.Newf("第 %d 个项目缺少 name", i+1)
+		}
+	}
+	return bundle, nil
+}
+
+// Import 将导出包导入到目标服务器，preview 为 true 时只返回导入计划
+// 同一服务器下按项目名判断冲突，strategy 决定冲突时跳过、覆盖或重命名；任一项目定义无效时都不导入
+func (s *SJpid) Import(ctx context.Context, bundle *model.ProjectBundle, worker, strategy string, preview bool) (*model.ImportResult, error) {
+	if worker == "" {
+		worker = system.GetWorkerName()
+	}
+	if strategy == "" {
+		strategy = model.ImportSkip
+	}
+	if strategy != model.ImportSkip && strategy != model.ImportOverwrite && strategy != model.ImportRename {
+		return nil, gerror.Newf("不支持的冲突策略: %s", strategy)
+	}
+
+	var existing []*entity.Jpid
+	if err := dao.Jpid.Ctx(ctx).Where("worker", worker).Order("id ASC").Scan(&existing); err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*entity.Jpid)
+	for _, project := range existing {
+		if _, ok := byName[project.Name]; !ok {
+			byName[project.Name] = project
+		}
+	}
+
+	result := &model.ImportResult{
+		Worker:   worker,
+		Strategy: strategy,
+		Preview:  preview,
+		Items:    make([]*model.ImportItem, 0, len(bundle.Projects)),
+	}
+	// 自启服务只能在本机创建
+	local := worker == system.GetWorkerName()
+	var writes []*importWrite
+
+	for _, def := range bundle.Projects {
+		item := &model.ImportItem{Name: def.Name, TargetName: def.Name, Action: model.ImportCreate}
+		current, conflict := byName[def.Name]
+		if conflict {
+			item.ExistingId = current.Id
+			switch strategy {
+			case model.ImportSkip:
+				item.Action = model.ImportSkip
+				item.Message = "已存在同名项目"
+			case model.ImportOverwrite:
+				item.Action = model.ImportOverwrite
+				if current.Manifest != "" {
+					item.Message = "该项目由清单管理，下次对账时会按清单恢复"
+				}
+			case model.ImportRename:
+				item.Action = model.ImportRename
+				item.TargetName = uniqueName(def.Name, byName)
+			}
+		}
+		result.Items = append(result.Items, item)
+
+		switch item.Action {
+		case model.ImportSkip:
+			result.Skipped++
+			continue
+		case model.ImportOverwrite:
+			result.Updated++
+		default:
+			result.Created++
+			// 占用名称，避免同一导出包内重名
+			byName[item.TargetName] = &entity.Jpid{Name: item.TargetName}
+		}
+		// JDK 目录只能在本机检查
+		jdkHome := def.JdkHome
+		if !local {
+			jdkHome = ""
+		}
+		if err := ValidateLaunch(def.JvmOpts, jdkHome); err != nil {
+			return nil, gerror.Wrapf(err, "项目 %s", def.Name)
+		}
+		if err := Secret().CheckRefs(ctx, map[string]string{"env": FormatEnv(def.Env), "jolokia": def.Jolokia}); err != nil {
+			return nil, gerror.Wrapf(err, "项目 %s", def.Name)
+		}
+		writes = append(writes, &importWrite{item: item, def: def, current: current})
+	}
+	if preview {
+		return result, nil
+	}
+
+	// 全部导入或全部不导入，不会留下导入了一半的服务器
+	err := dao.Jpid.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		for _, w := range writes {
+			data := definitionData(w.def)
+			data.Name = w.item.TargetName
+			if w.item.Action == model.ImportOverwrite {
+				w.id = w.current.Id
+				if _, err := dao.Jpid.Ctx(ctx).Data(data).Where("id", w.id).Update(); err != nil {
+					return gerror.Wrapf(err, "覆盖项目 %s 失败", w.def.Name)
+				}
+				continue
+			}
+			data.Pid = 0
+			data.Status = 0
+			data.Worker = worker
+			data.Autostart = 0
+			newId, err := dao.Jpid.Ctx(ctx).Data(data).InsertAndGetId()
+			if err != nil {
+				return gerror.Wrapf(err, "导入项目 %s 失败", w.def.Name)
+			}
+			w.id = int(newId)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 自启服务不在事务内，提交后再开启，失败只记录在导入结果中
+	for _, w := range writes {
+		if w.def.Autostart != 1 || (w.item.Action == model.ImportOverwrite && w.current.Autostart == 1) {
+			continue
+		}
+		if !local {
+			w.item.Message = "自启需在目标服务器上开启"
+		} else if err := s.UpdateAutostart(ctx, w.id, 1); err != nil {
+			w.item.Message = fmt.Sprintf("开启自启失败: %v", err)
+			g.Log().Warningf(ctx, "导入项目 %s 开启自启失败: %v", w.item.TargetName, err)
+		}
+	}
+	return result, nil
+}
+
+// importWrite 导入计划中需要写入的项目
+type importWrite struct {
+	item    *model.ImportItem
+	def     *model.ProjectDefinition
+	current *entity.Jpid
+	id      int
+}
+
+// uniqueName 生成不冲突的项目名：demo.jar -> demo-1.jar
+func uniqueName(name string, taken map[string]*entity.Jpid) string {
+	base, ext := name, ""
+	if i := strings.LastIndex(name, "."); i > 0 {
+		base, ext = name[:i], name[i:]
+	}
+	for n := 1; ; n++ {
+		candidate := fmt.Sprintf("%s-%d%s", base, n, ext)
+		if _, ok := taken[candidate]; !ok {
+			return candidate
+		}
+	}
+}
+
+// projectDefinition 项目记录转换为项目定义
+func projectDefinition(project *entity.Jpid) *model.ProjectDefinition {
+	env := ParseEnv(project.Env)
+	if len(env) == 0 {
+		env = nil
+	}
+	return &model.ProjectDefinition{
+		Name:           project.Name,
+		Ports:          project.Ports,
+		Catalog:        project.Catalog,
+		Run:            project.Run,
+		Script:         project.Script,
+		Description:    project.Description,
+		Way:            project.Way,
+		Autostart:      project.Autostart,
+		Env:            env,
+		HealthCheck:    project.HealthCheck,
+		JvmOpts:        ParseJvmOptions(project.JvmOpts),
+		Args:           ParseArgs(project.Args),
+		JdkHome:        project.JdkHome,
+		Actuator:       project.Actuator,
+		StopStrategy:   project.StopStrategy,
+		Jolokia:        project.Jolokia,
+		JolokiaQueries: ParseJolokiaQueries(project.JolokiaQueries),
+	}
+}
+
+// definitionData 项目定义转换为项目记录，不包含 pid、状态、worker 和自启
+func definitionData(def *model.ProjectDefinition) do.Jpid {
+	way := def.Way
+	if way == 0 {
+		way = 2
+	}
+	return do.Jpid{
+		Name:           def.Name,
+		Ports:          def.Ports,
+		Catalog:        def.Catalog,
+		Run:            def.Run,
+		Script:         def.Script,
+		Description:    def.Description,
+		Way:            way,
+		Env:            FormatEnv(def.Env),
+		HealthCheck:    def.HealthCheck,
+		JvmOpts:        FormatJvmOptions(def.JvmOpts),
+		Args:           FormatArgs(def.Args),
+		JdkHome:        def.JdkHome,
+		JdkId:          0, // JDK 编号只在本机有效，导入后按目录使用
+		Actuator:       def.Actuator,
+		StopStrategy:   def.StopStrategy,
+		Jolokia:        def.Jolokia,
+		JolokiaQueries: FormatJolokiaQueries(def.JolokiaQueries),
+	}
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/os/gfile"
+	"omniscient/internal/dao"
+	"omniscient/internal/model"
+	"omniscient/internal/model/do"
+	"omniscient/internal/model/entity"
+)
+
+// gcFlags 垃圾回收器对应的 JVM 参数
+var gcFlags = map[string]string{
+	"g1":         "-XX:+UseG1GC",
+	"zgc":        "-XX:+UseZGC",
+	"parallel":   "-XX:+UseParallelGC",
+	"serial":     "-XX:+UseSerialGC",
+	"shenandoah": "-XX:+UseShenandoahGC",
+}
+
+// heapSizePattern 堆内存大小，如 512m、2g
+var heapSizePattern = regexp.MustCompile(`^\d+[kKmMgG]?$`)
+
+// LaunchConfig 项目的结构化启动配置
+type LaunchConfig struct {
+	Env     map[string]string // 环境变量
+	JvmOpts *model.JvmOptions // JVM参数
+	Args    []string          // 程序参数
+	JdkHome string            // JDK目录
+	JdkId   int               // 指定的JDK，不为 0 时覆盖 JdkHome
+	Run     string            // 原生启动命令，不为空时覆盖组合命令
+}
+
+// Command 生成项目的启动命令
+// run 不为空时原样使用（兼容自动注册和手写的命令，设置了 JDK 目录时替换命令开头的 java），
+// 否则由 JDK、JVM 参数、jar 和程序参数组合
+func (s *SJpid) Command(project *entity.Jpid) (string, error) {
+	if project.Run != "" {
+		return replaceJava(project.Run, project.JdkHome), nil
+	}
+	if project.Way == 1 {
+		return "", gerror.New("docker 项目没有启动命令")
+	}
+	if !strings.HasSuffix(project.Name, ".jar") {
+		return "", gerror.Newf("项目名 %s 不是 jar 文件，无法组合启动命令，请设置 run", project.Name)
+	}
+
+	java := "java"
+	if project.JdkHome != "" {
+		java = filepath.Join(project.JdkHome, "bin", "java")
+	}
+
+	parts := []string{shellQuote(java)}
+	for _, opt := range JvmArgs(ParseJvmOptions(project.JvmOpts)) {
+		parts = append(parts, shellQuote(opt))
+	}
+	parts = append(parts, "-jar", shellQuote(project.Name))
+	for _, arg := range ParseArgs(project.Args) {
+		parts = append(parts, shellQuote(arg))
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// JvmArgs 将结构化 JVM 参数转换为命令行参数
+func JvmArgs(opts *model.JvmOptions) []string {
+	if opts == nil {
+		return nil
+	}
+
+	var args []string
+	if opts.Xms != "" {
+		args = append(args, "-Xms"+opts.Xms)
+	}
+	if opts.Xmx != "" {
+		args = append(args, "-Xmx"+opts.Xmx)
+	}
+	if flag, ok := gcFlags[strings.ToLower(opts.Gc)]; ok {
+		args = append(args, flag)
+	}
+
+	keys := make([]string, 0, len(opts.Properties))
+	for key := range opts.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		args = append(args, fmt.Sprintf("-D%s=%s", key, opts.Properties[key]))
+	}
+	return append(args, opts.Options...)
+}
+
+// UpdateLaunch 更新项目的结构化启动配置，返回生效的启动命令
+func (s *SJpid) UpdateLaunch(ctx context.Context, id int, cfg *LaunchConfig) (string, error) {
+	var project *entity.Jpid
+	if err := dao.Jpid.Ctx(ctx).Where("id", id).Scan(&project); err != nil {
+		return "", err
+	}
+	if project == nil {
+		return "", gerror.New("项目不存在")
+	}
+	if err := checkManifestJdk(project, cfg.JdkId); err != nil {
+		return "", err
+	}
+	if cfg.JdkId > 0 {
+		record, err := Jdk().Get(ctx, cfg.JdkId)
+		if err != nil {
+			return "", err
+		}
+		if record.Worker != project.Worker {
+			return "", gerror.Newf("JDK %d 不在项目所在的服务器 %s 上", cfg.JdkId, project.Worker)
+		}
+		cfg.JdkHome = record.Home
+	}
+	if err := ValidateLaunch(cfg.JvmOpts, cfg.JdkHome); err != nil {
+		return "", err
+	}
+	if err := Secret().CheckRefs(ctx, cfg.Env); err != nil {
+		return "", err
+	}
+
+	project.Env = FormatEnv(cfg.Env)
+	project.JvmOpts = FormatJvmOptions(cfg.JvmOpts)
+	project.Args = FormatArgs(cfg.Args)
+	project.JdkHome = cfg.JdkHome
+	project.JdkId = cfg.JdkId
+	project.Run = cfg.Run
+	command, err := s.Command(project)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = dao.Jpid.Ctx(ctx).Data(do.Jpid{
+		Env:     project.Env,
+		JvmOpts: project.JvmOpts,
+		Args:    project.Args,
+		JdkHome: project.JdkHome,
+		JdkId:   project.JdkId,
+		Run:     project.Run,
+	}).Where("id", id).Update()
+	return command, err
+}
+
+// ValidateLaunch 校验 JVM 参数和 JDK 目录
+func ValidateLaunch(opts *model.JvmOptions, jdkHome string) error {
+	if opts != nil {
+		if opts.Xms != "" && !heapSizePattern.MatchString(opts.Xms) {
+			return gerror.Newf("无效的 xms: %s", opts.Xms)
+		}
+		if opts.Xmx != "" && !heapSizePattern.MatchString(opts.Xmx) {
+			return gerror.Newf("无效的 xmx: %s", opts.Xmx)
+		}
+		if _, ok := gcFlags[strings.ToLower(opts.Gc)]; opts.Gc != "" && !ok {
+			return gerror.Newf("不支持的垃圾回收器: %s", opts.Gc)
+		}
+		for _, opt := range opts.Options {
+			if !strings.HasPrefix(opt, "-") {
+				return gerror.Newf("无效的 JVM 参数: %s", opt)
+			}
+		}
+	}
+	if jdkHome != "" && !gfile.IsFile(filepath.Join(jdkHome, "bin", "java")) {
+		return gerror.Newf("JDK 目录无效，未找到 %s", filepath.Join(jdkHome, "bin", "java"))
+	}
+	return nil
+}
+
+// LaunchEnv 项目启动时追加的环境变量：项目环境变量（解析 ${secret:NAME} 引用），设置了 JDK 目录时同时设置 JAVA_HOME 和 PATH
+func LaunchEnv(ctx context.Context, project *entity.Jpid) ([]string, error) {
+	var env []string
+	if project.JdkHome != "" {
+		env = append(env,
+			"JAVA_HOME="+project.JdkHome,
+			fmt.Sprintf("PATH=%s:%s", filepath.Join(project.JdkHome, "bin"), os.Getenv("PATH")),
+		)
+	}
+	for _, item := range EnvList(project.Env) {
+		resolved, err := Secret().Resolve(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+		env = append(env, resolved)
+	}
+	return env, nil
+}
+
+// HasLaunchConfig 项目是否使用结构化启动配置
+func HasLaunchConfig(project *entity.Jpid) bool {
+	return project.JvmOpts != "" || project.Args != "" || project.JdkHome != ""
+}
+
+// ParseJvmOptions 解析 JVM 参数
+func ParseJvmOptions(data string) *model.JvmOptions {
+	if strings.TrimSpace(data) == "" {
+		return nil
+	}
+	var opts *model.JvmOptions
+	_ = json.Unmarshal([]byte(data), &opts)
+	return opts
+}
+
+// FormatJvmOptions 序列化 JVM 参数，空参数返回空字符串
+func FormatJvmOptions(opts *model.JvmOptions) string {
+	if opts == nil || (opts.Xms == "" && opts.Xmx == "" && opts.Gc == "" && len(opts.Properties) == 0 && len(opts.Options) == 0) {
+		return ""
+	}
+	data, _ := json.Marshal(opts)
+	return string(data)
+}
+
+// ParseArgs 解析程序参数
+func ParseArgs(data string) []string {
+	var args []string
+	if strings.TrimSpace(data) != "" {
+		_ = json.Unmarshal([]byte(data), &args)
+	}
+	return args
+}
+
+// FormatArgs 序列化程序参数
+func FormatArgs(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(args)
+	return string(data)
+}
+
+// replaceJava 将命令开头的 java 可执行文件替换为指定 JDK 的 java，其他命令原样返回
+func replaceJava(run, jdkHome string) string {
+	trimmed := strings.TrimLeft(run, " \t")
+	first := strings.Fields(trimmed)
+	if jdkHome == "" || len(first) == 0 || filepath.Base(first[0]) != "java" {
+		return run
+	}
+	return shellQuote(filepath.Join(jdkHome, "bin", "java")) + trimmed[len(first[0]):]
+}
+
+// shellQuote 参数包含特殊字符时使用单引号包裹
+func shellQuote(s string) string {
+	if s != "" && !strings.ContainsAny(s, " \t\n'\"\\$`!*?&;|<>()[]{}#~") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
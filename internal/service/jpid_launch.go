@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"omniscient/internal/dao"
+	"omniscient/internal/model/entity"
+)
+
+// Launch 后台启动项目并回写 PID 和状态，供对账等非交互场景使用
+// docker 项目执行 docker start；jdk 项目优先使用 script，否则以 nohup 后台执行 run
+func (s *SJpid) Launch(ctx context.Context, project *entity.Jpid) (int, error) {
+	if project.Way == 1 {
+		output, err := exec.CommandContext(ctx, "docker", "start", project.Name).CombinedOutput()
+		if err != nil {
+			return 0, gerror.Wrapf(err, "启动容器失败: %s", strings.TrimSpace(string(output)))
+		}
+		return project.Pid, s.UpdateStatusById(ctx, project.Id, 1)
+	}
+
+	if project.Catalog == "" {
+		return 0, gerror.New("项目目录为空")
+	}
+	if _, err := os.Stat(project.Catalog); err != nil {
+		return 0, gerror.Wrapf(err, "项目目录不存在: %s", project.Catalog)
+	}
+
+	var shell string
+	if project.Script != "" {
+		shell = project.Script
+	} else {
+		command, err := s.Command(project)
+		if err != nil {
+			return 0, err
+		}
+		// nohup 会 exec 目标程序，$! 即为 java 进程 PID
+		shell = fmt.Sprintf("nohup %s > nohup.log 2>&1 &\necho $!", command)
+	}
+
+	cmd := exec.CommandContext(ctx, "bash", "-c", shell)
+	cmd.Dir = project.Catalog
+	cmd.Env = append(os.Environ(),
+		fmt.Sprintf("PROJECT_NAME=%s", project.Name),
+		fmt.Sprintf("PROJECT_PID=%d", project.Pid),
+		"LANG=en_US.UTF-8",
+	)
+	launchEnv, err := LaunchEnv(ctx, project)
+	if err != nil {
+		return 0, err
+	}
+	cmd.Env = append(cmd.Env, launchEnv...)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return 0, gerror.Wrapf(err, "启动失败: %s", strings.TrimSpace(string(output)))
+	}
+
+	// 优先按名称和端口匹配新进程，匹配不到时使用 nohup 返回的 PID
+	newPid, findErr := s.FindNewPid(ctx, project)
+	if findErr != nil && project.Script == "" {
+		if pid, convErr := strconv.Atoi(strings.TrimSpace(string(output))); convErr == nil && s.IsProcessRunning(pid) {
+			newPid, findErr = pid, nil
+		}
+	}
+	if findErr != nil {
+		return 0, gerror.Wrap(findErr, "启动后未找到项目进程")
+	}
+
+	if _, err = dao.Jpid.Ctx(ctx).
+		Data(g.Map{"pid": newPid, "status": 1}).
+		Where("id", project.Id).
+		Update(); err != nil {
+		return 0, err
+	}
+	s.recordStarted(ctx, project, newPid)
+	return newPid, nil
+}
+
+// Shutdown 停止项目并更新状态
+func (s *SJpid) Shutdown(ctx context.Context, project *entity.Jpid) error {
+	if project.Way == 1 {
+		output, err := exec.CommandContext(ctx, "docker", "stop", "-t", "10", project.Name).CombinedOutput()
+		if err != nil {
+			return gerror.Wrapf(err, "停止容器失败: %s", strings.TrimSpace(string(output)))
+		}
+	} else if project.Pid > 0 && s.IsProcessRunning(project.Pid) && !s.StopByActuator(ctx, project) {
+		if err := s.Stop(ctx, project.Pid); err != nil {
+			return err
+		}
+	}
+	return s.UpdateStatusById(ctx, project.Id, 0)
+}
+
+// IsRunning 判断项目是否处于运行状态
+func (s *SJpid) IsRunning(project *entity.Jpid) bool {
+	if project.Status != 1 {
+		return false
+	}
+	if project.Way == 1 {
+		output, err := exec.Command("docker", "inspect", "-f", "{{.State.Running}}", project.Name).Output()
+		return err == nil && strings.TrimSpace(string(output)) == "true"
+	}
+	return project.Pid > 0 && s.IsProcessRunning(project.Pid)
+}
+
+// ParseEnv 解析项目环境变量（JSON 对象）
+func ParseEnv(env string) map[string]string {
+	result := make(map[string]string)
+	if strings.TrimSpace(env) == "" {
+		return result
+	}
+	_ = json.Unmarshal([]byte(env), &result)
+	return result
+}
+
+// FormatEnv 序列化项目环境变量，按 key 排序保证结果稳定
+func FormatEnv(env map[string]string) string {
+	if len(env) == 0 {
+		return ""
+	}
+	data, _ := json.Marshal(env) // encoding/json 对 map 的 key 排序输出
+	return string(data)
+}
+
+// EnvList 转换为 KEY=VALUE 列表
+func EnvList(env string) []string {
+	vars := ParseEnv(env)
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	list := make([]string, 0, len(keys))
+	for _, key := range keys {
+		list = append(list, fmt.Sprintf("%s=%s", key, vars[key]))
+	}
+	return list
+}
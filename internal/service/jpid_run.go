@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"omniscient/internal/dao"
+	"omniscient/internal/model"
+	"omniscient/internal/model/do"
+	"omniscient/internal/model/entity"
+	"omniscient/internal/util/jarinfo"
+	"omniscient/internal/util/javaprocess"
+)
+
+// defaultRunLimit 运行记录默认返回条数
+const defaultRunLimit = 50
+
+type SJpidRun struct{}
+
+func JpidRun() *SJpidRun {
+	return &SJpidRun{}
+}
+
+// Started 记录项目以新的 pid 启动：结束上一条运行记录，解析 jar 构建信息后新增运行记录，并更新项目的构建版本
+// 同一个 pid 重复调用不会重复记录
+func (s *SJpidRun) Started(ctx context.Context, project *entity.Jpid, pid int) error {
+	var running *entity.JpidRun
+	if err := dao.JpidRun.Ctx(ctx).
+		Where("jpid_id", project.Id).
+		WhereNull("stopped_at").
+		Order("id DESC").
+		Scan(&running); err != nil {
+		return err
+	}
+	if running != nil && running.Pid == pid {
+		return nil
+	}
+	if err := s.Stopped(ctx, project.Id); err != nil {
+		return err
+	}
+
+	info, err := s.inspectProcess(project, pid)
+	if err != nil {
+		g.Log().Debugf(ctx, "解析项目 %s 的 jar 失败: %v", project.Name, err)
+		info = &model.JarInfo{}
+	}
+	var jarInfo string
+	if info.Path != "" {
+		data, _ := json.Marshal(info)
+		jarInfo = string(data)
+	}
+
+	if _, err = dao.JpidRun.Ctx(ctx).Data(do.JpidRun{
+		JpidId:       project.Id,
+		Worker:       project.Worker,
+		Pid:          pid,
+		JarPath:      info.Path,
+		Checksum:     info.Checksum,
+		BuildVersion: info.Version,
+		BuildCommit:  info.Commit,
+		JarInfo:      jarInfo,
+		StartedAt:    gtime.Now(),
+	}).Insert(); err != nil {
+		return err
+	}
+	_, err = dao.Jpid.Ctx(ctx).Data(do.Jpid{
+		BuildVersion: info.Version,
+		BuildCommit:  info.Commit,
+		JarChecksum:  info.Checksum,
+	}).Where("id", project.Id).Update()
+	return err
+}
+
+// Stopped 结束项目运行中的记录
+func (s *SJpidRun) Stopped(ctx context.Context, projectId int) error {
+	_, err := dao.JpidRun.Ctx(ctx).
+		Data(do.JpidRun{StoppedAt: gtime.Now()}).
+		Where("jpid_id", projectId).
+		WhereNull("stopped_at").
+		Update()
+	return err
+}
+
+// Crashed 结束项目运行中的记录，并记录意外退出的原因
+func (s *SJpidRun) Crashed(ctx context.Context, projectId int, report *model.CrashReport) error {
+	_, err := dao.JpidRun.Ctx(ctx).
+		Data(do.JpidRun{
+			StoppedAt:  gtime.Now(),
+			ExitReason: report.Reason,
+			ExitDetail: report.Detail,
+			CrashFile:  report.File,
+		}).
+		Where("jpid_id", projectId).
+		WhereNull("stopped_at").
+		Update()
+	return err
+}
+
+// List 项目的运行记录，按启动时间倒序
+func (s *SJpidRun) List(ctx context.Context, projectId, limit int) (list []*entity.JpidRun, err error) {
+	if limit <= 0 {
+		limit = defaultRunLimit
+	}
+	err = dao.JpidRun.Ctx(ctx).Where("jpid_id", projectId).Order("id DESC").Limit(limit).Scan(&list)
+	return
+}
+
+// Inspect 解析项目的 jar：运行中使用进程命令行中的 jar，否则使用 <catalog>/<name>
+func (s *SJpidRun) Inspect(ctx context.Context, projectId int) (*model.JarInfo, error) {
+	var project *entity.Jpid
+	if err := dao.Jpid.Ctx(ctx).Where("id", projectId).Scan(&project); err != nil {
+		return nil, err
+	}
+	if project == nil {
+		return nil, gerror.New("项目不存在")
+	}
+	if Jpid().IsRunning(project) && project.Way != 1 {
+		return s.inspectProcess(project, project.Pid)
+	}
+	if project.Catalog == "" {
+		return nil, gerror.New("项目未运行且没有设置运行目录")
+	}
+	return jarinfo.Inspect(filepath.Join(project.Catalog, project.Name))
+}
+
+// Builds 各服务器上项目运行的构建版本，name 为空时返回全部项目
+func (s *SJpidRun) Builds(ctx context.Context, name string) (list []*entity.Jpid, err error) {
+	query := dao.Jpid.Ctx(ctx).Fields("id, name, worker, pid, status, build_version, build_commit, jar_checksum")
+	if name != "" {
+		query = query.Where("name", name)
+	}
+	err = query.Order("name ASC, worker ASC").Scan(&list)
+	return
+}
+
+// inspectProcess 解析进程命令行中的 jar，取不到时使用 <catalog>/<name>
+func (s *SJpidRun) inspectProcess(project *entity.Jpid, pid int) (*model.JarInfo, error) {
+	path, err := javaprocess.ProcessJarPath(pid)
+	if err != nil {
+		if project.Catalog == "" {
+			return nil, err
+		}
+		path = filepath.Join(project.Catalog, project.Name)
+	}
+	return jarinfo.Inspect(path)
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gtime"
+	"omniscient/internal/dao"
+	"omniscient/internal/model"
+	"omniscient/internal/model/do"
+	"omniscient/internal/model/entity"
+	"omniscient/internal/util/system"
+)
+
+// defaultNotificationLimit 通知默认返回条数
+const defaultNotificationLimit = 50
+
+type SNotification struct{}
+
+func Notification() *SNotification {
+	return &SNotification{}
+}
+
+// Notify 保存一条站内通知并写入日志，失败只记录日志，不影响调用方
+func (s *SNotification) Notify(ctx context.Context, projectId int, level, title, content string) {
+	switch level {
+	case model.NotifyError:
+		g.Log().Errorf(ctx, "%s: %s", title, content)
+	case model.NotifyWarning:
+		g.Log().Warningf(ctx, "%s: %s", title, content)
+	default:
+		g.Log().Infof(ctx, "%s: %s", title, content)
+	}
+	if _, err := dao.Notification.Ctx(ctx).Data(do.Notification{
+		JpidId:    projectId,
+		Worker:    system.GetWorkerName(),
+		Level:     level,
+		Title:     title,
+		Content:   content,
+		CreatedAt: gtime.Now(),
+	}).Insert(); err != nil {
+		g.Log().Warningf(ctx, "保存通知失败: %v", err)
+	}
+}
+
+// List 通知列表，按时间倒序
+func (s *SNotification) List(ctx context.Context, unread bool, limit int) (list []*entity.Notification, err error) {
+	if limit <= 0 {
+		limit = defaultNotificationLimit
+	}
+	query := dao.Notification.Ctx(ctx)
+	if unread {
+		query = query.WhereNull("read_at")
+	}
+	err = query.Order("id DESC").Limit(limit).Scan(&list)
+	return
+}
+
+// Unread 未读通知数
+func (s *SNotification) Unread(ctx context.Context) (int, error) {
+	return dao.Notification.Ctx(ctx).WhereNull("read_at").Count()
+}
+
+// Read 标记通知为已读，ids 为空时标记全部
+func (s *SNotification) Read(ctx context.Context, ids []int) (int64, error) {
+	query := dao.Notification.Ctx(ctx).Data(do.Notification{ReadAt: gtime.Now()}).WhereNull("read_at")
+	if len(ids) > 0 {
+		query = query.WhereIn("id", ids)
+	}
+	result, err := query.Update()
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
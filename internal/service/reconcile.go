@@ -0,0 +1,513 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/encoding/gjson"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcron"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/os/gfsnotify"
+	"omniscient/internal/dao"
+	"omniscient/internal/model"
+	"omniscient/internal/model/do"
+	"omniscient/internal/model/entity"
+	"omniscient/internal/util/health"
+	"omniscient/internal/util/system"
+)
+
+const (
+	manifestStateRunning = "running"
+	manifestStateStopped = "stopped"
+	manifestRuntimeJdk   = "jdk"
+	manifestRuntimeDock  = "docker"
+
+	defaultReconcileInterval = "60s"
+	defaultHealthTimeout     = 60 * time.Second
+	// 清单目录变更后的合并等待时间，避免编辑器连续写入触发多次对账
+	reconcileDebounce = 2 * time.Second
+)
+
+type SReconcile struct{}
+
+func Reconcile() *SReconcile {
+	return &SReconcile{}
+}
+
+var (
+	// reconcileMu 保证同一时间只有一个对账在执行（定时任务、目录监听、接口调用）
+	reconcileMu sync.Mutex
+
+	debounceMu    sync.Mutex
+	debounceTimer *time.Timer
+)
+
+// reconcileItem 单个清单的对账上下文
+type reconcileItem struct {
+	manifest *model.ProjectManifest
+	project  *entity.Jpid
+	actions  []*model.ReconcileAction
+}
+
+// Dir 清单目录，为空表示未启用
+func (s *SReconcile) Dir(ctx context.Context) string {
+	return g.Cfg().MustGet(ctx, "reconcile.dir").String()
+}
+
+// Plan 生成对账计划（dry-run），不做任何修改
+func (s *SReconcile) Plan(ctx context.Context) (*model.ReconcilePlan, error) {
+	reconcileMu.Lock()
+	defer reconcileMu.Unlock()
+
+	plan, _, err := s.plan(ctx)
+	return plan, err
+}
+
+// Apply 生成对账计划并执行，返回带执行结果的计划
+func (s *SReconcile) Apply(ctx context.Context) (*model.ReconcilePlan, error) {
+	reconcileMu.Lock()
+	defer reconcileMu.Unlock()
+
+	plan, items, err := s.plan(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		s.execute(ctx, item)
+	}
+	plan.Applied = true
+	return plan, nil
+}
+
+// Start 按 reconcile 配置启动定时对账和清单目录监听，dir 为空时不启用
+func (s *SReconcile) Start(ctx context.Context) error {
+	dir := s.Dir(ctx)
+	if dir == "" {
+		return nil
+	}
+	if !gfile.IsDir(dir) {
+		return gerror.Newf("清单目录不存在: %s", dir)
+	}
+	interval := g.Cfg().MustGet(ctx, "reconcile.interval", defaultReconcileInterval).String()
+
+	_, err := gcron.AddSingleton(ctx, "@every "+interval, func(ctx context.Context) {
+		s.run(ctx)
+	}, "project-reconcile")
+	if err != nil {
+		return gerror.Wrap(err, "启动定时对账失败")
+	}
+
+	_, err = gfsnotify.Add(dir, func(event *gfsnotify.Event) {
+		if !isManifestFile(event.Path) {
+			return
+		}
+		debounceMu.Lock()
+		defer debounceMu.Unlock()
+		if debounceTimer != nil {
+			debounceTimer.Stop()
+		}
+		debounceTimer = time.AfterFunc(reconcileDebounce, func() {
+			s.run(context.Background())
+		})
+	})
+	if err != nil {
+		g.Log().Warningf(ctx, "监听清单目录失败，仅使用定时对账: %v", err)
+	}
+
+	g.Log().Infof(ctx, "已启用声明式项目清单: dir=%s, interval=%s", dir, interval)
+	go s.run(ctx)
+	return nil
+}
+
+// run 后台对账，reconcile.apply 为 false 时只记录漂移
+func (s *SReconcile) run(ctx context.Context) {
+	var (
+		plan *model.ReconcilePlan
+		err  error
+	)
+	if g.Cfg().MustGet(ctx, "reconcile.apply", true).Bool() {
+		plan, err = s.Apply(ctx)
+	} else {
+		plan, err = s.Plan(ctx)
+	}
+	if err != nil {
+		g.Log().Errorf(ctx, "项目对账失败: %v", err)
+		return
+	}
+
+	for _, msg := range plan.Errors {
+		g.Log().Warningf(ctx, "项目清单错误: %s", msg)
+	}
+	for _, drift := range plan.Drift {
+		g.Log().Infof(ctx, "项目漂移 [%s] %s: 清单=%q, 实际=%q", drift.Project, drift.Field, drift.Declared, drift.Actual)
+	}
+	for _, action := range plan.Actions {
+		if action.Error != "" {
+			g.Log().Errorf(ctx, "对账动作失败 [%s] %s: %s", action.Project, action.Action, action.Error)
+		} else if plan.Applied {
+			g.Log().Infof(ctx, "对账动作完成 [%s] %s: %s", action.Project, action.Action, action.Detail)
+		}
+	}
+}
+
+// LoadManifests 读取目录下全部项目清单
+func (s *SReconcile) LoadManifests(dir string) ([]*model.ProjectManifest, []string) {
+	var (
+		manifests []*model.ProjectManifest
+		errs      []string
+		names     = make(map[string]string)
+	)
+
+	files, err := gfile.ScanDirFile(dir, "*.yaml,*.yml,*.json")
+	if err != nil {
+		return nil, []string{fmt.Sprintf("读取清单目录失败: %v", err)}
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		manifest, err := loadManifest(file)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", file, err))
+			continue
+		}
+		if other, ok := names[manifest.Name]; ok {
+			errs = append(errs, fmt.Sprintf("%s: 项目 %s 已在 %s 中声明", file, manifest.Name, other))
+			continue
+		}
+		names[manifest.Name] = file
+		manifests = append(manifests, manifest)
+	}
+	return manifests, errs
+}
+
+// loadManifest 解析并校验单个清单文件
+func loadManifest(file string) (*model.ProjectManifest, error) {
+	var (
+		j   *gjson.Json
+		err error
+	)
+	if strings.HasSuffix(file, ".json") {
+		j, err = gjson.LoadJson(gfile.GetBytes(file))
+	} else {
+		j, err = gjson.LoadYaml(gfile.GetBytes(file))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest *model.ProjectManifest
+	if err = j.Scan(&manifest); err != nil {
+		return nil, err
+	}
+	if manifest == nil {
+		return nil, gerror.New("清单内容为空")
+	}
+
+	manifest.File, _ = filepath.Abs(file)
+	if manifest.Runtime == "" {
+		manifest.Runtime = manifestRuntimeJdk
+	}
+	if manifest.State == "" {
+		manifest.State = manifestStateRunning
+	}
+
+	switch {
+	case manifest.Name == "":
+		return nil, gerror.New("name 不能为空")
+	case manifest.Runtime != manifestRuntimeJdk && manifest.Runtime != manifestRuntimeDock:
+		return nil, gerror.Newf("不支持的 runtime: %s", manifest.Runtime)
+	case manifest.State != manifestStateRunning && manifest.State != manifestStateStopped:
+		return nil, gerror.Newf("不支持的 state: %s", manifest.State)
+	case manifest.Runtime == manifestRuntimeJdk && manifest.Catalog == "":
+		return nil, gerror.New("jdk 项目 catalog 不能为空")
+	case manifest.Runtime == manifestRuntimeJdk && manifest.Run == "" && manifest.Script == "" && !strings.HasSuffix(manifest.Name, ".jar"):
+		return nil, gerror.New("run 和 script 为空时 name 必须是 jar 文件")
+	}
+	if err = ValidateLaunch(manifest.JvmOpts, manifest.JdkHome); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// isManifestFile 是否为清单文件
+func isManifestFile(path string) bool {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// plan 对比清单和数据库、进程的实际状态，生成动作和漂移列表
+func (s *SReconcile) plan(ctx context.Context) (*model.ReconcilePlan, []*reconcileItem, error) {
+	dir := s.Dir(ctx)
+	if dir == "" {
+		return nil, nil, gerror.New("未配置清单目录 reconcile.dir")
+	}
+
+	plan := &model.ReconcilePlan{
+		Dir:         dir,
+		GeneratedAt: time.Now().Format(time.DateTime),
+		Actions:     []*model.ReconcileAction{},
+		Drift:       []*model.DriftItem{},
+	}
+	manifests, errs := s.LoadManifests(dir)
+	plan.Errors = append([]string{}, errs...)
+
+	var projects []*entity.Jpid
+	if err := dao.Jpid.Ctx(ctx).Where("worker", system.GetWorkerName()).Order("id ASC").Scan(&projects); err != nil {
+		return nil, nil, err
+	}
+	byName := make(map[string]*entity.Jpid)
+	for _, project := range projects {
+		// 同名项目优先匹配由同一清单管理的记录
+		if current, ok := byName[project.Name]; !ok || (current.Manifest == "" && project.Manifest != "") {
+			byName[project.Name] = project
+		}
+	}
+
+	var (
+		items    []*reconcileItem
+		declared = make(map[string]bool)
+	)
+	for _, manifest := range manifests {
+		declared[manifest.File] = true
+		item := &reconcileItem{manifest: manifest, project: byName[manifest.Name]}
+		s.diff(ctx, item, plan)
+		plan.Actions = append(plan.Actions, item.actions...)
+		items = append(items, item)
+	}
+
+	// 清单文件已删除的项目只报告，不自动删除
+	for _, project := range projects {
+		if project.Manifest != "" && !declared[project.Manifest] {
+			plan.Drift = append(plan.Drift, &model.DriftItem{
+				Project: project.Name, Field: "manifest", Declared: "", Actual: project.Manifest,
+			})
+		}
+	}
+	return plan, items, nil
+}
+
+// diff 计算单个清单需要执行的动作
+func (s *SReconcile) diff(ctx context.Context, item *reconcileItem, plan *model.ReconcilePlan) {
+	manifest := item.manifest
+	addAction := func(action, detail string) {
+		item.actions = append(item.actions, &model.ReconcileAction{
+			Project: manifest.Name, Manifest: manifest.File, Action: action, Detail: detail,
+		})
+	}
+	addDrift := func(field, declared, actual string) {
+		plan.Drift = append(plan.Drift, &model.DriftItem{
+			Project: manifest.Name, Field: field, Declared: Secret().Mask(declared), Actual: Secret().Mask(actual),
+		})
+	}
+
+	if item.project == nil {
+		addDrift("project", "present", "missing")
+		addAction(model.ReconcileCreate, "新建项目记录")
+		if manifest.Autostart {
+			addAction(model.ReconcileAutostart, "开启自启")
+		}
+		if manifest.State == manifestStateRunning {
+			addAction(model.ReconcileStart, "启动项目")
+		}
+		return
+	}
+
+	project := item.project
+	desired := manifestFields(manifest)
+	actual := projectFields(project)
+
+	var changed, launchChanged []string
+	for _, field := range reconcileFields {
+		if desired[field] == actual[field] {
+			continue
+		}
+		addDrift(field, desired[field], actual[field])
+		changed = append(changed, field)
+		if launchFields[field] {
+			launchChanged = append(launchChanged, field)
+		}
+	}
+	if len(changed) > 0 {
+		addAction(model.ReconcileUpdate, "更新字段: "+strings.Join(changed, ", "))
+	}
+
+	if autostart := boolToInt(manifest.Autostart); autostart != project.Autostart {
+		addDrift("autostart", fmt.Sprint(autostart), fmt.Sprint(project.Autostart))
+		if autostart == 1 {
+			addAction(model.ReconcileAutostart, "开启自启")
+		} else {
+			addAction(model.ReconcileAutostart, "关闭自启")
+		}
+	}
+
+	running := Jpid().IsRunning(project)
+	switch {
+	case manifest.State == manifestStateRunning && !running:
+		addDrift("state", manifestStateRunning, manifestStateStopped)
+		addAction(model.ReconcileStart, "启动项目")
+	case manifest.State == manifestStateStopped && running:
+		addDrift("state", manifestStateStopped, manifestStateRunning)
+		addAction(model.ReconcileStop, "停止项目")
+	case running && len(launchChanged) > 0:
+		addAction(model.ReconcileRestart, "启动配置变更: "+strings.Join(launchChanged, ", "))
+	case running && manifest.HealthCheck != "":
+		// 健康检查失败只报告，不自动重启
+		if err := health.Check(ctx, manifest.HealthCheck, 3*time.Second); err != nil {
+			addDrift("health", "healthy", err.Error())
+		}
+	}
+}
+
+// execute 依次执行单个清单的动作，任一动作失败则跳过后续动作
+func (s *SReconcile) execute(ctx context.Context, item *reconcileItem) {
+	for i, action := range item.actions {
+		if err := s.executeAction(ctx, item, action); err != nil {
+			action.Error = err.Error()
+			for _, skipped := range item.actions[i+1:] {
+				skipped.Error = "前置动作失败，已跳过"
+			}
+			return
+		}
+	}
+}
+
+// executeAction 执行单个动作
+func (s *SReconcile) executeAction(ctx context.Context, item *reconcileItem, action *model.ReconcileAction) error {
+	manifest := item.manifest
+	switch action.Action {
+	case model.ReconcileCreate:
+		data := manifestData(manifest)
+		data.Pid = 0
+		data.Status = 0
+		data.Worker = system.GetWorkerName()
+		data.Autostart = 0
+		id, err := dao.Jpid.Ctx(ctx).Data(data).InsertAndGetId()
+		if err != nil {
+			return err
+		}
+		return dao.Jpid.Ctx(ctx).Where("id", id).Scan(&item.project)
+
+	case model.ReconcileUpdate:
+		if _, err := dao.Jpid.Ctx(ctx).Data(manifestData(manifest)).Where("id", item.project.Id).Update(); err != nil {
+			return err
+		}
+		return dao.Jpid.Ctx(ctx).Where("id", item.project.Id).Scan(&item.project)
+
+	case model.ReconcileAutostart:
+		return Jpid().UpdateAutostart(ctx, item.project.Id, boolToInt(manifest.Autostart))
+
+	case model.ReconcileStop:
+		return Jpid().Shutdown(ctx, item.project)
+
+	case model.ReconcileRestart:
+		if err := Jpid().Shutdown(ctx, item.project); err != nil {
+			return err
+		}
+		return s.launch(ctx, item)
+
+	case model.ReconcileStart:
+		return s.launch(ctx, item)
+	}
+	return gerror.Newf("未知的对账动作: %s", action.Action)
+}
+
+// launch 启动项目并等待健康检查通过
+func (s *SReconcile) launch(ctx context.Context, item *reconcileItem) error {
+	pid, err := Jpid().Launch(ctx, item.project)
+	if err != nil {
+		return err
+	}
+	item.project.Pid, item.project.Status = pid, 1
+
+	if item.manifest.HealthCheck == "" {
+		return nil
+	}
+	timeout := g.Cfg().MustGet(ctx, "reconcile.healthTimeout", defaultHealthTimeout).Duration()
+	return health.Wait(ctx, item.manifest.HealthCheck, timeout)
+}
+
+// reconcileFields 参与对账的字段，顺序即漂移报告的顺序
+var reconcileFields = []string{"ports", "catalog", "run", "script", "way", "env", "jvm_opts", "args", "jdk_home", "health_check", "description", "manifest"}
+
+// launchFields 变更后需要重启才能生效的字段
+var launchFields = map[string]bool{
+	"catalog": true, "run": true, "script": true, "way": true, "env": true, "jvm_opts": true, "args": true, "jdk_home": true,
+}
+
+// manifestFields 清单中声明的字段值
+func manifestFields(manifest *model.ProjectManifest) map[string]string {
+	data := manifestData(manifest)
+	return map[string]string{
+		"ports":        fmt.Sprint(data.Ports),
+		"catalog":      fmt.Sprint(data.Catalog),
+		"run":          fmt.Sprint(data.Run),
+		"script":       fmt.Sprint(data.Script),
+		"way":          fmt.Sprint(data.Way),
+		"env":          fmt.Sprint(data.Env),
+		"jvm_opts":     fmt.Sprint(data.JvmOpts),
+		"args":         fmt.Sprint(data.Args),
+		"jdk_home":     fmt.Sprint(data.JdkHome),
+		"health_check": fmt.Sprint(data.HealthCheck),
+		"description":  fmt.Sprint(data.Description),
+		"manifest":     fmt.Sprint(data.Manifest),
+	}
+}
+
+// projectFields 项目记录中的字段值
+func projectFields(project *entity.Jpid) map[string]string {
+	return map[string]string{
+		"ports":        project.Ports,
+		"catalog":      project.Catalog,
+		"run":          project.Run,
+		"script":       project.Script,
+		"way":          fmt.Sprint(project.Way),
+		"env":          FormatEnv(ParseEnv(project.Env)),
+		"jvm_opts":     FormatJvmOptions(ParseJvmOptions(project.JvmOpts)),
+		"args":         FormatArgs(ParseArgs(project.Args)),
+		"jdk_home":     project.JdkHome,
+		"health_check": project.HealthCheck,
+		"description":  project.Description,
+		"manifest":     project.Manifest,
+	}
+}
+
+// manifestData 清单转换为项目记录
+func manifestData(manifest *model.ProjectManifest) do.Jpid {
+	way := 2
+	if manifest.Runtime == manifestRuntimeDock {
+		way = 1
+	}
+	return do.Jpid{
+		Name:        manifest.Name,
+		Ports:       manifest.Ports,
+		Catalog:     manifest.Catalog,
+		Run:         manifest.Run,
+		Script:      manifest.Script,
+		Way:         way,
+		Env:         FormatEnv(manifest.Env),
+		JvmOpts:     FormatJvmOptions(manifest.JvmOpts),
+		Args:        FormatArgs(manifest.Args),
+		JdkHome:     manifest.JdkHome,
+		JdkId:       0, // 清单通过 jdkHome 指定 JDK，页面上不能为清单项目选择 JDK
+		HealthCheck: manifest.HealthCheck,
+		Description: manifest.Description,
+		Manifest:    manifest.File,
+	}
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,502 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gogf/gf/v2/database/gdb"
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcron"
+	"github.com/gogf/gf/v2/os/gfile"
+	"github.com/gogf/gf/v2/os/glog"
+	"github.com/gogf/gf/v2/util/gconv"
+	"omniscient/internal/dao"
+	"omniscient/internal/model/do"
+	"omniscient/internal/model/entity"
+	"omniscient/internal/util/secretbox"
+)
+
+const (
+	// MasterKeyEnv 主密钥环境变量，设置后优先于密钥文件
+	MasterKeyEnv = "OMNISCIENT_MASTER_KEY"
+
+	defaultSecretKeyFile = "./data/secret.key"
+	// defaultSecretReloadInterval 重新加载脱敏缓存的间隔，其他进程（CLI、其他服务器）新增的密钥在此之后生效
+	defaultSecretReloadInterval = "10s"
+	// secretMask 密钥值在日志、SSE、接口响应中的替换文本
+	secretMask = "******"
+	// minMaskLength 过短的值不做替换，避免误伤普通文本
+	minMaskLength = 4
+)
+
+var (
+	// secretRefPattern 环境变量中引用密钥的写法：${secret:NAME}
+	secretRefPattern = regexp.MustCompile(`\$\{secret:([A-Za-z0-9_.-]+)\}`)
+	// secretNamePattern 密钥名称
+	secretNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.-]{1,100}$`)
+
+	// maskValues 已解密的密钥值，按长度倒序，用于输出时脱敏
+	maskMu     sync.RWMutex
+	maskValues []string
+)
+
+type SSecret struct{}
+
+func Secret() *SSecret {
+	return &SSecret{}
+}
+
+// Init 加载主密钥和密钥值缓存，并为日志输出安装脱敏处理
+func (s *SSecret) Init(ctx context.Context) error {
+	if _, err := s.masterKey(ctx); err != nil {
+		return err
+	}
+	if err := s.Reload(ctx); err != nil {
+		return err
+	}
+	glog.SetDefaultHandler(maskLogHandler)
+	return nil
+}
+
+// Start 按 secret.reloadInterval 定时重新加载脱敏缓存，使 CLI 或其他服务器修改的密钥也能被遮盖
+func (s *SSecret) Start(ctx context.Context) error {
+	interval := g.Cfg().MustGet(ctx, "secret.reloadInterval", defaultSecretReloadInterval).String()
+	_, err := gcron.AddSingleton(ctx, "@every "+interval, func(ctx context.Context) {
+		if err := s.Reload(ctx); err != nil {
+			g.Log().Warningf(ctx, "重新加载密钥失败: %v", err)
+		}
+	}, "secret-reload")
+	if err != nil {
+		return gerror.Wrap(err, "启动密钥定时加载失败")
+	}
+	return nil
+}
+
+// List 获取全部密钥（不含明文）
+func (s *SSecret) List(ctx context.Context) (list []*entity.Secret, err error) {
+	err = dao.Secret.Ctx(ctx).Order("name ASC").Scan(&list)
+	return
+}
+
+// Set 新增或更新密钥
+func (s *SSecret) Set(ctx context.Context, name, value, description string) error {
+	if !secretNamePattern.MatchString(name) {
+		return gerror.Newf("无效的密钥名称: %s（只能包含字母、数字、下划线、点和横线）", name)
+	}
+	if value == "" {
+		return gerror.New("密钥值不能为空")
+	}
+
+	key, err := s.masterKey(ctx)
+	if err != nil {
+		return err
+	}
+	ciphertext, err := secretbox.Encrypt(key, []byte(value))
+	if err != nil {
+		return gerror.Wrap(err, "加密失败")
+	}
+
+	count, err := dao.Secret.Ctx(ctx).Where("name", name).Count()
+	if err != nil {
+		return err
+	}
+	data := do.Secret{
+		Name:        name,
+		Ciphertext:  ciphertext,
+		KeyId:       secretbox.KeyId(key),
+		Description: description,
+	}
+	if count > 0 {
+		_, err = dao.Secret.Ctx(ctx).Data(data).Where("name", name).Update()
+	} else {
+		_, err = dao.Secret.Ctx(ctx).Data(data).Insert()
+	}
+	if err != nil {
+		return err
+	}
+	return s.Reload(ctx)
+}
+
+// Delete 删除密钥，仍被项目引用时不允许删除
+func (s *SSecret) Delete(ctx context.Context, name string) error {
+	var projects []*entity.Jpid
+	if err := dao.Jpid.Ctx(ctx).
+		WhereLike("env", "%${secret:%").
+		WhereOrLike("jolokia", "%${secret:%").
+		Scan(&projects); err != nil {
+		return err
+	}
+	var refs []string
+	for _, project := range projects {
+		for _, ref := range s.Refs(project.Env + project.Jolokia) {
+			if ref == name {
+				refs = append(refs, fmt.Sprintf("%s[%s]", project.Name, project.Worker))
+				break
+			}
+		}
+	}
+	if len(refs) > 0 {
+		return gerror.Newf("密钥 %s 仍被项目引用: %s", name, strings.Join(refs, ", "))
+	}
+	var channels []*entity.AlertChannel
+	if err := dao.AlertChannel.Ctx(ctx).
+		WhereLike("url", "%${secret:%").
+		WhereOrLike("secret", "%${secret:%").
+		WhereOrLike("config", "%${secret:%").
+		Scan(&channels); err != nil {
+		return err
+	}
+	for _, channel := range channels {
+		for _, ref := range s.Refs(channel.Url + channel.Secret + channel.Config) {
+			if ref == name {
+				refs = append(refs, channel.Name)
+				break
+			}
+		}
+	}
+	if len(refs) > 0 {
+		return gerror.Newf("密钥 %s 仍被通知渠道引用: %s", name, strings.Join(refs, ", "))
+	}
+	var hooks []*entity.Webhook
+	if err := dao.Webhook.Ctx(ctx).
+		WhereLike("url", "%${secret:%").
+		WhereOrLike("secret", "%${secret:%").
+		Scan(&hooks); err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		for _, ref := range s.Refs(hook.Url + hook.Secret) {
+			if ref == name {
+				refs = append(refs, hook.Name)
+				break
+			}
+		}
+	}
+	if len(refs) > 0 {
+		return gerror.Newf("密钥 %s 仍被 webhook 订阅引用: %s", name, strings.Join(refs, ", "))
+	}
+
+	result, err := dao.Secret.Ctx(ctx).Where("name", name).Delete()
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return gerror.Newf("密钥不存在: %s", name)
+	}
+	return s.Reload(ctx)
+}
+
+// Refs 获取文本中引用的密钥名称
+func (s *SSecret) Refs(text string) []string {
+	var names []string
+	for _, match := range secretRefPattern.FindAllStringSubmatch(text, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
+
+// CheckRefs 检查环境变量（或其他文本）中引用的密钥是否存在
+func (s *SSecret) CheckRefs(ctx context.Context, env map[string]string) error {
+	var names []string
+	for _, value := range env {
+		names = append(names, s.Refs(value)...)
+	}
+	if len(names) == 0 {
+		return nil
+	}
+
+	existing, err := dao.Secret.Ctx(ctx).Fields("name").WhereIn("name", names).Array()
+	if err != nil {
+		return err
+	}
+	found := make(map[string]bool)
+	for _, name := range existing {
+		found[name.String()] = true
+	}
+	for _, name := range names {
+		if !found[name] {
+			return gerror.Newf("引用的密钥不存在: %s", name)
+		}
+	}
+	return nil
+}
+
+// Resolve 将文本中的 ${secret:NAME} 替换为明文，只应在实际使用时调用（启动进程、访问 Jolokia）
+func (s *SSecret) Resolve(ctx context.Context, text string) (string, error) {
+	names := s.Refs(text)
+	if len(names) == 0 {
+		return text, nil
+	}
+
+	values, err := s.decryptAll(ctx, names...)
+	if err != nil {
+		return "", err
+	}
+	var missing string
+	resolved := secretRefPattern.ReplaceAllStringFunc(text, func(ref string) string {
+		name := secretRefPattern.FindStringSubmatch(ref)[1]
+		value, ok := values[name]
+		if !ok && missing == "" {
+			missing = name
+		}
+		return value
+	})
+	if missing != "" {
+		return "", gerror.Newf("引用的密钥不存在: %s", missing)
+	}
+	// 缓存中没有的值是其他进程新增或修改的密钥，立即重新加载，保证输出时能被遮盖
+	for _, value := range values {
+		if !masked(value) {
+			if err = s.Reload(ctx); err != nil {
+				g.Log().Warningf(ctx, "重新加载密钥失败: %v", err)
+			}
+			break
+		}
+	}
+	return resolved, nil
+}
+
+// masked 值是否已在脱敏缓存中，过短不做替换的值视为已缓存
+func masked(value string) bool {
+	if len(value) < minMaskLength {
+		return true
+	}
+	maskMu.RLock()
+	defer maskMu.RUnlock()
+	for _, cached := range maskValues {
+		if cached == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Mask 将文本中出现的密钥明文替换为 ******
+func (s *SSecret) Mask(text string) string {
+	maskMu.RLock()
+	defer maskMu.RUnlock()
+	for _, value := range maskValues {
+		if strings.Contains(text, value) {
+			text = strings.ReplaceAll(text, value, secretMask)
+		}
+	}
+	return text
+}
+
+// MaskProject 对项目中可能包含密钥明文的字段脱敏，用于列表和详情
+func (s *SSecret) MaskProject(project *entity.Jpid) {
+	project.Run = s.Mask(project.Run)
+	project.Script = s.Mask(project.Script)
+	project.Env = s.Mask(project.Env)
+	project.JvmOpts = s.Mask(project.JvmOpts)
+	project.Args = s.Mask(project.Args)
+}
+
+// Referrer 将密钥明文替换为 ${secret:NAME} 引用的替换器，用于导出，过短的值不替换
+func (s *SSecret) Referrer(ctx context.Context) (*strings.Replacer, error) {
+	values, err := s.decryptAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(values))
+	for name, value := range values {
+		if len(value) >= minMaskLength {
+			names = append(names, name)
+		}
+	}
+	// 同一位置优先匹配长的值，Replacer 一次替换完，不会再匹配替换进去的引用
+	sort.Slice(names, func(i, j int) bool { return len(values[names[i]]) > len(values[names[j]]) })
+	pairs := make([]string, 0, len(names)*2)
+	for _, name := range names {
+		pairs = append(pairs, values[name], "${secret:"+name+"}")
+	}
+	return strings.NewReplacer(pairs...), nil
+}
+
+// Reload 重新加载脱敏使用的密钥值缓存
+func (s *SSecret) Reload(ctx context.Context) error {
+	values, err := s.decryptAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	list := make([]string, 0, len(values))
+	for _, value := range values {
+		if len(value) >= minMaskLength {
+			list = append(list, value)
+		}
+	}
+	// 先替换长的值，避免短值是长值的一部分时替换不完整
+	sort.Slice(list, func(i, j int) bool { return len(list[i]) > len(list[j]) })
+
+	maskMu.Lock()
+	maskValues = list
+	maskMu.Unlock()
+	return nil
+}
+
+// RotateKey 轮换主密钥：用新密钥重新加密全部密钥，返回重新加密的数量
+// 使用密钥文件时自动生成新密钥并替换文件（旧文件备份为 .bak-时间）；使用环境变量时需要传入新密钥，完成后更新环境变量
+func (s *SSecret) RotateKey(ctx context.Context, newKeyText string) (int, error) {
+	oldKey, err := s.masterKey(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	fromEnv := os.Getenv(MasterKeyEnv) != ""
+	var newKey []byte
+	switch {
+	case newKeyText != "":
+		newKey = secretbox.ParseKey(newKeyText)
+	case fromEnv:
+		return 0, gerror.Newf("主密钥来自环境变量 %s，请通过 --new-key 指定新密钥", MasterKeyEnv)
+	default:
+		if newKey, err = secretbox.GenerateKey(); err != nil {
+			return 0, err
+		}
+	}
+	if secretbox.KeyId(newKey) == secretbox.KeyId(oldKey) {
+		return 0, gerror.New("新密钥与当前密钥相同")
+	}
+
+	// 先写入临时文件，重新加密成功后再替换，保证任何时刻都有可用的密钥文件
+	keyFile := s.keyFile(ctx)
+	newKeyFile := keyFile + ".new"
+	if !fromEnv {
+		if err = writeKeyFile(newKeyFile, newKey); err != nil {
+			return 0, err
+		}
+	}
+
+	var count int
+	err = dao.Secret.Transaction(ctx, func(ctx context.Context, tx gdb.TX) error {
+		var list []*entity.Secret
+		if err := tx.Model(dao.Secret.Table()).Scan(&list); err != nil {
+			return err
+		}
+		for _, secret := range list {
+			plaintext, err := secretbox.Decrypt(oldKey, secret.Ciphertext)
+			if err != nil {
+				return gerror.Wrapf(err, "解密密钥 %s 失败", secret.Name)
+			}
+			ciphertext, err := secretbox.Encrypt(newKey, plaintext)
+			if err != nil {
+				return err
+			}
+			if _, err = tx.Model(dao.Secret.Table()).Data(do.Secret{
+				Ciphertext: ciphertext,
+				KeyId:      secretbox.KeyId(newKey),
+			}).Where("id", secret.Id).Update(); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		_ = gfile.Remove(newKeyFile)
+		return 0, err
+	}
+
+	if !fromEnv {
+		backup := fmt.Sprintf("%s.bak-%s", keyFile, time.Now().Format("20060102-150405"))
+		if err = gfile.Rename(keyFile, backup); err != nil {
+			return count, gerror.Wrapf(err, "备份旧密钥文件失败，新密钥保存在 %s", newKeyFile)
+		}
+		if err = gfile.Rename(newKeyFile, keyFile); err != nil {
+			return count, gerror.Wrapf(err, "替换密钥文件失败，新密钥保存在 %s", newKeyFile)
+		}
+	}
+	return count, nil
+}
+
+// decryptAll 解密密钥，names 为空时解密全部
+func (s *SSecret) decryptAll(ctx context.Context, names ...string) (map[string]string, error) {
+	key, err := s.masterKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var list []*entity.Secret
+	query := dao.Secret.Ctx(ctx)
+	if len(names) > 0 {
+		query = query.WhereIn("name", names)
+	}
+	if err = query.Scan(&list); err != nil {
+		return nil, err
+	}
+
+	keyId := secretbox.KeyId(key)
+	values := make(map[string]string, len(list))
+	for _, secret := range list {
+		if secret.KeyId != keyId {
+			return nil, gerror.Newf("密钥 %s 由其他主密钥(%s)加密，当前主密钥为 %s", secret.Name, secret.KeyId, keyId)
+		}
+		plaintext, err := secretbox.Decrypt(key, secret.Ciphertext)
+		if err != nil {
+			return nil, gerror.Wrapf(err, "解密密钥 %s 失败", secret.Name)
+		}
+		values[secret.Name] = string(plaintext)
+	}
+	return values, nil
+}
+
+// masterKey 获取主密钥：优先环境变量，其次密钥文件，密钥文件不存在时自动生成
+func (s *SSecret) masterKey(ctx context.Context) ([]byte, error) {
+	if text := os.Getenv(MasterKeyEnv); text != "" {
+		return secretbox.ParseKey(text), nil
+	}
+
+	keyFile := s.keyFile(ctx)
+	if gfile.Exists(keyFile) {
+		return secretbox.ParseKey(gfile.GetContents(keyFile)), nil
+	}
+
+	key, err := secretbox.GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	if err = writeKeyFile(keyFile, key); err != nil {
+		return nil, err
+	}
+	g.Log().Infof(ctx, "已生成主密钥文件: %s，请妥善备份", keyFile)
+	return key, nil
+}
+
+// keyFile 主密钥文件路径
+func (s *SSecret) keyFile(ctx context.Context) string {
+	return g.Cfg().MustGet(ctx, "secret.keyFile", defaultSecretKeyFile).String()
+}
+
+// writeKeyFile 写入主密钥文件，仅当前用户可读写
+func writeKeyFile(path string, key []byte) error {
+	if err := gfile.Mkdir(filepath.Dir(path)); err != nil {
+		return gerror.Wrap(err, "创建密钥目录失败")
+	}
+	if err := os.WriteFile(path, []byte(secretbox.EncodeKey(key)+"\n"), 0600); err != nil {
+		return gerror.Wrap(err, "写入密钥文件失败")
+	}
+	return nil
+}
+
+// maskLogHandler 日志脱敏处理
+func maskLogHandler(ctx context.Context, in *glog.HandlerInput) {
+	in.Content = Secret().Mask(in.Content)
+	for i, value := range in.Values {
+		if text := gconv.String(value); text != "" {
+			if masked := Secret().Mask(text); masked != text {
+				in.Values[i] = masked
+			}
+		}
+	}
+	in.Next(ctx)
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"context"
+	"testing"
+)
+
+func TestResolveReloadsMaskCache(t *testing.T) {
+	ctx := context.Background()
+	useTestDB(t)
+	if err := Secret().Set(ctx, "TEST_RELOAD", "s3cret-from-cli", ""); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	t.Cleanup(func() { Secret().Delete(ctx, "TEST_RELOAD") })
+
+	// 模拟密钥由 CLI 或其他服务器写入，当前进程的脱敏缓存中还没有
+	maskMu.Lock()
+	maskValues = nil
+	maskMu.Unlock()
+	if got := Secret().Mask("password=s3cret-from-cli"); got != "password=s3cret-from-cli" {
+		t.Fatalf("Mask before reload = %q", got)
+	}
+
+	resolved, err := Secret().Resolve(ctx, "DB_PASSWORD=${secret:TEST_RELOAD}")
+	if err != nil || resolved != "DB_PASSWORD=s3cret-from-cli" {
+		t.Fatalf("Resolve = %q, %v", resolved, err)
+	}
+	if got := Secret().Mask("password=s3cret-from-cli"); got != "password="+secretMask {
+		t.Errorf("Mask after resolve = %q", got)
+	}
+}
@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogf/gf/v2/errors/gerror"
+	"github.com/gogf/gf/v2/frame/g"
+	"github.com/gogf/gf/v2/os/gcron"
+	"github.com/gogf/gf/v2/os/gtime"
+	"github.com/gogf/gf/v2/text/gstr"
+	"omniscient/internal/dao"
+	"omniscient/internal/model"
+	"omniscient/internal/model/do"
+	"omniscient/internal/model/entity"
+	"omniscient/internal/util/notify"
+	"omniscient/internal/util/system"
+)
+
+const (
+	defaultWebhookInterval    = "5s"
+	defaultWebhookTimeout     = "10s"
+	defaultWebhookRetryBase   = "10s"
+	defaultWebhookRetryMax    = "1h"
+	defaultWebhookMaxAttempts = 8
+	defaultWebhookKeepDays    = 7
+	defaultDeliveryLimit      = 100
+	// webhookBatchSize 每轮最多投递的记录数，剩余的留到下一轮
+	webhookBatchSize = 50
+	// webhookResubscribeDelay 订阅被断开后重新订阅前的等待时间
+	webhookResubscribeDelay = time.Second
+)
+
+type SWebhook struct{}
+
+func Webhook() *SWebhook {
+	return &SWebhook{}
+}
+
+// WebhookConfig 新增或修改 webhook 订阅的参数
+type WebhookConfig struct {
+	Id      int      // 为 0 时新增
+	Name    string   // 订阅名称
+	Url     string   // 推送地址
+	Secret  string   // 签名密钥，为空时不签名
+	Events  []string // 订阅的事件，支持 project.* 前缀，为空表示全部
+	Enabled bool
+}
+
+// Start 订阅本机的事件写入投递队列，并按 webhook.interval 定时投递
+// 投递记录保存在数据库中，服务重启后未完成的投递会继续重试
+func (s *SWebhook) Start(ctx context.Context) error {
+	interval := g.Cfg().MustGet(ctx, "webhook.interval", defaultWebhookInterval).String()
+	if _, err := gcron.AddSingleton(ctx, "@every "+interval, s.Deliver, "webhook-deliver"); err != nil {
+		return gerror.Wrap(err, "启动 webhook 投递失败")
+	}
+	if _, err := gcron.AddSingleton(ctx, "@every 1h", s.Prune, "webhook-prune"); err != nil {
+		return gerror.Wrap(err, "启动 webhook 投递记录清理失败")
+	}
+	go s.consume(ctx)
+	return nil
+}
+
+// consume 持续消费事件总线，订阅因消费过慢被断开时等待片刻后从上次的游标重新订阅，ctx 结束时退出
+func (s *SWebhook) consume(ctx context.Context) {
+	var cursor int64
+	for {
+		backlog, events, cancel, complete := Event().Subscribe(cursor)
+		if !complete {
+			g.Log().Warningf(ctx, "webhook 重新订阅时游标 %d 之后的部分事件已丢失", cursor)
+		}
+		for _, event := range backlog {
+			s.enqueue(ctx, event)
+			cursor = event.Id
+		}
+		if !s.drain(ctx, events, &cursor) {
+			cancel()
+			return
+		}
+		cancel()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(webhookResubscribeDelay):
+		}
+	}
+}
+
+// drain 消费订阅直到通道被关闭，ctx 结束时返回 false
+func (s *SWebhook) drain(ctx context.Context, events <-chan *model.Event, cursor *int64) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return true
+			}
+			s.enqueue(ctx, event)
+			*cursor = event.Id
+		}
+	}
+}
+
+// enqueue 为订阅了该事件的每个启用的 webhook 写入一条待投递记录
+func (s *SWebhook) enqueue(ctx context.Context, event *model.Event) {
+	var hooks []*entity.Webhook
+	if err := dao.Webhook.Ctx(ctx).Where("enabled", 1).Scan(&hooks); err != nil {
+		g.Log().Warningf(ctx, "读取 webhook 订阅失败: %v", err)
+		return
+	}
+	var payload string
+	for _, hook := range hooks {
+		if !matchEvent(hook.Events, event.Type) {
+			continue
+		}
+		if payload == "" {
+			data, err := json.Marshal(event)
+			if err != nil {
+				g.Log().Warningf(ctx, "序列化事件 %d 失败: %v", event.Id, err)
+				return
+			}
+			payload = Secret().Mask(string(data))
+		}
+		now := gtime.Now()
+		if _, err := dao.WebhookDelivery.Ctx(ctx).Data(do.WebhookDelivery{
+			WebhookId:     hook.Id,
+			Worker:        system.GetWorkerName(),
+			EventId:       event.Id,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        model.DeliveryPending,
+			Attempts:      0,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+		}).Insert(); err != nil {
+			g.Log().Warningf(ctx, "写入 webhook %s 投递队列失败: %v", hook.Name, err)
+		}
+	}
+}
+
+// matchEvent 订阅为空或包含 *、事件类型本身、事件类型前缀（如 project.*）时匹配
+func matchEvent(events, eventType string) bool {
+	if strings.TrimSpace(events) == "" {
+		return true
+	}
+	for _, item := range strings.Split(events, ",") {
+		item = strings.TrimSpace(item)
+		if item == "*" || item == eventType ||
+			strings.HasSuffix(item, ".*") && strings.HasPrefix(eventType, strings.TrimSuffix(item, "*")) {
+			return true
+		}
+	}
+	return false
+}
+
+// Deliver 投递本机到期的待投递记录，对方返回 2xx 视为成功
+// 失败后按指数退避重试，达到 webhook.maxAttempts 次后标记为失败
+func (s *SWebhook) Deliver(ctx context.Context) {
+	var list []*entity.WebhookDelivery
+	if err := dao.WebhookDelivery.Ctx(ctx).
+		Where("status", model.DeliveryPending).
+		Where("worker", system.GetWorkerName()).
+		WhereLTE("next_attempt_at", gtime.Now()).
+		Order("id ASC").
+		Limit(webhookBatchSize).
+		Scan(&list); err != nil {
+		g.Log().Warningf(ctx, "读取 webhook 投递队列失败: %v", err)
+		return
+	}
+	if len(list) == 0 {
+		return
+	}
+	var hooks []*entity.Webhook
+	if err := dao.Webhook.Ctx(ctx).Scan(&hooks); err != nil {
+		g.Log().Warningf(ctx, "读取 webhook 订阅失败: %v", err)
+		return
+	}
+	hookMap := make(map[int]*entity.Webhook, len(hooks))
+	for _, hook := range hooks {
+		hookMap[hook.Id] = hook
+	}
+	for _, delivery := range list {
+		s.attempt(ctx, hookMap[delivery.WebhookId], delivery)
+	}
+}
+
+// attempt 投递一次并记录结果
+func (s *SWebhook) attempt(ctx context.Context, hook *entity.Webhook, delivery *entity.WebhookDelivery) {
+	data := do.WebhookDelivery{Attempts: delivery.Attempts + 1}
+	switch {
+	case hook == nil:
+		data.Status, data.LastError = model.DeliveryFailed, "订阅已删除"
+	case hook.Enabled == 0:
+		data.Status, data.LastError = model.DeliveryFailed, "订阅已停用"
+	default:
+		status, err := s.post(ctx, hook, delivery)
+		data.ResponseStatus = status
+		attempts := delivery.Attempts + 1
+		maxAttempts := g.Cfg().MustGet(ctx, "webhook.maxAttempts", defaultWebhookMaxAttempts).Int()
+		if err == nil {
+			data.Status, data.LastError, data.DeliveredAt = model.DeliverySuccess, "", gtime.Now()
+		} else if attempts >= maxAttempts {
+			data.Status, data.LastError = model.DeliveryFailed, err.Error()
+			g.Log().Warningf(ctx, "webhook %s 投递 #%d 失败 %d 次，不再重试: %v", hook.Name, delivery.Id, attempts, err)
+		} else {
+			data.LastError, data.NextAttemptAt = err.Error(), gtime.Now().Add(webhookBackoff(ctx, attempts))
+			g.Log().Infof(ctx, "webhook %s 投递 #%d 第 %d 次失败，稍后重试: %v", hook.Name, delivery.Id, attempts, err)
+		}
+	}
+	if _, err := dao.WebhookDelivery.Ctx(ctx).Data(data).Where("id", delivery.Id).Update(); err != nil {
+		g.Log().Warningf(ctx, "更新 webhook 投递 #%d 失败: %v", delivery.Id, err)
+	}
+}
+
+// post 解析地址和签名密钥中的密钥引用后提交请求体
+func (s *SWebhook) post(ctx context.Context, hook *entity.Webhook, delivery *entity.WebhookDelivery) (int, error) {
+	target, err := Secret().Resolve(ctx, hook.Url)
+	if err != nil {
+		return 0, err
+	}
+	secret, err := Secret().Resolve(ctx, hook.Secret)
+	if err != nil {
+		return 0, err
+	}
+	headers := map[string]string{
+		"X-Omniscient-Event":    delivery.EventType,
+		"X-Omniscient-Event-Id": strconv.FormatInt(delivery.EventId, 10),
+		"X-Omniscient-Delivery": strconv.Itoa(delivery.Id),
+	}
+	timeout := g.Cfg().MustGet(ctx, "webhook.timeout", defaultWebhookTimeout).Duration()
+	return notify.Deliver(ctx, target, secret, headers, []byte(delivery.Payload), timeout)
+}
+
+// webhookBackoff 第 n 次失败后的等待时间：retryBase * 2^(n-1)，不超过 retryMax
+func webhookBackoff(ctx context.Context, attempts int) time.Duration {
+	base := g.Cfg().MustGet(ctx, "webhook.retryBase", defaultWebhookRetryBase).Duration()
+	maxDelay := g.Cfg().MustGet(ctx, "webhook.retryMax", defaultWebhookRetryMax).Duration()
+	delay := base
+	for i := 1; i < attempts && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// Prune 清理超过 webhook.keepDays 天的已完成投递记录
+func (s *SWebhook) Prune(ctx context.Context) {
+	keepDays := g.Cfg().MustGet(ctx, "webhook.keepDays", defaultWebhookKeepDays).Int()
+	if keepDays <= 0 {
+		return
+	}
+	result, err := dao.WebhookDelivery.Ctx(ctx).
+		WhereNot("status", model.DeliveryPending).
+		WhereLT("created_at", gtime.Now().AddDate(0, 0, -keepDays)).
+		Delete()
+	if err != nil {
+		g.Log().Warningf(ctx, "清理 webhook 投递记录失败: %v", err)
+		return
+	}
+	if affected, _ := result.RowsAffected(); affected > 0 {
+		g.Log().Infof(ctx, "已清理 %d 条 webhook 投递记录", affected)
+	}
+}
+
+// List webhook 订阅列表，地址中的密钥值替换为 ******，签名密钥明文不返回
+func (s *SWebhook) List(ctx context.Context) (list []*entity.Webhook, err error) {
+	if err = dao.Webhook.Ctx(ctx).Order("id ASC").Scan(&list); err != nil {
+		return nil, err
+	}
+	for _, hook := range list {
+		hook.Url = Secret().Mask(hook.Url)
+		if hook.Secret != "" && len(Secret().Refs(hook.Secret)) == 0 {
+			hook.Secret = secretMask
+		}
+	}
+	return
+}
+
+// Save 新增或修改 webhook 订阅，返回订阅ID
+// 修改时签名密钥传 ****** 表示保持不变；地址和签名密钥都可以使用 ${secret:NAME} 引用密钥
+func (s *SWebhook) Save(ctx context.Context, cfg *WebhookConfig) (int, error) {
+	cfg.Name, cfg.Url, cfg.Secret = strings.TrimSpace(cfg.Name), strings.TrimSpace(cfg.Url), strings.TrimSpace(cfg.Secret)
+	if cfg.Name == "" {
+		return 0, gerror.New("订阅名称不能为空")
+	}
+	if !strings.HasPrefix(cfg.Url, "http://") && !strings.HasPrefix(cfg.Url, "https://") {
+		return 0, gerror.Newf("推送地址必须以 http:// 或 https:// 开头: %s", cfg.Url)
+	}
+	var events []string
+	for _, item := range cfg.Events {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		if item != "*" && !strings.HasSuffix(item, ".*") && !gstr.InArray(model.ProjectEventTypes, item) {
+			return 0, gerror.Newf("不支持的事件类型: %s", item)
+		}
+		events = append(events, item)
+	}
+	keepSecret := cfg.Id > 0 && cfg.Secret == secretMask
+	if keepSecret {
+		cfg.Secret = ""
+	}
+	if err := Secret().CheckRefs(ctx, map[string]string{"url": cfg.Url, "secret": cfg.Secret}); err != nil {
+		return 0, err
+	}
+
+	count, err := dao.Webhook.Ctx(ctx).Where("name", cfg.Name).WhereNot("id", cfg.Id).Count()
+	if err != nil {
+		return 0, err
+	}
+	if count > 0 {
+		return 0, gerror.Newf("webhook 订阅 %s 已存在", cfg.Name)
+	}
+	data := do.Webhook{
+		Name:    cfg.Name,
+		Url:     cfg.Url,
+		Secret:  cfg.Secret,
+		Events:  strings.Join(events, ","),
+		Enabled: boolToInt(cfg.Enabled),
+	}
+	if keepSecret {
+		data.Secret = nil
+	}
+	if cfg.Id == 0 {
+		data.CreatedAt = gtime.Now()
+		id, err := dao.Webhook.Ctx(ctx).Data(data).InsertAndGetId()
+		return int(id), err
+	}
+	result, err := dao.Webhook.Ctx(ctx).Data(data).Where("id", cfg.Id).Update()
+	if err != nil {
+		return 0, err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return 0, gerror.New("webhook 订阅不存在")
+	}
+	return cfg.Id, nil
+}
+
+// Delete 删除 webhook 订阅及其投递记录
+func (s *SWebhook) Delete(ctx context.Context, id int) error {
+	result, err := dao.Webhook.Ctx(ctx).Where("id", id).Delete()
+	if err != nil {
+		return err
+	}
+	if affected, _ := result.RowsAffected(); affected == 0 {
+		return gerror.New("webhook 订阅不存在")
+	}
+	_, err = dao.WebhookDelivery.Ctx(ctx).Where("webhook_id", id).Delete()
+	return err
+}
+
+// Deliveries 投递记录，按时间倒序
+func (s *SWebhook) Deliveries(ctx context.Context, webhookId int, status string, limit int) (list []*entity.WebhookDelivery, err error) {
+	if limit <= 0 {
+		limit = defaultDeliveryLimit
+	}
+	query := dao.WebhookDelivery.Ctx(ctx)
+	if webhookId > 0 {
+		query = query.Where("webhook_id", webhookId)
+	}
+	if status != "" {
+		query = query.Where("status", status)
+	}
+	err = query.Order("id DESC").Limit(limit).Scan(&list)
+	return
+}
+
+// Replay 以原请求体重新投递一次，生成新的投递记录并由本机在下一轮投递，返回新记录ID
+func (s *SWebhook) Replay(ctx context.Context, id int) (int, error) {
+	var delivery *entity.WebhookDelivery
+	if err := dao.WebhookDelivery.Ctx(ctx).Where("id", id).Scan(&delivery); err != nil {
+		return 0, err
+	}
+	if delivery == nil {
+		return 0, gerror.New("投递记录不存在")
+	}
+	count, err := dao.Webhook.Ctx(ctx).Where("id", delivery.WebhookId).Count()
+	if err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return 0, gerror.New("webhook 订阅不存在")
+	}
+	now := gtime.Now()
+	newId, err := dao.WebhookDelivery.Ctx(ctx).Data(do.WebhookDelivery{
+		WebhookId:     delivery.WebhookId,
+		Worker:        system.GetWorkerName(),
+		EventId:       delivery.EventId,
+		EventType:     delivery.EventType,
+		Payload:       delivery.Payload,
+		Status:        model.DeliveryPending,
+		Attempts:      0,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}).InsertAndGetId()
+	return int(newId), err
+}
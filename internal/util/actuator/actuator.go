@@ -0,0 +1,209 @@
+package actuator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"omniscient/internal/model"
+)
+
+// Client Spring Boot Actuator 客户端，BaseURL 形如 http://127.0.0.1:8080/actuator
+type Client struct {
+	BaseURL string
+	Timeout time.Duration
+}
+
+// New 创建客户端
+func New(baseURL string, timeout time.Duration) *Client {
+	return &Client{BaseURL: strings.TrimRight(baseURL, "/"), Timeout: timeout}
+}
+
+// Detect 依次探测本机端口上的 actuator 入口，返回第一个可用的基础地址
+// 入口返回包含 _links 的 JSON 才视为 actuator（管理端口可能与业务端口不同）
+func Detect(ctx context.Context, ports []string, basePath string, timeout time.Duration) (string, error) {
+	basePath = "/" + strings.Trim(basePath, "/")
+	var lastErr error
+	for _, port := range ports {
+		port = strings.TrimSpace(port)
+		if port == "" {
+			continue
+		}
+		client := New(fmt.Sprintf("http://127.0.0.1:%s%s", port, basePath), timeout)
+		var index struct {
+			Links map[string]interface{} `json:"_links"`
+		}
+		if lastErr = client.get(ctx, "", &index); lastErr != nil {
+			continue
+		}
+		if len(index.Links) == 0 {
+			lastErr = fmt.Errorf("%s 不是 actuator 入口", client.BaseURL)
+			continue
+		}
+		return client.BaseURL, nil
+	}
+	if lastErr == nil {
+		return "", fmt.Errorf("项目没有可探测的端口")
+	}
+	return "", fmt.Errorf("未探测到 actuator: %v", lastErr)
+}
+
+// Health 获取 /health，DOWN 时 actuator 返回 503，仍然解析内容
+func (c *Client) Health(ctx context.Context) (map[string]interface{}, error) {
+	var health map[string]interface{}
+	err := c.get(ctx, "/health", &health)
+	return health, err
+}
+
+// Info 获取 /info
+func (c *Client) Info(ctx context.Context) (map[string]interface{}, error) {
+	var info map[string]interface{}
+	err := c.get(ctx, "/info", &info)
+	return info, err
+}
+
+// Metric 获取 /metrics/{name}
+func (c *Client) Metric(ctx context.Context, name string) (*model.ActuatorMetric, error) {
+	var data struct {
+		Name         string `json:"name"`
+		Description  string `json:"description"`
+		BaseUnit     string `json:"baseUnit"`
+		Measurements []struct {
+			Statistic string  `json:"statistic"`
+			Value     float64 `json:"value"`
+		} `json:"measurements"`
+	}
+	if err := c.get(ctx, "/metrics/"+url.PathEscape(name), &data); err != nil {
+		return nil, err
+	}
+
+	metric := &model.ActuatorMetric{
+		Name:         name,
+		Description:  data.Description,
+		BaseUnit:     data.BaseUnit,
+		Measurements: make(map[string]float64, len(data.Measurements)),
+	}
+	for _, measurement := range data.Measurements {
+		metric.Measurements[measurement.Statistic] = measurement.Value
+	}
+	if value, ok := metric.Measurements["VALUE"]; ok {
+		metric.Value = value
+	} else if value, ok = metric.Measurements["COUNT"]; ok {
+		metric.Value = value
+	} else if len(data.Measurements) > 0 {
+		metric.Value = data.Measurements[0].Value
+	}
+	return metric, nil
+}
+
+// Loggers 获取 /loggers，按名称排序
+func (c *Client) Loggers(ctx context.Context) (*model.ActuatorLoggers, error) {
+	var data struct {
+		Levels  []string `json:"levels"`
+		Loggers map[string]struct {
+			ConfiguredLevel string `json:"configuredLevel"`
+			EffectiveLevel  string `json:"effectiveLevel"`
+		} `json:"loggers"`
+	}
+	if err := c.get(ctx, "/loggers", &data); err != nil {
+		return nil, err
+	}
+
+	result := &model.ActuatorLoggers{
+		Levels:  data.Levels,
+		Loggers: make([]*model.ActuatorLogger, 0, len(data.Loggers)),
+	}
+	for name, logger := range data.Loggers {
+		result.Loggers = append(result.Loggers, &model.ActuatorLogger{
+			Name:            name,
+			ConfiguredLevel: logger.ConfiguredLevel,
+			EffectiveLevel:  logger.EffectiveLevel,
+		})
+	}
+	sort.Slice(result.Loggers, func(i, j int) bool {
+		// ROOT 始终排在最前
+		if result.Loggers[i].Name == "ROOT" || result.Loggers[j].Name == "ROOT" {
+			return result.Loggers[i].Name == "ROOT"
+		}
+		return result.Loggers[i].Name < result.Loggers[j].Name
+	})
+	return result, nil
+}
+
+// SetLogger 修改 logger 级别，level 为空时清除配置的级别（恢复继承）
+func (c *Client) SetLogger(ctx context.Context, name, level string) error {
+	var configured interface{}
+	if level != "" {
+		configured = level
+	}
+	return c.post(ctx, "/loggers/"+url.PathEscape(name), map[string]interface{}{"configuredLevel": configured})
+}
+
+// Shutdown 调用 /shutdown 优雅停止应用（需要应用开启 management.endpoint.shutdown.enabled）
+func (c *Client) Shutdown(ctx context.Context) error {
+	return c.post(ctx, "/shutdown", nil)
+}
+
+// get 请求端点并解析 JSON，health 端点 DOWN 时的 503 也会解析
+func (c *Client) get(ctx context.Context, path string, result interface{}) error {
+	body, status, err := c.do(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	if status >= 400 && !(status == http.StatusServiceUnavailable && path == "/health") {
+		return fmt.Errorf("%s%s 返回状态码 %d", c.BaseURL, path, status)
+	}
+	if err = json.Unmarshal(body, result); err != nil {
+		return fmt.Errorf("%s%s 返回的不是 JSON: %v", c.BaseURL, path, err)
+	}
+	return nil
+}
+
+// post 以 JSON 提交请求体
+func (c *Client) post(ctx context.Context, path string, payload interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	_, status, err := c.do(ctx, http.MethodPost, path, body)
+	if err != nil {
+		return err
+	}
+	if status >= 400 {
+		return fmt.Errorf("%s%s 返回状态码 %d", c.BaseURL, path, status)
+	}
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, body io.Reader) ([]byte, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("无效的 actuator 地址: %v", err)
+	}
+	req.Header.Set("Accept", "application/vnd.spring-boot.actuator.v3+json, application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	return data, resp.StatusCode, err
+}
@@ -0,0 +1,238 @@
+package actuator
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeActuator 模拟 Spring Boot actuator 的端点，记录修改 logger 和 shutdown 的请求
+type fakeActuator struct {
+	mu       sync.Mutex
+	levels   map[string]string // logger -> configuredLevel
+	posted   []string          // 收到的 POST 路径和请求体
+	shutdown bool
+}
+
+func newFakeActuator(t *testing.T, basePath string) (*httptest.Server, *fakeActuator) {
+	t.Helper()
+	fake := &fakeActuator{levels: map[string]string{"ROOT": "INFO", "com.example": ""}}
+	mux := http.NewServeMux()
+	mux.HandleFunc(basePath, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"_links":{"self":{"href":"http://127.0.0.1`+basePath+`"},"health":{"href":"http://127.0.0.1`+basePath+`/health"}}}`)
+	})
+	mux.HandleFunc(basePath+"/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = io.WriteString(w, `{"status":"DOWN","components":{"db":{"status":"DOWN"}}}`)
+	})
+	mux.HandleFunc(basePath+"/metrics/", func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimPrefix(r.URL.Path, basePath+"/metrics/") {
+		case "jvm.threads.live":
+			_, _ = io.WriteString(w, `{"name":"jvm.threads.live","baseUnit":"threads","measurements":[{"statistic":"VALUE","value":27}]}`)
+		case "http.server.requests":
+			_, _ = io.WriteString(w, `{"name":"http.server.requests","baseUnit":"seconds","measurements":[{"statistic":"TOTAL_TIME","value":1.5},{"statistic":"COUNT","value":12},{"statistic":"MAX","value":0.3}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	mux.HandleFunc(basePath+"/loggers", func(w http.ResponseWriter, r *http.Request) {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		loggers := make(map[string]interface{})
+		for name, level := range fake.levels {
+			effective := level
+			if effective == "" {
+				effective = fake.levels["ROOT"]
+			}
+			loggers[name] = map[string]interface{}{"configuredLevel": nullable(level), "effectiveLevel": effective}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"levels": []string{"OFF", "ERROR", "WARN", "INFO", "DEBUG", "TRACE"}, "loggers": loggers})
+	})
+	mux.HandleFunc(basePath+"/loggers/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		var body struct {
+			ConfiguredLevel *string `json:"configuredLevel"`
+		}
+		data, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(data, &body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, basePath+"/loggers/")
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		fake.posted = append(fake.posted, "loggers/"+name+" "+string(data))
+		fake.levels[name] = ""
+		if body.ConfiguredLevel != nil {
+			fake.levels[name] = *body.ConfiguredLevel
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc(basePath+"/shutdown", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		fake.mu.Lock()
+		fake.shutdown = true
+		fake.mu.Unlock()
+		_, _ = io.WriteString(w, `{"message":"Shutting down, bye..."}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, fake
+}
+
+func nullable(level string) interface{} {
+	if level == "" {
+		return nil
+	}
+	return level
+}
+
+func port(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Port()
+}
+
+// closedPort 一个没有监听的本机端口
+func closedPort(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, p, _ := net.SplitHostPort(listener.Addr().String())
+	listener.Close()
+	return p
+}
+
+func TestDetect(t *testing.T) {
+	ctx := context.Background()
+	// 业务端口不是 actuator，管理端口使用自定义 base-path
+	business := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"message":"hello"}`)
+	}))
+	defer business.Close()
+	management, _ := newFakeActuator(t, "/manage")
+
+	ports := []string{closedPort(t), " " + port(t, business) + " ", "", port(t, management)}
+	got, err := Detect(ctx, ports, "manage/", time.Second)
+	if err != nil {
+		t.Fatalf("Detect: %v", err)
+	}
+	if want := "http://127.0.0.1:" + port(t, management) + "/manage"; got != want {
+		t.Errorf("Detect = %q, want %q", got, want)
+	}
+
+	// 没有一个端口是 actuator 时返回最后一个端口的原因
+	_, err = Detect(ctx, []string{closedPort(t), port(t, business)}, "/manage", time.Second)
+	if err == nil || !strings.Contains(err.Error(), "不是 actuator 入口") {
+		t.Errorf("Detect error = %v", err)
+	}
+	_, err = Detect(ctx, []string{port(t, business)}, "/actuator", time.Second)
+	if err == nil || !strings.Contains(err.Error(), "未探测到 actuator") {
+		t.Errorf("Detect error = %v", err)
+	}
+	if _, err = Detect(ctx, []string{"", " "}, "/actuator", time.Second); err == nil || !strings.Contains(err.Error(), "没有可探测的端口") {
+		t.Errorf("Detect without ports = %v", err)
+	}
+}
+
+func TestHealthDown(t *testing.T) {
+	server, _ := newFakeActuator(t, "/actuator")
+	health, err := New(server.URL+"/actuator/", time.Second).Health(context.Background())
+	if err != nil {
+		t.Fatalf("Health: %v", err)
+	}
+	if health["status"] != "DOWN" {
+		t.Errorf("health = %v", health)
+	}
+}
+
+func TestMetric(t *testing.T) {
+	server, _ := newFakeActuator(t, "/actuator")
+	client := New(server.URL+"/actuator", time.Second)
+
+	metric, err := client.Metric(context.Background(), "jvm.threads.live")
+	if err != nil || metric.Value != 27 || metric.BaseUnit != "threads" {
+		t.Errorf("Metric = %+v, %v", metric, err)
+	}
+	// 没有 VALUE 时取 COUNT
+	metric, err = client.Metric(context.Background(), "http.server.requests")
+	if err != nil || metric.Value != 12 || metric.Measurements["MAX"] != 0.3 {
+		t.Errorf("Metric = %+v, %v", metric, err)
+	}
+	if _, err = client.Metric(context.Background(), "missing"); err == nil || !strings.Contains(err.Error(), "状态码 404") {
+		t.Errorf("missing metric error = %v", err)
+	}
+}
+
+func TestLoggers(t *testing.T) {
+	ctx := context.Background()
+	server, fake := newFakeActuator(t, "/actuator")
+	fake.levels["app.web"] = "WARN"
+	client := New(server.URL+"/actuator", time.Second)
+
+	loggers, err := client.Loggers(ctx)
+	if err != nil {
+		t.Fatalf("Loggers: %v", err)
+	}
+	var names []string
+	for _, logger := range loggers.Loggers {
+		names = append(names, logger.Name+"="+logger.ConfiguredLevel+"/"+logger.EffectiveLevel)
+	}
+	// ROOT 排在最前，其余按名称排序
+	if got := strings.Join(names, ","); got != "ROOT=INFO/INFO,app.web=WARN/WARN,com.example=/INFO" {
+		t.Errorf("loggers = %s", got)
+	}
+	if len(loggers.Levels) != 6 {
+		t.Errorf("levels = %v", loggers.Levels)
+	}
+
+	if err = client.SetLogger(ctx, "com.example", "DEBUG"); err != nil {
+		t.Fatalf("SetLogger: %v", err)
+	}
+	if err = client.SetLogger(ctx, "app.web", ""); err != nil {
+		t.Fatalf("SetLogger reset: %v", err)
+	}
+	want := []string{`loggers/com.example {"configuredLevel":"DEBUG"}`, `loggers/app.web {"configuredLevel":null}`}
+	if strings.Join(fake.posted, "\n") != strings.Join(want, "\n") {
+		t.Errorf("posted = %q, want %q", fake.posted, want)
+	}
+	if fake.levels["com.example"] != "DEBUG" || fake.levels["app.web"] != "" {
+		t.Errorf("levels = %v", fake.levels)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	server, fake := newFakeActuator(t, "/actuator")
+	if err := New(server.URL+"/actuator", time.Second).Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if !fake.shutdown {
+		t.Error("shutdown endpoint was not called")
+	}
+
+	// 未开启 shutdown 端点时返回 404
+	disabled := httptest.NewServer(http.NotFoundHandler())
+	defer disabled.Close()
+	if err := New(disabled.URL+"/actuator", time.Second).Shutdown(context.Background()); err == nil || !strings.Contains(err.Error(), "状态码 404") {
+		t.Errorf("Shutdown error = %v", err)
+	}
+}
@@ -64,6 +64,79 @@ func InitDatabase(ctx g.Ctx) error {
 	return nil
 }
 
+// StartDatabaseBackup 按配置启动定时自动备份
+func StartDatabaseBackup(ctx g.Ctx) error {
+	dbManager := service.NewDatabaseManager()
+	if err := dbManager.Attach(ctx); err != nil {
+		return err
+	}
+	return dbManager.StartBackupSchedule(ctx)
+}
+
+// InitSecrets 加载主密钥和密钥脱敏缓存
+func InitSecrets(ctx g.Ctx) error {
+	return service.Secret().Init(ctx)
+}
+
+// StartSecretReload 启动密钥脱敏缓存的定时加载
+func StartSecretReload(ctx g.Ctx) error {
+	return service.Secret().Start(ctx)
+}
+
+// StartReconcile 启动声明式项目清单对账
+func StartReconcile(ctx g.Ctx) error {
+	return service.Reconcile().Start(ctx)
+}
+
+// StartJolokia 启动 JMX 指标定时采集
+func StartJolokia(ctx g.Ctx) error {
+	return service.Jolokia().Start(ctx)
+}
+
+// StartAutostart 启动自启服务与项目的定时同步
+func StartAutostart(ctx g.Ctx) error {
+	return service.Autostart().Start(ctx)
+}
+
+// StartAlert 启动意外退出检测和告警规则评估
+func StartAlert(ctx g.Ctx) error {
+	return service.Alert().Start(ctx)
+}
+
+// StartWebhook 启动出站 webhook 投递
+func StartWebhook(ctx g.Ctx) error {
+	return service.Webhook().Start(ctx)
+}
+
+// PrintDatabaseHelp 打印数据库命令帮助信息
+func PrintDatabaseHelp() {
+	fmt.Println("Database Commands:")
+	fmt.Println("Usage: omniscient db <command> [options]")
+	fmt.Println("  backup [--out=<file|dir>] [--format=sqlite|json]  - Backup current database (sqlite online backup or logical dump)")
+	fmt.Println("  restore <file> [--yes]                           - Restore current database from a backup file")
+	fmt.Println("  copy --from=<type> --to=<type> [--yes]           - Copy all tables between databases, e.g. --from=sqlite --to=mysql")
+}
+
+// PrintProjectsHelp 打印项目导入导出命令帮助信息
+func PrintProjectsHelp() {
+	fmt.Println("Projects Commands:")
+	fmt.Println("Usage: omniscient projects <command> [options]")
+	fmt.Println("  export [--worker=<name>] [--format=yaml|json] [--out=<file>]       - Export project definitions (default: current worker, stdout)")
+	fmt.Println("  import <file> [--worker=<name>] [--strategy=skip|overwrite|rename] - Import project definitions into a worker")
+	fmt.Println("         [--preview] [--yes]                                         - Only show the import plan / skip confirmation")
+}
+
+// PrintSecretsHelp 打印密钥命令帮助信息
+func PrintSecretsHelp() {
+	fmt.Println("Secrets Commands:")
+	fmt.Println("Usage: omniscient secrets <command> [options]")
+	fmt.Println("  list                                            - List secret names (values are never shown)")
+	fmt.Println("  set <name> [--value=<value>] [--description=] - Create or update a secret (reads value from stdin if --value is omitted)")
+	fmt.Println("  delete <name>                                   - Delete a secret that is no longer referenced")
+	fmt.Println("  rotate-key [--new-key=<key>] [--yes]            - Re-encrypt all secrets with a new master key")
+	fmt.Println("Reference a secret in project env vars with ${secret:<name>}")
+}
+
 // ShowDatabaseInfo 显示数据库信息
 func ShowDatabaseInfo(ctx g.Ctx) error {
 	dbManager := service.NewDatabaseManager()
@@ -88,6 +161,9 @@ func ShowDatabaseInfo(ctx g.Ctx) error {
 	if database, ok := info["database"]; ok {
 		g.Log().Infof(ctx, "数据库名: %v", database)
 	}
+	if schema, ok := info["schema"]; ok {
+		g.Log().Infof(ctx, "Schema: %v", schema)
+	}
 	if dbFile, ok := info["database_file"]; ok {
 		g.Log().Infof(ctx, "数据库文件: %v", dbFile)
 	}
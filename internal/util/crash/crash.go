@@ -0,0 +1,336 @@
+package crash
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"golang.org/x/sys/unix"
+	"omniscient/internal/model"
+)
+
+// defaultTailBytes 默认扫描日志末尾的字节数
+const defaultTailBytes = 512 << 10
+
+// Options 崩溃分析的输入
+type Options struct {
+	Pid       int       // 退出的进程 PID
+	Since     time.Time // 进程启动时间，早于此时间的 hs_err 和日志不参与分析
+	Dirs      []string  // 查找 hs_err_pid<pid>.log 的目录，如运行目录、jar 所在目录
+	ErrorFile string    // -XX:ErrorFile 的值，支持 %p
+	LogFiles  []string  // 项目日志，支持通配符
+	TailBytes int64     // 扫描日志末尾的字节数，0 使用默认值
+}
+
+// finding 一条崩溃线索，priority 越小越可信
+type finding struct {
+	priority int
+	reason   string
+	detail   string
+	file     string
+}
+
+// Analyze 分析进程退出的原因：内核 OOM killer > hs_err 文件 > 日志中的 OutOfMemoryError > StackOverflowError
+// 找不到任何线索时返回 unknown
+func Analyze(opts Options) *model.CrashReport {
+	var findings []*finding
+	if f := scanKernel(opts.Pid, opts.Since); f != nil {
+		findings = append(findings, f)
+	}
+	if f := scanHsErr(opts); f != nil {
+		findings = append(findings, f)
+	}
+	findings = append(findings, scanLogs(opts)...)
+
+	report := &model.CrashReport{Reason: model.CrashUnknown}
+	var best *finding
+	for _, f := range findings {
+		report.Evidence = append(report.Evidence, fmt.Sprintf("%s: %s", f.reason, f.detail))
+		if best == nil || f.priority < best.priority {
+			best = f
+		}
+		if f.file != "" && report.File == "" {
+			report.File = f.file
+		}
+	}
+	if best != nil {
+		report.Reason, report.Detail = best.reason, best.detail
+	}
+	return report
+}
+
+var (
+	// Out of memory: Killed process 1234 (java) total-vm:...
+	// Memory cgroup out of memory: Killed process 1234 (java) ...
+	killedProcessPattern = regexp.MustCompile(`Killed process (\d+)`)
+	// oom-kill:constraint=CONSTRAINT_NONE,...,task=java,pid=1234,uid=0
+	oomKillPattern = regexp.MustCompile(`oom-kill:.*[,:]pid=(\d+)`)
+	// dmesg 的 [12345.678901] 前缀，开机后的秒数
+	dmesgTimePattern = regexp.MustCompile(`^\[\s*([\d.]+)\]\s*`)
+)
+
+// scanKernel 在内核日志中查找 OOM killer 杀死该进程的记录，优先使用 dmesg，失败时读取 /dev/kmsg
+// 只认进程启动后的记录，避免 PID 复用时把以前其他进程的 OOM 记录当作本次的原因
+func scanKernel(pid int, since time.Time) *finding {
+	lines, err := dmesg()
+	if err != nil {
+		if lines, err = kmsg(); err != nil {
+			return nil
+		}
+	}
+	matched := matchKernel(lines, pid, bootSeconds(since))
+	if matched == "" {
+		return nil
+	}
+	return &finding{priority: 0, reason: model.CrashOomKiller, detail: truncate(dmesgTimePattern.ReplaceAllString(matched, ""))}
+}
+
+// matchKernel 最后一条 OOM killer 杀死 pid 的内核日志，since 为开机后的秒数，
+// 大于等于 0 时忽略更早的和没有时间戳的记录
+func matchKernel(lines []string, pid int, since float64) string {
+	target := strconv.Itoa(pid)
+	var matched string
+	for _, line := range lines {
+		if since >= 0 {
+			m := dmesgTimePattern.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			if at, err := strconv.ParseFloat(m[1], 64); err != nil || at < since {
+				continue
+			}
+		}
+		for _, pattern := range []*regexp.Regexp{killedProcessPattern, oomKillPattern} {
+			if m := pattern.FindStringSubmatch(line); m != nil && m[1] == target {
+				matched = line
+			}
+		}
+	}
+	return matched
+}
+
+// bootSeconds 将时间换算为开机后的秒数，与内核日志的时间戳使用同一个时钟（不含休眠时间），零值返回 -1
+func bootSeconds(t time.Time) float64 {
+	if t.IsZero() {
+		return -1
+	}
+	var ts unix.Timespec
+	if err := unix.ClockGettime(unix.CLOCK_MONOTONIC, &ts); err != nil {
+		return -1
+	}
+	uptime := time.Duration(ts.Nano())
+	seconds := (uptime - time.Since(t)).Seconds()
+	if seconds < 0 {
+		return 0
+	}
+	return seconds
+}
+
+func dmesg() ([]string, error) {
+	output, err := exec.Command("dmesg").Output()
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(string(output), "\n"), nil
+}
+
+// kmsg 读取 /dev/kmsg 中现有的记录，每次 read 返回一条 "优先级,序号,时间,标志;内容"，
+// 转换为与 dmesg 相同的 "[秒.微秒] 内容"
+func kmsg() ([]string, error) {
+	file, err := os.OpenFile("/dev/kmsg", os.O_RDONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	// 读完现有记录后 read 会等待新日志，用超时结束
+	_ = file.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+
+	var lines []string
+	buf := make([]byte, 8192)
+	for {
+		n, err := file.Read(buf)
+		if err != nil {
+			if errors.Is(err, syscall.EPIPE) {
+				// 记录在读取前被覆盖，继续读下一条
+				continue
+			}
+			break
+		}
+		lines = append(lines, parseKmsg(string(buf[:n])))
+	}
+	return lines, nil
+}
+
+// parseKmsg 将一条 /dev/kmsg 记录转换为 dmesg 的格式，无法解析时间时只保留内容
+func parseKmsg(record string) string {
+	record = strings.TrimRight(record, "\n")
+	i := strings.IndexByte(record, ';')
+	if i < 0 {
+		return record
+	}
+	fields := strings.Split(record[:i], ",")
+	message := record[i+1:]
+	if len(fields) < 3 {
+		return message
+	}
+	usec, err := strconv.ParseInt(fields[2], 10, 64)
+	if err != nil {
+		return message
+	}
+	return fmt.Sprintf("[%d.%06d] %s", usec/1e6, usec%1e6, message)
+}
+
+// scanHsErr 查找并解析 JVM 崩溃时写出的 hs_err_pid<pid>.log
+func scanHsErr(opts Options) *finding {
+	name := fmt.Sprintf("hs_err_pid%d.log", opts.Pid)
+	var candidates []string
+	if opts.ErrorFile != "" {
+		candidates = append(candidates, strings.NewReplacer("%p", strconv.Itoa(opts.Pid), "%%", "%").Replace(opts.ErrorFile))
+	}
+	for _, dir := range opts.Dirs {
+		if dir != "" {
+			candidates = append(candidates, filepath.Join(dir, name))
+		}
+	}
+	// 工作目录不可写时 JVM 写到临时目录
+	candidates = append(candidates, filepath.Join(os.TempDir(), name))
+
+	for _, path := range candidates {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() || info.ModTime().Before(opts.Since) {
+			continue
+		}
+		if f := parseHsErr(path); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+// parseHsErr 解析 hs_err 文件头部的错误说明
+//
+//	# There is insufficient memory for the Java Runtime Environment to continue.
+//	# Native memory allocation (mmap) failed to map 12288 bytes for committing reserved memory.
+//
+//	#  SIGSEGV (0xb) at pc=0x00007f..., pid=1234, tid=5678
+//	# Problematic frame:
+//	# C  [libc.so.6+0x18b7a1]
+func parseHsErr(path string) *finding {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	var header []string
+	scanner := bufio.NewScanner(io.LimitReader(file, 64<<10))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "#") {
+			if len(header) > 0 && strings.TrimSpace(line) != "" {
+				break
+			}
+			continue
+		}
+		if line = strings.TrimSpace(strings.TrimLeft(line, "#")); line != "" {
+			header = append(header, line)
+		}
+	}
+
+	result := &finding{priority: 1, reason: model.CrashJvmFatal, file: path}
+	var details []string
+	for i, line := range header {
+		switch {
+		case strings.Contains(line, "insufficient memory"):
+			result.reason = model.CrashNativeOom
+		case strings.HasPrefix(line, "Native memory allocation"):
+			details = append(details, line)
+		case strings.Contains(line, "OutOfMemory encountered"):
+			// -XX:+CrashOnOutOfMemoryError 时堆内存溢出也会写出 hs_err
+			result.reason = model.CrashOutOfMemory
+			details = append(details, line)
+		case strings.HasPrefix(line, "SIG") || strings.HasPrefix(line, "EXCEPTION_") || strings.HasPrefix(line, "Internal Error"):
+			details = append(details, line)
+		case line == "Problematic frame:" && i+1 < len(header):
+			details = append(details, "Problematic frame: "+header[i+1])
+		}
+	}
+	if len(details) == 0 {
+		details = append(details, "JVM 写出了 "+filepath.Base(path))
+	}
+	result.detail = truncate(strings.Join(details, "; "))
+	return result
+}
+
+// scanLogs 扫描启动后写过的日志末尾，取最后一次出现的 OutOfMemoryError 和 StackOverflowError
+func scanLogs(opts Options) []*finding {
+	tail := opts.TailBytes
+	if tail <= 0 {
+		tail = defaultTailBytes
+	}
+	var oom, overflow *finding
+	for _, pattern := range opts.LogFiles {
+		paths, _ := filepath.Glob(pattern)
+		for _, path := range paths {
+			info, err := os.Stat(path)
+			if err != nil || info.IsDir() || info.ModTime().Before(opts.Since) {
+				continue
+			}
+			data, err := readTail(path, info.Size(), tail)
+			if err != nil {
+				continue
+			}
+			for _, line := range bytes.Split(data, []byte("\n")) {
+				text := string(line)
+				if i := strings.Index(text, "java.lang.OutOfMemoryError"); i >= 0 {
+					oom = &finding{priority: 2, reason: model.CrashOutOfMemory, detail: truncate(text[i:]), file: path}
+				} else if i = strings.Index(text, "java.lang.StackOverflowError"); i >= 0 {
+					overflow = &finding{priority: 3, reason: model.CrashStackOverflow, detail: truncate(text[i:]), file: path}
+				}
+			}
+		}
+	}
+	var findings []*finding
+	for _, f := range []*finding{oom, overflow} {
+		if f != nil {
+			// 日志不是崩溃文件，只作为线索
+			f.detail, f.file = f.detail+" ("+filepath.Base(f.file)+")", ""
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+func readTail(path string, size, tail int64) ([]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	offset := size - tail
+	if offset < 0 {
+		offset = 0
+	}
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(file)
+}
+
+func truncate(text string) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) > 300 {
+		return string(runes[:300]) + "..."
+	}
+	return string(runes)
+}
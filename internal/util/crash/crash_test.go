@@ -0,0 +1,182 @@
+package crash
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"omniscient/internal/model"
+)
+
+// kernelLog 开机后不同时间的 OOM 记录，pid 1234 先被其他进程用过
+var kernelLog = []string{
+	"[  100.000001] Out of memory: Killed process 1234 (python3) total-vm:812340kB, anon-rss:512000kB",
+	"[  500.250000] oom-kill:constraint=CONSTRAINT_NONE,nodemask=(null),task=java,pid=4321,uid=0",
+	"[ 9000.500000] Memory cgroup out of memory: Killed process 1234 (java) total-vm:4123456kB",
+	"[ 9001.000000] oom-kill:constraint=CONSTRAINT_MEMCG,task=java,pid=12345,uid=1000",
+	"Out of memory: Killed process 1234 (java) without timestamp",
+}
+
+func TestMatchKernel(t *testing.T) {
+	tests := []struct {
+		name  string
+		pid   int
+		since float64
+		want  string
+	}{
+		{"no time bound takes the last line", 1234, -1, kernelLog[4]},
+		{"started before both kills", 1234, 50, kernelLog[2]},
+		{"pid reused after an old kill", 1234, 8000, kernelLog[2]},
+		{"old kill of a reused pid is ignored", 1234, 9500, ""},
+		{"oom-kill summary line", 4321, 400, kernelLog[1]},
+		{"pid prefix does not match", 123, -1, ""},
+		{"killed at the start time", 12345, 9001, kernelLog[3]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchKernel(kernelLog, tt.pid, tt.since); got != tt.want {
+				t.Errorf("matchKernel(%d, %v) = %q, want %q", tt.pid, tt.since, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseKmsg(t *testing.T) {
+	tests := []struct {
+		record string
+		want   string
+	}{
+		{"3,1042,9000500000,-;Out of memory: Killed process 1234 (java)\n", "[9000.500000] Out of memory: Killed process 1234 (java)"},
+		{"6,7,1500,c;usb 1-1: new device", "[0.001500] usb 1-1: new device"},
+		{"6,7,bad,-;message", "message"},
+		{"no separator", "no separator"},
+	}
+	for _, tt := range tests {
+		if got := parseKmsg(tt.record); got != tt.want {
+			t.Errorf("parseKmsg(%q) = %q, want %q", tt.record, got, tt.want)
+		}
+	}
+}
+
+func TestBootSeconds(t *testing.T) {
+	if got := bootSeconds(time.Time{}); got != -1 {
+		t.Errorf("bootSeconds(zero) = %v", got)
+	}
+	now := bootSeconds(time.Now())
+	earlier := bootSeconds(time.Now().Add(-10 * time.Second))
+	if now <= 0 || now-earlier < 9 || now-earlier > 11 {
+		t.Errorf("bootSeconds now = %v, 10s ago = %v", now, earlier)
+	}
+	if got := bootSeconds(time.Now().Add(-100 * 365 * 24 * time.Hour)); got != 0 {
+		t.Errorf("bootSeconds before boot = %v, want 0", got)
+	}
+}
+
+func TestParseHsErr(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		wantReason string
+		wantDetail string
+	}{
+		{"native oom", `#
+# There is insufficient memory for the Java Runtime Environment to continue.
+# Native memory allocation (mmap) failed to map 12288 bytes for committing reserved memory.
+# Possible reasons:
+#   The system is out of physical RAM or swap space
+#
+
+---------------  S U M M A R Y ------------
+`, model.CrashNativeOom, "Native memory allocation (mmap) failed to map 12288 bytes for committing reserved memory."},
+		{"sigsegv", `#
+# A fatal error has been detected by the Java Runtime Environment:
+#
+#  SIGSEGV (0xb) at pc=0x00007f3a1c2b47a1, pid=1234, tid=5678
+#
+# JRE version: OpenJDK Runtime Environment (17.0.9+9) (build 17.0.9+9)
+# Problematic frame:
+# C  [libc.so.6+0x18b7a1]
+#
+`, model.CrashJvmFatal, "SIGSEGV (0xb) at pc=0x00007f3a1c2b47a1, pid=1234, tid=5678; Problematic frame: C  [libc.so.6+0x18b7a1]"},
+		{"crash on heap oom", `#
+# A fatal error has been detected by the Java Runtime Environment:
+#
+#  Internal Error (debug.cpp:362), pid=1234, tid=5678
+#  fatal error: OutOfMemory encountered: Java heap space
+#
+`, model.CrashOutOfMemory, "Internal Error (debug.cpp:362), pid=1234, tid=5678; fatal error: OutOfMemory encountered: Java heap space"},
+		{"no header", "---------------  T H R E A D  ---------------\n", model.CrashJvmFatal, "JVM 写出了 hs_err_pid1234.log"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "hs_err_pid1234.log")
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			f := parseHsErr(path)
+			if f == nil {
+				t.Fatal("parseHsErr returned nil")
+			}
+			if f.reason != tt.wantReason || f.detail != tt.wantDetail || f.file != path {
+				t.Errorf("parseHsErr = %s %q %s, want %s %q", f.reason, f.detail, f.file, tt.wantReason, tt.wantDetail)
+			}
+		})
+	}
+	if f := parseHsErr(filepath.Join(t.TempDir(), "missing.log")); f != nil {
+		t.Errorf("missing file = %+v", f)
+	}
+}
+
+func TestScanLogs(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string, modTime time.Time) {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	started := time.Now().Add(-time.Hour)
+	write("app.log", strings.Join([]string{
+		"2026-10-19 08:00:00 ERROR Exception in thread \"main\" java.lang.OutOfMemoryError: Metaspace",
+		"2026-10-19 08:00:01 ERROR java.lang.StackOverflowError",
+		"2026-10-19 08:00:02 ERROR Exception in thread \"worker-1\" java.lang.OutOfMemoryError: Java heap space",
+	}, "\n"), time.Now())
+	// 启动前写的日志不参与分析
+	write("old.log", "java.lang.OutOfMemoryError: GC overhead limit exceeded", started.Add(-time.Hour))
+
+	tests := []struct {
+		name string
+		opts Options
+		want []string
+	}{
+		{"last of each kind", Options{Since: started, LogFiles: []string{filepath.Join(dir, "*.log")}}, []string{
+			model.CrashOutOfMemory + ": java.lang.OutOfMemoryError: Java heap space (app.log)",
+			model.CrashStackOverflow + ": java.lang.StackOverflowError (app.log)",
+		}},
+		{"tail only", Options{Since: started, LogFiles: []string{filepath.Join(dir, "app.log")}, TailBytes: 90}, []string{
+			model.CrashOutOfMemory + ": java.lang.OutOfMemoryError: Java heap space (app.log)",
+		}},
+		{"only logs written before start", Options{Since: time.Now().Add(time.Hour), LogFiles: []string{filepath.Join(dir, "*.log")}}, nil},
+		{"no matching files", Options{LogFiles: []string{filepath.Join(dir, "*.out")}}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, f := range scanLogs(tt.opts) {
+				if f.file != "" {
+					t.Errorf("log finding %s should not be reported as a crash file", f.reason)
+				}
+				got = append(got, f.reason+": "+f.detail)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("scanLogs = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Check 执行一次健康检查
+// 支持 http(s)://host:port/path（2xx、3xx 视为健康）和 tcp://host:port（端口可连接视为健康）
+func Check(ctx context.Context, target string, timeout time.Duration) error {
+	target = strings.TrimSpace(target)
+	switch {
+	case strings.HasPrefix(target, "http://"), strings.HasPrefix(target, "https://"):
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+		if err != nil {
+			return fmt.Errorf("无效的健康检查地址: %v", err)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode >= 400 {
+			return fmt.Errorf("健康检查返回状态码 %d", resp.StatusCode)
+		}
+		return nil
+	case strings.HasPrefix(target, "tcp://"):
+		conn, err := net.DialTimeout("tcp", strings.TrimPrefix(target, "tcp://"), timeout)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	default:
+		return fmt.Errorf("不支持的健康检查地址: %s", target)
+	}
+}
+
+// Wait 轮询健康检查直到成功或超时
+func Wait(ctx context.Context, target string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	var lastErr error
+	for {
+		if lastErr = Check(ctx, target, 3*time.Second); lastErr == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("等待健康检查超时(%s): %v", timeout, lastErr)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package jarinfo
+
+import (
+	"archive/zip"
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"omniscient/internal/model"
+)
+
+// gitPropertiesPaths git.properties 在 jar 中的常见位置（Spring Boot fat jar、普通 jar）
+var gitPropertiesPaths = []string{"BOOT-INF/classes/git.properties", "git.properties", "WEB-INF/classes/git.properties"}
+
+// cache 按路径缓存解析结果，文件大小和修改时间不变时不重复计算校验和
+var cache sync.Map
+
+type cacheEntry struct {
+	size    int64
+	modTime time.Time
+	info    *model.JarInfo
+}
+
+// Inspect 解析 jar 的 MANIFEST、build-info.properties、git.properties，并计算校验和
+func Inspect(path string) (*model.JarInfo, error) {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if cached, ok := cache.Load(path); ok {
+		entry := cached.(*cacheEntry)
+		if entry.size == stat.Size() && entry.modTime.Equal(stat.ModTime()) {
+			return entry.info, nil
+		}
+	}
+
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("无法打开 jar %s: %v", path, err)
+	}
+	defer reader.Close()
+
+	info := &model.JarInfo{
+		Path:    path,
+		Size:    stat.Size(),
+		ModTime: stat.ModTime().Format(time.DateTime),
+	}
+	files := make(map[string]*zip.File, len(reader.File))
+	for _, file := range reader.File {
+		files[file.Name] = file
+	}
+
+	if file, ok := files["META-INF/MANIFEST.MF"]; ok {
+		if info.Manifest, err = readManifest(file); err != nil {
+			return nil, err
+		}
+	}
+	if file, ok := files["META-INF/build-info.properties"]; ok {
+		if info.BuildInfo, err = readProperties(file); err != nil {
+			return nil, err
+		}
+	}
+	for _, name := range gitPropertiesPaths {
+		if file, ok := files[name]; ok {
+			if info.Git, err = readProperties(file); err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+	fill(info)
+
+	if info.Checksum, err = checksum(path); err != nil {
+		return nil, err
+	}
+	cache.Store(path, &cacheEntry{size: stat.Size(), modTime: stat.ModTime(), info: info})
+	return info, nil
+}
+
+// fill 从 MANIFEST、build-info、git 信息中提取常用字段
+func fill(info *model.JarInfo) {
+	manifest, build, git := info.Manifest, info.BuildInfo, info.Git
+	info.Title = first(build["build.name"], manifest["Implementation-Title"], build["build.artifact"])
+	info.Version = first(build["build.version"], manifest["Implementation-Version"], git["git.build.version"])
+	info.Commit = first(git["git.commit.id.abbrev"], abbrev(git["git.commit.id"]), abbrev(git["git.commit.id.full"]))
+	info.Branch = git["git.branch"]
+	info.BuildTime = first(build["build.time"], git["git.build.time"])
+	info.MainClass = manifest["Main-Class"]
+	info.StartClass = manifest["Start-Class"]
+	info.SpringBootVersion = manifest["Spring-Boot-Version"]
+	info.BuildJdk = first(manifest["Build-Jdk-Spec"], manifest["Build-Jdk"], manifest["Created-By"])
+}
+
+// readManifest 解析 MANIFEST.MF 的主属性，续行以一个空格开头
+func readManifest(file *zip.File) (map[string]string, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	values := make(map[string]string)
+	var lastKey string
+	scanner := bufio.NewScanner(rc)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			// 空行之后是各个条目的属性，只取主属性
+			break
+		}
+		if strings.HasPrefix(line, " ") && lastKey != "" {
+			values[lastKey] += line[1:]
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		lastKey = strings.TrimSpace(key)
+		values[lastKey] = strings.TrimSpace(value)
+	}
+	return values, scanner.Err()
+}
+
+// readProperties 解析 java properties 文件（忽略注释，不处理续行和转义）
+func readProperties(file *zip.File) (map[string]string, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(rc)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "!") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			key, value, ok = strings.Cut(line, ":")
+		}
+		if ok {
+			values[strings.TrimSpace(key)] = strings.ReplaceAll(strings.TrimSpace(value), `\:`, ":")
+		}
+	}
+	return values, scanner.Err()
+}
+
+// checksum 计算文件的 SHA-256
+func checksum(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err = io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// abbrev 提交号缩写为 7 位
+func abbrev(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
+// first 返回第一个非空值
+func first(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -614,3 +614,61 @@ func removeDuplicates(ports []string) []string {
 
 	return result
 }
+
+// ProcessJarPath 获取进程命令行中 -jar 指定的 jar 文件路径
+// 相对路径按进程工作目录解析，容器内的进程通过 /proc/<pid>/root 访问
+func ProcessJarPath(pid int) (string, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		return "", err
+	}
+
+	var jarPath string
+	args := strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+	for i, arg := range args {
+		if arg == "-jar" && i+1 < len(args) {
+			jarPath = args[i+1]
+			break
+		}
+	}
+	if jarPath == "" {
+		return "", gerror.Newf("进程 %d 的命令行中没有 -jar 参数", pid)
+	}
+
+	if !filepath.IsAbs(jarPath) {
+		cwd, err := os.Readlink(fmt.Sprintf("/proc/%d/cwd", pid))
+		if err != nil {
+			return "", err
+		}
+		jarPath = filepath.Join(cwd, jarPath)
+	}
+	root := fmt.Sprintf("/proc/%d/root", pid)
+	if target, err := os.Readlink(root); err == nil && target != "/" {
+		jarPath = filepath.Join(root, jarPath)
+	}
+	return jarPath, nil
+}
+
+// ProcessRSS 获取进程的常驻内存（字节），读取 /proc/<pid>/status 中的 VmRSS
+func ProcessRSS(pid int) (int64, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/status", pid))
+	if err != nil {
+		return 0, err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(line, "VmRSS:") {
+			continue
+		}
+		// VmRSS:	  123456 kB
+		fields := strings.Fields(strings.TrimPrefix(line, "VmRSS:"))
+		if len(fields) == 0 {
+			break
+		}
+		kb, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return kb * 1024, nil
+	}
+	return 0, gerror.Newf("进程 %d 没有 VmRSS", pid)
+}
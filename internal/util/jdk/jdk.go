@@ -0,0 +1,187 @@
+package jdk
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Info JDK 信息
+type Info struct {
+	Home    string `json:"home"    dc:"JDK目录"`
+	Version string `json:"version" dc:"Java版本"`
+	Vendor  string `json:"vendor"  dc:"发行厂商"`
+}
+
+// DefaultSearchPaths 常见的 JDK 安装目录，目录下的每个子目录都视为一个候选 JDK
+var DefaultSearchPaths = []string{
+	"/usr/lib/jvm",
+	"/usr/java",
+	"/usr/local/java",
+	"/opt/java",
+	"/opt/jdk",
+	"/opt",
+	"/usr/local",
+}
+
+// versionPattern java -version 输出中的版本号
+var versionPattern = regexp.MustCompile(`version "([^"]+)"`)
+
+// cache 运行进程的 JDK 信息缓存，按目录缓存，避免每次列表都读取文件或执行 java -version
+var cache sync.Map
+
+// Scan 扫描常见安装目录、JAVA_HOME、PATH 中的 java 以及 extra 指定的目录，返回有效的 JDK 目录
+func Scan(extra ...string) []string {
+	seen := make(map[string]bool)
+	var homes []string
+	add := func(home string) {
+		if home == "" {
+			return
+		}
+		if resolved, err := filepath.EvalSymlinks(home); err == nil {
+			home = resolved
+		}
+		home = normalizeHome(home)
+		if seen[home] || !IsHome(home) {
+			return
+		}
+		seen[home] = true
+		homes = append(homes, home)
+	}
+
+	for _, dir := range append(DefaultSearchPaths, extra...) {
+		// 目录本身就是 JDK
+		add(dir)
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			add(filepath.Join(dir, entry.Name()))
+		}
+	}
+	add(os.Getenv("JAVA_HOME"))
+	if home, err := os.UserHomeDir(); err == nil {
+		if entries, err := os.ReadDir(filepath.Join(home, ".sdkman", "candidates", "java")); err == nil {
+			for _, entry := range entries {
+				add(filepath.Join(home, ".sdkman", "candidates", "java", entry.Name()))
+			}
+		}
+	}
+	if java, err := exec.LookPath("java"); err == nil {
+		if resolved, err := filepath.EvalSymlinks(java); err == nil {
+			add(HomeOf(resolved))
+		}
+	}
+
+	sort.Strings(homes)
+	return homes
+}
+
+// IsHome 目录下是否存在 bin/java
+func IsHome(home string) bool {
+	info, err := os.Stat(filepath.Join(home, "bin", "java"))
+	return err == nil && !info.IsDir()
+}
+
+// HomeOf 根据 java 可执行文件路径推算 JDK 目录，JDK 8 的 jre/bin/java 返回外层 JDK 目录
+func HomeOf(exe string) string {
+	return normalizeHome(filepath.Dir(filepath.Dir(exe)))
+}
+
+// normalizeHome JDK 8 的 jre 子目录归一到外层 JDK 目录
+func normalizeHome(home string) string {
+	if filepath.Base(home) == "jre" && IsHome(filepath.Dir(home)) {
+		return filepath.Dir(home)
+	}
+	return home
+}
+
+// Inspect 读取 JDK 的 release 文件获取版本和厂商，没有 release 文件时执行 java -version
+func Inspect(home string) (*Info, error) {
+	info := &Info{Home: home}
+	release, err := readRelease(filepath.Join(home, "release"))
+	if err == nil && release["JAVA_VERSION"] != "" {
+		info.Version = release["JAVA_VERSION"]
+		info.Vendor = release["IMPLEMENTOR"]
+		if info.Vendor == "" {
+			info.Vendor = release["JAVA_VENDOR"]
+		}
+		return info, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	output, err := exec.CommandContext(ctx, filepath.Join(home, "bin", "java"), "-version").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("无法识别 JDK %s: %v", home, err)
+	}
+	match := versionPattern.FindStringSubmatch(string(output))
+	if match == nil {
+		return nil, fmt.Errorf("无法识别 JDK %s 的版本", home)
+	}
+	info.Version = match[1]
+	return info, nil
+}
+
+// ProcessHome 通过 /proc/<pid>/exe 获取进程使用的 JDK 目录
+func ProcessHome(pid int) (string, error) {
+	exe, err := os.Readlink(fmt.Sprintf("/proc/%d/exe", pid))
+	if err != nil {
+		return "", err
+	}
+	return HomeOf(strings.TrimSuffix(exe, " (deleted)")), nil
+}
+
+// ProcessInfo 获取运行进程实际使用的 JDK 信息
+// 通过 /proc/<pid>/root 读取，容器内的进程同样适用
+func ProcessInfo(pid int) (*Info, error) {
+	home, err := ProcessHome(pid)
+	if err != nil {
+		return nil, err
+	}
+	root := fmt.Sprintf("/proc/%d/root", pid)
+	if target, err := os.Readlink(root); err == nil && target == "/" {
+		root = ""
+	}
+
+	key := root + home
+	if cached, ok := cache.Load(key); ok {
+		return cached.(*Info), nil
+	}
+	info, err := Inspect(filepath.Join(root, home))
+	if err != nil {
+		return nil, err
+	}
+	info.Home = home
+	cache.Store(key, info)
+	return info, nil
+}
+
+// readRelease 解析 release 文件（KEY="value" 格式）
+func readRelease(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := make(map[string]string)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), "=")
+		if !ok {
+			continue
+		}
+		values[key] = strings.Trim(value, `"`)
+	}
+	return values, scanner.Err()
+}
@@ -0,0 +1,193 @@
+package jolokia
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"omniscient/internal/model"
+)
+
+// stubResponses 按 MBean 返回的结果，没有配置的 MBean 返回 404
+var stubResponses = map[string]string{
+	"java.lang:type=Memory":    `{"status":200,"value":{"init":262144,"used":104857600,"max":4294967296}}`,
+	"java.lang:type=Threading": `{"status":200,"value":42}`,
+	"java.lang:type=Runtime":   `{"status":200,"value":"OpenJDK 64-Bit Server VM"}`,
+	"app:type=Flags":           `{"status":200,"value":true}`,
+	"app:type=Counter":         `{"status":200,"value":"17"}`,
+	"app:type=List":            `{"status":200,"value":["a","b"]}`,
+	"Tomcat:type=ThreadPool,name=*": `{"status":200,"value":{
+		"Tomcat:name=\"http-nio-8080\",type=ThreadPool":{"currentThreadsBusy":3},
+		"Tomcat:name=\"http-nio-8443\",type=ThreadPool":{"currentThreadsBusy":1}}}`,
+}
+
+// jolokiaStub 按批量请求逐个返回 stubResponses，记录收到的请求
+func jolokiaStub(t *testing.T) (*httptest.Server, *[]request) {
+	t.Helper()
+	var received []request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		parts := make([]string, 0, len(received))
+		for _, req := range received {
+			resp, ok := stubResponses[req.Mbean]
+			if !ok {
+				resp = `{"status":404,"error_type":"javax.management.InstanceNotFoundException","error":"javax.management.InstanceNotFoundException : ` + req.Mbean + `"}`
+			}
+			parts = append(parts, resp)
+		}
+		_, _ = w.Write([]byte("[" + strings.Join(parts, ",") + "]"))
+	}))
+	t.Cleanup(server.Close)
+	return server, &received
+}
+
+func TestRead(t *testing.T) {
+	server, received := jolokiaStub(t)
+	queries := []*model.JolokiaQuery{
+		{Name: "jvm.heap.used", Mbean: "java.lang:type=Memory", Attribute: "HeapMemoryUsage", Path: "used"},
+		{Name: "jvm.threads", Mbean: "java.lang:type=Threading", Attribute: "ThreadCount"},
+		{Name: "jvm.name", Mbean: "java.lang:type=Runtime", Attribute: "VmName"},
+		{Name: "app.enabled", Mbean: "app:type=Flags", Attribute: "Enabled"},
+		{Name: "app.count", Mbean: "app:type=Counter", Attribute: "Count"},
+		{Name: "app.list", Mbean: "app:type=List", Attribute: "Items"},
+		{Name: "tomcat.threads.busy", Mbean: "Tomcat:type=ThreadPool,name=*", Attribute: "currentThreadsBusy"},
+		{Name: "hikari.active", Mbean: "com.zaxxer.hikari:type=Pool (*)", Attribute: "ActiveConnections"},
+		{Name: "jvm.heap.missing", Mbean: "java.lang:type=Memory", Attribute: "HeapMemoryUsage", Path: "used/bytes"},
+	}
+
+	samples, err := New(server.URL+"/jolokia/", 5*time.Second).Read(context.Background(), queries)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if len(*received) != len(queries) {
+		t.Fatalf("stub received %d requests, want one batch of %d", len(*received), len(queries))
+	}
+	if req := (*received)[0]; req.Type != "read" || req.Mbean != queries[0].Mbean || req.Attribute != queries[0].Attribute {
+		t.Errorf("request = %+v", req)
+	}
+
+	type result struct {
+		mbean string
+		value float64
+		raw   string
+		err   string
+	}
+	want := []result{
+		{mbean: "java.lang:type=Memory", value: 104857600},
+		{mbean: "java.lang:type=Threading", value: 42},
+		{mbean: "java.lang:type=Runtime", raw: `"OpenJDK 64-Bit Server VM"`},
+		{mbean: "app:type=Flags", value: 1},
+		{mbean: "app:type=Counter", value: 17},
+		{mbean: "app:type=List", raw: `["a","b"]`},
+		{mbean: `Tomcat:name="http-nio-8080",type=ThreadPool`, value: 3},
+		{mbean: `Tomcat:name="http-nio-8443",type=ThreadPool`, value: 1},
+		{mbean: "com.zaxxer.hikari:type=Pool (*)", err: "404 javax.management.InstanceNotFoundException"},
+		{mbean: "java.lang:type=Memory", err: "属性中没有路径 used/bytes"},
+	}
+	if len(samples) != len(want) {
+		t.Fatalf("got %d samples, want %d", len(samples), len(want))
+	}
+	for i, w := range want {
+		s := samples[i]
+		if s.Mbean != w.mbean {
+			t.Errorf("sample %d (%s) mbean = %q, want %q", i, s.Name, s.Mbean, w.mbean)
+		}
+		switch {
+		case w.err != "":
+			if s.Err == nil || !strings.Contains(s.Err.Error(), w.err) {
+				t.Errorf("sample %d (%s) err = %v, want %q", i, s.Name, s.Err, w.err)
+			}
+		case w.raw != "":
+			if s.Value != nil || s.Raw != w.raw {
+				t.Errorf("sample %d (%s) raw = %q, value = %v", i, s.Name, s.Raw, s.Value)
+			}
+		default:
+			if s.Err != nil || s.Value == nil || *s.Value != w.value {
+				t.Errorf("sample %d (%s) value = %v, err = %v, want %v", i, s.Name, s.Value, s.Err, w.value)
+			}
+		}
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	queries := []*model.JolokiaQuery{{Name: "jvm.threads", Mbean: "java.lang:type=Threading", Attribute: "ThreadCount"}}
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"http error", http.StatusUnauthorized, ``, "状态码 401"},
+		{"not a batch", http.StatusOK, `{"status":200,"value":42}`, "不是批量结果"},
+		{"wrong count", http.StatusOK, `[]`, "返回 0 个结果，请求了 1 个"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+			_, err := New(server.URL, 5*time.Second).Read(context.Background(), queries)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Read error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if samples, err := New("http://127.0.0.1:1", time.Second).Read(context.Background(), nil); samples != nil || err != nil {
+		t.Errorf("empty queries = %v, %v", samples, err)
+	}
+}
+
+func TestReadBasicAuth(t *testing.T) {
+	var user, password string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ = r.BasicAuth()
+		_, _ = w.Write([]byte(`[{"status":200,"value":1}]`))
+	}))
+	defer server.Close()
+
+	target := strings.Replace(server.URL, "http://", "http://jolokia:s3cret@", 1)
+	if _, err := New(target, 5*time.Second).Read(context.Background(), []*model.JolokiaQuery{{Name: "up", Mbean: "app:type=Up"}}); err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if user != "jolokia" || password != "s3cret" {
+		t.Errorf("basic auth = %q:%q", user, password)
+	}
+}
+
+func TestReadTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	if _, err := New(server.URL, 100*time.Millisecond).Read(context.Background(), []*model.JolokiaQuery{{Name: "up", Mbean: "app:type=Up"}}); err == nil {
+		t.Fatal("slow Jolokia should time out")
+	}
+}
+
+func TestIsPattern(t *testing.T) {
+	for mbean, want := range map[string]bool{
+		"java.lang:type=Memory":           false,
+		"Tomcat:type=ThreadPool,name=*":   true,
+		"com.zaxxer.hikari:type=Pool (?)": true,
+	} {
+		if got := IsPattern(mbean); got != want {
+			t.Errorf("IsPattern(%q) = %v", mbean, got)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"omniscient/internal/model"
+)
+
+// Channel 通知渠道，URL、Secret、Email 中的密钥引用需要调用方先解析
+type Channel struct {
+	Type    string             // 渠道类型[webhook, dingtalk, wecom, feishu, email]
+	URL     string             // webhook 或机器人地址
+	Secret  string             // 钉钉、飞书机器人的加签密钥
+	Email   *model.EmailConfig // 邮件配置
+	Timeout time.Duration
+}
+
+// Send 发送消息
+func Send(ctx context.Context, ch *Channel, msg *model.AlertMessage) error {
+	switch ch.Type {
+	case model.ChannelWebhook:
+		return ch.post(ctx, ch.URL, msg, nil)
+	case model.ChannelDingtalk:
+		return ch.dingtalk(ctx, msg)
+	case model.ChannelWecom:
+		payload := map[string]interface{}{"msgtype": "text", "text": map[string]string{"content": text(msg)}}
+		return ch.post(ctx, ch.URL, payload, checkErrcode)
+	case model.ChannelFeishu:
+		return ch.feishu(ctx, msg)
+	case model.ChannelEmail:
+		return ch.email(ctx, msg)
+	default:
+		return fmt.Errorf("不支持的通知渠道: %s", ch.Type)
+	}
+}
+
+// text 机器人消息正文
+func text(msg *model.AlertMessage) string {
+	return msg.Title + "\n" + msg.Content
+}
+
+// dingtalk 钉钉机器人，设置了加签密钥时在地址上附加 timestamp 和 sign
+func (ch *Channel) dingtalk(ctx context.Context, msg *model.AlertMessage) error {
+	target := ch.URL
+	if ch.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		mac := hmac.New(sha256.New, []byte(ch.Secret))
+		mac.Write([]byte(timestamp + "\n" + ch.Secret))
+		sign := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+		separator := "?"
+		if strings.Contains(target, "?") {
+			separator = "&"
+		}
+		target += separator + "timestamp=" + timestamp + "&sign=" + url.QueryEscape(sign)
+	}
+	payload := map[string]interface{}{"msgtype": "text", "text": map[string]string{"content": text(msg)}}
+	return ch.post(ctx, target, payload, checkErrcode)
+}
+
+// feishu 飞书机器人，加签使用 timestamp + "\n" + secret 作为 HMAC 密钥
+func (ch *Channel) feishu(ctx context.Context, msg *model.AlertMessage) error {
+	payload := map[string]interface{}{"msg_type": "text", "content": map[string]string{"text": text(msg)}}
+	if ch.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(timestamp+"\n"+ch.Secret))
+		payload["timestamp"] = timestamp
+		payload["sign"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+	return ch.post(ctx, ch.URL, payload, func(body []byte) error {
+		var result struct {
+			Code       *int   `json:"code"`
+			Msg        string `json:"msg"`
+			StatusCode *int   `json:"StatusCode"`
+		}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return fmt.Errorf("飞书返回的不是 JSON: %v", err)
+		}
+		if result.Code != nil && *result.Code != 0 {
+			return fmt.Errorf("飞书返回错误 %d: %s", *result.Code, result.Msg)
+		}
+		if result.StatusCode != nil && *result.StatusCode != 0 {
+			return fmt.Errorf("飞书返回错误 %d", *result.StatusCode)
+		}
+		return nil
+	})
+}
+
+// checkErrcode 钉钉、企业微信机器人返回 {"errcode": 0, "errmsg": "ok"}
+func checkErrcode(body []byte) error {
+	var result struct {
+		Errcode int    `json:"errcode"`
+		Errmsg  string `json:"errmsg"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("机器人返回的不是 JSON: %v", err)
+	}
+	if result.Errcode != 0 {
+		return fmt.Errorf("机器人返回错误 %d: %s", result.Errcode, result.Errmsg)
+	}
+	return nil
+}
+
+// post 以 JSON 提交，check 不为空时校验响应内容
+func (ch *Channel) post(ctx context.Context, target string, payload interface{}, check func(body []byte) error) error {
+	if target == "" {
+		return fmt.Errorf("没有设置通知地址")
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, ch.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("无效的通知地址: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("通知地址返回状态码 %d", resp.StatusCode)
+	}
+	if check != nil {
+		return check(body)
+	}
+	return nil
+}
+
+// email 通过 SMTP 发送纯文本邮件
+func (ch *Channel) email(ctx context.Context, msg *model.AlertMessage) error {
+	cfg := ch.Email
+	if cfg == nil || cfg.Host == "" || cfg.From == "" || len(cfg.To) == 0 {
+		return fmt.Errorf("邮件渠道缺少 host、from 或 to")
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = 25
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+	deadline := time.Now().Add(ch.Timeout)
+
+	dialer := &net.Dialer{Deadline: deadline}
+	var (
+		conn net.Conn
+		err  error
+	)
+	if port == 465 {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, &tls.Config{ServerName: cfg.Host})
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	_ = conn.SetDeadline(deadline)
+	client, err := smtp.NewClient(conn, cfg.Host)
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && port != 465 {
+		if err = client.StartTLS(&tls.Config{ServerName: cfg.Host}); err != nil {
+			return err
+		}
+	}
+	if cfg.Username != "" {
+		// PlainAuth 只允许在 TLS 连接或本机上发送密码
+		if err = client.Auth(smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)); err != nil {
+			return err
+		}
+	}
+	if err = client.Mail(cfg.From); err != nil {
+		return err
+	}
+	for _, to := range cfg.To {
+		if err = client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err = writer.Write(message(cfg, msg)); err != nil {
+		return err
+	}
+	if err = writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// message 邮件内容，正文使用 base64 编码避免中文和长行问题
+func message(cfg *model.EmailConfig, msg *model.AlertMessage) []byte {
+	var buf bytes.Buffer
+	headers := [][2]string{
+		{"From", cfg.From},
+		{"To", strings.Join(cfg.To, ", ")},
+		{"Subject", mime.BEncoding.Encode("UTF-8", msg.Title)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "text/plain; charset=UTF-8"},
+		{"Content-Transfer-Encoding", "base64"},
+	}
+	for _, header := range headers {
+		buf.WriteString(header[0] + ": " + header[1] + "\r\n")
+	}
+	buf.WriteString("\r\n")
+	body := base64.StdEncoding.EncodeToString([]byte(msg.Content))
+	for len(body) > 76 {
+		buf.WriteString(body[:76] + "\r\n")
+		body = body[76:]
+	}
+	buf.WriteString(body + "\r\n")
+	return buf.Bytes()
+}
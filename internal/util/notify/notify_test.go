@@ -0,0 +1,374 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"omniscient/internal/model"
+)
+
+var testMessage = &model.AlertMessage{Title: "项目 demo 意外退出", Content: "服务器 vm-1，PID 1234", Level: model.NotifyError}
+
+// captured 机器人 stub 收到的请求
+type captured struct {
+	mu    sync.Mutex
+	query url.Values
+	body  map[string]interface{}
+	ctype string
+}
+
+// robotServer 返回固定响应的机器人 stub
+func robotServer(t *testing.T, status int, response string) (*httptest.Server, *captured) {
+	t.Helper()
+	got := &captured{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		got.mu.Lock()
+		got.query, got.ctype = r.URL.Query(), r.Header.Get("Content-Type")
+		got.body = nil
+		_ = json.Unmarshal(data, &got.body)
+		got.mu.Unlock()
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, response)
+	}))
+	t.Cleanup(server.Close)
+	return server, got
+}
+
+func channel(typ, target, secret string) *Channel {
+	return &Channel{Type: typ, URL: target, Secret: secret, Timeout: 5 * time.Second}
+}
+
+func sign(key, data string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(data))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestSendWebhook(t *testing.T) {
+	server, got := robotServer(t, http.StatusOK, "")
+	if err := Send(context.Background(), channel(model.ChannelWebhook, server.URL, ""), testMessage); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if !strings.HasPrefix(got.ctype, "application/json") {
+		t.Errorf("Content-Type = %q", got.ctype)
+	}
+	if got.body["title"] != testMessage.Title || got.body["content"] != testMessage.Content {
+		t.Errorf("body = %v", got.body)
+	}
+}
+
+func TestSendDingtalkSign(t *testing.T) {
+	server, got := robotServer(t, http.StatusOK, `{"errcode":0,"errmsg":"ok"}`)
+	ch := channel(model.ChannelDingtalk, server.URL+"/robot/send?access_token=token", "SECabc")
+	if err := Send(context.Background(), ch, testMessage); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if got.query.Get("access_token") != "token" {
+		t.Errorf("access_token = %q", got.query.Get("access_token"))
+	}
+	timestamp := got.query.Get("timestamp")
+	if _, err := strconv.ParseInt(timestamp, 10, 64); err != nil {
+		t.Fatalf("timestamp = %q", timestamp)
+	}
+	if want := sign("SECabc", timestamp+"\nSECabc"); got.query.Get("sign") != want {
+		t.Errorf("sign = %q, want %q", got.query.Get("sign"), want)
+	}
+	text := got.body["text"].(map[string]interface{})
+	if got.body["msgtype"] != "text" || text["content"] != testMessage.Title+"\n"+testMessage.Content {
+		t.Errorf("body = %v", got.body)
+	}
+}
+
+func TestSendDingtalkWithoutSecret(t *testing.T) {
+	server, got := robotServer(t, http.StatusOK, `{"errcode":0}`)
+	if err := Send(context.Background(), channel(model.ChannelDingtalk, server.URL, ""), testMessage); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got.query.Has("sign") || got.query.Has("timestamp") {
+		t.Errorf("unexpected sign parameters: %v", got.query)
+	}
+}
+
+func TestSendWecom(t *testing.T) {
+	server, got := robotServer(t, http.StatusOK, `{"errcode":0,"errmsg":"ok"}`)
+	if err := Send(context.Background(), channel(model.ChannelWecom, server.URL+"/cgi-bin/webhook/send?key=k", ""), testMessage); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	text := got.body["text"].(map[string]interface{})
+	if got.query.Get("key") != "k" || got.body["msgtype"] != "text" || text["content"] != testMessage.Title+"\n"+testMessage.Content {
+		t.Errorf("query = %v, body = %v", got.query, got.body)
+	}
+}
+
+func TestSendFeishuSign(t *testing.T) {
+	server, got := robotServer(t, http.StatusOK, `{"code":0,"msg":"success"}`)
+	if err := Send(context.Background(), channel(model.ChannelFeishu, server.URL, "fs-secret"), testMessage); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	timestamp, _ := got.body["timestamp"].(string)
+	if _, err := strconv.ParseInt(timestamp, 10, 64); err != nil {
+		t.Fatalf("timestamp = %v", got.body["timestamp"])
+	}
+	// 飞书以 timestamp + "\n" + secret 为密钥，对空串签名
+	if want := sign(timestamp+"\nfs-secret", ""); got.body["sign"] != want {
+		t.Errorf("sign = %v, want %q", got.body["sign"], want)
+	}
+	content := got.body["content"].(map[string]interface{})
+	if got.body["msg_type"] != "text" || content["text"] != testMessage.Title+"\n"+testMessage.Content {
+		t.Errorf("body = %v", got.body)
+	}
+}
+
+func TestSendResponseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		typ      string
+		status   int
+		response string
+		wantErr  string
+	}{
+		{"dingtalk ok", model.ChannelDingtalk, http.StatusOK, `{"errcode":0,"errmsg":"ok"}`, ""},
+		{"dingtalk errcode", model.ChannelDingtalk, http.StatusOK, `{"errcode":310000,"errmsg":"sign not match"}`, "310000"},
+		{"dingtalk not json", model.ChannelDingtalk, http.StatusOK, `<html>`, "不是 JSON"},
+		{"wecom errcode", model.ChannelWecom, http.StatusOK, `{"errcode":93000,"errmsg":"invalid webhook url"}`, "93000"},
+		{"wecom http error", model.ChannelWecom, http.StatusBadGateway, ``, "502"},
+		{"feishu code", model.ChannelFeishu, http.StatusOK, `{"code":19021,"msg":"sign match fail"}`, "19021"},
+		{"feishu StatusCode ok", model.ChannelFeishu, http.StatusOK, `{"StatusCode":0,"StatusMessage":"success"}`, ""},
+		{"feishu StatusCode", model.ChannelFeishu, http.StatusOK, `{"StatusCode":9499}`, "9499"},
+		{"webhook ignores body", model.ChannelWebhook, http.StatusOK, `{"errcode":1}`, ""},
+		{"webhook http error", model.ChannelWebhook, http.StatusInternalServerError, ``, "500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := robotServer(t, tt.status, tt.response)
+			err := Send(context.Background(), channel(tt.typ, server.URL, ""), testMessage)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Send: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Send error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSendInvalidChannel(t *testing.T) {
+	if err := Send(context.Background(), channel("sms", "http://127.0.0.1", ""), testMessage); err == nil {
+		t.Error("unknown channel type should fail")
+	}
+	if err := Send(context.Background(), channel(model.ChannelWebhook, "", ""), testMessage); err == nil {
+		t.Error("empty url should fail")
+	}
+	if err := Send(context.Background(), &Channel{Type: model.ChannelEmail, Timeout: time.Second}, testMessage); err == nil {
+		t.Error("email without config should fail")
+	}
+}
+
+func TestDeliverSignature(t *testing.T) {
+	var header, custom string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header, custom = r.Header.Get(SignatureHeader), r.Header.Get("X-Omniscient-Event")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	body := []byte(`{"type":"project.started"}`)
+	status, err := Deliver(context.Background(), server.URL, "hook-secret", map[string]string{"X-Omniscient-Event": "project.started"}, body, 5*time.Second)
+	if err != nil || status != http.StatusAccepted {
+		t.Fatalf("Deliver = %d, %v", status, err)
+	}
+	if header != Sign("hook-secret", body) || !strings.HasPrefix(header, "sha256=") {
+		t.Errorf("signature = %q", header)
+	}
+	if custom != "project.started" {
+		t.Errorf("custom header = %q", custom)
+	}
+}
+
+func TestDeliverErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = io.WriteString(w, "maintenance\n")
+	}))
+	defer server.Close()
+
+	status, err := Deliver(context.Background(), server.URL, "", nil, []byte(`{}`), 5*time.Second)
+	if status != http.StatusServiceUnavailable || err == nil || !strings.Contains(err.Error(), "maintenance") {
+		t.Fatalf("Deliver = %d, %v", status, err)
+	}
+}
+
+// smtpSession 本地 SMTP stub 记录的一次会话
+type smtpSession struct {
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+// smtpServer 最小的 SMTP 服务，支持 EHLO、AUTH PLAIN、MAIL、RCPT、DATA、QUIT，不支持 STARTTLS
+func smtpServer(t *testing.T) (string, int, <-chan *smtpSession) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	sessions := make(chan *smtpSession, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+		session := &smtpSession{}
+		reply("220 localhost ESMTP stub")
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			command := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(command, "EHLO"):
+				reply("250-localhost")
+				reply("250 AUTH PLAIN")
+			case strings.HasPrefix(command, "AUTH PLAIN"):
+				decoded, _ := base64.StdEncoding.DecodeString(strings.TrimSpace(line[len("AUTH PLAIN"):]))
+				session.auth = string(decoded)
+				reply("235 2.7.0 Authentication successful")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				session.from = strings.Trim(line[len("MAIL FROM:"):], "<> ")
+				reply("250 OK")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				session.to = append(session.to, strings.Trim(line[len("RCPT TO:"):], "<> "))
+				reply("250 OK")
+			case command == "DATA":
+				reply("354 End data with <CR><LF>.<CR><LF>")
+				var data strings.Builder
+				for {
+					dataLine, err := reader.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if dataLine == ".\r\n" {
+						break
+					}
+					data.WriteString(dataLine)
+				}
+				session.data = data.String()
+				reply("250 OK queued")
+			case command == "QUIT":
+				reply("221 Bye")
+				sessions <- session
+				return
+			default:
+				reply("502 command not implemented")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, sessions
+}
+
+func TestSendEmail(t *testing.T) {
+	host, port, sessions := smtpServer(t)
+	cfg := &model.EmailConfig{
+		Host:     host,
+		Port:     port,
+		Username: "alert@example.com",
+		Password: "smtp-password",
+		From:     "alert@example.com",
+		To:       []string{"ops@example.com", "dev@example.com"},
+	}
+	ch := &Channel{Type: model.ChannelEmail, Email: cfg, Timeout: 5 * time.Second}
+	if err := Send(context.Background(), ch, testMessage); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	var session *smtpSession
+	select {
+	case session = <-sessions:
+	case <-time.After(5 * time.Second):
+		t.Fatal("smtp session not finished")
+	}
+	if session.auth != "\x00alert@example.com\x00smtp-password" {
+		t.Errorf("auth = %q", session.auth)
+	}
+	if session.from != cfg.From || strings.Join(session.to, ",") != "ops@example.com,dev@example.com" {
+		t.Errorf("from = %q, to = %v", session.from, session.to)
+	}
+	if !strings.Contains(session.data, "To: ops@example.com, dev@example.com\r\n") {
+		t.Errorf("data missing To header: %q", session.data)
+	}
+	assertMessage(t, session.data)
+}
+
+func TestMessage(t *testing.T) {
+	cfg := &model.EmailConfig{From: "a@example.com", To: []string{"b@example.com"}}
+	msg := &model.AlertMessage{Title: "告警", Content: strings.Repeat("内存不足 ", 40)}
+	data := string(message(cfg, msg))
+
+	header, body, ok := strings.Cut(data, "\r\n\r\n")
+	if !ok {
+		t.Fatalf("no header separator: %q", data)
+	}
+	for _, want := range []string{"From: a@example.com", "To: b@example.com", "MIME-Version: 1.0", "Content-Transfer-Encoding: base64"} {
+		if !strings.Contains(header+"\r\n", want+"\r\n") {
+			t.Errorf("header missing %q", want)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(body, "\r\n"), "\r\n") {
+		if len(line) > 76 {
+			t.Errorf("body line longer than 76: %d", len(line))
+		}
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(body, "\r\n", ""))
+	if err != nil || string(decoded) != msg.Content {
+		t.Errorf("body = %q, %v", decoded, err)
+	}
+}
+
+// assertMessage 校验邮件的主题和正文
+func assertMessage(t *testing.T, data string) {
+	t.Helper()
+	header, body, _ := strings.Cut(data, "\r\n\r\n")
+	var subject string
+	for _, line := range strings.Split(header, "\r\n") {
+		if value, ok := strings.CutPrefix(line, "Subject: "); ok {
+			subject, _ = new(mime.WordDecoder).DecodeHeader(value)
+		}
+	}
+	if subject != testMessage.Title {
+		t.Errorf("subject = %q", subject)
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(body, "\r\n", ""))
+	if err != nil || string(decoded) != testMessage.Content {
+		t.Errorf("body = %q, %v", decoded, err)
+	}
+}
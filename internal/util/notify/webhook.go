@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SignatureHeader 请求体签名头，值为 sha256=<hex(HMAC-SHA256(secret, body))>
+const SignatureHeader = "X-Omniscient-Signature"
+
+// Sign 计算请求体的 HMAC-SHA256 签名
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Deliver 以 JSON 提交已序列化的请求体，secret 不为空时附带签名头
+// 返回对方的状态码，非 2xx 时 err 中带上响应内容的开头部分
+func Deliver(ctx context.Context, target, secret string, headers map[string]string, body []byte, timeout time.Duration) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("无效的推送地址: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("User-Agent", "Omniscient-Webhook")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if secret != "" {
+		req.Header.Set(SignatureHeader, Sign(secret, body))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("推送地址返回状态码 %d: %s", resp.StatusCode, strings.TrimSpace(strings.ToValidUTF8(string(data), "")))
+	}
+	return resp.StatusCode, nil
+}
@@ -79,21 +79,6 @@ func getLastIPSegment() string {
 	return "0"
 }
 
-// CheckSudoNoPassword 检查是否可以无密码执行sudo命令
-func CheckSudoNoPassword(command string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	cmd := exec.CommandContext(ctx, "sudo", "-n", command, "--help")
-	if err := cmd.Run(); err != nil {
-		return gerror.New("无法无密码执行sudo命令，请配置sudo免密:\n" +
-			"1. 执行: sudo visudo\n" +
-			"2. 添加: " + GetCurrentUser() + " ALL=(ALL) NOPASSWD: /*/autostart\n" +
-			"3. 保存并退出")
-	}
-	return nil
-}
-
 // GetCurrentUser 获取当前用户名
 func GetCurrentUser() string {
 	cmd := exec.Command("whoami")
@@ -7,6 +7,7 @@ import (
 	"os"
 
 	_ "github.com/gogf/gf/contrib/drivers/mysql/v2"
+	_ "github.com/gogf/gf/contrib/drivers/pgsql/v2"
 	_ "github.com/gogf/gf/contrib/drivers/sqlite/v2"
 
 	"github.com/gogf/gf/v2/os/gcmd"
@@ -41,6 +42,24 @@ func main() {
 		if err != nil {
 			return
 		}
+	case "db":
+		// 数据库备份、恢复、迁移
+		if err := cmd.Database.Func(ctx, nil); err != nil {
+			g.Log().Error(ctx, "数据库命令执行失败:", err)
+			return
+		}
+	case "projects":
+		// 项目定义导入导出
+		if err := cmd.Projects.Func(ctx, nil); err != nil {
+			g.Log().Error(ctx, "项目命令执行失败:", err)
+			return
+		}
+	case "secrets":
+		// 密钥管理
+		if err := cmd.Secrets.Func(ctx, nil); err != nil {
+			g.Log().Error(ctx, "密钥命令执行失败:", err)
+			return
+		}
 	case "dbinfo":
 		// 显示数据库信息
 		if err := common.ShowDatabaseInfo(ctx); err != nil {
@@ -60,11 +79,21 @@ Available Commands:
   run      - Run the HTTP server (default)
   sh       - Usage: sudo omniscient sh <command> (Service management shell commands)
   dbinfo   - Show database information
+  db       - Database backup, restore and cross-database copy
+  projects - Export and import project definitions between workers
+  secrets  - Manage encrypted secrets and rotate the master key
 
 Examples:
   omniscient              # Run the server (default)
   omniscient run          # Run the server explicitly  
   omniscient dbinfo       # Show database configuration and status
+  omniscient db backup    # Backup current database to ./data/backup
+  omniscient db restore <file>               # Restore current database from a backup file
+  omniscient db copy --from=sqlite --to=mysql # Copy all tables from sqlite to mysql
+  omniscient projects export --out=projects.yaml      # Export projects of current worker
+  omniscient projects import projects.yaml --preview  # Preview importing projects into current worker
+  omniscient secrets set DB_PASSWORD                  # Store a secret, reference it as ${secret:DB_PASSWORD}
+  omniscient secrets rotate-key                       # Re-encrypt all secrets with a new master key
   omniscient sh status    # Show service status
   omniscient sh install   # Install systemd service
   omniscient sh uninstall # uninstall systemd service
@@ -79,7 +108,7 @@ Examples:
 		}
 
 		// 添加子命令
-		err := command.AddCommand(&cmd.Run, &cmd.Shell)
+		err := command.AddCommand(&cmd.Run, &cmd.Shell, &cmd.Database, &cmd.Projects, &cmd.Secrets)
 		if err != nil {
 			g.Log().Error(ctx, "子命令运行失败=========================")
 			return
@@ -8,7 +8,8 @@ const API_ENDPOINTS = {
     START_DOCKER: '/jpid/start/docker/',
     DELETE: '/jpid/delete/',
     UPDATE: '/jpid/update/',
-    AUTOSTART: '/jpid/autostart/'
+    AUTOSTART: '/jpid/autostart/',
+    JPID: '/jpid/'
 };
 
 const AUTO_REGISTER_INTERVAL = 60000; // 60秒
@@ -41,7 +42,7 @@ window.apiRequest = async function (url, method = 'GET', body = null) {
     const data = await response.json();
 
     if (!response.ok) {
-        throw new Error(data.error || `Request failed (${response.status})`);
+        throw new Error(data.error || data.message || `Request failed (${response.status})`);
     }
 
     return data;
@@ -687,3 +688,141 @@ window.updateAutostart = async function (id, autostart) {
     }
 };
 
+/**
+ * 按项目当前的启动配置重新生成自启服务
+ * @param {number} id - 项目ID
+ */
+window.regenerateAutostart = async function (id) {
+    if (!confirm('确定按当前启动配置重新生成自启服务吗？正在运行的服务重启后生效')) {
+        return;
+    }
+    try {
+        await window.apiRequest(`${API_ENDPOINTS.JPID}${id}/autostart/regenerate`, 'POST', {});
+        window.showNotification('自启服务已重新生成', 'success');
+        await window.fetchProjects();
+    } catch (error) {
+        window.showNotification(`重新生成失败：${error.message}`, 'danger');
+    }
+};
+
+/**
+ * 认领本机未关联项目的自启服务，启动命令和运行目录与项目匹配的服务排在前面
+ * @param {number} id - 项目ID
+ */
+window.adoptAutostart = async function (id) {
+    try {
+        const result = await window.apiRequest(`${API_ENDPOINTS.JPID}units`);
+        const units = (result.data.list || [])
+            .filter(unit => unit.projectId === 0)
+            .sort((a, b) => b.candidate.includes(id) - a.candidate.includes(id));
+        if (units.length === 0) {
+            window.showNotification('没有未关联的自启服务', 'info');
+            return;
+        }
+
+        const options = units.map(unit => `autostart-${unit.name}: ${unit.execStart}`).join('\n');
+        const name = prompt(`输入要认领的服务名：\n${options}`, units[0].name);
+        if (!name) {
+            return;
+        }
+        await window.apiRequest(`${API_ENDPOINTS.JPID}${id}/autostart/adopt`, 'POST', {name: name.trim()});
+        window.showNotification('已认领自启服务', 'success');
+        await window.fetchProjects();
+    } catch (error) {
+        window.showNotification(`认领失败：${error.message}`, 'danger');
+    }
+};
+
+/**
+ * 加载项目的 actuator 信息和 logger 级别并渲染
+ * @param {number} id - 项目ID
+ */
+window.loadActuator = async function (id) {
+    const content = document.getElementById('actuatorContent');
+    if (content) {
+        content.innerHTML = '<div class="text-center py-4"><div class="spinner-border text-primary" role="status"></div></div>';
+    }
+
+    const [overview, loggers] = await Promise.allSettled([
+        window.apiRequest(`${API_ENDPOINTS.JPID}${id}/actuator`),
+        window.apiRequest(`${API_ENDPOINTS.JPID}${id}/loggers`)
+    ]);
+
+    if (typeof window.renderActuator === 'function') {
+        window.renderActuator(
+            overview.status === 'fulfilled' ? overview.value.data : null,
+            loggers.status === 'fulfilled' ? loggers.value.data : null,
+            overview.status === 'rejected' ? overview.reason.message : ''
+        );
+    } else {
+        console.error("renderActuator function not available.");
+    }
+};
+
+/**
+ * 按筛选条件加载项目自启服务的日志并渲染
+ * @param {number} id - 项目ID
+ */
+window.loadJournal = async function (id) {
+    const content = document.getElementById('journalContent');
+    if (content) {
+        content.innerHTML = '<div class="text-center py-4"><div class="spinner-border text-primary" role="status"></div></div>';
+    }
+
+    const params = new URLSearchParams();
+    ['lines', 'since', 'priority', 'grep'].forEach(key => {
+        const input = document.getElementById(`journal${key.charAt(0).toUpperCase()}${key.slice(1)}`);
+        if (input && input.value.trim() !== '') {
+            params.set(key, input.value.trim());
+        }
+    });
+
+    try {
+        const result = await window.apiRequest(`${API_ENDPOINTS.JPID}${id}/journal?${params}`);
+        window.renderJournal(result.data, '');
+    } catch (error) {
+        window.renderJournal(null, error.message);
+    }
+};
+
+/**
+ * 修改 logger 级别
+ * @param {number} id - 项目ID
+ * @param {string} name - logger 名称
+ * @param {string} level - 日志级别，为空时恢复继承
+ */
+window.setLoggerLevel = async function (id, name, level) {
+    try {
+        const result = await window.apiRequest(`${API_ENDPOINTS.JPID}${id}/loggers`, 'POST', {name, level});
+        if (typeof window.showNotification === 'function') {
+            window.showNotification(`${name} 级别已修改为 ${result.data.effectiveLevel}`);
+        }
+        return result.data;
+    } catch (error) {
+        if (typeof window.showNotification === 'function') {
+            window.showNotification(`修改级别失败：${error.message}`, 'danger');
+        }
+        return null;
+    }
+};
+
+/**
+ * 保存项目的 actuator 地址和停止方式
+ * @param {number} id - 项目ID
+ * @param {string} actuator - actuator 地址，为空时自动探测
+ * @param {string} stopStrategy - 停止方式[signal, actuator]
+ */
+window.saveActuator = async function (id, actuator, stopStrategy) {
+    try {
+        const result = await window.apiRequest(`${API_ENDPOINTS.JPID}${id}/actuator`, 'POST', {actuator, stopStrategy});
+        if (typeof window.showNotification === 'function') {
+            window.showNotification(result.data.url ? `已保存，actuator 地址：${result.data.url}` : '已保存，actuator 暂时无法访问', result.data.url ? 'success' : 'warning');
+        }
+        await window.fetchProjects();
+        await window.loadActuator(id);
+    } catch (error) {
+        if (typeof window.showNotification === 'function') {
+            window.showNotification(`保存失败：${error.message}`, 'danger');
+        }
+    }
+};
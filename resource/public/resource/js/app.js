@@ -64,6 +64,42 @@ window.stopAutoRegister = function () {
     }
 };
 
+// ===== 实时事件 =====
+
+let eventSource = null;
+let eventRefreshTimer = null;
+
+/**
+ * 订阅 /events，项目状态变化时刷新列表（短时间内的多个事件合并为一次刷新）
+ * EventSource 断线后自动重连，并通过 Last-Event-ID 续传
+ */
+window.subscribeEvents = function () {
+    if (typeof EventSource === 'undefined' || eventSource) {
+        return;
+    }
+    eventSource = new EventSource('/events');
+
+    const refresh = () => {
+        clearTimeout(eventRefreshTimer);
+        eventRefreshTimer = setTimeout(() => {
+            if (typeof window.fetchProjects === 'function') {
+                window.fetchProjects();
+            }
+        }, 300);
+    };
+
+    ['project.started', 'project.stopped', 'project.registered', 'project.pid', 'project.autostart', 'stream.reset']
+        .forEach(type => eventSource.addEventListener(type, refresh));
+
+    eventSource.addEventListener('project.crashed', (e) => {
+        const event = JSON.parse(e.data);
+        if (typeof window.showNotification === 'function') {
+            window.showNotification(`项目 ${event.name} 意外退出：${event.data && event.data.reason || 'unknown'}`, 'danger');
+        }
+        refresh();
+    });
+};
+
 // ===== 事件监听器 =====
 
 /**
@@ -195,6 +231,36 @@ window.setupEventListeners = function () {
                 }
             }
 
+            // Actuator
+            if (e.target.closest('.actuator-btn')) {
+                const button = e.target.closest('.actuator-btn');
+                const id = parseInt(button.getAttribute('data-id'));
+                if (typeof window.showActuatorModal === 'function') {
+                    window.showActuatorModal(id);
+                } else {
+                    console.error("showActuatorModal function not available.");
+                }
+            }
+
+            // 自启服务日志
+            if (e.target.closest('.journal-btn')) {
+                const button = e.target.closest('.journal-btn');
+                const id = parseInt(button.getAttribute('data-id'));
+                if (typeof window.showJournalModal === 'function') {
+                    window.showJournalModal(id);
+                } else {
+                    console.error("showJournalModal function not available.");
+                }
+            }
+
+            // 重新生成、认领自启服务
+            if (e.target.closest('.regenerate-autostart-btn')) {
+                window.regenerateAutostart(parseInt(e.target.closest('.regenerate-autostart-btn').getAttribute('data-id')));
+            }
+            if (e.target.closest('.adopt-autostart-btn')) {
+                window.adoptAutostart(parseInt(e.target.closest('.adopt-autostart-btn').getAttribute('data-id')));
+            }
+
             // 编辑项目
             if (e.target.closest('.edit-project-btn')) {
                 const button = e.target.closest('.edit-project-btn');
@@ -294,6 +360,56 @@ window.setupEventListeners = function () {
         console.error("Confirm autostart button not found.");
     }
 
+    // Actuator 模态框：修改 logger 级别、过滤 logger、保存配置、刷新
+    const actuatorContent = document.getElementById('actuatorContent');
+    if (actuatorContent) {
+        actuatorContent.addEventListener('change', async (e) => {
+            const select = e.target.closest('.logger-level-select');
+            if (!select) {
+                return;
+            }
+            const id = parseInt(document.getElementById('actuatorProjectId').value);
+            const logger = await window.setLoggerLevel(id, select.getAttribute('data-name'), select.value);
+            if (logger) {
+                select.closest('tr').children[1].textContent = logger.effectiveLevel || '';
+            }
+        });
+        actuatorContent.addEventListener('input', (e) => {
+            if (e.target.id !== 'loggerFilter') {
+                return;
+            }
+            const keyword = e.target.value.trim().toLowerCase();
+            actuatorContent.querySelectorAll('.logger-row').forEach(row => {
+                row.style.display = row.getAttribute('data-name').includes(keyword) ? '' : 'none';
+            });
+        });
+    }
+
+    const saveActuatorButton = document.getElementById('saveActuatorButton');
+    if (saveActuatorButton) {
+        saveActuatorButton.addEventListener('click', () => {
+            const id = parseInt(document.getElementById('actuatorProjectId').value);
+            const actuator = document.getElementById('actuatorUrl').value.trim();
+            const stopStrategy = document.getElementById('actuatorStopStrategy').value;
+            window.saveActuator(id, actuator, stopStrategy);
+        });
+    }
+
+    const refreshActuatorButton = document.getElementById('refreshActuatorButton');
+    if (refreshActuatorButton) {
+        refreshActuatorButton.addEventListener('click', () => {
+            window.loadActuator(parseInt(document.getElementById('actuatorProjectId').value));
+        });
+    }
+
+    // 自启日志模态框：按筛选条件刷新
+    const refreshJournalButton = document.getElementById('refreshJournalButton');
+    if (refreshJournalButton) {
+        refreshJournalButton.addEventListener('click', () => {
+            window.loadJournal(parseInt(document.getElementById('journalProjectId').value));
+        });
+    }
+
     // 清理页面卸载时的资源
     window.addEventListener('beforeunload', () => {
         if (typeof window.stopAutoRegister === 'function') {
@@ -408,6 +524,9 @@ async function initApp() {
         console.error("startAutoRegister function not available.");
     }
 
+    // 订阅项目状态事件
+    window.subscribeEvents();
+
 
     // 隐藏通知区域
     const notificationArea = document.getElementById('notificationArea');
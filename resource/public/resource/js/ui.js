@@ -128,7 +128,35 @@ window.renderProjectList = function(projects) {
                 data-description="${escapeHtmlFunc(project.description || '')}">
                 <i class="bi bi-pencil text-info"></i> 编辑
             </button></li>
+            <li><button class="dropdown-item actuator-btn" data-id="${project.id}">
+                <i class="bi bi-heart-pulse text-success"></i> Actuator
+            </button></li>
+        `;
+        if (project.way !== 1 && project.autostart === 1) {
+            operationItems += `
+            <li><button class="dropdown-item journal-btn" data-id="${project.id}">
+                <i class="bi bi-journal-text text-secondary"></i> 自启日志
+            </button></li>
+        `;
+        }
+        if (project.way !== 1) {
+            operationItems += project.unitName ? `
+            <li><button class="dropdown-item regenerate-autostart-btn" data-id="${project.id}">
+                <i class="bi bi-arrow-repeat text-primary"></i> 重新生成自启服务
+            </button></li>
+        ` : `
+            <li><button class="dropdown-item adopt-autostart-btn" data-id="${project.id}">
+                <i class="bi bi-link-45deg text-primary"></i> 认领自启服务
+            </button></li>
         `;
+        }
+
+        // 关联的自启服务的运行状态，failed 时标红
+        const unitState = project.autostartState;
+        const unitStateHtml = unitState ? `
+                <br><small class="${unitState.failed ? 'text-danger' : 'text-muted'}" title="${escapeHtmlFunc(unitState.unit)} (${escapeHtmlFunc(unitState.unitFile)})">
+                    ${escapeHtmlFunc(unitState.activeState)}${unitState.subState ? '/' + escapeHtmlFunc(unitState.subState) : ''}
+                </small>` : '';
 
         tr.innerHTML = `
             <td>
@@ -137,6 +165,7 @@ window.renderProjectList = function(projects) {
                         '容器名: ' + escapeHtmlFunc(project.name) :
                         'JAR包: ' + escapeHtmlFunc(project.name)}"
                 >${escapeHtmlFunc(project.name)}</div>
+                ${project.java ? `<small class="text-muted" title="${escapeHtmlFunc(project.java.home)}">Java ${escapeHtmlFunc(project.java.version)}</small>` : ''}
             </td>
             <td>${escapeHtmlFunc(project.ports)}</td>
             <td>${escapeHtmlFunc(project.pid)}</td>
@@ -171,6 +200,7 @@ window.renderProjectList = function(projects) {
                     <i class="bi bi-${project.way === 1 ? 'dash-circle' : (project.autostart === 1 ? 'check-circle' : 'dash-circle')} me-1"></i>
                     ${project.way === 1 ? '不支持' : (project.autostart === 1 ? '自启中' : '待自启')}
                 </span>
+                ${unitStateHtml}
             </td>
             <td>
                 <div class="btn-group"> <button class="btn btn-sm btn-outline-secondary dropdown-toggle"
@@ -313,7 +343,7 @@ window.showAutostartConfirmModal = function(id, newAutostart, projectName, proje
             ? '注册自启后，项目将在系统启动时自动运行。'
             : '卸载自启后，项目将不再在系统启动时自动运行。';
         const additionalInfo = isRegister
-            ? '<br>(请确保autostart环境正确安装 <a href="https://gitee.com/tanoo/omniscient/blob/master/tools/autostart/README.md#%E5%85%A8%E5%B1%80%E7%8E%AF%E5%A2%83%E8%AE%BE%E7%BD%AE" target="_blank">查看安装指南</a>)'
+            ? '<br>(自启服务以 systemd 服务 autostart-&lt;项目名&gt;_&lt;端口&gt; 注册，已关联服务的项目使用关联的服务；非 root 运行 Omniscient 时注册为当前用户的服务，需要开启 lingering)'
             : '';
         noteElement.innerHTML = `${baseMessage}${additionalInfo}`;
     }
@@ -334,3 +364,202 @@ window.showAutostartConfirmModal = function(id, newAutostart, projectName, proje
         }
     }
 };
+
+/**
+ * 显示 Actuator 模态框
+ * @param {number} id - 项目ID
+ */
+window.showActuatorModal = function(id) {
+    const project = (window.projectsData || []).find(p => p.id === id);
+    const modal = document.getElementById('actuatorModal');
+    if (!project || !modal) {
+        console.error("Project or actuator modal not found.");
+        return;
+    }
+
+    document.getElementById('actuatorProjectId').value = id;
+    document.getElementById('actuatorProjectName').textContent = project.name;
+    document.getElementById('actuatorUrl').value = project.actuator || '';
+    document.getElementById('actuatorStopStrategy').value = project.stopStrategy || 'signal';
+    document.getElementById('actuatorStopStrategy').disabled = project.way === 1;
+
+    if (typeof bootstrap !== 'undefined' && bootstrap.Modal) {
+        bootstrap.Modal.getOrCreateInstance(modal).show();
+    } else {
+        console.error("Bootstrap Modal is not available.");
+    }
+    if (typeof window.loadActuator === 'function') {
+        window.loadActuator(id);
+    }
+};
+
+/**
+ * 显示自启服务日志模态框
+ * @param {number} id - 项目ID
+ */
+window.showJournalModal = function(id) {
+    const project = (window.projectsData || []).find(p => p.id === id);
+    const modal = document.getElementById('journalModal');
+    if (!project || !modal) {
+        console.error("Project or journal modal not found.");
+        return;
+    }
+
+    document.getElementById('journalProjectId').value = id;
+    document.getElementById('journalProjectName').textContent = project.name;
+
+    if (typeof bootstrap !== 'undefined' && bootstrap.Modal) {
+        bootstrap.Modal.getOrCreateInstance(modal).show();
+    } else {
+        console.error("Bootstrap Modal is not available.");
+    }
+    if (typeof window.loadJournal === 'function') {
+        window.loadJournal(id);
+    }
+};
+
+/**
+ * 渲染自启服务日志
+ * @param {Object|null} journal - /jpid/:id/journal 结果
+ * @param {string} error - 错误信息
+ */
+window.renderJournal = function(journal, error) {
+    const content = document.getElementById('journalContent');
+    if (!content) {
+        return;
+    }
+    if (!journal) {
+        content.innerHTML = `<div class="alert alert-warning mb-0">${window.escapeHtml(error || '读取日志失败')}</div>`;
+        return;
+    }
+
+    document.getElementById('journalUnit').textContent = journal.unit;
+    if (!journal.list || journal.list.length === 0) {
+        content.innerHTML = '<div class="text-muted">没有符合条件的日志</div>';
+        return;
+    }
+    // 级别 0~3 为错误，4 为警告
+    const lines = journal.list.map(entry => {
+        const css = entry.priority <= 3 ? 'text-danger' : (entry.priority === 4 ? 'text-warning' : '');
+        const time = new Date(entry.time).toLocaleString();
+        return `<div class="${css}">${window.escapeHtml(time)} ${window.escapeHtml(entry.message)}</div>`;
+    }).join('');
+    content.innerHTML = `<div class="output-content journal-log">${lines}</div>`;
+    const log = content.querySelector('.journal-log');
+    log.scrollTop = log.scrollHeight;
+};
+
+/**
+ * 格式化指标值
+ * @param {Object} metric - 指标
+ */
+window.formatMetric = function(metric) {
+    const value = metric.value;
+    switch (metric.baseUnit) {
+        case 'bytes':
+            return `${(value / 1024 / 1024).toFixed(1)} MB`;
+        case 'seconds':
+            if (metric.name === 'process.uptime') {
+                return `${(value / 3600).toFixed(1)} 小时`;
+            }
+            return `${value.toFixed(3)} s`;
+        default:
+            if (metric.name.endsWith('cpu.usage')) {
+                return `${(value * 100).toFixed(1)}%`;
+            }
+            return Number.isInteger(value) ? `${value}` : value.toFixed(3);
+    }
+};
+
+/**
+ * 渲染 actuator 信息和 logger 列表
+ * @param {Object|null} overview - /jpid/:id/actuator 结果
+ * @param {Object|null} loggers - /jpid/:id/loggers 结果
+ * @param {string} error - 获取 actuator 信息失败的原因
+ */
+window.renderActuator = function(overview, loggers, error) {
+    const content = document.getElementById('actuatorContent');
+    if (!content) {
+        console.error("Actuator content element not found.");
+        return;
+    }
+    const escapeHtmlFunc = typeof window.escapeHtml === 'function' ? window.escapeHtml : (str) => str;
+
+    if (!overview) {
+        content.innerHTML = `<div class="alert alert-warning">${escapeHtmlFunc(error || '无法获取 actuator 信息')}</div>`;
+        return;
+    }
+
+    const status = overview.health ? overview.health.status : 'UNKNOWN';
+    const statusClass = status === 'UP' ? 'bg-success' : (status === 'DOWN' ? 'bg-danger' : 'bg-secondary');
+    const components = overview.health && overview.health.components ? overview.health.components : {};
+    const componentBadges = Object.keys(components).map(name => {
+        const componentStatus = components[name].status;
+        return `<span class="badge ${componentStatus === 'UP' ? 'bg-success' : 'bg-danger'} me-1">${escapeHtmlFunc(name)}: ${escapeHtmlFunc(componentStatus)}</span>`;
+    }).join('');
+
+    const metricRows = (overview.metrics || []).map(metric => `
+        <tr>
+            <td><span data-bs-toggle="tooltip" title="${escapeHtmlFunc(metric.description || '')}">${escapeHtmlFunc(metric.name)}</span></td>
+            <td>${escapeHtmlFunc(window.formatMetric(metric))}</td>
+        </tr>
+    `).join('');
+
+    const errors = overview.errors ? Object.keys(overview.errors).map(endpoint =>
+        `<div class="small text-muted">${escapeHtmlFunc(endpoint)}: ${escapeHtmlFunc(overview.errors[endpoint])}</div>`
+    ).join('') : '';
+
+    let loggerHtml = '<div class="text-muted">loggers 端点不可用</div>';
+    if (loggers) {
+        const options = (levels, selected) => ['', ...levels].map(level =>
+            `<option value="${level}" ${level === (selected || '') ? 'selected' : ''}>${level || '继承'}</option>`
+        ).join('');
+        const rows = loggers.loggers.map(logger => `
+            <tr class="logger-row" data-name="${escapeHtmlFunc(logger.name.toLowerCase())}">
+                <td class="code-block">${escapeHtmlFunc(logger.name)}</td>
+                <td>${escapeHtmlFunc(logger.effectiveLevel || '')}</td>
+                <td>
+                    <select class="form-select form-select-sm logger-level-select" data-name="${escapeHtmlFunc(logger.name)}">
+                        ${options(loggers.levels, logger.configuredLevel)}
+                    </select>
+                </td>
+            </tr>
+        `).join('');
+        loggerHtml = `
+            <input type="text" class="form-control form-control-sm mb-2" id="loggerFilter" placeholder="按名称过滤 logger">
+            <div style="max-height: 360px; overflow-y: auto;">
+                <table class="table table-sm align-middle">
+                    <thead><tr><th>Logger</th><th>生效级别</th><th style="width: 140px;">配置级别</th></tr></thead>
+                    <tbody>${rows}</tbody>
+                </table>
+            </div>
+        `;
+    }
+
+    content.innerHTML = `
+        <div class="mb-3">
+            <span class="badge ${statusClass} fs-6 me-2">${escapeHtmlFunc(status)}</span>
+            <span class="text-muted small">${escapeHtmlFunc(overview.url)}</span>
+            <div class="mt-2">${componentBadges}</div>
+        </div>
+        <div class="row">
+            <div class="col-md-5">
+                <h6>指标</h6>
+                <table class="table table-sm">
+                    <tbody>${metricRows || '<tr><td class="text-muted">无</td></tr>'}</tbody>
+                </table>
+                <h6>应用信息</h6>
+                <pre class="code-block small">${escapeHtmlFunc(JSON.stringify(overview.info || {}, null, 2))}</pre>
+                ${errors}
+            </div>
+            <div class="col-md-7">
+                <h6>日志级别</h6>
+                ${loggerHtml}
+            </div>
+        </div>
+    `;
+
+    if (typeof window.initTooltips === 'function') {
+        window.initTooltips();
+    }
+};
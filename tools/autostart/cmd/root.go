@@ -1,15 +1,20 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
 	"autostart/internal/service"
 	"autostart/internal/utils"
+	"autostart/pkg/autostart"
 )
 
 const (
@@ -20,18 +25,36 @@ const (
 // commandHandler 定义命令处理函数类型
 type commandHandler func()
 
+var (
+	// jsonOutput --output=json，结果和错误都以 JSON 输出到标准输出
+	jsonOutput bool
+	// assumeYes --yes，跳过确认
+	assumeYes bool
+	// userMode --user，管理当前用户的服务（systemctl --user），不需要 root
+	userMode bool
+	// errorExitCode 出错时的退出码，exists 命令约定 0 存在、1 不存在、2 出错
+	errorExitCode = 1
+)
+
 // Execute 是应用程序的主入口点
 func Execute() {
 	if err := run(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		handleError(err)
 	}
 }
 
 // run 执行主要的应用程序逻辑
 func run() error {
-	// 检查操作系统支持
-	if err := checkSystemSupport(); err != nil {
+	// 解析全局选项，剩余参数按原来的位置交给各命令
+	args, err := parseGlobalFlags(os.Args)
+	if err != nil {
+		return err
+	}
+	os.Args = args
+
+	// 检查操作系统支持，export 的结果输出到标准输出，不输出系统信息
+	quiet := jsonOutput || (len(os.Args) > 1 && strings.ToLower(os.Args[1]) == "export")
+	if err := checkSystemSupport(quiet); err != nil {
 		return err
 	}
 
@@ -42,6 +65,9 @@ func run() error {
 	}
 
 	command := strings.ToLower(os.Args[1])
+	if command == "exists" || command == "check" {
+		errorExitCode = 2
+	}
 
 	// 检查权限要求
 	if err := checkPermissions(command); err != nil {
@@ -53,9 +79,11 @@ func run() error {
 }
 
 // checkSystemSupport 检查操作系统支持
-func checkSystemSupport() error {
+func checkSystemSupport(quiet bool) error {
 	currentOS := runtime.GOOS
-	fmt.Printf("System: %s\n", currentOS)
+	if !quiet {
+		fmt.Printf("System: %s\n", currentOS)
+	}
 
 	if currentOS != "linux" {
 		return fmt.Errorf("this tool currently only supports Linux systems (current: %s)", currentOS)
@@ -69,10 +97,16 @@ func checkPermissions(command string) error {
 	if !utils.NeedsRoot(command) {
 		return nil
 	}
+	// 用户服务只操作自己的目录和 systemd 实例，不需要 root
+	if userMode && command != "install-global" && command != "uninstall-global" {
+		return nil
+	}
 
 	if os.Geteuid() != 0 {
-		fmt.Printf("This operation requires root privileges. Please run with sudo:\n")
-		fmt.Printf("  sudo %s %s\n", os.Args[0], strings.Join(os.Args[1:], " "))
+		if !jsonOutput {
+			fmt.Printf("This operation requires root privileges. Please run with sudo:\n")
+			fmt.Printf("  sudo %s %s\n", os.Args[0], strings.Join(os.Args[1:], " "))
+		}
 		return fmt.Errorf("insufficient privileges")
 	}
 
@@ -81,7 +115,11 @@ func checkPermissions(command string) error {
 
 // executeCommand 根据命令执行相应的操作
 func executeCommand(command string) error {
-	sm := service.NewServiceManager()
+	sm, err := service.NewServiceManager(userMode)
+	if err != nil {
+		return err
+	}
+	sm.JSON, sm.AssumeYes = jsonOutput, assumeYes
 	gi := NewGlobalInstaller()
 
 	// 命令映射表
@@ -94,11 +132,15 @@ func executeCommand(command string) error {
 		"add":       func() { handleServiceAdd(sm) },
 		"create":    func() { handleServiceAdd(sm) },
 		"install":   func() { handleServiceAdd(sm) },
+		"add-timer": func() { handleTimerAdd(sm) },
 		"remove":    func() { handleServiceRemove(sm) },
 		"rm":        func() { handleServiceRemove(sm) },
 		"delete":    func() { handleServiceRemove(sm) },
 		"uninstall": func() { handleServiceRemove(sm) },
 		"edit":      func() { handleServiceEdit(sm) },
+		"override":  func() { handleServiceOverride(sm) },
+		"apply":     func() { handleManifestApply(sm) },
+		"export":    func() { handleManifestExport(sm) },
 
 		// 服务控制命令
 		"enable":  func() { handleServiceEnable(sm) },
@@ -125,9 +167,14 @@ func executeCommand(command string) error {
 	}
 
 	handler, exists := commandMap[command]
+	if exists && jsonOutput && (command == "install-global" || command == "uninstall-global") {
+		return fmt.Errorf("%s does not support --output=json", command)
+	}
 	if !exists {
-		fmt.Printf("Unknown command: %s\n\n", command)
-		utils.PrintHelp()
+		if !jsonOutput {
+			fmt.Printf("Unknown command: %s\n\n", command)
+			utils.PrintHelp()
+		}
 		return fmt.Errorf("unknown command: %s", command)
 	}
 
@@ -146,9 +193,22 @@ func handleServiceAdd(sm *service.ServiceManager) {
 
 	serviceName := os.Args[2]
 	execStart := os.Args[3]
-	options := os.Args[4:]
+	options, dryRun := takeFlag(os.Args[4:], "--dry-run")
 
-	handleError(sm.AddAutostartService(serviceName, execStart, options))
+	handleError(sm.AddAutostartService(serviceName, execStart, options, dryRun))
+}
+
+// handleTimerAdd 处理定时任务添加命令
+func handleTimerAdd(sm *service.ServiceManager) {
+	if len(os.Args) < 4 {
+		fmt.Println("Usage: autostart add-timer <name> <exec-start> --on-calendar=<spec> [options...]")
+		fmt.Println("Example: autostart add-timer cleanup \"/opt/jobs/cleanup.sh\" --on-calendar=\"*-*-* 02:00\" --persistent")
+		handleError(fmt.Errorf("insufficient arguments for add-timer command"))
+		return
+	}
+
+	options, dryRun := takeFlag(os.Args[4:], "--dry-run")
+	handleError(sm.AddTimerService(os.Args[2], os.Args[3], options, dryRun))
 }
 
 // handleServiceRemove 处理服务移除命令
@@ -168,7 +228,95 @@ func handleServiceEdit(sm *service.ServiceManager) {
 		handleError(err)
 		return
 	}
-	handleError(sm.EditService(serviceName))
+	options, restart := takeFlag(os.Args[3:], "--now")
+	options, dryRun := takeFlag(options, "--dry-run")
+	handleError(sm.EditService(serviceName, options, restart, dryRun))
+}
+
+// handleServiceOverride 处理 drop-in 覆盖文件命令
+func handleServiceOverride(sm *service.ServiceManager) {
+	serviceName, err := getServiceNameArg("override")
+	if err != nil {
+		handleError(err)
+		return
+	}
+
+	args, restart := takeFlag(os.Args[3:], "--now")
+	args, remove := takeFlag(args, "--remove")
+	if len(args) > 1 || (remove && len(args) > 0) {
+		handleError(fmt.Errorf("usage: autostart override <name> [<file>|-|--remove] [--now]"))
+		return
+	}
+
+	source := ""
+	if len(args) == 1 {
+		source = args[0]
+	}
+	handleError(sm.OverrideService(serviceName, source, remove, restart))
+}
+
+// handleManifestApply 处理清单应用命令
+func handleManifestApply(sm *service.ServiceManager) {
+	args, prune := takeFlag(os.Args[2:], "--prune")
+	args, dryRun := takeFlag(args, "--dry-run")
+	args, source, err := takeValue(args, "-f", "--file")
+	if err == nil && (source == "" || len(args) > 0) {
+		err = fmt.Errorf("usage: autostart apply -f <file>|- [--prune] [--dry-run]")
+	}
+	if err != nil {
+		handleError(err)
+		return
+	}
+	handleError(sm.ApplyManifest(source, prune, dryRun))
+}
+
+// handleManifestExport 处理清单导出命令
+func handleManifestExport(sm *service.ServiceManager) {
+	args, target, err := takeValue(os.Args[2:], "-f", "--file")
+	if err == nil && len(args) > 0 {
+		err = fmt.Errorf("usage: autostart export [-f <file>]")
+	}
+	if err != nil {
+		handleError(err)
+		return
+	}
+	handleError(sm.ExportManifest(target))
+}
+
+// takeValue 从参数中取出带值的选项，支持 -f value、--file=value 两种写法
+func takeValue(args []string, short, long string) ([]string, string, error) {
+	rest := make([]string, 0, len(args))
+	value := ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case (short != "" && arg == short) || arg == long:
+			if i+1 >= len(args) {
+				return nil, "", fmt.Errorf("missing value for %s", arg)
+			}
+			i++
+			value = args[i]
+		case strings.HasPrefix(arg, long+"="):
+			value = strings.TrimPrefix(arg, long+"=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return rest, value, nil
+}
+
+// takeFlag 从参数中取出不带值的开关，返回剩余参数和开关是否出现
+func takeFlag(args []string, flag string) ([]string, bool) {
+	rest := make([]string, 0, len(args))
+	found := false
+	for _, arg := range args {
+		if arg == flag {
+			found = true
+			continue
+		}
+		rest = append(rest, arg)
+	}
+	return rest, found
 }
 
 // handleServiceEnable 处理服务启用命令
@@ -238,13 +386,56 @@ func handleServiceLogs(sm *service.ServiceManager) {
 		handleError(err)
 		return
 	}
+	opts, follow, err := parseLogOptions(os.Args[3:])
+	if err != nil {
+		handleError(err)
+		return
+	}
+	handleError(sm.ShowServiceLogs(serviceName, opts, follow))
+}
 
-	lines := ""
-	if len(os.Args) > 3 {
-		lines = os.Args[3]
+// parseLogOptions 解析 logs 的选项，行数可以是位置参数（logs <name> 100）或 -n
+func parseLogOptions(args []string) (autostart.LogOptions, bool, error) {
+	var opts autostart.LogOptions
+	args, follow := takeFlag(args, "-f")
+	args, followLong := takeFlag(args, "--follow")
+
+	values := make(map[string]string)
+	for _, option := range [][2]string{{"-n", "--lines"}, {"", "--since"}, {"", "--until"}, {"-p", "--priority"}, {"-g", "--grep"}} {
+		var err error
+		if args, values[option[1]], err = takeValue(args, option[0], option[1]); err != nil {
+			return opts, false, err
+		}
+	}
+	lines := values["--lines"]
+	if len(args) == 1 && lines == "" {
+		lines = args[0]
+	} else if len(args) > 0 {
+		return opts, false, fmt.Errorf("usage: autostart logs <name> [lines] [-f] [--since=<time>] [--until=<time>] [-p <priority>] [--grep=<regex>]")
 	}
 
-	handleError(sm.ShowServiceLogs(serviceName, lines))
+	if lines != "" {
+		n, err := strconv.Atoi(lines)
+		if err != nil || n <= 0 {
+			return opts, false, fmt.Errorf("invalid lines: %s (must be a positive integer)", lines)
+		}
+		opts.Lines = n
+	}
+	now := time.Now()
+	for _, bound := range []struct {
+		flag   string
+		target *time.Time
+	}{{"--since", &opts.Since}, {"--until", &opts.Until}} {
+		if value := values[bound.flag]; value != "" {
+			t, err := autostart.ParseLogTime(value, now)
+			if err != nil {
+				return opts, false, fmt.Errorf("invalid %s: %w", bound.flag, err)
+			}
+			*bound.target = t
+		}
+	}
+	opts.Priority, opts.Grep = values["--priority"], values["--grep"]
+	return opts, follow || followLong, nil
 }
 
 // getServiceNameArg 获取服务名称参数
@@ -257,6 +448,12 @@ func getServiceNameArg(command string) (string, error) {
 
 // printVersion 打印版本信息
 func printVersion() {
+	if jsonOutput {
+		handleError(utils.PrintJSON(map[string]string{
+			"tool": ToolName, "version": Version, "os": runtime.GOOS, "arch": runtime.GOARCH, "go": runtime.Version(),
+		}))
+		return
+	}
 	fmt.Printf("%s v%s\n", ToolName, Version)
 	fmt.Printf("Built for %s/%s\n", runtime.GOOS, runtime.GOARCH)
 	fmt.Printf("Go version: %s\n", runtime.Version())
@@ -264,10 +461,57 @@ func printVersion() {
 
 // handleError 统一的错误处理
 func handleError(err error) {
-	if err != nil {
+	if err == nil {
+		return
+	}
+	var reported *service.ReportedError
+	if jsonOutput && errors.As(err, &reported) {
+		os.Exit(errorExitCode)
+	}
+	if jsonOutput {
+		data, _ := json.Marshal(map[string]string{"error": err.Error()})
+		fmt.Println(string(data))
+	} else {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
 	}
+	os.Exit(errorExitCode)
+}
+
+// parseGlobalFlags 取出 --output、--yes、--user 等全局选项，可以出现在任意位置
+func parseGlobalFlags(args []string) ([]string, error) {
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		var output string
+		switch {
+		case arg == "--yes" || arg == "-y":
+			assumeYes = true
+			continue
+		case arg == "--user":
+			userMode = true
+			continue
+		case strings.HasPrefix(arg, "--output="):
+			output = strings.TrimPrefix(arg, "--output=")
+		case arg == "--output" || arg == "-o":
+			if i+1 >= len(args) {
+				return nil, fmt.Errorf("missing value for %s (json or text)", arg)
+			}
+			i++
+			output = args[i]
+		default:
+			rest = append(rest, arg)
+			continue
+		}
+		switch output {
+		case "json":
+			jsonOutput = true
+		case "text":
+			jsonOutput = false
+		default:
+			return nil, fmt.Errorf("invalid output format: %s (json or text)", output)
+		}
+	}
+	return rest, nil
 }
 
 // handleServiceExists 处理服务存在性检查命令
@@ -279,6 +523,19 @@ func handleServiceExists(sm *service.ServiceManager) {
 	}
 
 	exists := sm.ServiceExists(serviceName)
+	if jsonOutput {
+		result := map[string]interface{}{"name": serviceName, "exists": exists}
+		if exists {
+			if status, err := sm.Status(serviceName); err == nil {
+				result["status"] = status
+			}
+		}
+		handleError(utils.PrintJSON(result))
+		if !exists {
+			os.Exit(1)
+		}
+		return
+	}
 	if exists {
 		fmt.Printf("✓ Service '%s' exists\n", serviceName)
 		sm.ShowServiceBriefStatus(serviceName)
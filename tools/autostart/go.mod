@@ -2,6 +2,4 @@ module autostart
 
 go 1.24
 
-require (
-
-)
+require gopkg.in/yaml.v3 v3.0.1
@@ -1,34 +1,104 @@
 package service
 
 import (
-	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
-	"path/filepath"
+	"os/signal"
+	"os/user"
 	"strings"
+	"syscall"
+	"time"
 
-	"autostart/internal/config"
 	"autostart/internal/utils"
+	"autostart/pkg/autostart"
 )
 
 const ToolName = "autostart"
 
-// ServiceManager 服务管理器
-type ServiceManager struct{}
+// ServiceManager 命令行的输出和交互，服务操作都交给 pkg/autostart
+type ServiceManager struct {
+	ctx     context.Context
+	manager *autostart.ServiceManager
+	// JSON 以 JSON 输出结果，不输出提示信息
+	JSON bool
+	// AssumeYes 跳过确认
+	AssumeYes bool
+	// command 提示中使用的命令，用户模式下带 --user
+	command string
+}
+
+// ReportedError 结果（包括失败的原因）已经以 JSON 输出，命令只需要以非 0 退出，不再输出错误
+type ReportedError struct {
+	Err error
+}
+
+func (e *ReportedError) Error() string { return e.Err.Error() }
+
+func (e *ReportedError) Unwrap() error { return e.Err }
+
+// NewServiceManager 创建新的服务管理器，user 为 true 时管理当前用户的服务
+func NewServiceManager(user bool) (*ServiceManager, error) {
+	if !user {
+		return &ServiceManager{ctx: context.Background(), manager: autostart.NewServiceManager(), command: ToolName}, nil
+	}
+	manager, err := autostart.NewUserServiceManager()
+	if err != nil {
+		return nil, err
+	}
+	return &ServiceManager{ctx: context.Background(), manager: manager, command: ToolName + " --user"}, nil
+}
+
+// sudoCommand 需要 root 的命令的提示，用户模式下不需要 sudo
+func (sm *ServiceManager) sudoCommand() string {
+	if sm.manager.User {
+		return sm.command
+	}
+	return "sudo " + sm.command
+}
+
+// warnLinger 用户模式下没有开启 lingering 时提示，输出到标准错误，不影响 JSON 输出
+func (sm *ServiceManager) warnLinger() {
+	if lingering, err := sm.manager.Lingering(); err != nil || lingering {
+		return
+	}
+	name := "$USER"
+	if current, err := user.Current(); err == nil {
+		name = current.Username
+	}
+	fmt.Fprintf(os.Stderr, "Warning: lingering is not enabled for %s, user services stop at logout and do not start at boot.\n", name)
+	fmt.Fprintf(os.Stderr, "Ask an administrator to run: sudo loginctl enable-linger %s\n", name)
+}
 
-// NewServiceManager 创建新的服务管理器
-func NewServiceManager() *ServiceManager {
-	return &ServiceManager{}
+// printStatus JSON 模式下操作完成后输出服务的最新状态
+func (sm *ServiceManager) printStatus(serviceName string) error {
+	status, err := sm.manager.Status(sm.ctx, serviceName)
+	if err != nil {
+		return err
+	}
+	return utils.PrintJSON(status)
 }
 
 // ListAutostartServices 列出所有自启服务
 func (sm *ServiceManager) ListAutostartServices() error {
+	if sm.JSON {
+		services, err := sm.manager.List(sm.ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list services: %w", err)
+		}
+		if services == nil {
+			services = []*autostart.ServiceInfo{}
+		}
+		return utils.PrintJSON(services)
+	}
+
 	fmt.Printf("Autostart Services managed by %s:\n", ToolName)
 	fmt.Println("============================================")
 
-	services, err := sm.getAutostartServices()
+	services, err := sm.manager.List(sm.ctx)
 	if err != nil {
 		return fmt.Errorf("failed to list services: %w", err)
 	}
@@ -36,113 +106,61 @@ func (sm *ServiceManager) ListAutostartServices() error {
 	if len(services) == 0 {
 		fmt.Println("No autostart services found.")
 		fmt.Println("")
-		fmt.Printf("Create your first service with: sudo %s add <name> <command>\n", ToolName)
+		fmt.Printf("Create your first service with: %s add <name> <command>\n", sm.sudoCommand())
 		return nil
 	}
 
-	fmt.Printf("%-20s %-12s %-12s %-30s\n", "SERVICE", "AUTOSTART", "STATUS", "DESCRIPTION")
-	fmt.Println("--------------------------------------------------------------------------------")
+	fmt.Printf("%-20s %-12s %-12s %-16s %-16s %-30s\n", "SERVICE", "AUTOSTART", "STATUS", "NEXT RUN", "LAST RUN", "DESCRIPTION")
+	fmt.Println("--------------------------------------------------------------------------------------------------------------")
 
 	for _, svc := range services {
-		fmt.Printf("%-20s %-12s %-12s %-30s\n",
-			svc.Name, svc.AutostartStatus, svc.ActiveStatus, svc.Description)
+		nextRun, lastRun := "-", "-"
+		if svc.Timer != nil {
+			nextRun, lastRun = formatRunTime(svc.Timer.NextRun), formatRunTime(svc.Timer.LastRun)
+		}
+		fmt.Printf("%-20s %-12s %-12s %-16s %-16s %-30s\n",
+			svc.Name, svc.AutostartStatus, svc.ActiveStatus, nextRun, lastRun, shortDescription(svc.Description))
 	}
 
 	fmt.Println("")
 	fmt.Println("LEGEND:")
 	fmt.Println("  enabled/disabled - Autostart on boot")
 	fmt.Println("  active/inactive  - Current running status")
+	fmt.Println("  NEXT/LAST RUN    - Scheduled jobs only, autostart follows the timer")
 	fmt.Println("")
-	fmt.Printf("Use '%s status <name>' for detailed status\n", ToolName)
-	fmt.Printf("Use '%s logs <name>' to view service logs\n", ToolName)
+	fmt.Printf("Use '%s status <name>' for detailed status\n", sm.command)
+	fmt.Printf("Use '%s logs <name>' to view service logs\n", sm.command)
 
 	return nil
 }
 
-// ServiceInfo 服务信息
-type ServiceInfo struct {
-	Name            string
-	AutostartStatus string
-	ActiveStatus    string
-	Description     string
-}
-
-// getAutostartServices 获取所有自启服务信息
-func (sm *ServiceManager) getAutostartServices() ([]ServiceInfo, error) {
-	cmd := exec.Command("systemctl", "list-unit-files", "--type=service", "--no-pager")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, err
-	}
-
-	var services []ServiceInfo
-	scanner := bufio.NewScanner(strings.NewReader(string(output)))
-
-	for scanner.Scan() {
-		line := scanner.Text()
-		if !strings.Contains(line, "autostart-") || !strings.Contains(line, ".service") {
-			continue
-		}
-
-		parts := strings.Fields(line)
-		if len(parts) < 2 {
-			continue
-		}
-
-		serviceName := strings.TrimSuffix(parts[0], ".service")
-		serviceName = strings.TrimPrefix(serviceName, "autostart-")
-		autostartStatus := parts[1]
-
-		activeStatus := sm.getServiceActiveStatus(serviceName)
-		description := sm.getServiceDescription(serviceName)
-
-		services = append(services, ServiceInfo{
-			Name:            serviceName,
-			AutostartStatus: autostartStatus,
-			ActiveStatus:    activeStatus,
-			Description:     description,
-		})
-	}
-
-	return services, scanner.Err()
-}
-
-// getServiceActiveStatus 获取服务激活状态
-func (sm *ServiceManager) getServiceActiveStatus(serviceName string) string {
-	cmd := exec.Command("systemctl", "is-active", "autostart-"+serviceName)
-	output, _ := cmd.Output()
-	return strings.TrimSpace(string(output))
-}
-
-// getServiceDescription 获取服务描述
-func (sm *ServiceManager) getServiceDescription(serviceName string) string {
-	configFile := filepath.Join(config.ConfigDir, serviceName+".json")
-	data, err := os.ReadFile(configFile)
-	if err != nil {
+// formatRunTime 定时任务的触发时间，没有时显示 -
+func formatRunTime(t *time.Time) string {
+	if t == nil {
 		return "-"
 	}
+	return t.Format("2006-01-02 15:04")
+}
 
-	var cfg config.ServiceConfig
-	if err := json.Unmarshal(data, &cfg); err != nil || cfg.Description == "" {
+// shortDescription 截断长描述
+func shortDescription(description string) string {
+	if description == "" {
 		return "-"
 	}
-	// 截断长描述
-	if len(cfg.Description) > 28 {
-		return cfg.Description[:25] + "..."
+	if len(description) > 28 {
+		return description[:25] + "..."
 	}
-	return cfg.Description
+	return description
 }
 
 // EnableService 启用服务自启动
 func (sm *ServiceManager) EnableService(serviceName string) error {
-	if !sm.ServiceExists(serviceName) {
-		return fmt.Errorf("service '%s' does not exist", serviceName)
+	if err := sm.manager.Enable(sm.ctx, serviceName); err != nil {
+		return err
 	}
-
-	fullServiceName := fmt.Sprintf("autostart-%s", serviceName)
-	cmd := exec.Command("systemctl", "enable", fullServiceName)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to enable service: %w\nOutput: %s", err, string(output))
+	sm.warnLinger()
+	if sm.JSON {
+		return sm.printStatus(serviceName)
 	}
 
 	fmt.Printf("✓ Service '%s' enabled for autostart on boot\n", serviceName)
@@ -152,48 +170,91 @@ func (sm *ServiceManager) EnableService(serviceName string) error {
 
 // DisableService 禁用服务自启动
 func (sm *ServiceManager) DisableService(serviceName string) error {
-	if !sm.ServiceExists(serviceName) {
-		return fmt.Errorf("service '%s' does not exist", serviceName)
+	if err := sm.manager.Disable(sm.ctx, serviceName); err != nil {
+		return err
 	}
-
-	fullServiceName := fmt.Sprintf("autostart-%s", serviceName)
-	cmd := exec.Command("systemctl", "disable", fullServiceName)
-	if output, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to disable service: %w\nOutput: %s", err, string(output))
+	if sm.JSON {
+		return sm.printStatus(serviceName)
 	}
 
 	fmt.Printf("✓ Service '%s' disabled from autostart on boot\n", serviceName)
-	fmt.Printf("Note: Service is still running if it was started. Use '%s stop %s' to stop it.\n", ToolName, serviceName)
+	fmt.Printf("Note: Service is still running if it was started. Use '%s stop %s' to stop it.\n", sm.command, serviceName)
 	sm.ShowServiceBriefStatus(serviceName)
 	return nil
 }
 
-// AddAutostartService 添加自启服务
-func (sm *ServiceManager) AddAutostartService(serviceName, execStart string, options []string) error {
-	if sm.ServiceExists(serviceName) {
+// AddAutostartService 添加自启服务，dryRun 为 true 时只输出生成的单元文件和检查结果
+func (sm *ServiceManager) AddAutostartService(serviceName, execStart string, options []string, dryRun bool) error {
+	if sm.manager.Exists(serviceName) {
 		return fmt.Errorf("service '%s' already exists. Use '%s remove %s' to remove it first, or '%s edit %s' to modify it",
-			serviceName, ToolName, serviceName, ToolName, serviceName)
+			serviceName, sm.command, serviceName, sm.command, serviceName)
 	}
 
-	configObj, err := utils.ParseAddOptions(serviceName, execStart, options)
+	configObj, err := autostart.ParseAddOptions(serviceName, execStart, options)
 	if err != nil {
 		return fmt.Errorf("failed to parse options: %w", err)
 	}
 
-	if err := sm.createSystemdService(configObj); err != nil {
-		return fmt.Errorf("failed to create systemd service: %w", err)
+	if dryRun {
+		return sm.printDryRun(configObj, "")
 	}
 
-	if err := config.SaveServiceConfig(configObj); err != nil {
-		fmt.Printf("Warning: Failed to save service config: %v\n", err)
+	if err := sm.manager.Add(sm.ctx, configObj); err != nil {
+		return fmt.Errorf("failed to create systemd service: %w", err)
+	}
+	sm.warnLinger()
+	if sm.JSON {
+		return sm.printStatus(serviceName)
 	}
 
 	sm.printServiceAddedInfo(configObj)
 	return nil
 }
 
+// AddTimerService 添加定时任务，生成 oneshot 服务和同名定时器
+func (sm *ServiceManager) AddTimerService(serviceName, execStart string, options []string, dryRun bool) error {
+	if sm.manager.Exists(serviceName) {
+		return fmt.Errorf("service '%s' already exists. Use '%s remove %s' to remove it first, or '%s edit %s' to modify it",
+			serviceName, sm.command, serviceName, sm.command, serviceName)
+	}
+
+	cfg, err := autostart.ParseTimerOptions(serviceName, execStart, options)
+	if err != nil {
+		return fmt.Errorf("failed to parse options: %w", err)
+	}
+
+	if dryRun {
+		return sm.printDryRun(cfg, "")
+	}
+
+	if err := sm.manager.Add(sm.ctx, cfg); err != nil {
+		return fmt.Errorf("failed to create systemd timer: %w", err)
+	}
+	sm.warnLinger()
+	if sm.JSON {
+		return sm.printStatus(serviceName)
+	}
+
+	fmt.Printf("✓ Scheduled job '%s' added successfully!\n", cfg.Name)
+	fmt.Printf("  Command: %s\n", cfg.ExecStart)
+	fmt.Printf("  User: %s\n", cfg.User)
+	for _, spec := range cfg.Timer.OnCalendar {
+		if next, err := sm.manager.CheckCalendar(sm.ctx, spec); err == nil && !next.IsZero() {
+			fmt.Printf("  Schedule: %s (next: %s)\n", spec, next.Format("2006-01-02 15:04:05"))
+		} else {
+			fmt.Printf("  Schedule: %s\n", spec)
+		}
+	}
+	fmt.Println("")
+	fmt.Println("Next steps:")
+	fmt.Printf("  %s enable %s     # Enable the timer on boot\n", sm.command, cfg.Name)
+	fmt.Printf("  %s start %s      # Start the timer now\n", sm.command, cfg.Name)
+	fmt.Printf("  %s list               # Show next and last run\n", sm.command)
+	return nil
+}
+
 // printServiceAddedInfo 打印服务添加成功信息
-func (sm *ServiceManager) printServiceAddedInfo(cfg *config.ServiceConfig) {
+func (sm *ServiceManager) printServiceAddedInfo(cfg *autostart.ServiceConfig) {
 	fmt.Printf("✓ Service '%s' added successfully!\n", cfg.Name)
 	fmt.Printf("  Command: %s\n", cfg.ExecStart)
 	fmt.Printf("  User: %s\n", cfg.User)
@@ -201,60 +262,36 @@ func (sm *ServiceManager) printServiceAddedInfo(cfg *config.ServiceConfig) {
 	fmt.Printf("  Restart Policy: %s\n", cfg.Restart)
 	fmt.Println("")
 	fmt.Println("Next steps:")
-	fmt.Printf("  %s enable %s     # Enable autostart on boot\n", ToolName, cfg.Name)
-	fmt.Printf("  %s start %s      # Start the service now\n", ToolName, cfg.Name)
-	fmt.Printf("  %s status %s     # Check service status\n", ToolName, cfg.Name)
-}
-
-// createSystemdService 创建systemd服务
-func (sm *ServiceManager) createSystemdService(cfg *config.ServiceConfig) error {
-	serviceName := fmt.Sprintf("autostart-%s", cfg.Name)
-	servicePath := fmt.Sprintf("/etc/systemd/system/%s.service", serviceName)
-	// 构建服务文件内容
-	serviceContent := utils.BuildServiceContent(cfg, serviceName)
-	// 写入服务文件
-	if err := os.WriteFile(servicePath, []byte(serviceContent), 0644); err != nil {
-		return fmt.Errorf("failed to create service file: %w", err)
-	}
-	// 重载 systemd
-	cmd := exec.Command("systemctl", "daemon-reload")
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to reload systemd: %w", err)
-	}
-
-	return nil
+	fmt.Printf("  %s enable %s     # Enable autostart on boot\n", sm.command, cfg.Name)
+	fmt.Printf("  %s start %s      # Start the service now\n", sm.command, cfg.Name)
+	fmt.Printf("  %s status %s     # Check service status\n", sm.command, cfg.Name)
 }
 
 // RemoveAutostartService 移除自启服务
 func (sm *ServiceManager) RemoveAutostartService(serviceName string) error {
-	fullServiceName := fmt.Sprintf("autostart-%s", serviceName)
-	servicePath := fmt.Sprintf("/etc/systemd/system/%s.service", fullServiceName)
-
-	if _, err := os.Stat(servicePath); os.IsNotExist(err) {
+	if !sm.manager.Exists(serviceName) {
 		return fmt.Errorf("service '%s' does not exist", serviceName)
 	}
 
-	if !sm.confirmRemoval(serviceName) {
-		fmt.Println("Removal cancelled.")
-		return nil
+	if sm.JSON {
+		// 脚本调用时无法交互确认
+		if !sm.AssumeYes {
+			return fmt.Errorf("removing '%s' with --output=json requires --yes", serviceName)
+		}
+		if err := sm.manager.Remove(sm.ctx, serviceName); err != nil {
+			return err
+		}
+		return utils.PrintJSON(map[string]interface{}{"name": serviceName, "removed": true})
 	}
 
-	// 停止并禁用服务
-	sm.stopAndDisableService(fullServiceName)
-
-	// 删除服务文件
-	if err := os.Remove(servicePath); err != nil {
-		return fmt.Errorf("failed to remove service file: %w", err)
+	if !sm.AssumeYes && !sm.confirmRemoval(serviceName) {
+		fmt.Println("Removal cancelled.")
+		return nil
 	}
 
-	// 删除配置文件
-	configFile := filepath.Join(config.ConfigDir, serviceName+".json")
-	os.Remove(configFile) // 忽略错误
-
-	// 重载systemd
-	cmd := exec.Command("systemctl", "daemon-reload")
-	if err := cmd.Run(); err != nil {
-		fmt.Printf("Warning: Failed to reload systemd: %v\n", err)
+	fmt.Printf("Stopping and disabling service...\n")
+	if err := sm.manager.Remove(sm.ctx, serviceName); err != nil {
+		return err
 	}
 
 	fmt.Printf("✓ Service '%s' removed successfully!\n", serviceName)
@@ -272,56 +309,262 @@ func (sm *ServiceManager) confirmRemoval(serviceName string) bool {
 	return response == "y" || response == "yes"
 }
 
-// stopAndDisableService 停止并禁用服务
-func (sm *ServiceManager) stopAndDisableService(fullServiceName string) {
-	fmt.Printf("Stopping service...\n")
-	stopCmd := exec.Command("systemctl", "stop", fullServiceName)
-	stopCmd.Run() // 忽略错误
-
-	fmt.Printf("Disabling service...\n")
-	disableCmd := exec.Command("systemctl", "disable", fullServiceName)
-	disableCmd.Run() // 忽略错误
-}
-
 // ShowServiceStatus 显示服务状态
 func (sm *ServiceManager) ShowServiceStatus(serviceName string) error {
-	fullServiceName := fmt.Sprintf("autostart-%s", serviceName)
-	cmd := exec.Command("systemctl", "status", fullServiceName)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	if sm.JSON {
+		return sm.printStatus(serviceName)
+	}
+	output, err := sm.manager.StatusDetail(sm.ctx, serviceName)
+	if err != nil {
+		return err
+	}
+	fmt.Println(output)
+	return nil
 }
 
-// ShowServiceLogs 显示服务日志
-func (sm *ServiceManager) ShowServiceLogs(serviceName string, lines string) error {
-	if lines == "" {
-		lines = "50"
+// ShowServiceLogs 显示服务日志，follow 为 true 时持续输出新日志直到中断
+// JSON 模式下跟踪时每行输出一条日志（NDJSON）
+func (sm *ServiceManager) ShowServiceLogs(serviceName string, opts autostart.LogOptions, follow bool) error {
+	if follow {
+		ctx, stop := signal.NotifyContext(sm.ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		return sm.manager.FollowLogs(ctx, serviceName, opts, func(entry *autostart.LogEntry) error {
+			if sm.JSON {
+				data, err := json.Marshal(entry)
+				if err != nil {
+					return err
+				}
+				_, err = fmt.Println(string(data))
+				return err
+			}
+			_, err := fmt.Println(formatLogEntry(entry))
+			return err
+		})
+	}
+
+	entries, err := sm.manager.QueryLogs(sm.ctx, serviceName, opts)
+	if err != nil {
+		return err
+	}
+	if sm.JSON {
+		return utils.PrintJSON(entries)
 	}
-	fullServiceName := fmt.Sprintf("autostart-%s", serviceName)
-	cmd := exec.Command("journalctl", "-u", fullServiceName, "-n", lines, "--no-pager")
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	if len(entries) == 0 {
+		fmt.Println("-- No entries --")
+	}
+	for _, entry := range entries {
+		fmt.Println(formatLogEntry(entry))
+	}
+	return nil
 }
 
-// EditService 编辑服务配置
-func (sm *ServiceManager) EditService(serviceName string) error {
-	configFile := filepath.Join(config.ConfigDir, serviceName+".json")
-	if _, err := os.Stat(configFile); os.IsNotExist(err) {
-		return fmt.Errorf("service '%s' configuration not found", serviceName)
+// formatLogEntry 与 journalctl 默认格式相近的一行日志，警告及以上的级别标在消息前
+func formatLogEntry(entry *autostart.LogEntry) string {
+	source := entry.Identifier
+	if entry.PID > 0 {
+		source = fmt.Sprintf("%s[%d]", source, entry.PID)
 	}
+	message := entry.Message
+	if entry.Priority <= 4 {
+		message = fmt.Sprintf("<%s> %s", autostart.PriorityName(entry.Priority), message)
+	}
+	if source == "" {
+		return fmt.Sprintf("%s %s", entry.Time.Format(time.DateTime), message)
+	}
+	return fmt.Sprintf("%s %s: %s", entry.Time.Format(time.DateTime), source, message)
+}
 
-	cfg, err := config.LoadServiceConfig(serviceName)
+// EditService 编辑服务配置，没有选项时显示当前配置
+// 有选项时修改保存的配置并重新生成单元文件，确认差异后才写入，restart 为 true 时随后重启服务，dryRun 为 true 时只显示差异
+func (sm *ServiceManager) EditService(serviceName string, options []string, restart, dryRun bool) error {
+	cfg, err := sm.manager.Config(serviceName)
+	if errors.Is(err, autostart.ErrConfigNotFound) {
+		return fmt.Errorf("service '%s' configuration not found", serviceName)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to load service config: %w", err)
 	}
 
-	sm.printCurrentConfig(serviceName, cfg)
+	if len(options) == 0 {
+		if sm.JSON {
+			return utils.PrintJSON(cfg)
+		}
+		sm.printCurrentConfig(serviceName, cfg)
+		return nil
+	}
+
+	updated := cfg.Clone()
+	if err := autostart.ApplyOptions(updated, options); err != nil {
+		return fmt.Errorf("failed to parse options: %w", err)
+	}
+	diff, err := sm.manager.UnitDiff(updated)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		return sm.printDryRun(updated, diff)
+	}
+
+	return sm.applyChange(serviceName, diff, restart, func() error {
+		return sm.manager.Update(sm.ctx, updated)
+	})
+}
+
+// OverrideService 查看或修改 drop-in 覆盖文件，source 为文件路径，- 表示从标准输入读取
+func (sm *ServiceManager) OverrideService(serviceName, source string, remove, restart bool) error {
+	current, err := sm.manager.Override(serviceName)
+	if err != nil {
+		return err
+	}
+	path := sm.manager.OverridePath(serviceName)
+
+	if source == "" && !remove {
+		if sm.JSON {
+			return utils.PrintJSON(map[string]interface{}{"name": serviceName, "path": path, "content": current})
+		}
+		if current == "" {
+			fmt.Printf("Service '%s' has no override.\n", serviceName)
+			fmt.Printf("Create one with: %s override %s <file>\n", sm.sudoCommand(), serviceName)
+			return nil
+		}
+		fmt.Printf("# %s\n%s", path, current)
+		return nil
+	}
+
+	content := ""
+	if !remove {
+		if content, err = readSource(source); err != nil {
+			return err
+		}
+	}
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	diff := autostart.UnifiedDiff(path, path, current, content)
+
+	return sm.applyChange(serviceName, diff, restart, func() error {
+		return sm.manager.SetOverride(sm.ctx, serviceName, content)
+	})
+}
+
+// printDryRun 输出将要写入的单元文件（修改时为差异）和检查结果，不写入任何文件
+func (sm *ServiceManager) printDryRun(cfg *autostart.ServiceConfig, diff string) error {
+	var problems []string
+	var verifyErr *autostart.VerifyError
+	if err := sm.manager.Verify(sm.ctx, cfg); errors.As(err, &verifyErr) {
+		problems = verifyErr.Problems
+	} else if err != nil {
+		return err
+	}
+
+	if sm.JSON {
+		result := map[string]interface{}{"name": cfg.Name, "valid": len(problems) == 0, "problems": problems}
+		if diff != "" {
+			result["diff"] = diff
+		} else {
+			result["units"] = sm.manager.RenderUnits(cfg)
+		}
+		if problems == nil {
+			result["problems"] = []string{}
+		}
+		return utils.PrintJSON(result)
+	}
+
+	if diff != "" {
+		fmt.Print(diff)
+	} else {
+		for _, unit := range sm.manager.RenderUnits(cfg) {
+			fmt.Printf("# %s\n%s\n", unit.Path, unit.Content)
+		}
+	}
+	if len(problems) > 0 {
+		fmt.Println("✗ Verification failed:")
+		for _, problem := range problems {
+			fmt.Printf("  - %s\n", problem)
+		}
+		return fmt.Errorf("service '%s' would not start, nothing written", cfg.Name)
+	}
+	fmt.Println("✓ Verification passed (dry run, nothing written)")
+	return nil
+}
+
+// readSource 读取文件内容，- 表示标准输入
+func readSource(source string) (string, error) {
+	var data []byte
+	var err error
+	if source == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	return string(data), nil
+}
+
+// applyChange 显示差异，确认后执行修改，按需重启
+func (sm *ServiceManager) applyChange(serviceName, diff string, restart bool, apply func() error) error {
+	if diff == "" {
+		if sm.JSON {
+			return utils.PrintJSON(map[string]interface{}{"name": serviceName, "changed": false})
+		}
+		fmt.Println("No changes.")
+		return nil
+	}
+
+	if sm.JSON {
+		// 脚本调用时无法交互确认
+		if !sm.AssumeYes {
+			return fmt.Errorf("editing '%s' with --output=json requires --yes", serviceName)
+		}
+	} else {
+		fmt.Print(diff)
+		if !sm.AssumeYes && !confirm("Apply these changes?") {
+			fmt.Println("Edit cancelled.")
+			return nil
+		}
+	}
+
+	if err := apply(); err != nil {
+		return err
+	}
+	if restart {
+		if err := sm.manager.Restart(sm.ctx, serviceName); err != nil {
+			return err
+		}
+	}
+
+	if sm.JSON {
+		result := map[string]interface{}{"name": serviceName, "changed": true, "diff": diff, "restarted": restart}
+		if status, err := sm.manager.Status(sm.ctx, serviceName); err == nil {
+			result["status"] = status
+		}
+		return utils.PrintJSON(result)
+	}
+
+	fmt.Printf("✓ Service '%s' updated\n", serviceName)
+	if restart {
+		sm.printActiveStatus(serviceName)
+	} else {
+		fmt.Printf("Run '%s restart %s' to apply the changes to the running service.\n", sm.command, serviceName)
+	}
 	return nil
 }
 
+// confirm 询问用户是否继续
+func confirm(prompt string) bool {
+	fmt.Printf("%s (y/N): ", prompt)
+
+	var response string
+	fmt.Scanln(&response)
+
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
+
 // printCurrentConfig 打印当前配置
-func (sm *ServiceManager) printCurrentConfig(serviceName string, cfg *config.ServiceConfig) {
+func (sm *ServiceManager) printCurrentConfig(serviceName string, cfg *autostart.ServiceConfig) {
 	fmt.Printf("Current configuration for service '%s':\n", serviceName)
 	fmt.Printf("Description: %s\n", cfg.Description)
 	fmt.Printf("ExecStart: %s\n", cfg.ExecStart)
@@ -336,36 +579,76 @@ func (sm *ServiceManager) printCurrentConfig(serviceName string, cfg *config.Ser
 			fmt.Printf("  %s=%s\n", k, v)
 		}
 	}
+	for _, file := range cfg.EnvironmentFile {
+		fmt.Printf("EnvironmentFile: %s\n", file)
+	}
+	if cfg.Timer != nil {
+		fmt.Printf("OnCalendar: %s\n", strings.Join(cfg.Timer.OnCalendar, "; "))
+		fmt.Printf("Persistent: %t\n", cfg.Timer.Persistent)
+		if cfg.Timer.RandomizedDelay != "" {
+			fmt.Printf("RandomizedDelay: %s\n", cfg.Timer.RandomizedDelay)
+		}
+	}
+
+	printLimit := func(key, value string) {
+		if value != "" {
+			fmt.Printf("%s: %s\n", key, value)
+		}
+	}
+	printLimit("MemoryMax", cfg.MemoryMax)
+	printLimit("MemoryHigh", cfg.MemoryHigh)
+	printLimit("CPUQuota", cfg.CPUQuota)
+	printLimit("TasksMax", cfg.TasksMax)
+	printLimit("LimitNOFILE", cfg.LimitNOFILE)
+	if cfg.Nice != nil {
+		fmt.Printf("Nice: %d\n", *cfg.Nice)
+	}
+	if cfg.OOMScoreAdjust != nil {
+		fmt.Printf("OOMScoreAdjust: %d\n", *cfg.OOMScoreAdjust)
+	}
+	printLimit("ProtectSystem", cfg.ProtectSystem)
+	if cfg.PrivateTmp != nil {
+		fmt.Printf("PrivateTmp: %t\n", *cfg.PrivateTmp)
+	}
+	if cfg.NoNewPrivileges != nil {
+		fmt.Printf("NoNewPrivileges: %t\n", *cfg.NoNewPrivileges)
+	}
+	if len(cfg.ReadWritePaths) > 0 {
+		fmt.Printf("ReadWritePaths: %s\n", strings.Join(cfg.ReadWritePaths, " "))
+	}
 
-	configFile := filepath.Join(config.ConfigDir, serviceName+".json")
-	fmt.Println("\nTo modify the configuration, edit the JSON file directly:")
-	fmt.Printf("  sudo nano %s\n", configFile)
-	fmt.Println("\nAfter editing, recreate the service:")
-	fmt.Printf("  sudo %s remove %s\n", ToolName, serviceName)
-	fmt.Printf("  sudo %s add %s \"<new-exec-start>\" [new-options]\n", ToolName, serviceName)
+	if override, err := sm.manager.Override(serviceName); err == nil && override != "" {
+		fmt.Printf("Override: %s\n", sm.manager.OverridePath(serviceName))
+	}
+
+	fmt.Println("\nTo modify the configuration, pass the options to change:")
+	fmt.Printf("  %s edit %s --memory-max=2G --env=KEY=VALUE [--now]\n", sm.sudoCommand(), serviceName)
+	fmt.Printf("  %s edit %s --unset=memory-max --unset=env:KEY\n", sm.sudoCommand(), serviceName)
+	fmt.Println("\nLocal tweaks that survive regeneration go to the drop-in override:")
+	fmt.Printf("  %s override %s <file>\n", sm.sudoCommand(), serviceName)
 }
 
 // StartService 启动服务
 func (sm *ServiceManager) StartService(serviceName string) error {
-	fullServiceName := fmt.Sprintf("autostart-%s", serviceName)
-	cmd := exec.Command("systemctl", "start", fullServiceName)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to start service: %w", err)
+	if err := sm.manager.Start(sm.ctx, serviceName); err != nil {
+		return err
+	}
+	if sm.JSON {
+		return sm.printStatus(serviceName)
 	}
 
 	fmt.Printf("✓ Service '%s' started successfully!\n", serviceName)
-	fmt.Println("\nCurrent status:")
-	status := sm.getServiceActiveStatus(serviceName)
-	fmt.Printf("Status: %s\n", status)
+	sm.printActiveStatus(serviceName)
 	return nil
 }
 
 // StopService 停止服务
 func (sm *ServiceManager) StopService(serviceName string) error {
-	fullServiceName := fmt.Sprintf("autostart-%s", serviceName)
-	cmd := exec.Command("systemctl", "stop", fullServiceName)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to stop service: %w", err)
+	if err := sm.manager.Stop(sm.ctx, serviceName); err != nil {
+		return err
+	}
+	if sm.JSON {
+		return sm.printStatus(serviceName)
 	}
 
 	fmt.Printf("✓ Service '%s' stopped successfully!\n", serviceName)
@@ -374,34 +657,181 @@ func (sm *ServiceManager) StopService(serviceName string) error {
 
 // RestartService 重启服务
 func (sm *ServiceManager) RestartService(serviceName string) error {
-	fullServiceName := fmt.Sprintf("autostart-%s", serviceName)
-	cmd := exec.Command("systemctl", "restart", fullServiceName)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to restart service: %w", err)
+	if err := sm.manager.Restart(sm.ctx, serviceName); err != nil {
+		return err
+	}
+	if sm.JSON {
+		return sm.printStatus(serviceName)
 	}
 
 	fmt.Printf("✓ Service '%s' restarted successfully!\n", serviceName)
-	fmt.Println("\nCurrent status:")
-	status := sm.getServiceActiveStatus(serviceName)
-	fmt.Printf("Status: %s\n", status)
+	sm.printActiveStatus(serviceName)
 	return nil
 }
 
-// serviceExists 检查服务是否存在
+// printActiveStatus 打印启动、重启后的运行状态
+func (sm *ServiceManager) printActiveStatus(serviceName string) {
+	fmt.Println("\nCurrent status:")
+	if status, err := sm.manager.Status(sm.ctx, serviceName); err == nil {
+		fmt.Printf("Status: %s\n", status.ActiveStatus)
+	}
+}
+
+// ServiceExists 检查服务是否存在
 func (sm *ServiceManager) ServiceExists(serviceName string) bool {
-	servicePath := fmt.Sprintf("/etc/systemd/system/autostart-%s.service", serviceName)
-	_, err := os.Stat(servicePath)
-	return err == nil
+	return sm.manager.Exists(serviceName)
+}
+
+// Status 服务的结构化状态
+func (sm *ServiceManager) Status(serviceName string) (*autostart.ServiceInfo, error) {
+	return sm.manager.Status(sm.ctx, serviceName)
 }
 
-// showServiceBriefStatus 显示服务简要状态
+// ShowServiceBriefStatus 显示服务简要状态
 func (sm *ServiceManager) ShowServiceBriefStatus(serviceName string) {
-	fullServiceName := fmt.Sprintf("autostart-%s", serviceName)
+	status, err := sm.manager.Status(sm.ctx, serviceName)
+	if err != nil {
+		return
+	}
+	fmt.Printf("Current status: autostart=%s, running=%s\n", status.AutostartStatus, status.ActiveStatus)
+}
+
+// ApplyManifest 按清单文件创建、修改服务，prune 为 true 时删除清单中没有的服务
+// 先输出计划，确认后按顺序执行，dryRun 为 true 时只输出计划
+func (sm *ServiceManager) ApplyManifest(source string, prune, dryRun bool) error {
+	content, err := readSource(source)
+	if err != nil {
+		return err
+	}
+	manifest, err := autostart.ParseManifest([]byte(content))
+	if err != nil {
+		return err
+	}
+	steps, err := sm.manager.Plan(sm.ctx, manifest, prune)
+	if err != nil {
+		return err
+	}
+
+	changes, problems := 0, 0
+	for _, step := range steps {
+		if step.Changed() {
+			changes++
+		}
+		problems += len(step.Problems)
+	}
+
+	if sm.JSON {
+		if problems == 0 && changes > 0 && !dryRun {
+			if !sm.AssumeYes {
+				return fmt.Errorf("applying a manifest with --output=json requires --yes")
+			}
+			if err := sm.manager.Apply(sm.ctx, steps); err != nil {
+				return err
+			}
+		}
+		if err := utils.PrintJSON(map[string]interface{}{
+			"steps": steps, "valid": problems == 0, "applied": problems == 0 && changes > 0 && !dryRun,
+		}); err != nil {
+			return err
+		}
+		if problems > 0 {
+			return &ReportedError{Err: fmt.Errorf("manifest has %d problem(s), nothing changed", problems)}
+		}
+		return nil
+	}
 
-	enabledCmd := exec.Command("systemctl", "is-enabled", fullServiceName)
-	enabledOutput, _ := enabledCmd.Output()
-	enabledStatus := strings.TrimSpace(string(enabledOutput))
+	sm.printPlan(steps)
+	if problems > 0 {
+		return fmt.Errorf("manifest has %d problem(s), nothing changed", problems)
+	}
+	if changes == 0 {
+		fmt.Println("No changes. Services match the manifest.")
+		return nil
+	}
+	if dryRun {
+		fmt.Println("Dry run, nothing changed.")
+		return nil
+	}
+	if !sm.AssumeYes && !confirm("Apply this plan?") {
+		fmt.Println("Apply cancelled.")
+		return nil
+	}
+
+	for _, step := range steps {
+		if !step.Changed() {
+			continue
+		}
+		if err := sm.manager.ApplyStep(sm.ctx, step); err != nil {
+			return err
+		}
+		fmt.Printf("✓ %s %s\n", step.Action, step.Name)
+	}
+	sm.warnLinger()
+	return nil
+}
+
+// printPlan 输出执行计划，修改的服务附带单元文件差异
+func (sm *ServiceManager) printPlan(steps []*autostart.PlanStep) {
+	symbols := map[autostart.PlanAction]string{
+		autostart.ActionCreate:    "+",
+		autostart.ActionUpdate:    "~",
+		autostart.ActionReplace:   "±",
+		autostart.ActionRemove:    "-",
+		autostart.ActionUnchanged: "=",
+		autostart.ActionUnmanaged: "?",
+	}
+	counts := make(map[autostart.PlanAction]int)
+
+	fmt.Println("Plan:")
+	for _, step := range steps {
+		counts[step.Action]++
+		note := ""
+		if step.Action == autostart.ActionUnmanaged {
+			note = "  (not in manifest, kept; use --prune to remove)"
+		}
+		fmt.Printf("  %s %-20s %s%s\n", symbols[step.Action], step.Name, step.Action, note)
+		for _, line := range strings.Split(strings.TrimSuffix(step.Diff, "\n"), "\n") {
+			if line != "" {
+				fmt.Printf("      %s\n", line)
+			}
+		}
+		for _, problem := range step.Problems {
+			fmt.Printf("      ✗ %s\n", problem)
+		}
+	}
+	fmt.Printf("\n%d to create, %d to update, %d to replace, %d to remove, %d unchanged, %d unmanaged\n",
+		counts[autostart.ActionCreate], counts[autostart.ActionUpdate], counts[autostart.ActionReplace],
+		counts[autostart.ActionRemove], counts[autostart.ActionUnchanged], counts[autostart.ActionUnmanaged])
+}
+
+// ExportManifest 把当前服务导出为清单，target 为空或 - 时输出到标准输出
+func (sm *ServiceManager) ExportManifest(target string) error {
+	manifest, skipped, err := sm.manager.Export()
+	if err != nil {
+		return err
+	}
+	for _, name := range skipped {
+		fmt.Fprintf(os.Stderr, "Warning: service '%s' has no saved configuration and was not exported\n", name)
+	}
+
+	var data []byte
+	if sm.JSON {
+		data, err = json.MarshalIndent(manifest, "", "  ")
+		data = append(data, '\n')
+	} else {
+		data, err = autostart.MarshalManifest(manifest)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
 
-	activeStatus := sm.getServiceActiveStatus(serviceName)
-	fmt.Printf("Current status: autostart=%s, running=%s\n", enabledStatus, activeStatus)
+	if target == "" || target == "-" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(target, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	fmt.Fprintf(os.Stderr, "✓ Exported %d service(s) to %s\n", len(manifest.Services), target)
+	return nil
 }
@@ -0,0 +1,170 @@
+package autostart
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// PlanAction 应用清单时对单个服务的操作
+type PlanAction string
+
+const (
+	// ActionCreate 创建服务，随后启用并启动
+	ActionCreate PlanAction = "create"
+	// ActionUpdate 重新生成单元文件，正在运行的服务会重启
+	ActionUpdate PlanAction = "update"
+	// ActionReplace 在普通服务和定时任务之间转换，删除后重新创建
+	ActionReplace PlanAction = "replace"
+	// ActionRemove 删除不在清单中的服务，只有 prune 时才有
+	ActionRemove PlanAction = "remove"
+	// ActionUnchanged 与清单一致，不需要操作
+	ActionUnchanged PlanAction = "unchanged"
+	// ActionUnmanaged 不在清单中，没有 prune 时保留
+	ActionUnmanaged PlanAction = "unmanaged"
+)
+
+// PlanStep 计划中的一步
+type PlanStep struct {
+	Name   string     `json:"name"`
+	Action PlanAction `json:"action"`
+	// Diff 修改时单元文件的差异
+	Diff string `json:"diff,omitempty"`
+	// Problems 按当前环境校验发现的问题，有问题时不应执行计划
+	Problems []string `json:"problems,omitempty"`
+	// Config 清单中的配置，删除和保留时为空
+	Config *ServiceConfig `json:"-"`
+}
+
+// Changed 这一步是否会修改服务
+func (step *PlanStep) Changed() bool {
+	return step.Action != ActionUnchanged && step.Action != ActionUnmanaged
+}
+
+// Plan 对比清单与现有的 autostart-* 服务，生成执行计划，不修改任何文件
+// 先删除（prune 为 true 时）再按清单顺序创建、修改，避免新服务与待删除的服务争用端口等资源
+func (sm *ServiceManager) Plan(ctx context.Context, manifest *Manifest, prune bool) ([]*PlanStep, error) {
+	names, err := sm.Names()
+	if err != nil {
+		return nil, err
+	}
+	declared := make(map[string]bool, len(manifest.Services))
+	for _, cfg := range manifest.Services {
+		declared[cfg.Name] = true
+	}
+
+	var steps []*PlanStep
+	for _, name := range names {
+		if declared[name] {
+			continue
+		}
+		action := ActionUnmanaged
+		if prune {
+			action = ActionRemove
+		}
+		steps = append(steps, &PlanStep{Name: name, Action: action})
+	}
+
+	for _, declaredCfg := range manifest.Services {
+		cfg := declaredCfg.Clone()
+		sm.scopeConfig(cfg)
+		step, err := sm.planService(ctx, cfg)
+		if err != nil {
+			return nil, fmt.Errorf("service %s: %w", cfg.Name, err)
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+// planService 单个清单服务的操作
+func (sm *ServiceManager) planService(ctx context.Context, cfg *ServiceConfig) (*PlanStep, error) {
+	step := &PlanStep{Name: cfg.Name, Config: cfg}
+	switch {
+	case !sm.Exists(cfg.Name):
+		step.Action = ActionCreate
+	case (cfg.Timer != nil) != sm.IsTimer(cfg.Name):
+		step.Action = ActionReplace
+	default:
+		diff, err := sm.UnitDiff(cfg)
+		if err != nil {
+			return nil, err
+		}
+		step.Diff = diff
+		step.Action = ActionUnchanged
+		// 单元文件相同但保存的配置不同（或没有保存配置）时也要更新，保证 export 的结果与清单一致
+		if diff != "" || !sm.sameConfig(cfg) {
+			step.Action = ActionUpdate
+		}
+	}
+	if step.Action == ActionUnchanged {
+		return step, nil
+	}
+
+	if err := sm.checkCalendars(ctx, cfg); err != nil {
+		step.Problems = append(step.Problems, err.Error())
+	}
+	var verifyErr *VerifyError
+	if err := sm.Verify(ctx, cfg); errors.As(err, &verifyErr) {
+		step.Problems = append(step.Problems, verifyErr.Problems...)
+	} else if err != nil {
+		return nil, err
+	}
+	return step, nil
+}
+
+// sameConfig 保存的配置是否与 cfg 相同
+func (sm *ServiceManager) sameConfig(cfg *ServiceConfig) bool {
+	current, err := sm.Config(cfg.Name)
+	if err != nil {
+		return false
+	}
+	currentData, _ := json.Marshal(current)
+	data, _ := json.Marshal(cfg)
+	return string(currentData) == string(data)
+}
+
+// Apply 按顺序执行计划，遇到错误立即停止，已执行的步骤不会回滚
+func (sm *ServiceManager) Apply(ctx context.Context, steps []*PlanStep) error {
+	for _, step := range steps {
+		if err := sm.ApplyStep(ctx, step); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ApplyStep 执行计划中的一步：创建的服务会启用并启动，修改的服务正在运行时重启（定时任务重启定时器）
+func (sm *ServiceManager) ApplyStep(ctx context.Context, step *PlanStep) error {
+	var err error
+	switch step.Action {
+	case ActionCreate:
+		err = sm.create(ctx, step.Config)
+	case ActionUpdate:
+		if err = sm.Update(ctx, step.Config); err == nil {
+			err = sm.control(ctx, "try-restart", step.Name)
+		}
+	case ActionReplace:
+		if err = sm.Remove(ctx, step.Name); err == nil {
+			err = sm.create(ctx, step.Config)
+		}
+	case ActionRemove:
+		err = sm.Remove(ctx, step.Name)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to %s service %s: %w", step.Action, step.Name, err)
+	}
+	return nil
+}
+
+// create 创建服务并启用、启动
+func (sm *ServiceManager) create(ctx context.Context, cfg *ServiceConfig) error {
+	if err := sm.Add(ctx, cfg); err != nil {
+		return err
+	}
+	if err := sm.Enable(ctx, cfg.Name); err != nil {
+		return err
+	}
+	return sm.Start(ctx, cfg.Name)
+}
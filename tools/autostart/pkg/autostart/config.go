@@ -0,0 +1,93 @@
+package autostart
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const ConfigDir = "/etc/autostart-manager"
+
+type ServiceConfig struct {
+	Name         string            `json:"name" yaml:"name"`
+	ExecStart    string            `json:"exec_start" yaml:"exec_start"`       // 完整的启动命令
+	WorkDir      string            `json:"work_dir" yaml:"work_dir"`           // 工作目录
+	User         string            `json:"user" yaml:"user"`                   // 运行用户
+	Group        string            `json:"group" yaml:"group,omitempty"`       // 运行组
+	Description  string            `json:"description" yaml:"description"`     // 服务描述
+	Env          map[string]string `json:"env" yaml:"env,omitempty"`           // 环境变量
+	Restart      string            `json:"restart" yaml:"restart"`             // 重启策略
+	RestartSec   int               `json:"restart_sec" yaml:"restart_sec"`     // 重启间隔
+	KillMode     string            `json:"kill_mode" yaml:"kill_mode"`         // 终止模式
+	KillSignal   string            `json:"kill_signal" yaml:"kill_signal"`     // 终止信号
+	TimeoutStart int               `json:"timeout_start" yaml:"timeout_start"` // 启动超时
+	TimeoutStop  int               `json:"timeout_stop" yaml:"timeout_stop"`   // 停止超时
+	After        []string          `json:"after" yaml:"after"`                 // 依赖服务
+	Wants        []string          `json:"wants" yaml:"wants"`                 // 期望服务
+	Requires     []string          `json:"requires" yaml:"requires,omitempty"` // 必需服务
+
+	// 资源限制（cgroup），为空时不写入单元文件
+	MemoryMax      string `json:"memory_max,omitempty" yaml:"memory_max,omitempty"`             // 内存硬限制，如 2G、80%、infinity
+	MemoryHigh     string `json:"memory_high,omitempty" yaml:"memory_high,omitempty"`           // 内存软限制，超过后被限流回收
+	CPUQuota       string `json:"cpu_quota,omitempty" yaml:"cpu_quota,omitempty"`               // CPU 配额，如 200% 表示两个核
+	TasksMax       string `json:"tasks_max,omitempty" yaml:"tasks_max,omitempty"`               // 最大线程/进程数
+	LimitNOFILE    string `json:"limit_nofile,omitempty" yaml:"limit_nofile,omitempty"`         // 文件描述符上限，如 65535 或 软:硬
+	Nice           *int   `json:"nice,omitempty" yaml:"nice,omitempty"`                         // 调度优先级 -20~19
+	OOMScoreAdjust *int   `json:"oom_score_adjust,omitempty" yaml:"oom_score_adjust,omitempty"` // OOM 优先级 -1000~1000，越小越不容易被杀
+
+	// 沙箱
+	ProtectSystem   string   `json:"protect_system,omitempty" yaml:"protect_system,omitempty"` // true、full、strict
+	PrivateTmp      *bool    `json:"private_tmp,omitempty" yaml:"private_tmp,omitempty"`
+	NoNewPrivileges *bool    `json:"no_new_privileges,omitempty" yaml:"no_new_privileges,omitempty"`
+	ReadWritePaths  []string `json:"read_write_paths,omitempty" yaml:"read_write_paths,omitempty"` // ProtectSystem 下仍可写的路径
+	EnvironmentFile []string `json:"environment_file,omitempty" yaml:"environment_file,omitempty"` // 环境变量文件，- 开头表示文件不存在时忽略
+
+	// Timer 不为空时是定时任务，见 TimerConfig
+	Timer *TimerConfig `json:"timer,omitempty" yaml:"timer,omitempty"`
+}
+
+// SaveServiceConfig 保存系统服务的配置到文件
+func SaveServiceConfig(config *ServiceConfig) error {
+	return saveServiceConfig(ConfigDir, config)
+}
+
+// saveServiceConfig 保存服务配置到指定目录
+func saveServiceConfig(dir string, config *ServiceConfig) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	configFile := filepath.Join(dir, config.Name+".json")
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := os.WriteFile(configFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadServiceConfig 从文件加载系统服务的配置
+func LoadServiceConfig(name string) (*ServiceConfig, error) {
+	return loadServiceConfig(ConfigDir, name)
+}
+
+// loadServiceConfig 从指定目录加载服务配置
+func loadServiceConfig(dir, name string) (*ServiceConfig, error) {
+	configFile := filepath.Join(dir, name+".json")
+	data, err := os.ReadFile(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var config ServiceConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
+	}
+
+	return &config, nil
+}
@@ -0,0 +1,124 @@
+package autostart
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext 差异前后保留的上下文行数
+const diffContext = 3
+
+// diffLine 逐行比较的结果，op 为 ' '、'-' 或 '+'
+type diffLine struct {
+	op   byte
+	text string
+	// 在新旧文件中的行号（从 0 开始）
+	oldIndex, newIndex int
+}
+
+// UnifiedDiff 生成 unified 格式的差异，内容相同时返回空字符串
+func UnifiedDiff(oldName, newName, oldText, newText string) string {
+	if oldText == newText {
+		return ""
+	}
+
+	lines := diffLines(splitLines(oldText), splitLines(newText))
+
+	var out strings.Builder
+	out.WriteString(fmt.Sprintf("--- %s\n+++ %s\n", oldName, newName))
+	for _, hunk := range diffHunks(lines) {
+		writeHunk(&out, lines[hunk[0]:hunk[1]])
+	}
+	return out.String()
+}
+
+// splitLines 按行拆分，忽略末尾的换行
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines 基于最长公共子序列逐行比较，单元文件很小，直接用动态规划
+func diffLines(a, b []string) []diffLine {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i], i, j})
+			i++
+			j++
+		case j >= len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, diffLine{'-', a[i], i, j})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j], i, j})
+			j++
+		}
+	}
+	return lines
+}
+
+// diffHunks 把有变化的行连同上下文分组，返回每组的 [起, 止) 下标
+func diffHunks(lines []diffLine) [][2]int {
+	var hunks [][2]int
+	for i, line := range lines {
+		if line.op == ' ' {
+			continue
+		}
+		start, end := max(i-diffContext, 0), min(i+diffContext+1, len(lines))
+		// 与上一组的上下文重叠时合并
+		if n := len(hunks); n > 0 && start <= hunks[n-1][1] {
+			hunks[n-1][1] = end
+			continue
+		}
+		hunks = append(hunks, [2]int{start, end})
+	}
+	return hunks
+}
+
+// writeHunk 写入一组差异，包括 @@ 行
+func writeHunk(out *strings.Builder, lines []diffLine) {
+	oldStart, newStart := lines[0].oldIndex+1, lines[0].newIndex+1
+	oldCount, newCount := 0, 0
+	for _, line := range lines {
+		if line.op != '+' {
+			oldCount++
+		}
+		if line.op != '-' {
+			newCount++
+		}
+	}
+	// 与 diff -u 一致，没有行时起始行号为前一行
+	if oldCount == 0 {
+		oldStart--
+	}
+	if newCount == 0 {
+		newStart--
+	}
+
+	out.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@\n", oldStart, oldCount, newStart, newCount))
+	for _, line := range lines {
+		out.WriteByte(line.op)
+		out.WriteString(line.text)
+		out.WriteByte('\n')
+	}
+}
@@ -0,0 +1,189 @@
+package autostart
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// OverrideFile 本地修改使用的 drop-in 文件名，重新生成单元文件时不会覆盖
+const OverrideFile = "override.conf"
+
+// Clone 深拷贝配置，修改前用来保留原配置
+func (cfg *ServiceConfig) Clone() *ServiceConfig {
+	data, _ := json.Marshal(cfg)
+	clone := &ServiceConfig{}
+	json.Unmarshal(data, clone)
+	return clone
+}
+
+// Update 用修改后的配置重新生成单元文件并 daemon-reload，自启状态不变，不会重启服务
+func (sm *ServiceManager) Update(ctx context.Context, cfg *ServiceConfig) error {
+	sm.scopeConfig(cfg)
+	if err := ValidateConfig(cfg); err != nil {
+		return err
+	}
+	if !sm.Exists(cfg.Name) {
+		return fmt.Errorf("%w: %s", ErrNotFound, cfg.Name)
+	}
+	if (cfg.Timer != nil) != sm.IsTimer(cfg.Name) {
+		return fmt.Errorf("cannot convert '%s' between service and scheduled job, remove and add it again", cfg.Name)
+	}
+	if err := sm.checkCalendars(ctx, cfg); err != nil {
+		return err
+	}
+	if err := sm.Verify(ctx, cfg); err != nil {
+		return err
+	}
+
+	// 先写单元文件，失败时保存的配置仍与正在使用的单元文件一致
+	content := sm.buildUnit(cfg)
+	if err := writeFileAtomic(sm.UnitPath(cfg.Name), []byte(content)); err != nil {
+		return fmt.Errorf("failed to write service file: %w", err)
+	}
+	if cfg.Timer != nil {
+		if err := writeFileAtomic(sm.TimerPath(cfg.Name), []byte(BuildTimerContent(cfg, UnitName(cfg.Name)))); err != nil {
+			return fmt.Errorf("failed to write timer file: %w", err)
+		}
+	}
+	if err := sm.saveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save service config: %w", err)
+	}
+	if _, err := sm.systemctl(ctx, "daemon-reload"); err != nil {
+		return fmt.Errorf("failed to reload systemd: %w", err)
+	}
+	return nil
+}
+
+// UnitDiff 当前单元文件与按 cfg 重新生成的内容之间的差异，定时任务包括定时器，没有变化时返回空字符串
+func (sm *ServiceManager) UnitDiff(cfg *ServiceConfig) (string, error) {
+	current, err := os.ReadFile(sm.UnitPath(cfg.Name))
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, cfg.Name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read service file: %w", err)
+	}
+	path := sm.UnitPath(cfg.Name)
+	diff := UnifiedDiff(path, path, string(current), sm.buildUnit(cfg))
+
+	if cfg.Timer != nil {
+		// 定时器文件不存在时按空文件比较
+		timer, _ := os.ReadFile(sm.TimerPath(cfg.Name))
+		path = sm.TimerPath(cfg.Name)
+		diff += UnifiedDiff(path, path, string(timer), BuildTimerContent(cfg, UnitName(cfg.Name)))
+	}
+	return diff, nil
+}
+
+// Override 读取 drop-in 覆盖文件，不存在时返回空字符串
+func (sm *ServiceManager) Override(name string) (string, error) {
+	if !sm.Exists(name) {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	data, err := os.ReadFile(sm.OverridePath(name))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read override file: %w", err)
+	}
+	return string(data), nil
+}
+
+// SetOverride 写入 drop-in 覆盖文件并 daemon-reload，content 为空时删除覆盖文件
+func (sm *ServiceManager) SetOverride(ctx context.Context, name, content string) error {
+	if !sm.Exists(name) {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	if strings.TrimSpace(content) == "" {
+		if err := os.Remove(sm.OverridePath(name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove override file: %w", err)
+		}
+		os.Remove(sm.DropInDir(name)) // 目录中还有其他文件时删除失败，忽略
+	} else {
+		if !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		if err := os.MkdirAll(sm.DropInDir(name), 0755); err != nil {
+			return fmt.Errorf("failed to create drop-in directory: %w", err)
+		}
+		if err := writeFileAtomic(sm.OverridePath(name), []byte(content)); err != nil {
+			return fmt.Errorf("failed to write override file: %w", err)
+		}
+	}
+
+	if _, err := sm.systemctl(ctx, "daemon-reload"); err != nil {
+		return fmt.Errorf("failed to reload systemd: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic 先写临时文件再改名，避免 systemd 读到写了一半的单元文件
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// unsetOption 把选项恢复为默认值，列表类选项清空，env 可以用 env:KEY 只删除一个变量
+func unsetOption(cfg *ServiceConfig, key string) error {
+	if name, ok := strings.CutPrefix(key, "env:"); ok {
+		if _, exists := cfg.Env[name]; !exists {
+			return fmt.Errorf("environment variable not set: %s", name)
+		}
+		delete(cfg.Env, name)
+		return nil
+	}
+
+	defaults := NewServiceConfig(cfg.Name, cfg.ExecStart)
+	if cfg.Timer != nil {
+		defaults = NewTimerServiceConfig(cfg.Name, cfg.ExecStart)
+	}
+	unsetters := map[string]func(){
+		"workdir":           func() { cfg.WorkDir = defaults.WorkDir },
+		"user":              func() { cfg.User = defaults.User },
+		"group":             func() { cfg.Group = defaults.Group },
+		"description":       func() { cfg.Description = defaults.Description },
+		"env":               func() { cfg.Env = defaults.Env },
+		"restart":           func() { cfg.Restart = defaults.Restart },
+		"restart-sec":       func() { cfg.RestartSec = defaults.RestartSec },
+		"kill-mode":         func() { cfg.KillMode = defaults.KillMode },
+		"kill-signal":       func() { cfg.KillSignal = defaults.KillSignal },
+		"timeout-start":     func() { cfg.TimeoutStart = defaults.TimeoutStart },
+		"timeout-stop":      func() { cfg.TimeoutStop = defaults.TimeoutStop },
+		"after":             func() { cfg.After = []string{} },
+		"wants":             func() { cfg.Wants = []string{} },
+		"requires":          func() { cfg.Requires = []string{} },
+		"memory-max":        func() { cfg.MemoryMax = "" },
+		"memory-high":       func() { cfg.MemoryHigh = "" },
+		"cpu-quota":         func() { cfg.CPUQuota = "" },
+		"tasks-max":         func() { cfg.TasksMax = "" },
+		"limit-nofile":      func() { cfg.LimitNOFILE = "" },
+		"nice":              func() { cfg.Nice = nil },
+		"oom-score-adjust":  func() { cfg.OOMScoreAdjust = nil },
+		"protect-system":    func() { cfg.ProtectSystem = "" },
+		"private-tmp":       func() { cfg.PrivateTmp = nil },
+		"no-new-privileges": func() { cfg.NoNewPrivileges = nil },
+		"read-write-path":   func() { cfg.ReadWritePaths = nil },
+		"environment-file":  func() { cfg.EnvironmentFile = nil },
+		"persistent":        func() { ensureTimer(cfg).Persistent = false },
+		"randomized-delay":  func() { ensureTimer(cfg).RandomizedDelay = "" },
+	}
+
+	unset, exists := unsetters[key]
+	if !exists {
+		return fmt.Errorf("unknown option for unset: %s", key)
+	}
+	unset()
+	return nil
+}
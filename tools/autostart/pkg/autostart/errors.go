@@ -0,0 +1,46 @@
+package autostart
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrNotFound 服务不存在
+	ErrNotFound = errors.New("service does not exist")
+	// ErrAlreadyExists 服务已存在
+	ErrAlreadyExists = errors.New("service already exists")
+	// ErrConfigNotFound 服务存在但没有 autostart 保存的配置（例如手工创建的单元文件）
+	ErrConfigNotFound = errors.New("service configuration not found")
+	// ErrUnsupported 当前系统不是 Linux 或没有 systemd
+	ErrUnsupported = errors.New("only Linux systems with systemd are supported")
+)
+
+// CommandError systemctl、journalctl 执行失败，保留命令输出便于排查
+type CommandError struct {
+	Args   []string
+	Output string
+	Err    error
+}
+
+func (e *CommandError) Error() string {
+	if e.Output == "" {
+		return fmt.Sprintf("%s: %v", strings.Join(e.Args, " "), e.Err)
+	}
+	return fmt.Sprintf("%s: %v\nOutput: %s", strings.Join(e.Args, " "), e.Err, e.Output)
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// VerifyError 服务配置能通过格式校验，但按当前环境无法正常启动
+type VerifyError struct {
+	Name     string
+	Problems []string
+}
+
+func (e *VerifyError) Error() string {
+	return fmt.Sprintf("service '%s' failed verification: %s", e.Name, strings.Join(e.Problems, "; "))
+}
@@ -0,0 +1,118 @@
+package autostart
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JournalQuery 日志来源的查询条件，由 LogOptions 校验后生成
+type JournalQuery struct {
+	// Lines 最新的多少条，0 表示不限制；跟踪时为开始跟踪前输出的条数
+	Lines int
+	// Since、Until 时间范围，零值不限制
+	Since time.Time
+	Until time.Time
+	// MaxPriority 只返回级别小于等于该值（更重要）的日志，7 表示全部
+	MaxPriority int
+}
+
+// JournalSource 服务日志的来源，ServiceManager 默认通过 journalctl 读取，
+// 测试或没有 journald 的环境可以设置 ServiceManager.Journal 替换
+type JournalSource interface {
+	// Entries 按时间正序返回单元的日志
+	Entries(ctx context.Context, unit string, query JournalQuery) ([]*LogEntry, error)
+	// Follow 先输出最新的 query.Lines 条，然后持续输出新日志，直到 ctx 取消（返回 nil）或 fn 返回错误
+	Follow(ctx context.Context, unit string, query JournalQuery, fn func(*LogEntry) error) error
+}
+
+// journalctlSource 通过 journalctl -o json 读取日志，用户服务加 --user
+type journalctlSource struct {
+	sm *ServiceManager
+}
+
+func (j *journalctlSource) Entries(ctx context.Context, unit string, query JournalQuery) ([]*LogEntry, error) {
+	output, err := j.sm.journalctl(ctx, journalArgs(unit, query, false)...)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]*LogEntry, 0, query.Lines)
+	for _, line := range strings.Split(output, "\n") {
+		if entry := parseJournalLine(line); entry != nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// Follow 持续读取 journalctl -f 的输出，不受 ServiceManager.Timeout 限制
+func (j *journalctlSource) Follow(ctx context.Context, unit string, query JournalQuery, fn func(*LogEntry) error) error {
+	args := journalArgs(unit, query, true)
+	if j.sm.User {
+		args = append([]string{"--user"}, args...)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "journalctl", args...)
+	cmd.Env = j.sm.userEnv()
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return &CommandError{Args: append([]string{"journalctl"}, args...), Err: err}
+	}
+
+	// 日志消息可能很长（如异常堆栈），放宽单行长度限制
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		entry := parseJournalLine(scanner.Text())
+		if entry == nil {
+			continue
+		}
+		if err := fn(entry); err != nil {
+			cancel()
+			cmd.Wait()
+			return err
+		}
+	}
+
+	err = cmd.Wait()
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		return &CommandError{Args: append([]string{"journalctl"}, args...), Output: strings.TrimSpace(stderr.String()), Err: err}
+	}
+	return nil
+}
+
+// journalArgs journalctl 的参数，时间按本地时间传给 journalctl
+func journalArgs(unit string, query JournalQuery, follow bool) []string {
+	args := []string{"-u", unit, "-o", "json", "--no-pager"}
+	if follow {
+		args = append(args, "-f", "-n", strconv.Itoa(query.Lines))
+	} else if query.Lines > 0 {
+		args = append(args, "-n", strconv.Itoa(query.Lines))
+	}
+	if !query.Since.IsZero() {
+		args = append(args, "--since="+query.Since.Local().Format(time.DateTime))
+	}
+	if !query.Until.IsZero() {
+		args = append(args, "--until="+query.Until.Local().Format(time.DateTime))
+	}
+	if query.MaxPriority < 7 {
+		args = append(args, fmt.Sprintf("--priority=%d", query.MaxPriority))
+	}
+	return args
+}
@@ -0,0 +1,300 @@
+package autostart
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// 资源限制与沙箱选项的处理和校验
+
+var validProtectSystem = []string{"true", "false", "yes", "no", "full", "strict"}
+
+func handleMemoryMaxOption(cfg *ServiceConfig, value string) error {
+	if _, err := parseMemorySize(value); err != nil {
+		return fmt.Errorf("invalid memory-max: %w", err)
+	}
+	cfg.MemoryMax = value
+	return nil
+}
+
+func handleMemoryHighOption(cfg *ServiceConfig, value string) error {
+	if _, err := parseMemorySize(value); err != nil {
+		return fmt.Errorf("invalid memory-high: %w", err)
+	}
+	cfg.MemoryHigh = value
+	return nil
+}
+
+func handleCPUQuotaOption(cfg *ServiceConfig, value string) error {
+	if err := checkCPUQuota(value); err != nil {
+		return err
+	}
+	cfg.CPUQuota = value
+	return nil
+}
+
+func handleTasksMaxOption(cfg *ServiceConfig, value string) error {
+	if err := checkTasksMax(value); err != nil {
+		return err
+	}
+	cfg.TasksMax = value
+	return nil
+}
+
+func handleLimitNOFILEOption(cfg *ServiceConfig, value string) error {
+	if err := checkLimitNOFILE(value); err != nil {
+		return err
+	}
+	cfg.LimitNOFILE = value
+	return nil
+}
+
+func handleNiceOption(cfg *ServiceConfig, value string) error {
+	var nice int
+	if err := parseAndSetIntOption(&nice, value, "nice", -20, 19); err != nil {
+		return err
+	}
+	cfg.Nice = &nice
+	return nil
+}
+
+func handleOOMScoreAdjustOption(cfg *ServiceConfig, value string) error {
+	var adjust int
+	if err := parseAndSetIntOption(&adjust, value, "oom-score-adjust", -1000, 1000); err != nil {
+		return err
+	}
+	cfg.OOMScoreAdjust = &adjust
+	return nil
+}
+
+func handleProtectSystemOption(cfg *ServiceConfig, value string) error {
+	if !contains(validProtectSystem, value) {
+		return fmt.Errorf("invalid protect-system: %s (valid: %s)", value, strings.Join(validProtectSystem, ", "))
+	}
+	cfg.ProtectSystem = value
+	return nil
+}
+
+func handlePrivateTmpOption(cfg *ServiceConfig, value string) error {
+	return parseAndSetBoolOption(&cfg.PrivateTmp, value, "private-tmp")
+}
+
+func handleNoNewPrivilegesOption(cfg *ServiceConfig, value string) error {
+	return parseAndSetBoolOption(&cfg.NoNewPrivileges, value, "no-new-privileges")
+}
+
+func handleReadWritePathOption(cfg *ServiceConfig, value string) error {
+	if err := checkUnitPath(value, "read-write-path"); err != nil {
+		return err
+	}
+	cfg.ReadWritePaths = appendUnique(cfg.ReadWritePaths, value)
+	return nil
+}
+
+func handleEnvironmentFileOption(cfg *ServiceConfig, value string) error {
+	if err := checkUnitPath(value, "environment-file"); err != nil {
+		return err
+	}
+	cfg.EnvironmentFile = appendUnique(cfg.EnvironmentFile, value)
+	return nil
+}
+
+// parseAndSetBoolOption 解析布尔选项，接受 systemd 认可的写法
+func parseAndSetBoolOption(target **bool, value, optName string) error {
+	var val bool
+	switch strings.ToLower(value) {
+	case "yes", "true", "on", "1":
+		val = true
+	case "no", "false", "off", "0":
+		val = false
+	default:
+		return fmt.Errorf("invalid %s value: %s (must be yes or no)", optName, value)
+	}
+	*target = &val
+	return nil
+}
+
+// validateResources 验证资源限制，JSON 配置可能被手工修改过，这里重新校验一遍
+func validateResources(cfg *ServiceConfig) error {
+	var maxBytes, highBytes int64
+	var err error
+	if cfg.MemoryMax != "" {
+		if maxBytes, err = parseMemorySize(cfg.MemoryMax); err != nil {
+			return fmt.Errorf("invalid memory-max: %w", err)
+		}
+	}
+	if cfg.MemoryHigh != "" {
+		if highBytes, err = parseMemorySize(cfg.MemoryHigh); err != nil {
+			return fmt.Errorf("invalid memory-high: %w", err)
+		}
+	}
+	// 只比较都是字节数的情况，百分比依赖机器内存
+	if maxBytes > 0 && highBytes > 0 && highBytes > maxBytes {
+		return fmt.Errorf("memory-high (%s) cannot exceed memory-max (%s)", cfg.MemoryHigh, cfg.MemoryMax)
+	}
+
+	if cfg.CPUQuota != "" {
+		if err := checkCPUQuota(cfg.CPUQuota); err != nil {
+			return err
+		}
+	}
+	if cfg.TasksMax != "" {
+		if err := checkTasksMax(cfg.TasksMax); err != nil {
+			return err
+		}
+	}
+	if cfg.LimitNOFILE != "" {
+		if err := checkLimitNOFILE(cfg.LimitNOFILE); err != nil {
+			return err
+		}
+	}
+	if cfg.Nice != nil && (*cfg.Nice < -20 || *cfg.Nice > 19) {
+		return fmt.Errorf("nice value out of range: %d (valid range: -20-19)", *cfg.Nice)
+	}
+	if cfg.OOMScoreAdjust != nil && (*cfg.OOMScoreAdjust < -1000 || *cfg.OOMScoreAdjust > 1000) {
+		return fmt.Errorf("oom-score-adjust value out of range: %d (valid range: -1000-1000)", *cfg.OOMScoreAdjust)
+	}
+
+	return nil
+}
+
+// validateSandbox 验证沙箱配置
+func validateSandbox(cfg *ServiceConfig) error {
+	if cfg.ProtectSystem != "" && !contains(validProtectSystem, cfg.ProtectSystem) {
+		return fmt.Errorf("invalid protect-system: %s (valid: %s)", cfg.ProtectSystem, strings.Join(validProtectSystem, ", "))
+	}
+
+	for _, path := range cfg.ReadWritePaths {
+		if err := checkUnitPath(path, "read-write-path"); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range cfg.EnvironmentFile {
+		if err := checkUnitPath(path, "environment-file"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// parseMemorySize 解析内存大小，支持 K/M/G/T 后缀（1024 进制）、百分比和 infinity
+// 返回字节数，百分比和 infinity 返回 0
+func parseMemorySize(value string) (int64, error) {
+	if value == "infinity" {
+		return 0, nil
+	}
+	if strings.HasSuffix(value, "%") {
+		if _, err := parsePercent(value); err != nil {
+			return 0, err
+		}
+		return 0, nil
+	}
+
+	number, multiplier := value, int64(1)
+	if n := len(value); n > 0 {
+		switch strings.ToUpper(value[n-1:]) {
+		case "K":
+			multiplier = 1 << 10
+		case "M":
+			multiplier = 1 << 20
+		case "G":
+			multiplier = 1 << 30
+		case "T":
+			multiplier = 1 << 40
+		}
+		if multiplier > 1 {
+			number = value[:n-1]
+		}
+	}
+
+	size, err := strconv.ParseInt(number, 10, 64)
+	if err != nil || size <= 0 {
+		return 0, fmt.Errorf("%s (use bytes with optional K/M/G/T suffix, a percentage or infinity)", value)
+	}
+	if size > (1<<62)/multiplier {
+		return 0, fmt.Errorf("%s is too large", value)
+	}
+
+	return size * multiplier, nil
+}
+
+// parsePercent 解析百分比，如 80%
+func parsePercent(value string) (float64, error) {
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if !strings.HasSuffix(value, "%") || err != nil || percent <= 0 {
+		return 0, fmt.Errorf("%s (must be a positive percentage such as 80%%)", value)
+	}
+	return percent, nil
+}
+
+// checkCPUQuota CPU 配额必须是百分比，可以超过 100%（多核）
+func checkCPUQuota(value string) error {
+	if _, err := parsePercent(value); err != nil {
+		return fmt.Errorf("invalid cpu-quota: %w", err)
+	}
+	return nil
+}
+
+// checkTasksMax 任务数支持整数、百分比和 infinity
+func checkTasksMax(value string) error {
+	if value == "infinity" {
+		return nil
+	}
+	if strings.HasSuffix(value, "%") {
+		if percent, err := parsePercent(value); err != nil || percent > 100 {
+			return fmt.Errorf("invalid tasks-max: %s (percentage must be in 0-100%%)", value)
+		}
+		return nil
+	}
+	if n, err := strconv.Atoi(value); err != nil || n <= 0 {
+		return fmt.Errorf("invalid tasks-max: %s (must be a positive integer, a percentage or infinity)", value)
+	}
+	return nil
+}
+
+// checkLimitNOFILE 文件描述符上限，格式为 数量 或 软限制:硬限制
+func checkLimitNOFILE(value string) error {
+	soft, hard, hasHard := strings.Cut(value, ":")
+	softN, err := parseRlimit(soft)
+	if err != nil {
+		return fmt.Errorf("invalid limit-nofile: %s (use N, infinity or SOFT:HARD)", value)
+	}
+	if !hasHard {
+		return nil
+	}
+	hardN, err := parseRlimit(hard)
+	if err != nil {
+		return fmt.Errorf("invalid limit-nofile: %s (use N, infinity or SOFT:HARD)", value)
+	}
+	if hardN >= 0 && (softN < 0 || softN > hardN) {
+		return fmt.Errorf("invalid limit-nofile: %s (soft limit cannot exceed hard limit)", value)
+	}
+	return nil
+}
+
+// parseRlimit 解析单个 rlimit 值，infinity 返回 -1
+func parseRlimit(value string) (int64, error) {
+	if value == "infinity" {
+		return -1, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid limit: %s", value)
+	}
+	return n, nil
+}
+
+// checkUnitPath 单元文件中的路径必须是绝对路径，- 前缀表示路径不存在时忽略
+func checkUnitPath(value, optName string) error {
+	path := strings.TrimPrefix(value, "-")
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("invalid %s: %s (must be an absolute path)", optName, value)
+	}
+	if strings.ContainsAny(path, " \t\n") {
+		return fmt.Errorf("invalid %s: %s (whitespace is not allowed)", optName, value)
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package autostart
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// LogEntry 一条 journal 日志
+type LogEntry struct {
+	Time       time.Time `json:"time"`
+	Priority   int       `json:"priority"` // syslog 级别，0 emerg ~ 7 debug
+	PID        int       `json:"pid,omitempty"`
+	Identifier string    `json:"identifier,omitempty"`
+	Message    string    `json:"message"`
+}
+
+// LogOptions 日志查询条件
+type LogOptions struct {
+	// Lines 返回最新的多少条，小于等于 0 时为 DefaultLogLines；跟踪时为开始跟踪前输出的条数
+	Lines int
+	// Since、Until 时间范围，零值不限制
+	Since time.Time
+	Until time.Time
+	// Priority 只返回该级别及更重要的日志，如 err、warning 或 0~7，为空时不过滤
+	Priority string
+	// Grep 消息匹配的正则表达式，没有大写字母时不区分大小写（与 journalctl --grep 相同）
+	// 查询时没有 Since 只在 Until（或现在）之前的 DefaultGrepWindow 内查找
+	Grep string
+}
+
+// DefaultGrepWindow 有 Grep 而没有 Since 时查询的时间范围，避免读取全部日志
+const DefaultGrepWindow = 24 * time.Hour
+
+// priorityNames syslog 级别名，下标即级别
+var priorityNames = []string{"emerg", "alert", "crit", "err", "warning", "notice", "info", "debug"}
+
+// Logs 读取服务最近的日志（journalctl 的原始输出），lines 小于等于 0 时读取 DefaultLogLines 行
+func (sm *ServiceManager) Logs(ctx context.Context, name string, lines int) (string, error) {
+	if lines <= 0 {
+		lines = DefaultLogLines
+	}
+	return sm.journalctl(ctx, "-u", UnitName(name), "-n", strconv.Itoa(lines), "--no-pager")
+}
+
+// LogEntries 以结构化的方式读取服务最近的日志，按时间正序
+func (sm *ServiceManager) LogEntries(ctx context.Context, name string, lines int) ([]*LogEntry, error) {
+	return sm.QueryLogs(ctx, name, LogOptions{Lines: lines})
+}
+
+// QueryLogs 按条件读取服务的日志，按时间正序
+// Grep 在本地过滤，会先读取时间范围内的全部日志，没有 Since 时只查找 DefaultGrepWindow 内的日志
+func (sm *ServiceManager) QueryLogs(ctx context.Context, name string, opts LogOptions) ([]*LogEntry, error) {
+	query, match, err := opts.compile()
+	if err != nil {
+		return nil, err
+	}
+	lines := query.Lines
+	if match != nil {
+		query.Lines = 0
+		if query.Since.IsZero() {
+			until := query.Until
+			if until.IsZero() {
+				until = time.Now()
+			}
+			query.Since = until.Add(-DefaultGrepWindow)
+		}
+	}
+
+	entries, err := sm.journal().Entries(ctx, UnitName(name), query)
+	if err != nil {
+		return nil, err
+	}
+	if match == nil {
+		return entries, nil
+	}
+
+	matched := make([]*LogEntry, 0, lines)
+	for _, entry := range entries {
+		if match.MatchString(entry.Message) {
+			matched = append(matched, entry)
+		}
+	}
+	if len(matched) > lines {
+		matched = matched[len(matched)-lines:]
+	}
+	return matched, nil
+}
+
+// FollowLogs 先输出最新的 opts.Lines 条日志，然后持续输出新日志，直到 ctx 取消（返回 nil）或 fn 返回错误
+// 有 Grep 时只输出匹配的日志，开始跟踪前的日志是在最新的 Lines 条中过滤
+func (sm *ServiceManager) FollowLogs(ctx context.Context, name string, opts LogOptions, fn func(*LogEntry) error) error {
+	query, match, err := opts.compile()
+	if err != nil {
+		return err
+	}
+	return sm.journal().Follow(ctx, UnitName(name), query, func(entry *LogEntry) error {
+		if match != nil && !match.MatchString(entry.Message) {
+			return nil
+		}
+		return fn(entry)
+	})
+}
+
+// journal 日志来源，没有设置 Journal 时使用 journalctl
+func (sm *ServiceManager) journal() JournalSource {
+	if sm.Journal != nil {
+		return sm.Journal
+	}
+	return &journalctlSource{sm: sm}
+}
+
+// compile 校验查询条件，转换为日志来源的查询和消息的匹配规则
+func (opts LogOptions) compile() (JournalQuery, *regexp.Regexp, error) {
+	query := JournalQuery{Lines: opts.Lines, Since: opts.Since, Until: opts.Until, MaxPriority: 7}
+	if query.Lines <= 0 {
+		query.Lines = DefaultLogLines
+	}
+	if !query.Since.IsZero() && !query.Until.IsZero() && query.Until.Before(query.Since) {
+		return query, nil, fmt.Errorf("until (%s) is before since (%s)", query.Until.Format(time.DateTime), query.Since.Format(time.DateTime))
+	}
+	if opts.Priority != "" {
+		priority, err := ParsePriority(opts.Priority)
+		if err != nil {
+			return query, nil, err
+		}
+		query.MaxPriority = priority
+	}
+
+	if opts.Grep == "" {
+		return query, nil, nil
+	}
+	pattern := opts.Grep
+	if !strings.ContainsFunc(pattern, unicode.IsUpper) {
+		pattern = "(?i)" + pattern
+	}
+	match, err := regexp.Compile(pattern)
+	if err != nil {
+		return query, nil, fmt.Errorf("invalid grep pattern: %w", err)
+	}
+	return query, match, nil
+}
+
+// ParsePriority 解析日志级别，支持 err、warning 等名称（也接受 error、warn）和 0~7
+func ParsePriority(value string) (int, error) {
+	name := strings.ToLower(strings.TrimSpace(value))
+	switch name {
+	case "error":
+		name = "err"
+	case "warn":
+		name = "warning"
+	}
+	for i, priorityName := range priorityNames {
+		if name == priorityName || name == strconv.Itoa(i) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid priority: %s (valid: %s or 0-7)", value, strings.Join(priorityNames, ", "))
+}
+
+// PriorityName 日志级别名，如 3 为 err
+func PriorityName(priority int) string {
+	if priority < 0 || priority >= len(priorityNames) {
+		return strconv.Itoa(priority)
+	}
+	return priorityNames[priority]
+}
+
+// ParseLogTime 解析日志时间范围，支持 2006-01-02 15:04:05、2006-01-02 15:04、2006-01-02、RFC 3339，
+// now、today、yesterday，以及 1h、30m 这样的时长（表示多久以前，可以带 - 前缀）
+func ParseLogTime(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch value {
+	case "now":
+		return now, nil
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+	if d, err := time.ParseDuration(strings.TrimPrefix(value, "-")); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{time.DateTime, "2006-01-02 15:04", time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, value, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %s (use 2006-01-02 15:04:05, today, yesterday or a duration such as 1h)", value)
+}
+
+// parseJournalLine 解析 journalctl -o json 的一行，不是日志（如 -- No entries --）时返回 nil
+func parseJournalLine(line string) *LogEntry {
+	var fields map[string]json.RawMessage
+	if line == "" || json.Unmarshal([]byte(line), &fields) != nil {
+		return nil
+	}
+	entry := &LogEntry{Message: journalField(fields["MESSAGE"]), Identifier: journalField(fields["SYSLOG_IDENTIFIER"])}
+	if usec, err := strconv.ParseInt(journalField(fields["__REALTIME_TIMESTAMP"]), 10, 64); err == nil {
+		entry.Time = time.UnixMicro(usec)
+	}
+	entry.Priority, _ = strconv.Atoi(journalField(fields["PRIORITY"]))
+	entry.PID, _ = strconv.Atoi(journalField(fields["_PID"]))
+	return entry
+}
+
+// journalField journal 字段是字符串，包含不可打印字符的消息是字节数组
+func journalField(raw json.RawMessage) string {
+	if len(raw) == 0 {
+		return ""
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+	var data []byte
+	var values []int
+	if err := json.Unmarshal(raw, &values); err == nil {
+		for _, v := range values {
+			data = append(data, byte(v))
+		}
+	}
+	return string(data)
+}
@@ -0,0 +1,234 @@
+package autostart
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeJournal 按 journalctl 的规则过滤内存中的日志，记录最后一次查询
+type fakeJournal struct {
+	entries []*LogEntry
+	unit    string
+	query   JournalQuery
+}
+
+func (j *fakeJournal) Entries(ctx context.Context, unit string, query JournalQuery) ([]*LogEntry, error) {
+	j.unit, j.query = unit, query
+	var list []*LogEntry
+	for _, entry := range j.entries {
+		if entry.Priority > query.MaxPriority ||
+			(!query.Since.IsZero() && entry.Time.Before(query.Since)) ||
+			(!query.Until.IsZero() && entry.Time.After(query.Until)) {
+			continue
+		}
+		list = append(list, entry)
+	}
+	if query.Lines > 0 && len(list) > query.Lines {
+		list = list[len(list)-query.Lines:]
+	}
+	return list, nil
+}
+
+func (j *fakeJournal) Follow(ctx context.Context, unit string, query JournalQuery, fn func(*LogEntry) error) error {
+	list, _ := j.Entries(ctx, unit, query)
+	for _, entry := range list {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// testJournal 最近 10 分钟每分钟一条日志，第 3、7 条是 err，第 5 条包含 Timeout
+func testJournal(now time.Time) *fakeJournal {
+	j := &fakeJournal{}
+	for i := 0; i < 10; i++ {
+		entry := &LogEntry{Time: now.Add(time.Duration(i-10) * time.Minute), Priority: 6, Message: "request handled"}
+		switch i {
+		case 3, 7:
+			entry.Priority, entry.Message = 3, "connection refused"
+		case 5:
+			entry.Message = "read Timeout after 30s"
+		}
+		j.entries = append(j.entries, entry)
+	}
+	return j
+}
+
+func messages(entries []*LogEntry) []string {
+	list := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, entry.Message)
+	}
+	return list
+}
+
+func TestLogOptionsCompile(t *testing.T) {
+	since := time.Date(2026, 10, 19, 8, 0, 0, 0, time.Local)
+	until := since.Add(time.Hour)
+	tests := []struct {
+		name     string
+		opts     LogOptions
+		want     JournalQuery
+		match    []string // 应匹配的消息
+		notMatch []string
+		wantErr  string
+	}{
+		{name: "defaults", want: JournalQuery{Lines: DefaultLogLines, MaxPriority: 7}},
+		{name: "lines and range", opts: LogOptions{Lines: 10, Since: since, Until: until}, want: JournalQuery{Lines: 10, Since: since, Until: until, MaxPriority: 7}},
+		{name: "priority name", opts: LogOptions{Priority: "warning"}, want: JournalQuery{Lines: DefaultLogLines, MaxPriority: 4}},
+		{name: "priority alias", opts: LogOptions{Priority: "Error"}, want: JournalQuery{Lines: DefaultLogLines, MaxPriority: 3}},
+		{name: "priority number", opts: LogOptions{Priority: "0"}, want: JournalQuery{Lines: DefaultLogLines, MaxPriority: 0}},
+		{name: "invalid priority", opts: LogOptions{Priority: "fatal"}, wantErr: "invalid priority"},
+		{name: "until before since", opts: LogOptions{Since: until, Until: since}, wantErr: "is before since"},
+		{name: "grep case-insensitive", opts: LogOptions{Grep: "timeout|refused"}, want: JournalQuery{Lines: DefaultLogLines, MaxPriority: 7},
+			match: []string{"read TIMEOUT", "Connection refused"}, notMatch: []string{"ok"}},
+		{name: "grep case-sensitive", opts: LogOptions{Grep: "Timeout"}, want: JournalQuery{Lines: DefaultLogLines, MaxPriority: 7},
+			match: []string{"read Timeout"}, notMatch: []string{"read timeout"}},
+		{name: "invalid grep", opts: LogOptions{Grep: "("}, wantErr: "invalid grep pattern"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, match, err := tt.opts.compile()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("compile error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("compile: %v", err)
+			}
+			if query != tt.want {
+				t.Errorf("query = %+v, want %+v", query, tt.want)
+			}
+			if (match != nil) != (tt.opts.Grep != "") {
+				t.Fatalf("match = %v for grep %q", match, tt.opts.Grep)
+			}
+			for _, message := range tt.match {
+				if !match.MatchString(message) {
+					t.Errorf("%q should match %q", tt.opts.Grep, message)
+				}
+			}
+			for _, message := range tt.notMatch {
+				if match.MatchString(message) {
+					t.Errorf("%q should not match %q", tt.opts.Grep, message)
+				}
+			}
+		})
+	}
+}
+
+func TestQueryLogs(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		opts LogOptions
+		want []string
+	}{
+		{"lines", LogOptions{Lines: 3}, []string{"connection refused", "request handled", "request handled"}},
+		{"priority", LogOptions{Priority: "err"}, []string{"connection refused", "connection refused"}},
+		{"since", LogOptions{Since: now.Add(-150 * time.Second)}, []string{"request handled", "request handled"}},
+		{"until", LogOptions{Lines: 1, Until: now.Add(-5 * time.Minute)}, []string{"read Timeout after 30s"}},
+		{"grep", LogOptions{Grep: "timeout|refused"}, []string{"connection refused", "read Timeout after 30s", "connection refused"}},
+		{"grep keeps latest lines", LogOptions{Lines: 1, Grep: "refused"}, []string{"connection refused"}},
+		{"grep with priority", LogOptions{Priority: "err", Grep: "timeout"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			journal := testJournal(now)
+			sm := &ServiceManager{Journal: journal}
+			entries, err := sm.QueryLogs(context.Background(), "demo", tt.opts)
+			if err != nil {
+				t.Fatalf("QueryLogs: %v", err)
+			}
+			if got := messages(entries); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("messages = %q, want %q", got, tt.want)
+			}
+			if journal.unit != "autostart-demo" {
+				t.Errorf("unit = %q", journal.unit)
+			}
+		})
+	}
+}
+
+func TestQueryLogsGrepWindow(t *testing.T) {
+	now := time.Now()
+	journal := testJournal(now)
+	// 两天前的匹配日志不在默认时间范围内
+	journal.entries = append([]*LogEntry{{Time: now.Add(-48 * time.Hour), Priority: 6, Message: "old timeout"}}, journal.entries...)
+	sm := &ServiceManager{Journal: journal}
+
+	entries, err := sm.QueryLogs(context.Background(), "demo", LogOptions{Grep: "timeout"})
+	if err != nil {
+		t.Fatalf("QueryLogs: %v", err)
+	}
+	if got := messages(entries); !reflect.DeepEqual(got, []string{"read Timeout after 30s"}) {
+		t.Errorf("messages = %q", got)
+	}
+	if journal.query.Lines != 0 {
+		t.Errorf("grep should read the whole window, lines = %d", journal.query.Lines)
+	}
+	if since := now.Add(-DefaultGrepWindow); journal.query.Since.Before(since) || journal.query.Since.After(time.Now().Add(-DefaultGrepWindow)) {
+		t.Errorf("since = %s, want about %s", journal.query.Since, since)
+	}
+
+	until := now.Add(-time.Hour)
+	if _, err := sm.QueryLogs(context.Background(), "demo", LogOptions{Grep: "timeout", Until: until}); err != nil {
+		t.Fatalf("QueryLogs: %v", err)
+	}
+	if !journal.query.Since.Equal(until.Add(-DefaultGrepWindow)) {
+		t.Errorf("since = %s, want %s", journal.query.Since, until.Add(-DefaultGrepWindow))
+	}
+
+	since := now.Add(-72 * time.Hour)
+	entries, err = sm.QueryLogs(context.Background(), "demo", LogOptions{Grep: "timeout", Since: since})
+	if err != nil {
+		t.Fatalf("QueryLogs: %v", err)
+	}
+	if !journal.query.Since.Equal(since) || len(entries) != 2 {
+		t.Errorf("since = %s, entries = %q", journal.query.Since, messages(entries))
+	}
+}
+
+func TestFollowLogsGrep(t *testing.T) {
+	sm := &ServiceManager{Journal: testJournal(time.Now())}
+	var got []string
+	err := sm.FollowLogs(context.Background(), "demo", LogOptions{Grep: "refused"}, func(entry *LogEntry) error {
+		got = append(got, entry.Message)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("FollowLogs: %v", err)
+	}
+	if !reflect.DeepEqual(got, []string{"connection refused", "connection refused"}) {
+		t.Errorf("messages = %q", got)
+	}
+}
+
+func TestParseJournalLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want *LogEntry
+	}{
+		{"empty", "", nil},
+		{"not json", "-- No entries --", nil},
+		{"entry", `{"__REALTIME_TIMESTAMP":"1760860800123456","PRIORITY":"3","_PID":"1234","SYSLOG_IDENTIFIER":"java","MESSAGE":"connection refused"}`,
+			&LogEntry{Time: time.UnixMicro(1760860800123456), Priority: 3, PID: 1234, Identifier: "java", Message: "connection refused"}},
+		{"binary message", `{"__REALTIME_TIMESTAMP":"1760860800000000","PRIORITY":"6","MESSAGE":[104,105,27,91,48,109]}`,
+			&LogEntry{Time: time.UnixMicro(1760860800000000), Priority: 6, Message: "hi\x1b[0m"}},
+		{"missing fields", `{"MESSAGE":"started"}`, &LogEntry{Message: "started"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseJournalLine(tt.line)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseJournalLine = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,322 @@
+// Package autostart 以 systemd 服务（autostart-<name>.service）的方式管理开机自启
+// 命令行工具和其他 Go 程序都通过 ServiceManager 操作服务，方法不输出到终端，也不等待用户确认
+package autostart
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// UnitPrefix systemd 服务名前缀，实际服务名为 autostart-<name>
+	UnitPrefix = "autostart-"
+	// SystemdDir 单元文件目录
+	SystemdDir = "/etc/systemd/system"
+	// DefaultTimeout 单条 systemctl、journalctl 命令的默认超时时间
+	DefaultTimeout = 30 * time.Second
+	// DefaultLogLines 默认读取的日志行数
+	DefaultLogLines = 50
+)
+
+// ServiceManager 服务管理器
+type ServiceManager struct {
+	// Timeout 单条命令的超时时间，为 0 时使用 DefaultTimeout
+	Timeout time.Duration
+	// User 为 true 时管理当前用户的服务（systemctl --user），不需要 root
+	User bool
+	// UnitDir 单元文件目录
+	UnitDir string
+	// ConfigDir autostart 保存服务配置的目录
+	ConfigDir string
+	// Journal 日志来源，为 nil 时使用 journalctl
+	Journal JournalSource
+}
+
+// NewServiceManager 创建管理系统服务的服务管理器，需要 root 权限
+func NewServiceManager() *ServiceManager {
+	return &ServiceManager{Timeout: DefaultTimeout, UnitDir: SystemdDir, ConfigDir: ConfigDir}
+}
+
+// UnitName systemd 服务名
+func UnitName(name string) string {
+	return UnitPrefix + name
+}
+
+// CheckSystem 检查当前系统是否支持，替代检查 autostart 命令是否安装
+func (sm *ServiceManager) CheckSystem() error {
+	if runtime.GOOS != "linux" {
+		return fmt.Errorf("%w (current: %s)", ErrUnsupported, runtime.GOOS)
+	}
+	if _, err := exec.LookPath("systemctl"); err != nil {
+		return fmt.Errorf("%w (systemctl not found)", ErrUnsupported)
+	}
+	return nil
+}
+
+// Exists 检查服务是否存在
+func (sm *ServiceManager) Exists(name string) bool {
+	_, err := os.Stat(sm.UnitPath(name))
+	return err == nil
+}
+
+// Names 单元目录中所有 autostart-*.service 的服务名，按名称排序，不查询 systemd
+func (sm *ServiceManager) Names() ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(sm.UnitDir, UnitPrefix+"*.service"))
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(paths))
+	for _, path := range paths {
+		names = append(names, strings.TrimPrefix(strings.TrimSuffix(filepath.Base(path), ".service"), UnitPrefix))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// List 列出所有自启服务
+func (sm *ServiceManager) List(ctx context.Context) ([]*ServiceInfo, error) {
+	output, err := sm.systemctl(ctx, "list-unit-files", "--type=service", "--no-pager", "--no-legend")
+	if err != nil {
+		return nil, err
+	}
+
+	var services []*ServiceInfo
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Fields(line)
+		if len(parts) < 2 || !strings.HasPrefix(parts[0], UnitPrefix) || !strings.HasSuffix(parts[0], ".service") {
+			continue
+		}
+		name := strings.TrimPrefix(strings.TrimSuffix(parts[0], ".service"), UnitPrefix)
+		services = append(services, sm.info(ctx, name))
+	}
+	return services, nil
+}
+
+// Status 服务的自启和运行状态
+func (sm *ServiceManager) Status(ctx context.Context, name string) (*ServiceInfo, error) {
+	if !sm.Exists(name) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return sm.info(ctx, name), nil
+}
+
+// StatusDetail systemctl status 的输出，服务未运行时也正常返回
+func (sm *ServiceManager) StatusDetail(ctx context.Context, name string) (string, error) {
+	if !sm.Exists(name) {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	units := []string{UnitName(name)}
+	if sm.IsTimer(name) {
+		units = append(units, UnitName(name)+".timer")
+	}
+	output, err := sm.systemctl(ctx, append(append([]string{"status"}, units...), "--no-pager")...)
+	// 未运行时 systemctl status 以 3 退出，输出仍然有效
+	if err != nil && output == "" {
+		return "", err
+	}
+	return output, nil
+}
+
+// Config 读取 autostart 保存的服务配置
+func (sm *ServiceManager) Config(name string) (*ServiceConfig, error) {
+	if _, err := os.Stat(sm.configPath(name)); os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w: %s", ErrConfigNotFound, name)
+	}
+	return sm.loadConfig(name)
+}
+
+// UnitExec 单元文件中实际的 ExecStart 和 WorkingDirectory，手动创建、没有保存配置的服务也能读取
+// 有多条 ExecStart 时取最后一条，并去掉 -、@、+、! 等前缀
+func (sm *ServiceManager) UnitExec(name string) (execStart, workDir string, err error) {
+	data, err := os.ReadFile(sm.UnitPath(name))
+	if os.IsNotExist(err) {
+		return "", "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	} else if err != nil {
+		return "", "", err
+	}
+
+	section := ""
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = line
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || section != "[Service]" {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "ExecStart":
+			execStart = strings.TrimLeft(strings.TrimSpace(value), "-@+!:")
+		case "WorkingDirectory":
+			workDir = strings.TrimLeft(strings.TrimSpace(value), "-")
+		}
+	}
+	return execStart, workDir, nil
+}
+
+// Add验证配置后创建服务，创建后需要调用 Enable、Start 才会自启和运行
+// 可执行文件不存在等开机才会暴露的问题返回 *VerifyError
+func (sm *ServiceManager) Add(ctx context.Context, cfg *ServiceConfig) error {
+	sm.scopeConfig(cfg)
+	if err := ValidateConfig(cfg); err != nil {
+		return err
+	}
+	if sm.Exists(cfg.Name) {
+		return fmt.Errorf("%w: %s", ErrAlreadyExists, cfg.Name)
+	}
+	if err := sm.checkCalendars(ctx, cfg); err != nil {
+		return err
+	}
+	if err := sm.Verify(ctx, cfg); err != nil {
+		return err
+	}
+
+	// 用户服务的单元目录第一次使用时可能不存在
+	if err := os.MkdirAll(sm.UnitDir, 0755); err != nil {
+		return fmt.Errorf("failed to create unit directory: %w", err)
+	}
+
+	// 先保存配置，单元文件写入失败时再删除，不会留下没有配置的服务
+	if err := sm.saveConfig(cfg); err != nil {
+		return fmt.Errorf("failed to save service config: %w", err)
+	}
+	content := sm.buildUnit(cfg)
+	if err := os.WriteFile(sm.UnitPath(cfg.Name), []byte(content), 0644); err != nil {
+		os.Remove(sm.configPath(cfg.Name))
+		return fmt.Errorf("failed to create service file: %w", err)
+	}
+	if cfg.Timer != nil {
+		timer := BuildTimerContent(cfg, UnitName(cfg.Name))
+		if err := os.WriteFile(sm.TimerPath(cfg.Name), []byte(timer), 0644); err != nil {
+			os.Remove(sm.UnitPath(cfg.Name))
+			os.Remove(sm.configPath(cfg.Name))
+			return fmt.Errorf("failed to create timer file: %w", err)
+		}
+	}
+	if _, err := sm.systemctl(ctx, "daemon-reload"); err != nil {
+		return fmt.Errorf("failed to reload systemd: %w", err)
+	}
+	return nil
+}
+
+// Remove 停止、禁用并删除服务，不需要确认
+func (sm *ServiceManager) Remove(ctx context.Context, name string) error {
+	if !sm.Exists(name) {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	// 停止、禁用失败（例如本来就没有运行）不影响删除，定时任务先停定时器，避免删除过程中再次触发
+	if sm.IsTimer(name) {
+		sm.systemctl(ctx, "stop", UnitName(name)+".timer")
+		sm.systemctl(ctx, "disable", UnitName(name)+".timer")
+		if err := os.Remove(sm.TimerPath(name)); err != nil {
+			return fmt.Errorf("failed to remove timer file: %w", err)
+		}
+	}
+	sm.systemctl(ctx, "stop", UnitName(name))
+	sm.systemctl(ctx, "disable", UnitName(name))
+
+	if err := os.Remove(sm.UnitPath(name)); err != nil {
+		return fmt.Errorf("failed to remove service file: %w", err)
+	}
+	os.Remove(sm.configPath(name)) // 忽略错误
+	os.RemoveAll(sm.DropInDir(name))
+
+	if _, err := sm.systemctl(ctx, "daemon-reload"); err != nil {
+		return fmt.Errorf("failed to reload systemd: %w", err)
+	}
+	return nil
+}
+
+// Enable 启用服务自启动，定时任务启用的是定时器
+func (sm *ServiceManager) Enable(ctx context.Context, name string) error {
+	return sm.control(ctx, "enable", name)
+}
+
+// Disable 禁用服务自启动，不会停止正在运行的服务
+func (sm *ServiceManager) Disable(ctx context.Context, name string) error {
+	return sm.control(ctx, "disable", name)
+}
+
+// Start 启动服务，定时任务启动的是定时器，不会立即执行
+func (sm *ServiceManager) Start(ctx context.Context, name string) error {
+	return sm.control(ctx, "start", name)
+}
+
+// Stop 停止服务
+func (sm *ServiceManager) Stop(ctx context.Context, name string) error {
+	return sm.control(ctx, "stop", name)
+}
+
+// Restart 重启服务
+func (sm *ServiceManager) Restart(ctx context.Context, name string) error {
+	return sm.control(ctx, "restart", name)
+}
+
+// control 对已存在的服务执行 systemctl 操作
+func (sm *ServiceManager) control(ctx context.Context, action, name string) error {
+	if !sm.Exists(name) {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if _, err := sm.systemctl(ctx, action, sm.controlUnit(name)); err != nil {
+		return fmt.Errorf("failed to %s service: %w", action, err)
+	}
+	return nil
+}
+
+// query systemctl is-active、is-enabled 等查询，非 0 退出时输出仍是查询结果
+func (sm *ServiceManager) query(ctx context.Context, action, name string) string {
+	return sm.queryUnit(ctx, action, UnitName(name))
+}
+
+// queryUnit 查询指定单元
+func (sm *ServiceManager) queryUnit(ctx context.Context, action, unit string) string {
+	output, _ := sm.systemctl(ctx, action, unit)
+	// 正常的查询结果是一个单词，连接不上 systemd 等情况输出的是错误信息
+	if fields := strings.Fields(output); len(fields) == 1 {
+		return fields[0]
+	}
+	return "unknown"
+}
+
+// description 保存的配置中的服务描述
+func (sm *ServiceManager) description(name string) string {
+	data, err := os.ReadFile(sm.configPath(name))
+	if err != nil {
+		return ""
+	}
+	var cfg ServiceConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return ""
+	}
+	return cfg.Description
+}
+
+// run 执行命令并返回输出，失败时返回 *CommandError
+func (sm *ServiceManager) run(ctx context.Context, name string, args ...string) (string, error) {
+	timeout := sm.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Env = sm.userEnv()
+	output, err := cmd.CombinedOutput()
+	text := strings.TrimSpace(string(output))
+	if err != nil {
+		return text, &CommandError{Args: append([]string{name}, args...), Output: text, Err: err}
+	}
+	return text, nil
+}
@@ -0,0 +1,164 @@
+package autostart
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Manifest 声明式的服务清单，apply 按清单创建、修改、删除服务，export 导出当前服务
+// 字段与保存的服务配置相同，省略的字段取 add（有 timer 时为 add-timer）的默认值，work_dir 省略时为空
+type Manifest struct {
+	Services []*ServiceConfig `json:"services" yaml:"services"`
+}
+
+// ParseManifest 解析 YAML 清单（JSON 也是合法的 YAML），未知字段、重复的服务名和无效配置都会报错
+func ParseManifest(data []byte) (*Manifest, error) {
+	// 第一遍严格解析，发现拼错的字段
+	var strict Manifest
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&strict); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("manifest is empty")
+		}
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	// 第二遍在默认配置上解析，省略的字段保留默认值
+	var raw struct {
+		Services []yaml.Node `yaml:"services"`
+	}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	manifest := &Manifest{Services: make([]*ServiceConfig, 0, len(raw.Services))}
+	seen := make(map[string]bool)
+	for i, node := range raw.Services {
+		probe := strict.Services[i]
+		if probe == nil || probe.Name == "" {
+			return nil, fmt.Errorf("services[%d]: name is required", i)
+		}
+		if seen[probe.Name] {
+			return nil, fmt.Errorf("service %s is declared more than once", probe.Name)
+		}
+		seen[probe.Name] = true
+
+		cfg := defaultConfig(probe)
+		if err := node.Decode(cfg); err != nil {
+			return nil, fmt.Errorf("service %s: %w", probe.Name, err)
+		}
+		if cfg.Env == nil {
+			cfg.Env = make(map[string]string)
+		}
+		if err := ValidateConfig(cfg); err != nil {
+			return nil, fmt.Errorf("service %s: %w", probe.Name, err)
+		}
+		manifest.Services = append(manifest.Services, cfg)
+	}
+	return manifest, nil
+}
+
+// MarshalManifest 生成 YAML 清单，与默认值相同的字段不输出，name、exec_start 和 work_dir 总是输出
+func MarshalManifest(manifest *Manifest) ([]byte, error) {
+	services := &yaml.Node{Kind: yaml.SequenceNode}
+	for _, cfg := range manifest.Services {
+		node, err := compactNode(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("service %s: %w", cfg.Name, err)
+		}
+		services.Content = append(services.Content, node)
+	}
+	root := &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Value: "services"}, services,
+	}}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(root); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Export 导出所有服务的配置，没有 autostart 配置的服务（手工创建的单元文件）无法导出，返回在 skipped 中
+func (sm *ServiceManager) Export() (manifest *Manifest, skipped []string, err error) {
+	names, err := sm.Names()
+	if err != nil {
+		return nil, nil, err
+	}
+	manifest = &Manifest{Services: []*ServiceConfig{}}
+	for _, name := range names {
+		cfg, err := sm.Config(name)
+		if errors.Is(err, ErrConfigNotFound) {
+			skipped = append(skipped, name)
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("service %s: %w", name, err)
+		}
+		manifest.Services = append(manifest.Services, cfg)
+	}
+	return manifest, skipped, nil
+}
+
+// defaultConfig 清单中服务的默认配置，有 timer 时是定时任务
+// 工作目录不按当前目录推断，省略时为空（使用 systemd 的默认目录），同一份清单在哪里执行结果都相同
+func defaultConfig(cfg *ServiceConfig) *ServiceConfig {
+	var defaults *ServiceConfig
+	if cfg.Timer != nil {
+		defaults = NewTimerServiceConfig(cfg.Name, cfg.ExecStart)
+	} else {
+		defaults = NewServiceConfig(cfg.Name, cfg.ExecStart)
+	}
+	defaults.WorkDir = ""
+	return defaults
+}
+
+// manifestKeptFields 导出清单时总是输出的字段
+var manifestKeptFields = map[string]bool{"name": true, "exec_start": true, "work_dir": true}
+
+// compactNode 把配置编码为 YAML 节点，去掉与默认配置相同的字段，name、exec_start 和 work_dir 总是保留
+func compactNode(cfg *ServiceConfig) (*yaml.Node, error) {
+	var node, defaults yaml.Node
+	if err := node.Encode(cfg); err != nil {
+		return nil, err
+	}
+	if err := defaults.Encode(defaultConfig(cfg)); err != nil {
+		return nil, err
+	}
+
+	defaultValues := make(map[string]*yaml.Node)
+	for i := 0; i+1 < len(defaults.Content); i += 2 {
+		defaultValues[defaults.Content[i].Value] = defaults.Content[i+1]
+	}
+
+	content := make([]*yaml.Node, 0, len(node.Content))
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if !manifestKeptFields[key.Value] && sameNode(value, defaultValues[key.Value]) {
+			continue
+		}
+		content = append(content, key, value)
+	}
+	node.Content = content
+	return &node, nil
+}
+
+// sameNode 两个节点编码后是否相同
+func sameNode(a, b *yaml.Node) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	dataA, errA := yaml.Marshal(a)
+	dataB, errB := yaml.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(dataA, dataB)
+}
@@ -0,0 +1,90 @@
+package autostart
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// testManifest 一个指定了 work_dir 的服务和一个省略 work_dir 的定时任务
+const testManifest = `services:
+  - name: myapp
+    exec_start: java -jar app.jar
+    work_dir: %s
+  - name: cleanup
+    exec_start: /opt/jobs/cleanup.sh
+    timer:
+      on_calendar: ["*-*-* 02:00"]
+`
+
+// parseIn 在指定目录下解析清单
+func parseIn(t *testing.T, dir, data string) *Manifest {
+	t.Helper()
+	previous, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(previous)
+	manifest, err := ParseManifest([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseManifest: %v", err)
+	}
+	return manifest
+}
+
+func TestParseManifestWorkDir(t *testing.T) {
+	workDir := t.TempDir()
+	data := fmt.Sprintf(testManifest, workDir)
+	first := parseIn(t, t.TempDir(), data)
+	second := parseIn(t, t.TempDir(), data)
+	for i, cfg := range first.Services {
+		if cfg.WorkDir != second.Services[i].WorkDir {
+			t.Errorf("service %s work_dir depends on the current directory: %q vs %q", cfg.Name, cfg.WorkDir, second.Services[i].WorkDir)
+		}
+	}
+	if got := first.Services[0].WorkDir; got != workDir {
+		t.Errorf("myapp work_dir = %q", got)
+	}
+	if got := first.Services[1].WorkDir; got != "" {
+		t.Errorf("omitted work_dir = %q, want empty", got)
+	}
+}
+
+func TestMarshalManifestKeepsWorkDir(t *testing.T) {
+	dir := t.TempDir()
+	cfg := NewServiceConfig("myapp", "java -jar app.jar")
+	cfg.WorkDir = dir
+	plain := NewServiceConfig("plain", "/bin/true")
+	data := marshalIn(t, dir, &Manifest{Services: []*ServiceConfig{cfg, plain}})
+
+	// 与导出时的当前目录相同也要输出，否则换个目录 apply 结果不同
+	if !strings.Contains(data, "work_dir: "+dir+"\n") {
+		t.Errorf("work_dir equal to the current directory was dropped:\n%s", data)
+	}
+	manifest := parseIn(t, t.TempDir(), data)
+	if manifest.Services[0].WorkDir != dir {
+		t.Errorf("round trip work_dir = %q, want %q", manifest.Services[0].WorkDir, dir)
+	}
+	if manifest.Services[1].WorkDir != plain.WorkDir {
+		t.Errorf("round trip plain work_dir = %q, want %q", manifest.Services[1].WorkDir, plain.WorkDir)
+	}
+}
+
+// marshalIn 在指定目录下生成清单
+func marshalIn(t *testing.T, dir string, manifest *Manifest) string {
+	t.Helper()
+	previous, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(previous)
+	data, err := MarshalManifest(manifest)
+	if err != nil {
+		t.Fatalf("MarshalManifest: %v", err)
+	}
+	return string(data)
+}
@@ -0,0 +1,548 @@
+package autostart
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// NewServiceConfig 创建带默认值的服务配置，工作目录根据启动命令推断
+func NewServiceConfig(name, execStart string) *ServiceConfig {
+	return &ServiceConfig{
+		Name:         name,
+		ExecStart:    execStart,
+		WorkDir:      inferWorkingDirectory(execStart),
+		User:         "root",
+		Group:        "",
+		Description:  fmt.Sprintf("Autostart service: %s", name),
+		Env:          make(map[string]string),
+		Restart:      "always",
+		RestartSec:   5,
+		KillMode:     "control-group",
+		KillSignal:   "SIGTERM",
+		TimeoutStart: 90,
+		TimeoutStop:  90,
+		After:        []string{"network.target"},
+		Wants:        []string{"network.target"},
+		Requires:     []string{},
+	}
+}
+
+// ParseAddOptions 解析添加选项
+func ParseAddOptions(name, execStart string, options []string) (*ServiceConfig, error) {
+	cfg := NewServiceConfig(name, execStart)
+
+	for _, option := range options {
+		if err := parseOption(cfg, option); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.Timer != nil {
+		return nil, fmt.Errorf("--on-calendar, --persistent and --randomized-delay are only supported by scheduled jobs (add-timer)")
+	}
+
+	if err := ValidateConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// ApplyOptions 将 --key=value 选项应用到已有配置并重新校验，供添加和修改共用
+func ApplyOptions(cfg *ServiceConfig, options []string) error {
+	if cfg.Env == nil {
+		cfg.Env = make(map[string]string)
+	}
+
+	for _, option := range options {
+		if err := parseOption(cfg, option); err != nil {
+			return err
+		}
+	}
+
+	return ValidateConfig(cfg)
+}
+
+// inferWorkingDirectory 推断工作目录
+func inferWorkingDirectory(execStart string) string {
+	parts := strings.Fields(execStart)
+	if len(parts) == 0 {
+		return getCurrentDir()
+	}
+
+	for _, part := range parts {
+		if strings.Contains(part, "/") && !strings.HasPrefix(part, "-") && filepath.IsAbs(part) {
+			if _, err := os.Stat(part); err == nil {
+				return filepath.Dir(part)
+			}
+		}
+	}
+
+	return getCurrentDir()
+}
+
+// getCurrentDir 获取当前目录
+func getCurrentDir() string {
+	if wd, err := os.Getwd(); err == nil {
+		return wd
+	}
+	return "/tmp"
+}
+
+// parseOption 解析单个选项
+func parseOption(cfg *ServiceConfig, option string) error {
+	if !strings.HasPrefix(option, "--") {
+		return fmt.Errorf("invalid option format: %s (must start with --)", option)
+	}
+
+	// 开关类选项可以不带值，如 --persistent
+	if contains(switchOptions, option[2:]) {
+		return applyOptionToConfig(cfg, option[2:], "yes")
+	}
+
+	key, value, err := parseOptionKeyValue(option)
+	if err != nil {
+		return err
+	}
+
+	return applyOptionToConfig(cfg, key, value)
+}
+
+// switchOptions 可以省略 =yes 的选项
+var switchOptions = []string{"persistent"}
+
+// parseOptionKeyValue 解析选项的键值对
+func parseOptionKeyValue(option string) (string, string, error) {
+	parts := strings.SplitN(option[2:], "=", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid option format: %s (use --key=value)", option)
+	}
+
+	key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+	if key == "" {
+		return "", "", fmt.Errorf("empty option key in: %s", option)
+	}
+	if value == "" {
+		return "", "", fmt.Errorf("empty option value in: %s", option)
+	}
+
+	return key, value, nil
+}
+
+// optionHandler 定义选项处理函数类型
+type optionHandler func(*ServiceConfig, string) error
+
+// applyOptionToConfig 将选项应用到配置中
+func applyOptionToConfig(cfg *ServiceConfig, key, value string) error {
+	// 选项处理映射表
+	optionHandlers := map[string]optionHandler{
+		"exec-start":    handleExecStartOption,
+		"workdir":       handleWorkdirOption,
+		"user":          handleUserOption,
+		"group":         handleGroupOption,
+		"description":   handleDescriptionOption,
+		"env":           handleEnvOption,
+		"restart":       handleRestartOption,
+		"restart-sec":   handleRestartSecOption,
+		"kill-mode":     handleKillModeOption,
+		"kill-signal":   handleKillSignalOption,
+		"timeout-start": handleTimeoutStartOption,
+		"timeout-stop":  handleTimeoutStopOption,
+		"after":         handleAfterOption,
+		"wants":         handleWantsOption,
+		"requires":      handleRequiresOption,
+
+		"memory-max":        handleMemoryMaxOption,
+		"memory-high":       handleMemoryHighOption,
+		"cpu-quota":         handleCPUQuotaOption,
+		"tasks-max":         handleTasksMaxOption,
+		"limit-nofile":      handleLimitNOFILEOption,
+		"nice":              handleNiceOption,
+		"oom-score-adjust":  handleOOMScoreAdjustOption,
+		"protect-system":    handleProtectSystemOption,
+		"private-tmp":       handlePrivateTmpOption,
+		"no-new-privileges": handleNoNewPrivilegesOption,
+		"read-write-path":   handleReadWritePathOption,
+		"environment-file":  handleEnvironmentFileOption,
+
+		"on-calendar":      handleOnCalendarOption,
+		"persistent":       handlePersistentOption,
+		"randomized-delay": handleRandomizedDelayOption,
+
+		"unset": unsetOption,
+	}
+
+	handler, exists := optionHandlers[key]
+	if !exists {
+		return fmt.Errorf("unknown option: --%s", key)
+	}
+
+	return handler(cfg, value)
+}
+
+// 各种选项处理函数
+func handleExecStartOption(cfg *ServiceConfig, value string) error {
+	cfg.ExecStart = value
+	return nil
+}
+
+func handleWorkdirOption(cfg *ServiceConfig, value string) error {
+	cfg.WorkDir = value
+	return nil
+}
+
+func handleUserOption(cfg *ServiceConfig, value string) error {
+	if !isValidUsername(value) {
+		return fmt.Errorf("invalid username: %s", value)
+	}
+	cfg.User = value
+	return nil
+}
+
+func handleGroupOption(cfg *ServiceConfig, value string) error {
+	if !isValidGroupname(value) {
+		return fmt.Errorf("invalid group name: %s", value)
+	}
+	cfg.Group = value
+	return nil
+}
+
+func handleDescriptionOption(cfg *ServiceConfig, value string) error {
+	if len(value) > 256 {
+		return fmt.Errorf("description too long (max 256 characters): %d", len(value))
+	}
+	cfg.Description = value
+	return nil
+}
+
+func handleEnvOption(cfg *ServiceConfig, value string) error {
+	return parseAndSetEnvVar(cfg, value)
+}
+
+func handleRestartOption(cfg *ServiceConfig, value string) error {
+	return setRestartPolicy(cfg, value)
+}
+
+func handleRestartSecOption(cfg *ServiceConfig, value string) error {
+	return parseAndSetIntOption(&cfg.RestartSec, value, "restart-sec", 1, 3600)
+}
+
+func handleKillModeOption(cfg *ServiceConfig, value string) error {
+	validModes := []string{"control-group", "process", "mixed", "none"}
+	if !contains(validModes, value) {
+		return fmt.Errorf("invalid kill mode: %s (valid: %s)", value, strings.Join(validModes, ", "))
+	}
+	cfg.KillMode = value
+	return nil
+}
+
+func handleKillSignalOption(cfg *ServiceConfig, value string) error {
+	validSignals := []string{"SIGTERM", "SIGKILL", "SIGINT", "SIGHUP", "SIGQUIT", "SIGUSR1", "SIGUSR2"}
+	if !contains(validSignals, value) {
+		return fmt.Errorf("invalid kill signal: %s (valid: %s)", value, strings.Join(validSignals, ", "))
+	}
+	cfg.KillSignal = value
+	return nil
+}
+
+func handleTimeoutStartOption(cfg *ServiceConfig, value string) error {
+	return parseAndSetIntOption(&cfg.TimeoutStart, value, "timeout-start", 1, 600)
+}
+
+func handleTimeoutStopOption(cfg *ServiceConfig, value string) error {
+	return parseAndSetIntOption(&cfg.TimeoutStop, value, "timeout-stop", 1, 600)
+}
+
+func handleAfterOption(cfg *ServiceConfig, value string) error {
+	if !isValidServiceName(value) {
+		return fmt.Errorf("invalid service name for after: %s", value)
+	}
+	cfg.After = appendUnique(cfg.After, value)
+	return nil
+}
+
+func handleWantsOption(cfg *ServiceConfig, value string) error {
+	if !isValidServiceName(value) {
+		return fmt.Errorf("invalid service name for wants: %s", value)
+	}
+	cfg.Wants = appendUnique(cfg.Wants, value)
+	return nil
+}
+
+func handleRequiresOption(cfg *ServiceConfig, value string) error {
+	if !isValidServiceName(value) {
+		return fmt.Errorf("invalid service name for requires: %s", value)
+	}
+	cfg.Requires = appendUnique(cfg.Requires, value)
+	return nil
+}
+
+// parseAndSetEnvVar 解析并设置环境变量
+func parseAndSetEnvVar(cfg *ServiceConfig, envStr string) error {
+	key, value, err := parseEnvVarString(envStr)
+	if err != nil {
+		return err
+	}
+
+	if !isValidEnvVarName(key) {
+		return fmt.Errorf("invalid environment variable name: %s", key)
+	}
+
+	cfg.Env[key] = value
+	return nil
+}
+
+// parseEnvVarString 解析环境变量字符串
+func parseEnvVarString(envStr string) (string, string, error) {
+	parts := strings.SplitN(envStr, "=", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("invalid environment variable format: %s (use KEY=VALUE)", envStr)
+	}
+
+	key, value := strings.TrimSpace(parts[0]), parts[1] // 值可能包含空格，不要trim
+	if key == "" {
+		return "", "", fmt.Errorf("empty environment variable name in: %s", envStr)
+	}
+
+	return key, value, nil
+}
+
+// setRestartPolicy 设置重启策略
+func setRestartPolicy(cfg *ServiceConfig, restart string) error {
+	validPolicies := []string{"always", "on-failure", "on-abnormal", "on-watchdog", "on-abort", "no"}
+	if !contains(validPolicies, restart) {
+		return fmt.Errorf("invalid restart policy: %s (valid: %s)", restart, strings.Join(validPolicies, ", "))
+	}
+	cfg.Restart = restart
+	return nil
+}
+
+// parseAndSetIntOption 解析并设置整数选项（带范围检查）
+func parseAndSetIntOption(target *int, value, optName string, min, max int) error {
+	val, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid %s value: %s (must be an integer)", optName, value)
+	}
+
+	if val < min || val > max {
+		return fmt.Errorf("%s value out of range: %d (valid range: %d-%d)", optName, val, min, max)
+	}
+
+	*target = val
+	return nil
+}
+
+// ValidateConfig 验证配置，相对路径的工作目录会转换为绝对路径
+func ValidateConfig(cfg *ServiceConfig) error {
+	validators := []func(*ServiceConfig) error{
+		validateServiceName,
+		validateExecStart,
+		validateWorkDir,
+		validateUser,
+		validateTimeouts,
+		validateDependencies,
+		validateResources,
+		validateSandbox,
+		validateTimer,
+	}
+
+	for _, validator := range validators {
+		if err := validator(cfg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateServiceName 验证服务名称
+func validateServiceName(cfg *ServiceConfig) error {
+	if cfg.Name == "" {
+		return fmt.Errorf("service name cannot be empty")
+	}
+
+	if !isValidServiceName(cfg.Name) {
+		return fmt.Errorf("invalid service name: %s (only letters, numbers, hyphens, and underscores allowed)", cfg.Name)
+	}
+
+	if len(cfg.Name) > 64 {
+		return fmt.Errorf("service name too long: %s (max 64 characters)", cfg.Name)
+	}
+
+	return nil
+}
+
+// validateExecStart 验证执行命令
+func validateExecStart(cfg *ServiceConfig) error {
+	if cfg.ExecStart == "" {
+		return fmt.Errorf("exec start command cannot be empty")
+	}
+
+	if len(cfg.ExecStart) > 1024 {
+		return fmt.Errorf("exec start command too long (max 1024 characters)")
+	}
+
+	return nil
+}
+
+// validateWorkDir 验证工作目录
+func validateWorkDir(cfg *ServiceConfig) error {
+	if cfg.WorkDir == "" {
+		return nil // 工作目录可以为空
+	}
+
+	// 转换为绝对路径
+	if !filepath.IsAbs(cfg.WorkDir) {
+		abs, err := filepath.Abs(cfg.WorkDir)
+		if err != nil {
+			return fmt.Errorf("failed to get absolute path for workdir: %v", err)
+		}
+		cfg.WorkDir = abs
+	}
+
+	// 检查目录是否存在
+	if stat, err := os.Stat(cfg.WorkDir); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("working directory does not exist: %s", cfg.WorkDir)
+		}
+		return fmt.Errorf("failed to access working directory: %v", err)
+	} else if !stat.IsDir() {
+		return fmt.Errorf("working directory is not a directory: %s", cfg.WorkDir)
+	}
+
+	return nil
+}
+
+// validateUser 验证用户配置
+func validateUser(cfg *ServiceConfig) error {
+	if cfg.User == "" {
+		cfg.User = "root" // 设置默认用户
+		return nil
+	}
+
+	if !isValidUsername(cfg.User) {
+		return fmt.Errorf("invalid username: %s", cfg.User)
+	}
+
+	return nil
+}
+
+// validateTimeouts 验证超时配置
+func validateTimeouts(cfg *ServiceConfig) error {
+	// 定时任务默认启动不超时，TimeoutStart 为 0
+	if cfg.TimeoutStart < 0 || (cfg.TimeoutStart == 0 && cfg.Timer == nil) {
+		return fmt.Errorf("timeout-start must be positive: %d", cfg.TimeoutStart)
+	}
+
+	if cfg.TimeoutStop <= 0 {
+		return fmt.Errorf("timeout-stop must be positive: %d", cfg.TimeoutStop)
+	}
+
+	if cfg.RestartSec < 0 {
+		return fmt.Errorf("restart-sec cannot be negative: %d", cfg.RestartSec)
+	}
+
+	return nil
+}
+
+// validateDependencies 验证依赖配置
+func validateDependencies(cfg *ServiceConfig) error {
+	allDeps := append(append(cfg.After, cfg.Wants...), cfg.Requires...)
+
+	for _, dep := range allDeps {
+		if !isValidServiceName(dep) {
+			return fmt.Errorf("invalid dependency service name: %s", dep)
+		}
+	}
+
+	return nil
+}
+
+// 工具函数
+
+// contains 检查字符串切片是否包含指定值
+func contains(slice []string, item string) bool {
+	for _, s := range slice {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
+
+// appendUnique 向切片添加唯一元素
+func appendUnique(slice []string, item string) []string {
+	if !contains(slice, item) {
+		return append(slice, item)
+	}
+	return slice
+}
+
+// isValidUsername 验证用户名格式
+func isValidUsername(username string) bool {
+	if len(username) == 0 || len(username) > 32 {
+		return false
+	}
+
+	// 用户名只能包含字母、数字、下划线和连字符，且不能以数字开头
+	for i, r := range username {
+		if i == 0 && (r >= '0' && r <= '9') {
+			return false
+		}
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+			(r >= '0' && r <= '9') || r == '_' || r == '-') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isValidGroupname 验证组名格式
+func isValidGroupname(groupname string) bool {
+	return isValidUsername(groupname) // 组名和用户名使用相同的验证规则
+}
+
+// isValidServiceName 验证服务名格式
+func isValidServiceName(serviceName string) bool {
+	if len(serviceName) == 0 || len(serviceName) > 64 {
+		return false
+	}
+
+	// 服务名只能包含字母、数字、下划线、连字符和点
+	for _, r := range serviceName {
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+			(r >= '0' && r <= '9') || r == '_' || r == '-' || r == '.') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isValidEnvVarName 验证环境变量名格式
+func isValidEnvVarName(name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+
+	// 环境变量名只能包含字母、数字和下划线，且不能以数字开头
+	for i, r := range name {
+		if i == 0 && (r >= '0' && r <= '9') {
+			return false
+		}
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') ||
+			(r >= '0' && r <= '9') || r == '_') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// needsQuoting 检查值是否需要引用
+func needsQuoting(value string) bool {
+	return strings.ContainsAny(value, " \t\n\"'\\$`|&;<>(){}[]?*~")
+}
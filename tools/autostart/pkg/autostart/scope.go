@@ -0,0 +1,120 @@
+package autostart
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+)
+
+// LingerDir systemd-logind 记录开启了 lingering 的用户的目录
+const LingerDir = "/var/lib/systemd/linger"
+
+// NewUserServiceManager 创建管理当前用户服务的服务管理器，单元文件在 ~/.config/systemd/user，
+// 配置在 ~/.config/autostart-manager，通过 systemctl --user 操作，不需要 root
+func NewUserServiceManager() (*ServiceManager, error) {
+	configHome, err := os.UserConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+	return &ServiceManager{
+		Timeout:   DefaultTimeout,
+		User:      true,
+		UnitDir:   filepath.Join(configHome, "systemd", "user"),
+		ConfigDir: filepath.Join(configHome, "autostart-manager"),
+	}, nil
+}
+
+// UnitPath 单元文件路径
+func (sm *ServiceManager) UnitPath(name string) string {
+	return filepath.Join(sm.UnitDir, UnitName(name)+".service")
+}
+
+// TimerPath 定时器单元文件路径
+func (sm *ServiceManager) TimerPath(name string) string {
+	return filepath.Join(sm.UnitDir, UnitName(name)+".timer")
+}
+
+// DropInDir 服务的 drop-in 目录
+func (sm *ServiceManager) DropInDir(name string) string {
+	return sm.UnitPath(name) + ".d"
+}
+
+// OverridePath drop-in 覆盖文件路径
+func (sm *ServiceManager) OverridePath(name string) string {
+	return filepath.Join(sm.DropInDir(name), OverrideFile)
+}
+
+// Lingering 当前用户是否开启了 lingering，没有开启时用户服务在退出登录后停止，开机也不会启动
+// 系统服务总是返回 true
+func (sm *ServiceManager) Lingering() (bool, error) {
+	if !sm.User {
+		return true, nil
+	}
+	current, err := user.Current()
+	if err != nil {
+		return false, fmt.Errorf("failed to get current user: %w", err)
+	}
+	_, err = os.Stat(filepath.Join(LingerDir, current.Username))
+	return err == nil, nil
+}
+
+// scopeConfig 用户服务总是以当前用户运行，配置中的用户、组以实际为准
+func (sm *ServiceManager) scopeConfig(cfg *ServiceConfig) {
+	if !sm.User {
+		return
+	}
+	if current, err := user.Current(); err == nil {
+		cfg.User, cfg.Group = current.Username, ""
+	}
+}
+
+// configPath 保存的服务配置文件路径
+func (sm *ServiceManager) configPath(name string) string {
+	return filepath.Join(sm.ConfigDir, name+".json")
+}
+
+// saveConfig 保存服务配置
+func (sm *ServiceManager) saveConfig(cfg *ServiceConfig) error {
+	return saveServiceConfig(sm.ConfigDir, cfg)
+}
+
+// loadConfig 加载服务配置
+func (sm *ServiceManager) loadConfig(name string) (*ServiceConfig, error) {
+	return loadServiceConfig(sm.ConfigDir, name)
+}
+
+// buildUnit 按管理范围生成服务单元文件内容
+func (sm *ServiceManager) buildUnit(cfg *ServiceConfig) string {
+	if sm.User {
+		return BuildUserServiceContent(cfg, UnitName(cfg.Name))
+	}
+	return BuildServiceContent(cfg, UnitName(cfg.Name))
+}
+
+// systemctl 执行 systemctl，用户服务加 --user
+func (sm *ServiceManager) systemctl(ctx context.Context, args ...string) (string, error) {
+	if sm.User {
+		args = append([]string{"--user"}, args...)
+	}
+	return sm.run(ctx, "systemctl", args...)
+}
+
+// journalctl 执行 journalctl，用户服务加 --user
+func (sm *ServiceManager) journalctl(ctx context.Context, args ...string) (string, error) {
+	if sm.User {
+		args = append([]string{"--user"}, args...)
+	}
+	return sm.run(ctx, "journalctl", args...)
+}
+
+// userEnv systemctl --user 需要 XDG_RUNTIME_DIR 连接用户的 systemd 实例，
+// 以服务方式运行或 su 切换过来时通常没有设置
+func (sm *ServiceManager) userEnv() []string {
+	if !sm.User || os.Getenv("XDG_RUNTIME_DIR") != "" {
+		return nil
+	}
+	return append(os.Environ(), "XDG_RUNTIME_DIR=/run/user/"+strconv.Itoa(os.Getuid()))
+}
@@ -0,0 +1,107 @@
+package autostart
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ServiceInfo 服务信息，来自 systemctl show
+type ServiceInfo struct {
+	Name            string     `json:"name"`
+	Unit            string     `json:"unit"`
+	Description     string     `json:"description"`   // 来自 autostart 保存的配置，没有时为空
+	Enabled         bool       `json:"enabled"`       // 是否开机自启
+	AutostartStatus string     `json:"unitFileState"` // UnitFileState，如 enabled、disabled
+	Active          bool       `json:"active"`        // 是否正在运行
+	ActiveStatus    string     `json:"activeState"`   // ActiveState，如 active、inactive、failed
+	SubState        string     `json:"subState"`      // SubState，如 running、dead、auto-restart
+	MainPID         int        `json:"mainPid"`       // 主进程 PID，未运行时为 0
+	Memory          int64      `json:"memory"`        // MemoryCurrent 字节数，未开启内存统计时为 0
+	Restarts        int        `json:"restarts"`      // NRestarts 自动重启次数，systemd 235 之前没有该属性
+	Since           *time.Time `json:"since"`         // 最近一次状态变化的时间
+	Timer           *TimerInfo `json:"timer,omitempty"`
+}
+
+// TimerInfo 定时任务的定时器状态，定时任务的自启状态（unitFileState）也取自定时器
+type TimerInfo struct {
+	Schedule []string   `json:"schedule"` // OnCalendar 表达式
+	State    string     `json:"state"`    // 定时器的 ActiveState，active 表示在等待下次触发
+	NextRun  *time.Time `json:"nextRun"`
+	LastRun  *time.Time `json:"lastRun"`
+}
+
+// showProperties systemctl show 读取的属性
+var showProperties = []string{
+	"UnitFileState", "ActiveState", "SubState", "MainPID", "MemoryCurrent", "NRestarts", "StateChangeTimestampMonotonic",
+}
+
+// info 读取服务状态，systemd 未运行（如容器中）时只能得到自启状态
+func (sm *ServiceManager) info(ctx context.Context, name string) *ServiceInfo {
+	info := &ServiceInfo{
+		Name:        name,
+		Unit:        UnitName(name),
+		Description: sm.description(name),
+	}
+	props, err := sm.showUnit(ctx, UnitName(name), showProperties...)
+	if err != nil {
+		info.AutostartStatus = sm.query(ctx, "is-enabled", name)
+		info.ActiveStatus = "unknown"
+	} else {
+		info.AutostartStatus = props["UnitFileState"]
+		info.ActiveStatus = props["ActiveState"]
+		info.SubState = props["SubState"]
+		info.MainPID, _ = strconv.Atoi(props["MainPID"])
+		info.Restarts, _ = strconv.Atoi(props["NRestarts"])
+		// 未开启内存统计时为 [not set] 或 uint64 最大值
+		if memory, err := strconv.ParseInt(props["MemoryCurrent"], 10, 64); err == nil {
+			info.Memory = memory
+		}
+		if usec, err := strconv.ParseInt(props["StateChangeTimestampMonotonic"], 10, 64); err == nil && usec > 0 {
+			if boot := bootTime(); !boot.IsZero() {
+				since := boot.Add(time.Duration(usec) * time.Microsecond)
+				info.Since = &since
+			}
+		}
+	}
+	if sm.IsTimer(name) {
+		sm.timerInfo(ctx, info)
+	}
+	info.Enabled = info.AutostartStatus == "enabled"
+	info.Active = info.ActiveStatus == "active"
+	return info
+}
+
+// showUnit 以 key=value 读取单元属性
+func (sm *ServiceManager) showUnit(ctx context.Context, unit string, properties ...string) (map[string]string, error) {
+	output, err := sm.systemctl(ctx, "show", unit, "--property="+strings.Join(properties, ","))
+	if err != nil {
+		return nil, err
+	}
+	props := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		if key, value, ok := strings.Cut(line, "="); ok {
+			props[key] = value
+		}
+	}
+	return props, nil
+}
+
+// bootTime 系统启动时间，用于把单调时钟的时间戳换算为墙上时间
+// 不依赖 systemctl 输出的时间格式，不同版本和时区都能得到一致的结果
+func bootTime() time.Time {
+	data, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return time.Time{}
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if value, ok := strings.CutPrefix(line, "btime "); ok {
+			if sec, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64); err == nil {
+				return time.Unix(sec, 0)
+			}
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,237 @@
+package autostart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimerConfig 定时任务配置，服务以 Type=oneshot 运行，由同名的 .timer 单元按计划触发
+type TimerConfig struct {
+	OnCalendar      []string `json:"on_calendar" yaml:"on_calendar"`                               // 日历表达式，如 *-*-* 02:00，可以有多个
+	Persistent      bool     `json:"persistent,omitempty" yaml:"persistent,omitempty"`             // 关机错过的触发在开机后补执行
+	RandomizedDelay string   `json:"randomized_delay,omitempty" yaml:"randomized_delay,omitempty"` // 随机延迟，如 5m，避免多台机器同时执行
+}
+
+// timespanPattern systemd 时间长度，如 30、30s、5min、1h 30m
+var timespanPattern = regexp.MustCompile(`^(\d+(\.\d+)?\s*(us|ms|s|sec|second|seconds|m|min|minute|minutes|h|hr|hour|hours|d|day|days|w|week|weeks)?\s*)+$`)
+
+// NewTimerServiceConfig 创建定时任务的默认配置：不自动重启，启动不超时
+func NewTimerServiceConfig(name, execStart string) *ServiceConfig {
+	cfg := NewServiceConfig(name, execStart)
+	cfg.Description = fmt.Sprintf("Autostart scheduled job: %s", name)
+	cfg.Restart = "no"
+	cfg.TimeoutStart = 0
+	cfg.Timer = &TimerConfig{}
+	return cfg
+}
+
+// ParseTimerOptions 解析定时任务选项，必须有 --on-calendar
+func ParseTimerOptions(name, execStart string, options []string) (*ServiceConfig, error) {
+	cfg := NewTimerServiceConfig(name, execStart)
+
+	if err := ApplyOptions(cfg, options); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// BuildTimerContent 构建定时器单元文件内容
+func BuildTimerContent(cfg *ServiceConfig, serviceName string) string {
+	var content strings.Builder
+	content.WriteString("[Unit]\n")
+	content.WriteString(fmt.Sprintf("Description=Timer for %s\n", cfg.Description))
+	content.WriteString("\n")
+
+	content.WriteString("[Timer]\n")
+	for _, spec := range cfg.Timer.OnCalendar {
+		content.WriteString(fmt.Sprintf("OnCalendar=%s\n", spec))
+	}
+	if cfg.Timer.Persistent {
+		content.WriteString("Persistent=true\n")
+	}
+	if cfg.Timer.RandomizedDelay != "" {
+		content.WriteString(fmt.Sprintf("RandomizedDelaySec=%s\n", cfg.Timer.RandomizedDelay))
+	}
+	content.WriteString(fmt.Sprintf("Unit=%s.service\n", serviceName))
+	content.WriteString("\n")
+
+	content.WriteString("[Install]\n")
+	content.WriteString("WantedBy=timers.target\n")
+	return content.String()
+}
+
+func handleOnCalendarOption(cfg *ServiceConfig, value string) error {
+	if err := checkCalendarSyntax(value); err != nil {
+		return err
+	}
+	timer := ensureTimer(cfg)
+	timer.OnCalendar = appendUnique(timer.OnCalendar, value)
+	return nil
+}
+
+func handlePersistentOption(cfg *ServiceConfig, value string) error {
+	var persistent *bool
+	if err := parseAndSetBoolOption(&persistent, value, "persistent"); err != nil {
+		return err
+	}
+	ensureTimer(cfg).Persistent = *persistent
+	return nil
+}
+
+func handleRandomizedDelayOption(cfg *ServiceConfig, value string) error {
+	if !timespanPattern.MatchString(value) {
+		return fmt.Errorf("invalid randomized-delay: %s (use a time span such as 30s, 5m or 1h)", value)
+	}
+	ensureTimer(cfg).RandomizedDelay = value
+	return nil
+}
+
+// ensureTimer 定时选项出现时创建定时配置
+func ensureTimer(cfg *ServiceConfig) *TimerConfig {
+	if cfg.Timer == nil {
+		cfg.Timer = &TimerConfig{}
+	}
+	return cfg.Timer
+}
+
+// validateTimer 验证定时任务配置，oneshot 服务只支持 no 和 on-failure 重启策略
+func validateTimer(cfg *ServiceConfig) error {
+	if cfg.Timer == nil {
+		return nil
+	}
+	if len(cfg.Timer.OnCalendar) == 0 {
+		return fmt.Errorf("scheduled job requires at least one --on-calendar")
+	}
+	for _, spec := range cfg.Timer.OnCalendar {
+		if err := checkCalendarSyntax(spec); err != nil {
+			return err
+		}
+	}
+	if cfg.Timer.RandomizedDelay != "" && !timespanPattern.MatchString(cfg.Timer.RandomizedDelay) {
+		return fmt.Errorf("invalid randomized-delay: %s (use a time span such as 30s, 5m or 1h)", cfg.Timer.RandomizedDelay)
+	}
+	if cfg.Restart != "no" && cfg.Restart != "on-failure" {
+		return fmt.Errorf("restart policy %s is not supported by scheduled jobs (valid: no, on-failure)", cfg.Restart)
+	}
+	return nil
+}
+
+// checkCalendarSyntax 日历表达式的基本格式检查，完整校验交给 systemd-analyze calendar
+func checkCalendarSyntax(spec string) error {
+	if spec == "" || len(spec) > 256 {
+		return fmt.Errorf("invalid on-calendar: %q", spec)
+	}
+	for _, r := range spec {
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') ||
+			strings.ContainsRune("*,./:~- +_", r)) {
+			return fmt.Errorf("invalid on-calendar: %s (unexpected character %q)", spec, r)
+		}
+	}
+	return nil
+}
+
+// CheckCalendar 用 systemd-analyze calendar 校验日历表达式并返回下次触发时间
+// 没有 systemd-analyze 时只做基本格式检查，返回零值时间
+func (sm *ServiceManager) CheckCalendar(ctx context.Context, spec string) (time.Time, error) {
+	if err := checkCalendarSyntax(spec); err != nil {
+		return time.Time{}, err
+	}
+	if _, err := exec.LookPath("systemd-analyze"); err != nil {
+		return time.Time{}, nil
+	}
+
+	output, err := sm.run(ctx, "systemd-analyze", "calendar", spec)
+	if err != nil {
+		var cmdErr *CommandError
+		if errors.As(err, &cmdErr) && cmdErr.Output != "" {
+			return time.Time{}, fmt.Errorf("invalid on-calendar: %s", cmdErr.Output)
+		}
+		return time.Time{}, fmt.Errorf("invalid on-calendar: %s: %w", spec, err)
+	}
+	for _, line := range strings.Split(output, "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), "Next elapse:"); ok {
+			return parseSystemdTime(strings.TrimSpace(value)), nil
+		}
+	}
+	return time.Time{}, nil
+}
+
+// checkCalendars 校验配置中的所有日历表达式
+func (sm *ServiceManager) checkCalendars(ctx context.Context, cfg *ServiceConfig) error {
+	if cfg.Timer == nil {
+		return nil
+	}
+	for _, spec := range cfg.Timer.OnCalendar {
+		if _, err := sm.CheckCalendar(ctx, spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IsTimer 服务是否是定时任务
+func (sm *ServiceManager) IsTimer(name string) bool {
+	_, err := os.Stat(sm.TimerPath(name))
+	return err == nil
+}
+
+// controlUnit 启用、启动等操作的单元，定时任务操作的是 .timer
+func (sm *ServiceManager) controlUnit(name string) string {
+	if sm.IsTimer(name) {
+		return UnitName(name) + ".timer"
+	}
+	return UnitName(name)
+}
+
+// timerInfo 补充定时器的自启状态和上次、下次触发时间
+func (sm *ServiceManager) timerInfo(ctx context.Context, info *ServiceInfo) {
+	unit := UnitName(info.Name) + ".timer"
+	info.Timer = &TimerInfo{}
+	if data, err := os.ReadFile(sm.TimerPath(info.Name)); err == nil {
+		for _, line := range strings.Split(string(data), "\n") {
+			if spec, ok := strings.CutPrefix(line, "OnCalendar="); ok {
+				info.Timer.Schedule = append(info.Timer.Schedule, spec)
+			}
+		}
+	}
+
+	props, err := sm.showUnit(ctx, unit, "UnitFileState", "ActiveState", "NextElapseUSecRealtime", "LastTriggerUSec")
+	if err != nil {
+		info.AutostartStatus = sm.queryUnit(ctx, "is-enabled", unit)
+		info.Timer.State = "unknown"
+		return
+	}
+	info.AutostartStatus = props["UnitFileState"]
+	info.Timer.State = props["ActiveState"]
+	if next := parseSystemdTime(props["NextElapseUSecRealtime"]); !next.IsZero() {
+		info.Timer.NextRun = &next
+	}
+	if last := parseSystemdTime(props["LastTriggerUSec"]); !last.IsZero() {
+		info.Timer.LastRun = &last
+	}
+}
+
+// parseSystemdTime 解析 systemctl 输出的时间，如 Tue 2026-10-20 02:00:00 UTC 或 @1760925600
+// 未设置（n/a、空）时返回零值
+func parseSystemdTime(value string) time.Time {
+	if sec, ok := strings.CutPrefix(value, "@"); ok {
+		if n, err := strconv.ParseInt(sec, 10, 64); err == nil {
+			return time.Unix(n, 0)
+		}
+		return time.Time{}
+	}
+	for _, layout := range []string{"Mon 2006-01-02 15:04:05 MST", "Mon 2006-01-02 15:04:05"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
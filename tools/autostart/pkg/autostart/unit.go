@@ -0,0 +1,283 @@
+package autostart
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// 构建服务文件内容
+func BuildServiceContent(cfg *ServiceConfig, serviceName string) string {
+	builder := &serviceContentBuilder{cfg: cfg, serviceName: serviceName}
+	return builder.build()
+}
+
+// BuildUserServiceContent 构建用户服务（systemctl --user）的文件内容，不写 User、Group，随用户的 systemd 实例启动
+func BuildUserServiceContent(cfg *ServiceConfig, serviceName string) string {
+	builder := &serviceContentBuilder{cfg: cfg, serviceName: serviceName, userUnit: true}
+	return builder.build()
+}
+
+// serviceContentBuilder 服务内容构建器
+type serviceContentBuilder struct {
+	cfg         *ServiceConfig
+	serviceName string
+	userUnit    bool
+	content     strings.Builder
+}
+
+// build 构建服务文件内容
+func (b *serviceContentBuilder) build() string {
+	b.buildUnitSection()
+	b.buildServiceSection()
+	b.buildInstallSection()
+	return b.content.String()
+}
+
+// buildUnitSection 构建 Unit 段
+func (b *serviceContentBuilder) buildUnitSection() {
+	b.content.WriteString("[Unit]\n")
+	b.content.WriteString(fmt.Sprintf("Description=%s\n", b.cfg.Description))
+
+	b.writeServiceList("After", b.cfg.After)
+	b.writeServiceList("Wants", b.cfg.Wants)
+	b.writeServiceList("Requires", b.cfg.Requires)
+
+	b.content.WriteString("\n")
+}
+
+// buildServiceSection 构建 Service 段
+func (b *serviceContentBuilder) buildServiceSection() {
+	b.content.WriteString("[Service]\n")
+	if b.cfg.Timer != nil {
+		b.content.WriteString("Type=oneshot\n")
+	} else {
+		b.content.WriteString("Type=simple\n")
+	}
+	// 用户服务总是以该用户运行，设置 User 会导致启动失败
+	if !b.userUnit {
+		b.content.WriteString(fmt.Sprintf("User=%s\n", b.cfg.User))
+
+		if b.cfg.Group != "" {
+			b.content.WriteString(fmt.Sprintf("Group=%s\n", b.cfg.Group))
+		}
+	}
+
+	if b.cfg.WorkDir != "" {
+		b.content.WriteString(fmt.Sprintf("WorkingDirectory=%s\n", b.cfg.WorkDir))
+	}
+
+	b.content.WriteString(fmt.Sprintf("ExecStart=%s\n", b.normalizeExecStart(b.cfg.ExecStart, b.cfg.WorkDir)))
+	b.writeRestart()
+	b.content.WriteString(fmt.Sprintf("KillMode=%s\n", b.cfg.KillMode))
+	b.content.WriteString(fmt.Sprintf("KillSignal=%s\n", b.cfg.KillSignal))
+	if b.cfg.TimeoutStart == 0 {
+		b.content.WriteString("TimeoutStartSec=infinity\n")
+	} else {
+		b.content.WriteString(fmt.Sprintf("TimeoutStartSec=%d\n", b.cfg.TimeoutStart))
+	}
+	b.content.WriteString(fmt.Sprintf("TimeoutStopSec=%d\n", b.cfg.TimeoutStop))
+	b.content.WriteString("StandardOutput=journal\n")
+	b.content.WriteString("StandardError=journal\n")
+	b.content.WriteString(fmt.Sprintf("SyslogIdentifier=%s\n", b.cfg.Name))
+
+	b.writeResourceLimits()
+	b.writeSandbox()
+	b.writeEnvironmentVariables()
+	b.content.WriteString("\n")
+}
+
+// writeRestart 写入重启策略，定时任务不重启时不写
+func (b *serviceContentBuilder) writeRestart() {
+	if b.cfg.Timer != nil && b.cfg.Restart == "no" {
+		return
+	}
+	b.content.WriteString(fmt.Sprintf("Restart=%s\n", b.cfg.Restart))
+	b.content.WriteString(fmt.Sprintf("RestartSec=%d\n", b.cfg.RestartSec))
+}
+
+// buildInstallSection 构建 Install 段，定时任务由定时器触发，服务本身没有 Install 段
+func (b *serviceContentBuilder) buildInstallSection() {
+	if b.cfg.Timer != nil {
+		return
+	}
+	b.content.WriteString("[Install]\n")
+	if b.userUnit {
+		b.content.WriteString("WantedBy=default.target\n")
+	} else {
+		b.content.WriteString("WantedBy=multi-user.target\n")
+	}
+}
+
+// writeServiceList 写入服务列表
+func (b *serviceContentBuilder) writeServiceList(key string, services []string) {
+	if len(services) > 0 {
+		b.content.WriteString(fmt.Sprintf("%s=%s\n", key, strings.Join(services, " ")))
+	}
+}
+
+// writeResourceLimits 写入资源限制，未设置的不写，沿用 systemd 默认值
+func (b *serviceContentBuilder) writeResourceLimits() {
+	b.writeOptional("MemoryMax", b.cfg.MemoryMax)
+	b.writeOptional("MemoryHigh", b.cfg.MemoryHigh)
+	b.writeOptional("CPUQuota", b.cfg.CPUQuota)
+	b.writeOptional("TasksMax", b.cfg.TasksMax)
+	b.writeOptional("LimitNOFILE", b.cfg.LimitNOFILE)
+
+	if b.cfg.Nice != nil {
+		b.content.WriteString(fmt.Sprintf("Nice=%d\n", *b.cfg.Nice))
+	}
+	if b.cfg.OOMScoreAdjust != nil {
+		b.content.WriteString(fmt.Sprintf("OOMScoreAdjust=%d\n", *b.cfg.OOMScoreAdjust))
+	}
+}
+
+// writeSandbox 写入沙箱配置
+func (b *serviceContentBuilder) writeSandbox() {
+	b.writeOptional("ProtectSystem", b.cfg.ProtectSystem)
+	b.writeBool("PrivateTmp", b.cfg.PrivateTmp)
+	b.writeBool("NoNewPrivileges", b.cfg.NoNewPrivileges)
+
+	if len(b.cfg.ReadWritePaths) > 0 {
+		b.content.WriteString(fmt.Sprintf("ReadWritePaths=%s\n", strings.Join(b.cfg.ReadWritePaths, " ")))
+	}
+}
+
+// writeOptional 值非空时写入
+func (b *serviceContentBuilder) writeOptional(key, value string) {
+	if value != "" {
+		b.content.WriteString(fmt.Sprintf("%s=%s\n", key, value))
+	}
+}
+
+// writeBool 写入 yes/no 形式的布尔值
+func (b *serviceContentBuilder) writeBool(key string, value *bool) {
+	if value == nil {
+		return
+	}
+	if *value {
+		b.content.WriteString(fmt.Sprintf("%s=yes\n", key))
+	} else {
+		b.content.WriteString(fmt.Sprintf("%s=no\n", key))
+	}
+}
+
+// writeEnvironmentVariables 写入环境变量，同名变量以 EnvironmentFile 中的为准
+func (b *serviceContentBuilder) writeEnvironmentVariables() {
+	for _, file := range b.cfg.EnvironmentFile {
+		b.content.WriteString(fmt.Sprintf("EnvironmentFile=%s\n", file))
+	}
+	// 按变量名排序，重新生成时内容稳定，差异中不会出现无关的行
+	keys := make([]string, 0, len(b.cfg.Env))
+	for key := range b.cfg.Env {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := b.cfg.Env[key]
+		// 对包含特殊字符的值进行引用
+		if needsQuoting(value) {
+			value = fmt.Sprintf("\"%s\"", strings.ReplaceAll(value, "\"", "\\\""))
+		}
+		b.content.WriteString(fmt.Sprintf("Environment=%s=%s\n", key, value))
+	}
+}
+
+// 结合工作目录生成绝对路径
+func (b *serviceContentBuilder) normalizeExecStart(execStart, workDir string) string {
+	parts := strings.Fields(execStart)
+	if len(parts) == 0 {
+		return execStart
+	}
+
+	// 获取第一个参数（可执行文件）
+	executable := parts[0]
+
+	// 如果已经是绝对路径，直接返回
+	if filepath.IsAbs(executable) {
+		return execStart
+	}
+
+	// 处理常见的可执行文件
+	var absoluteExec string
+
+	switch {
+	case executable == "sh" || strings.HasSuffix(executable, ".sh"):
+		// 处理 shell 脚本
+		if executable == "sh" && len(parts) > 1 {
+			// 如果是 "sh script.sh" 格式
+			scriptPath := parts[1]
+			if !filepath.IsAbs(scriptPath) {
+				if workDir != "" {
+					scriptPath = filepath.Join(workDir, scriptPath)
+				} else {
+					// 尝试获取脚本的绝对路径
+					if abs, err := filepath.Abs(scriptPath); err == nil {
+						scriptPath = abs
+					}
+				}
+			}
+			absoluteExec = "/bin/sh"
+			parts[1] = scriptPath
+		} else {
+			// 直接执行 .sh 文件
+			if workDir != "" {
+				absoluteExec = filepath.Join(workDir, executable)
+			} else {
+				if abs, err := filepath.Abs(executable); err == nil {
+					absoluteExec = abs
+				} else {
+					absoluteExec = executable
+				}
+			}
+		}
+
+	case executable == "java":
+		// Java 命令使用系统路径
+		if javaPath, err := exec.LookPath("java"); err == nil {
+			absoluteExec = javaPath
+		} else {
+			absoluteExec = "/usr/bin/java"
+		}
+
+	case executable == "python" || executable == "python3":
+		// Python 命令使用系统路径
+		if pythonPath, err := exec.LookPath(executable); err == nil {
+			absoluteExec = pythonPath
+		} else {
+			absoluteExec = "/usr/bin/" + executable
+		}
+
+	case executable == "node":
+		// Node.js 命令使用系统路径
+		if nodePath, err := exec.LookPath("node"); err == nil {
+			absoluteExec = nodePath
+		} else {
+			absoluteExec = "/usr/bin/node"
+		}
+
+	default:
+		// 其他情况，尝试查找可执行文件
+		if execPath, err := exec.LookPath(executable); err == nil {
+			absoluteExec = execPath
+		} else {
+			// 如果找不到，尝试相对于工作目录
+			if workDir != "" {
+				absoluteExec = filepath.Join(workDir, executable)
+			} else {
+				if abs, err := filepath.Abs(executable); err == nil {
+					absoluteExec = abs
+				} else {
+					absoluteExec = executable
+				}
+			}
+		}
+	}
+
+	// 重建命令
+	parts[0] = absoluteExec
+	return strings.Join(parts, " ")
+}
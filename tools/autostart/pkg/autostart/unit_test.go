@@ -0,0 +1,97 @@
+package autostart
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// update 重新生成 testdata 中的单元文件：go test ./pkg/autostart -run Content -update
+var update = flag.Bool("update", false, "rewrite golden unit files in testdata")
+
+func intPtr(v int) *int    { return &v }
+func boolPtr(v bool) *bool { return &v }
+
+// assertGolden 与 testdata/name 比较，-update 时重新写入
+func assertGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		t.Errorf("%s mismatch\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+	}
+}
+
+func TestBuildServiceContent(t *testing.T) {
+	simple := NewServiceConfig("myapp", "/usr/bin/java -jar /opt/myapp/app.jar --server.port=8080")
+	simple.WorkDir = "/opt/myapp"
+	simple.Env = map[string]string{"SPRING_PROFILES_ACTIVE": "prod", "JAVA_OPTS": "-Xms512m -Xmx1g", "GREETING": `say "hi"`}
+
+	limited := NewServiceConfig("worker", "sh start.sh --queue=jobs")
+	limited.WorkDir = "/opt/worker"
+	limited.User, limited.Group = "app", "app"
+	limited.Description = "Queue worker"
+	limited.Restart, limited.RestartSec = "on-failure", 10
+	limited.KillMode, limited.KillSignal = "mixed", "SIGINT"
+	limited.TimeoutStart, limited.TimeoutStop = 0, 30
+	limited.After = []string{"network-online.target", "postgresql.service"}
+	limited.Wants = []string{"network-online.target"}
+	limited.Requires = []string{"postgresql.service"}
+	limited.MemoryMax, limited.MemoryHigh = "2G", "1536M"
+	limited.CPUQuota, limited.TasksMax, limited.LimitNOFILE = "200%", "512", "65535"
+	limited.Nice, limited.OOMScoreAdjust = intPtr(5), intPtr(-500)
+	limited.ProtectSystem = "strict"
+	limited.PrivateTmp, limited.NoNewPrivileges = boolPtr(true), boolPtr(false)
+	limited.ReadWritePaths = []string{"/opt/worker/data", "/var/log/worker"}
+	limited.EnvironmentFile = []string{"/etc/worker/env", "-/etc/worker/env.local"}
+	limited.Env = map[string]string{"QUEUE": "jobs"}
+
+	tests := []struct {
+		golden string
+		cfg    *ServiceConfig
+		user   bool
+	}{
+		{"simple.service", simple, false},
+		{"limits.service", limited, false},
+		{"simple.user.service", simple, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			var got string
+			if tt.user {
+				got = BuildUserServiceContent(tt.cfg, UnitName(tt.cfg.Name))
+			} else {
+				got = BuildServiceContent(tt.cfg, UnitName(tt.cfg.Name))
+			}
+			assertGolden(t, tt.golden, got)
+		})
+	}
+}
+
+func TestBuildTimerContent(t *testing.T) {
+	job := NewTimerServiceConfig("backup", "/usr/local/bin/backup.sh --full")
+	job.WorkDir = "/var/backups"
+	job.Timer.OnCalendar = []string{"*-*-* 02:00:00", "Sat *-*-* 12:00:00"}
+	job.Timer.Persistent = true
+	job.Timer.RandomizedDelay = "5m"
+
+	// 失败重试的定时任务仍然写 Restart
+	retry := NewTimerServiceConfig("report", "/opt/report/run")
+	retry.WorkDir = "/opt/report"
+	retry.Restart, retry.RestartSec = "on-failure", 30
+	retry.Timer.OnCalendar = []string{"hourly"}
+
+	assertGolden(t, "backup.service", BuildServiceContent(job, UnitName(job.Name)))
+	assertGolden(t, "backup.timer", BuildTimerContent(job, UnitName(job.Name)))
+	assertGolden(t, "report.user.service", BuildUserServiceContent(retry, UnitName(retry.Name)))
+	assertGolden(t, "report.timer", BuildTimerContent(retry, UnitName(retry.Name)))
+}
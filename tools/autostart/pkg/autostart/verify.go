@@ -0,0 +1,136 @@
+package autostart
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+)
+
+// UnitFile 生成的单元文件
+type UnitFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// RenderUnits 按配置生成单元文件内容，不写入磁盘，定时任务包括 .timer
+func (sm *ServiceManager) RenderUnits(cfg *ServiceConfig) []UnitFile {
+	units := []UnitFile{{Path: sm.UnitPath(cfg.Name), Content: sm.buildUnit(cfg)}}
+	if cfg.Timer != nil {
+		units = append(units, UnitFile{Path: sm.TimerPath(cfg.Name), Content: BuildTimerContent(cfg, UnitName(cfg.Name))})
+	}
+	return units
+}
+
+// Verify 检查服务在开机时能否正常启动：可执行文件、工作目录、运行用户，
+// 有 systemd-analyze 时再对生成的单元文件执行 systemd-analyze verify，发现问题时返回 *VerifyError
+func (sm *ServiceManager) Verify(ctx context.Context, cfg *ServiceConfig) error {
+	// 基本检查不通过时 systemd-analyze 报的是同样的问题，不再重复
+	problems := sm.checkRuntime(cfg)
+	if len(problems) == 0 {
+		problems = sm.analyzeVerify(ctx, cfg)
+	}
+	if len(problems) > 0 {
+		return &VerifyError{Name: cfg.Name, Problems: problems}
+	}
+	return nil
+}
+
+// checkRuntime 不依赖 systemd 的检查
+func (sm *ServiceManager) checkRuntime(cfg *ServiceConfig) []string {
+	var problems []string
+
+	builder := &serviceContentBuilder{cfg: cfg}
+	if fields := strings.Fields(builder.normalizeExecStart(cfg.ExecStart, cfg.WorkDir)); len(fields) > 0 {
+		if problem := checkExecutable(fields[0]); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+
+	if cfg.WorkDir != "" {
+		if stat, err := os.Stat(cfg.WorkDir); err != nil {
+			problems = append(problems, fmt.Sprintf("working directory %s does not exist", cfg.WorkDir))
+		} else if !stat.IsDir() {
+			problems = append(problems, fmt.Sprintf("working directory %s is not a directory", cfg.WorkDir))
+		}
+	}
+
+	// 用户服务不写 User、Group
+	if !sm.User {
+		if _, err := user.Lookup(cfg.User); err != nil {
+			problems = append(problems, fmt.Sprintf("user %s does not exist", cfg.User))
+		}
+		if cfg.Group != "" {
+			if _, err := user.LookupGroup(cfg.Group); err != nil {
+				problems = append(problems, fmt.Sprintf("group %s does not exist", cfg.Group))
+			}
+		}
+	}
+
+	for _, file := range cfg.EnvironmentFile {
+		if strings.HasPrefix(file, "-") {
+			continue
+		}
+		if _, err := os.Stat(file); err != nil {
+			problems = append(problems, fmt.Sprintf("environment file %s does not exist (prefix with - to ignore)", file))
+		}
+	}
+
+	return problems
+}
+
+// checkExecutable 可执行文件必须是存在且可执行的绝对路径，路径中的空格会被拆成参数，也会在这里暴露
+func checkExecutable(path string) string {
+	if !filepath.IsAbs(path) {
+		return fmt.Sprintf("executable %s is not an absolute path", path)
+	}
+	stat, err := os.Stat(path)
+	if err != nil {
+		return fmt.Sprintf("executable %s does not exist", path)
+	}
+	if stat.IsDir() {
+		return fmt.Sprintf("executable %s is a directory", path)
+	}
+	if stat.Mode()&0111 == 0 {
+		return fmt.Sprintf("executable %s is not executable", path)
+	}
+	return ""
+}
+
+// analyzeVerify 把单元文件写到临时目录后执行 systemd-analyze verify，只保留与该单元有关的输出
+// 没有 systemd-analyze 或无法运行（如用户实例不可用）时跳过
+func (sm *ServiceManager) analyzeVerify(ctx context.Context, cfg *ServiceConfig) []string {
+	if _, err := exec.LookPath("systemd-analyze"); err != nil {
+		return nil
+	}
+	dir, err := os.MkdirTemp("", "autostart-verify-")
+	if err != nil {
+		return nil
+	}
+	defer os.RemoveAll(dir)
+
+	args := []string{"verify"}
+	if sm.User {
+		args = append([]string{"--user"}, args...)
+	}
+	for _, unit := range sm.RenderUnits(cfg) {
+		path := filepath.Join(dir, filepath.Base(unit.Path))
+		if err := os.WriteFile(path, []byte(unit.Content), 0644); err != nil {
+			return nil
+		}
+		args = append(args, path)
+	}
+
+	output, _ := sm.run(ctx, "systemd-analyze", args...)
+	var problems []string
+	for _, line := range strings.Split(output, "\n") {
+		// verify 同时会加载依赖的单元，其他单元的警告与本服务无关
+		if strings.Contains(line, UnitName(cfg.Name)+".") {
+			problems = append(problems, strings.ReplaceAll(line, dir+"/", ""))
+		}
+	}
+	return problems
+}